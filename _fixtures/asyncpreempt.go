@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+var sink int
+
+func spin(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += i
+	}
+	return s
+}
+
+func main() {
+	go func() {
+		for {
+			sink = spin(1 << 30)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		runtime.GC()
+	}
+	fmt.Println(sink)
+}
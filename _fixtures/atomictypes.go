@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+type T struct {
+	n int
+}
+
+type counters struct {
+	hits atomic.Int64
+	done atomic.Bool
+}
+
+func main() {
+	var (
+		b    atomic.Bool
+		i32  atomic.Int32
+		i64  atomic.Int64
+		u32  atomic.Uint32
+		u64  atomic.Uint64
+		uptr atomic.Uintptr
+		ptr  atomic.Pointer[T]
+		nptr atomic.Pointer[T]
+		val  atomic.Value
+		nval atomic.Value
+		cs   counters
+	)
+	b.Store(true)
+	i32.Store(-32)
+	i64.Store(-64)
+	u32.Store(32)
+	u64.Store(64)
+	uptr.Store(0xbeef)
+	ptr.Store(&T{n: 1})
+	val.Store(42)
+	cs.hits.Add(3)
+	runtime.Breakpoint()
+	fmt.Println(&b, &i32, &i64, &u32, &u64, &uptr, &ptr, &nptr, &val, &nval, &cs)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+)
+
+func handler(n int) {
+	fmt.Println(n)
+}
+
+func main() {
+	handler(0)
+	pprof.Do(context.Background(), pprof.Labels("service", "orders"), func(context.Context) {
+		handler(1)
+	})
+	pprof.Do(context.Background(), pprof.Labels("service", "payments", "region", "eu"), func(context.Context) {
+		handler(2)
+	})
+}
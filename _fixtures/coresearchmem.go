@@ -0,0 +1,16 @@
+package main
+
+type T struct {
+	n int
+	s string
+}
+
+var global *T
+
+func main() {
+	obj := &T{n: 0x1badc0de, s: "findme"}
+	global = obj
+	refs := []*T{obj, obj}
+	global2 := refs
+	panic(global2)
+}
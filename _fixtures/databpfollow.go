@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+func count(n int) int {
+	x := 0
+	for i := 0; i < n; i++ {
+		x += i
+	}
+	return x
+}
+
+func main() {
+	a := count(2)
+	b := count(3)
+	fmt.Println(a, b)
+}
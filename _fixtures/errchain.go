@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+type myError struct {
+	code int
+	err  error
+}
+
+func (e *myError) Error() string { return fmt.Sprintf("code %d: %v", e.code, e.err) }
+func (e *myError) Unwrap() error { return e.err }
+
+func main() {
+	base := errors.New("base error")
+	_, perr := os.Open("/nonexistent/file")
+	wrapped := fmt.Errorf("level 1: %w", base)
+	wrapped2 := fmt.Errorf("level 2: %w", wrapped)
+	multi := fmt.Errorf("multi: %w, %w", wrapped, perr)
+	joined := errors.Join(base, perr)
+	custom := fmt.Errorf("custom: %w", &myError{42, wrapped})
+	var nilerr error
+	runtime.Breakpoint()
+	fmt.Println(base, perr, wrapped, wrapped2, multi, joined, custom, nilerr)
+}
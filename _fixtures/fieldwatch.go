@@ -0,0 +1,23 @@
+package main
+
+import "fmt"
+
+type Counter struct {
+	name  string
+	count int
+}
+
+func newCounter(name string) *Counter {
+	return &Counter{name: name}
+}
+
+func main() {
+	var cs []*Counter
+	for i := 0; i < 3; i++ {
+		cs = append(cs, newCounter(fmt.Sprintf("c%d", i)))
+	}
+	for _, c := range cs {
+		c.count++
+	}
+	fmt.Println(cs[0].count, cs[1].count, cs[2].count)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type Counter struct {
+	name  string
+	count int
+}
+
+// Other has the same size and span class as Counter
+type Other struct {
+	p    *int
+	a, b uint64
+}
+
+var (
+	others  []*Other
+	counter *Counter
+)
+
+//go:noinline
+func useCounter() {
+	counter = &Counter{name: "c1"}
+	counter.count++
+	counter = nil
+}
+
+func main() {
+	useCounter()
+	runtime.GC()
+	for i := 0; i < 1000; i++ {
+		others = append(others, &Other{})
+	}
+	counter = &Counter{name: "c2"}
+	counter.count++
+	fmt.Println(counter.count, len(others))
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+var release bool
+
+func block() int {
+	for !release {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return 42
+}
+
+func main() {
+	runtime.Breakpoint()
+	release = true
+	fmt.Println(block())
+}
@@ -0,0 +1,16 @@
+package main
+
+import "fmt"
+
+type counter struct {
+	n int
+}
+
+func (c *counter) Get() int {
+	return c.n
+}
+
+func main() {
+	c := &counter{n: 2}
+	fmt.Println(c.Get()) // breakpoint here
+}
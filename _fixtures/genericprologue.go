@@ -0,0 +1,34 @@
+package main
+
+import "fmt"
+
+type number interface {
+	~int | ~float64
+}
+
+func sum[T number](a, b T, s []T) T {
+	r := a + b
+	for _, x := range s {
+		r += x
+	}
+	fmt.Println(r)
+	return r
+}
+
+type pair[K comparable, V any] struct {
+	k K
+	v V
+}
+
+func (p *pair[K, V]) set(k K, v V) {
+	p.k = k
+	p.v = v
+	fmt.Println(p.k, p.v)
+}
+
+func main() {
+	sum[int](1, 2, []int{3, 4})
+	sum[float64](1.5, 2.5, nil)
+	p := &pair[string, int]{}
+	p.set("a", 1)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type List[E any] struct {
+	elems []E
+}
+
+func (l *List[E]) Push(e E) {
+	l.elems = append(l.elems, e)
+	runtime.Breakpoint()
+}
+
+func F[T any](x T) {
+	runtime.Breakpoint()
+	fmt.Println(x)
+}
+
+func main() {
+	F(1)
+	F("hello")
+	l := &List[float64]{}
+	l.Push(1.5)
+	fmt.Println(l.elems)
+}
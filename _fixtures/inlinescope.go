@@ -0,0 +1,25 @@
+package main
+
+import "fmt"
+
+func inner(n int) int {
+	x := n * 2
+	if n > 0 {
+		onlyInner := x + 1
+		x += onlyInner
+	}
+	return x
+}
+
+func outer(n int) int {
+	x := n + 1
+	onlyOuter := inner(x)
+	return onlyOuter + x
+}
+
+func main() {
+	x := 100
+	onlyMain := x + 1
+	r := outer(3)
+	fmt.Println(x, onlyMain, r)
+}
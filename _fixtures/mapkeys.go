@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type paddedKey struct {
+	A byte
+	B [3]int16
+	C int64
+}
+
+func main() {
+	m1 := map[[2]int]string{{1, 2}: "one-two", {3, 4}: "three-four"}
+	m2 := map[paddedKey]int{{A: 1, B: [3]int16{2, 3, 4}, C: 5}: 10, {A: 6, B: [3]int16{7, 8, 9}, C: 10}: 20}
+	runtime.Breakpoint()
+	fmt.Println(m1, m2)
+}
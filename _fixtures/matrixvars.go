@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func main() {
+	var matrix [100][100]float64
+	for i := range matrix {
+		for j := range matrix[i] {
+			matrix[i][j] = float64(i*100 + j)
+		}
+	}
+	runtime.Breakpoint()
+	fmt.Println(matrix[0][0])
+}
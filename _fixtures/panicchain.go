@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+func g() {
+	defer func() {
+		runtime.Breakpoint()
+		recover()
+	}()
+	panic(errors.New("second"))
+}
+
+func f() {
+	defer func() {
+		r := recover()
+		runtime.Breakpoint()
+		fmt.Println("recovered", r)
+	}()
+	defer g()
+	panic("first")
+}
+
+func main() {
+	f()
+	fmt.Println("done")
+}
@@ -46,11 +46,17 @@ func main() {
 	} = &pkg.SomeType{4}
 	var iface2iface interface{} = &iface
 	var iface3 interface{} = &realname.SomeType{A: true}
-
+	A := 10 // shadows the package variable A
 	runtime.Breakpoint()
 	t := reflect.ValueOf(iface2iface).Elem().Type()
 	m := t.Method(0)
 	fmt.Println(m.Type.In(0))
 	fmt.Println(m.Type.String())
-	fmt.Println(badexpr, req, amap, amap2, dir0someType, dir1someType, amap3, anarray, achan, aslice, afunc, astruct, astruct2, iface2iface, iface3, pkg.SomeVar, pkg.A, dir1pkg.A, dirio.A, dirio.SomeFunction)
+	fmt.Println(badexpr, req, amap, amap2, dir0someType, dir1someType, amap3, anarray, achan, aslice, afunc, astruct, astruct2, iface2iface, iface3, pkg.SomeVar, pkg.A, dir1pkg.A, dirio.A, dirio.SomeFunction, A, packageA())
+}
+
+var A = "main.A"
+
+func packageA() string {
+	return A
 }
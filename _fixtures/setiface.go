@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type T struct {
+	A int
+	B string
+}
+
+type I interface {
+	M() int
+}
+
+func (t *T) M() int {
+	return t.A
+}
+
+func main() {
+	var ip interface{} = &T{A: 1}
+	var iv interface{} = T{A: 2}
+	var ii I = &T{A: 3}
+	var in interface{} = 4
+	var inil interface{}
+	runtime.Breakpoint()
+	fmt.Println(ip, iv, ii, in, inil)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+type point struct {
+	x, y int
+}
+
+func main() {
+	var empty sync.Map
+	var deleted sync.Map
+	deleted.Store("gone", 1)
+	deleted.Delete("gone")
+
+	var m sync.Map
+	m.Store("one", 1)
+	m.Store("two", 2)
+	m.Store("three", 3)
+	m.Store("removed", 4)
+	m.Delete("removed")
+	m.Store("two", 22)
+
+	var big sync.Map
+	for i := range 100 {
+		big.Store(i, point{i, -i})
+	}
+
+	runtime.Breakpoint()
+	fmt.Println(&empty, &deleted, &m, &big)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// reader blocks the thread it is executing on in the read system call,
+// until something is written to the pipe.
+func reader(fd int, done chan<- struct{}) {
+	buf := make([]byte, 1)
+	syscall.Read(fd, buf)
+	close(done)
+}
+
+func main() {
+	var fds [2]int
+	if err := syscall.Pipe(fds[:]); err != nil {
+		panic(err)
+	}
+	done := make(chan struct{})
+	go reader(fds[0], done)
+	time.Sleep(100 * time.Millisecond)
+	runtime.Breakpoint()
+	syscall.Write(fds[1], []byte{0})
+	<-done
+}
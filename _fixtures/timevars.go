@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+func main() {
+	tm := time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)
+	d1 := time.Hour + 2*time.Minute + 3*time.Second
+	d2 := 1500 * time.Millisecond
+	d3 := time.Second
+	runtime.Breakpoint()
+	fmt.Println(tm, d1, d2, d3)
+}
@@ -0,0 +1,18 @@
+package main
+
+import "fmt"
+
+func countdown(n int) int {
+	steps := 0
+	for n > 0 {
+		n--
+		steps++
+	}
+	return steps
+}
+
+func main() {
+	for i := 0; i < 5; i++ {
+		fmt.Println(countdown(i))
+	}
+}
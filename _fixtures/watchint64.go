@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+var counter int64
+
+func main() {
+	runtime.LockOSThread()
+	fmt.Println("start")
+	counter = 1 << 40 // write site
+	fmt.Println(counter)
+}
@@ -1,6 +1,8 @@
 package cmds
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -35,6 +37,16 @@ func TestParseRedirects(t *testing.T) {
 			[3]string{"three.txt", "one.txt", "two.txt"},
 			"",
 		},
+		{
+			[]string{"stdin=one.txt", "stdout=two.txt", "stderr:/dev/null"},
+			[3]string{"one.txt", "two.txt", "/dev/null"},
+			"",
+		},
+		{
+			[]string{"stdout=one.txt", "stdout:two.txt"},
+			[3]string{},
+			"redirect error: stdout redirected twice",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -57,3 +69,57 @@ func TestParseRedirects(t *testing.T) {
 		}
 	}
 }
+
+func TestParseByteSize(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want int64
+	}{
+		{"", 0},
+		{"100", 100},
+		{"100B", 100},
+		{"2KB", 2 << 10},
+		{"10MB", 10 << 20},
+		{"10 mb", 10 << 20},
+		{"1GB", 1 << 30},
+	} {
+		got, err := parseByteSize(tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d", tc.in, got, err, tc.want)
+		}
+	}
+	for _, in := range []string{"MB", "-1MB", "ten", "1TB"} {
+		if _, err := parseByteSize(in); err == nil {
+			t.Errorf("parseByteSize(%q) did not return an error", in)
+		}
+	}
+}
+
+func TestReadClientToken(t *testing.T) {
+	defer func() { clientTokenFile = "" }()
+
+	t.Setenv(clientTokenEnvVar, "envtoken")
+	token, err := readClientToken()
+	if err != nil || token != "envtoken" {
+		t.Errorf("readClientToken() = %q, %v (expected token from environment)", token, err)
+	}
+	if _, ok := os.LookupEnv(clientTokenEnvVar); ok {
+		t.Errorf("%s still set after reading the client token", clientTokenEnvVar)
+	}
+
+	clientTokenFile = filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(clientTokenFile, []byte("filetoken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	token, err = readClientToken()
+	if err != nil || token != "filetoken" {
+		t.Errorf("readClientToken() = %q, %v (expected token from file)", token, err)
+	}
+
+	if err := os.WriteFile(clientTokenFile, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := readClientToken(); err == nil {
+		t.Errorf("readClientToken() = %q, expected error for empty file", token)
+	}
+}
@@ -1,9 +1,13 @@
 package cmds
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"go/parser"
 	"log"
 	"net"
 	"os"
@@ -11,6 +15,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"syscall"
@@ -41,6 +46,10 @@ var (
 	logOutput string
 	// logDest is the file path or file descriptor where logs should go.
 	logDest string
+	// logMaxSize is the size after which the log file is rotated.
+	logMaxSize string
+	// logMaxFiles is the number of log files kept when rotating.
+	logMaxFiles int
 	// headless is whether to run without terminal.
 	headless bool
 	// continueOnStart is whether to continue the process on startup
@@ -49,12 +58,26 @@ var (
 	apiVersion int
 	// acceptMulti allows multiple clients to connect to the same server
 	acceptMulti bool
+	// clientTokenFile is the file containing the token JSON-RPC clients must
+	// present to the server, see readClientToken.
+	clientTokenFile string
+	// tlsCert and tlsKey are the certificate and key used by the headless
+	// server or, with mutual TLS, by the client.
+	tlsCert, tlsKey string
+	// tlsClientCA is the CA the headless server uses to verify client
+	// certificates.
+	tlsClientCA string
+	// tlsCA is the CA used by 'connect' to verify the server certificate.
+	tlsCA string
 	// addr is the debugging server listen address.
 	addr string
 	// initFile is the path to initialization file.
 	initFile string
 	// buildFlags is the flags passed during compiler invocation.
 	buildFlags string
+	// buildTags is the comma separated list of build tags passed during
+	// compiler invocation.
+	buildTags string
 	// workingDir is the working directory for running the program.
 	workingDir string
 	// checkLocalConnUser is true if the debugger should check that local
@@ -88,7 +111,9 @@ var (
 	traceUseEBPF       bool
 	traceShowTimestamp bool
 	traceFollowCalls   int
+	traceCond          string
 	traceVerbose       int
+	traceOutputFormat  string
 
 	// redirect specifications for target process
 	redirects []string
@@ -102,8 +127,10 @@ var (
 	rrDelOnDetach  bool
 
 	attachWaitFor         string
+	attachCheckUser       bool
 	attachWaitForInterval float64
 	attachWaitForDuration float64
+	attachDebugInfo       string
 )
 
 const dlvCommandLongDesc = `Delve is a source level debugger for Go programs.
@@ -148,19 +175,33 @@ func New(docCall bool) *cobra.Command {
 	must(rootCommand.RegisterFlagCompletionFunc("log-output", cobra.FixedCompletions([]string{"debugger", "gdbwire", "lldbout", "debuglineerr", "rpc", "dap", "fncall", "minidump", "stack"}, cobra.ShellCompDirectiveNoFileComp)))
 	rootCommand.PersistentFlags().StringVarP(&logDest, "log-dest", "", "", "Writes logs to the specified file or file descriptor (see 'dlv help log').")
 	must(rootCommand.MarkPersistentFlagFilename("log-dest", "log"))
+	rootCommand.PersistentFlags().StringVar(&logMaxSize, "log-max-size", "", "Rotates the log file specified by --log-dest once it grows past this size, for example 10MB (see 'dlv help log').")
+	must(rootCommand.RegisterFlagCompletionFunc("log-max-size", cobra.NoFileCompletions))
+	rootCommand.PersistentFlags().IntVar(&logMaxFiles, "log-max-files", 5, "Number of log files kept when --log-max-size is used, including the current one.")
+	must(rootCommand.RegisterFlagCompletionFunc("log-max-files", cobra.NoFileCompletions))
 
 	rootCommand.PersistentFlags().BoolVarP(&headless, "headless", "", false, "Run debug server only, in headless mode. Server will accept both JSON-RPC or DAP client connections.")
 	rootCommand.PersistentFlags().BoolVarP(&acceptMulti, "accept-multiclient", "", false, "Allows a headless server to accept multiple client connections via JSON-RPC or DAP.")
+	rootCommand.PersistentFlags().StringVar(&clientTokenFile, "client-token-file", "", "File containing the token that JSON-RPC clients must present to connect to a headless server, connections that don't present it (including DAP connections) are closed. If not specified the token is read from the DELVE_CLIENT_TOKEN environment variable. Also used by 'dlv connect'. This is not a replacement for an encrypted and authenticated transport.")
+	must(rootCommand.MarkPersistentFlagFilename("client-token-file"))
+	rootCommand.PersistentFlags().StringVar(&tlsCert, "tls-cert", "", "Certificate (PEM) used to serve TLS connections when headless. Also used by 'dlv connect' as client certificate for mutual TLS.")
+	must(rootCommand.MarkPersistentFlagFilename("tls-cert"))
+	rootCommand.PersistentFlags().StringVar(&tlsKey, "tls-key", "", "Private key (PEM) of the certificate specified with --tls-cert.")
+	must(rootCommand.MarkPersistentFlagFilename("tls-key"))
+	rootCommand.PersistentFlags().StringVar(&tlsClientCA, "tls-client-ca", "", "Requires clients of a TLS headless server to present a certificate signed by this CA (PEM).")
+	must(rootCommand.MarkPersistentFlagFilename("tls-client-ca"))
 	rootCommand.PersistentFlags().IntVar(&apiVersion, "api-version", 2, "Selects JSON-RPC API version when headless. The only valid value is 2. Can be reset via RPCServer.SetApiVersion. See Documentation/api/json-rpc/README.md.")
 	must(rootCommand.RegisterFlagCompletionFunc("api-version", cobra.FixedCompletions([]string{"1", "2"}, cobra.ShellCompDirectiveNoFileComp)))
 	rootCommand.PersistentFlags().StringVar(&initFile, "init", "", "Init file, executed by the terminal client.")
 	must(rootCommand.MarkPersistentFlagFilename("init"))
 	rootCommand.PersistentFlags().StringVar(&buildFlags, "build-flags", buildFlagsDefault, "Build flags, to be passed to the compiler. For example: --build-flags=\"-tags=integration -mod=vendor -cover -v\"")
 	must(rootCommand.RegisterFlagCompletionFunc("build-flags", cobra.NoFileCompletions))
+	rootCommand.PersistentFlags().StringVar(&buildTags, "tags", "", "Comma separated list of build tags, to be passed to the compiler. Can not be used if --build-flags also contains -tags.")
+	must(rootCommand.RegisterFlagCompletionFunc("tags", cobra.NoFileCompletions))
 	rootCommand.PersistentFlags().StringVar(&workingDir, "wd", "", "Working directory for running the program.")
 	must(rootCommand.MarkPersistentFlagDirname("wd"))
 	rootCommand.PersistentFlags().BoolVarP(&checkGoVersion, "check-go-version", "", true, "Exits if the version of Go in use is not compatible (too old or too new) with the version of Delve.")
-	rootCommand.PersistentFlags().BoolVarP(&checkLocalConnUser, "only-same-user", "", true, "Only connections from the same user that started this instance of Delve are allowed to connect.")
+	rootCommand.PersistentFlags().BoolVarP(&checkLocalConnUser, "only-same-user", "", true, "Only connections from the same user that started this instance of Delve are allowed to connect. When attaching, only processes owned by the same user can be debugged (Linux only).")
 	rootCommand.PersistentFlags().StringVar(&backend, "backend", "default", `Backend selection (see 'dlv help backend').`)
 	must(rootCommand.RegisterFlagCompletionFunc("backend", cobra.FixedCompletions([]string{"default", "native", "lldb", "rr"}, cobra.ShellCompDirectiveNoFileComp)))
 	rootCommand.PersistentFlags().StringArrayVarP(&redirects, "redirect", "r", []string{}, "Specifies redirect rules for target process (see 'dlv help redirect')")
@@ -177,6 +218,10 @@ func New(docCall bool) *cobra.Command {
 This command will cause Delve to take control of an already running process, and
 begin a new debug session.  When exiting the debug session you will have the
 option to let the process continue or kill it.
+
+On Linux, unless --only-same-user=false is specified, Delve will refuse to
+attach to processes owned by a different user, unless it is running as root
+or with the CAP_SYS_PTRACE capability.
 `,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 && attachWaitFor == "" {
@@ -199,6 +244,7 @@ option to let the process continue or kill it.
 	must(attachCommand.RegisterFlagCompletionFunc("waitfor-interval", cobra.NoFileCompletions))
 	attachCommand.Flags().Float64Var(&attachWaitForDuration, "waitfor-duration", 0, "Total time to wait for a process")
 	must(attachCommand.RegisterFlagCompletionFunc("waitfor-duration", cobra.NoFileCompletions))
+	attachCommand.Flags().StringVar(&attachDebugInfo, "debuginfo", "", "Load debug info from this file (an unstripped copy of the executable or a separate .debug file) instead of the executable, the build IDs of the two files must match.")
 	rootCommand.AddCommand(attachCommand)
 
 	// 'connect' subcommand.
@@ -215,6 +261,8 @@ option to let the process continue or kill it.
 		Run:               connectCmd,
 		ValidArgsFunction: cobra.NoFileCompletions,
 	}
+	connectCommand.Flags().StringVar(&tlsCA, "tls-ca", "", "Connects using TLS, verifying the server certificate with this CA (PEM).")
+	must(connectCommand.MarkFlagFilename("tls-ca"))
 	rootCommand.AddCommand(connectCommand)
 
 	// 'dap' subcommand.
@@ -352,7 +400,35 @@ is useful if you do not want to begin an entire debug session, but merely want
 to know what functions your process is executing.
 
 The output of the trace sub command is printed to stderr, so if you would like to
-only see the output of the trace operations you can redirect stdout.`,
+only see the output of the trace operations you can redirect stdout.
+
+With --output-format=json each tracepoint hit is instead written to stderr as
+a JSON object on its own line, containing a sequence number, a timestamp, the
+goroutine ID, the function name and its arguments or return values. Other
+lines written to stderr, for example warnings, do not start with '{'.
+
+With --cond only calls for which the specified condition is true are traced.
+The condition is evaluated when the function is called and can refer to the
+arguments of the function, return values are only printed for the calls that
+satisfied it, for example:
+
+dlv trace --cond 'x > 100' main.process
+
+When used with --follow-calls the condition only applies to the functions
+matching the regular expression.
+
+With --stack N the N callers of the traced function are printed below each
+call, for example:
+
+> goroutine(1): main.foo(99, 9801)
+	#1 main.main at ./main.go:18
+	#2 runtime.main at /usr/local/go/src/runtime/proc.go:283
+
+Unwinding the stack happens every time a traced function is called, while
+the target is stopped, so it adds to the already significant cost of each
+tracepoint hit, proportionally to N. Keep N small when tracing functions
+that are called often, or narrow down the regular expression. The stack is
+not collected when a traced function returns.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			os.Exit(traceCmd(cmd, args, conf))
 		},
@@ -365,12 +441,18 @@ only see the output of the trace operations you can redirect stdout.`,
 	traceCommand.Flags().BoolVarP(&traceTestBinary, "test", "t", false, "Trace a test binary.")
 	traceCommand.Flags().BoolVarP(&traceUseEBPF, "ebpf", "", false, "Trace using eBPF (experimental).")
 	traceCommand.Flags().BoolVarP(&traceShowTimestamp, "timestamp", "", false, "Show timestamp in the output")
-	traceCommand.Flags().IntVarP(&traceStackDepth, "stack", "s", 0, "Show stack trace with given depth. (Ignored with --ebpf)")
+	traceCommand.Flags().IntVarP(&traceStackDepth, "stack", "s", 0, "Show the given number of callers of each traced call, slows down every tracepoint hit. (Ignored with --ebpf)")
 	must(traceCommand.RegisterFlagCompletionFunc("stack", cobra.NoFileCompletions))
 	traceCommand.Flags().String("output", "", "Output path for the binary.")
 	must(traceCommand.MarkFlagFilename("output"))
 	traceCommand.Flags().IntVarP(&traceFollowCalls, "follow-calls", "", 0, "Trace all children of the function to the required depth. Trace also supports defer functions and cases where functions are dynamically returned and passed as parameters.")
 	traceCommand.Flags().IntVarP(&traceVerbose, "verbose", "v", 0, "Parameter verbosity: 0=values, 1=types, 2=inline, 3=expanded, 4=full (default 0)")
+	traceCommand.Flags().StringVarP(&traceOutputFormat, "output-format", "", "text", "Format of the trace output, one of: text, json.")
+	traceCommand.Flags().StringVarP(&traceCond, "cond", "", "", "Only trace calls for which the condition, evaluated when the function is called, is true. (Ignored with --ebpf)")
+	must(traceCommand.RegisterFlagCompletionFunc("cond", cobra.NoFileCompletions))
+	must(traceCommand.RegisterFlagCompletionFunc("output-format", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+	}))
 	rootCommand.AddCommand(traceCommand)
 
 	coreCommand := &cobra.Command{
@@ -405,10 +487,26 @@ Currently supports linux/amd64 and linux/arm64 core files, windows/amd64 minidum
 
 	// 'version' subcommand.
 	var versionVerbose = false
+	var versionOutput = "text"
 	versionCommand := &cobra.Command{
 		Use:   "version",
 		Short: "Prints version.",
+		Long: `Prints version.
+
+With '--output json' the version is printed as a JSON object containing the
+Delve version, the version of Go used to build Delve, the backend that will
+be used and which backends are supported on this platform. If --verbose is
+also specified the build settings and module dependencies are included.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if versionOutput != "text" && versionOutput != "json" {
+				return fmt.Errorf("unknown output format %q", versionOutput)
+			}
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
+			if versionOutput == "json" {
+				os.Exit(printVersionJSON(versionVerbose))
+			}
 			fmt.Printf("Delve Debugger\n%s\n", version.DelveVersion)
 			if versionVerbose {
 				fmt.Printf("Build Details: %s\n", version.BuildInfo())
@@ -417,9 +515,11 @@ Currently supports linux/amd64 and linux/arm64 core files, windows/amd64 minidum
 		ValidArgsFunction: cobra.NoFileCompletions,
 	}
 	versionCommand.Flags().BoolVarP(&versionVerbose, "verbose", "v", false, "print verbose version info")
+	versionCommand.Flags().StringVar(&versionOutput, "output", "text", "Output format, one of 'text' or 'json'.")
+	must(versionCommand.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp)))
 	rootCommand.AddCommand(versionCommand)
 
-	if path, _ := exec.LookPath("rr"); path != "" || docCall {
+	if runtime.GOOS == "linux" || docCall {
 		replayCommand := &cobra.Command{
 			Use:   "replay [trace directory]",
 			Short: "Replays a rr trace.",
@@ -464,6 +564,10 @@ are:
 	lldb		Uses lldb-server or debugserver.
 	rr		Uses mozilla rr (https://github.com/mozilla/rr).
 
+If the requested backend is not available Delve exits with an error instead
+of falling back to a different backend. The backend that was selected, and
+why, is logged when --log-output includes 'debugger'.
+
 Some backends can be configured using environment variables:
 
 * DELVE_DEBUGSERVER_PATH specifies the path of the debugserver executable for the lldb backend
@@ -499,6 +603,11 @@ otherwise as a file path.
 This option will also redirect the "server listening at" message in headless
 and dap modes.
 
+When --log-dest is a file path --log-max-size can be used to rotate the log
+file once it grows past the specified size (a number of bytes optionally
+followed by KB, MB or GB). Rotated files are renamed to <file>.1, <file>.2,
+etc, the number of files kept is controlled by --log-max-files.
+
 `,
 	})
 
@@ -512,8 +621,13 @@ The --tty argument allows redirecting all standard descriptors to a terminal, sp
 The syntax for '-r' argument is:
 
 		-r [source:]destination
+		-r [source=]destination
 
-Where source is one of 'stdin', 'stdout' or 'stderr' and destination is the path to a file. If the source is omitted stdin is used implicitly.
+Where source is one of 'stdin', 'stdout' or 'stderr' and destination is the path to a file. If the source is omitted stdin is used implicitly. The flag can be repeated to redirect more than one descriptor, redirected descriptors are no longer shared with Delve, so the terminal can still be used to interact with Delve. Special files, like /dev/null or named pipes, can also be used as destinations.
+
+For example:
+
+		dlv debug -r stdin=in.txt -r stdout=out.txt -r stderr=/dev/null
 
 File redirects can also be changed using the 'restart' command.
 `,
@@ -546,7 +660,7 @@ File redirects can also be changed using the 'restart' command.
 
 func dapCmd(cmd *cobra.Command, args []string) {
 	status := func() int {
-		if err := logflags.Setup(logFlag, logOutput, logDest); err != nil {
+		if err := setupLogging(); err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			return 1
 		}
@@ -562,6 +676,12 @@ func dapCmd(cmd *cobra.Command, args []string) {
 		if acceptMulti {
 			fmt.Fprintf(os.Stderr, "Warning: accept-multiclient mode not supported with dap\n")
 		}
+		if clientTokenFile != "" || os.Getenv(clientTokenEnvVar) != "" {
+			fmt.Fprintf(os.Stderr, "Warning: client token not supported with dap\n")
+		}
+		if tlsCert != "" || tlsKey != "" || tlsClientCA != "" {
+			fmt.Fprintf(os.Stderr, "Warning: TLS not supported with dap\n")
+		}
 		if initFile != "" {
 			fmt.Fprint(os.Stderr, "Warning: init file ignored with dap\n")
 		}
@@ -574,6 +694,9 @@ func dapCmd(cmd *cobra.Command, args []string) {
 		if buildFlags != "" {
 			fmt.Fprintf(os.Stderr, "Warning: build flags ignored with dap; specify via launch/attach request instead\n")
 		}
+		if buildTags != "" {
+			fmt.Fprintf(os.Stderr, "Warning: build tags ignored with dap; specify via launch/attach request instead\n")
+		}
 		if workingDir != "" {
 			fmt.Fprintf(os.Stderr, "Warning: working directory ignored with dap: specify via launch request instead\n")
 		}
@@ -594,6 +717,7 @@ func dapCmd(cmd *cobra.Command, args []string) {
 				DebugInfoDirectories: conf.DebugInfoDirectories,
 				CheckGoVersion:       checkGoVersion,
 				DisableASLR:          disableASLR,
+				MaxStackDepth:        conf.MaxStackDepth,
 			},
 			CheckLocalConnUser: checkLocalConnUser,
 		}
@@ -640,6 +764,15 @@ func buildBinary(cmd *cobra.Command, args []string, isTest bool) (string, bool)
 		}
 	}
 
+	buildFlags, err = gobuild.AddTags(buildFlags, buildTags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return "", false
+	}
+	if gobuild.StripsDebugInfo(buildFlags) {
+		fmt.Fprintf(os.Stderr, "Warning: build flags %q remove debug information from the executable, it will not be possible to debug it\n", buildFlags)
+	}
+
 	if isTest {
 		err = gobuild.GoTestBuild(debugname, args, buildFlags)
 	} else {
@@ -671,7 +804,7 @@ func debugCmd(cmd *cobra.Command, args []string) {
 
 func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 	status := func() int {
-		err := logflags.Setup(logFlag, logOutput, logDest)
+		err := setupLogging()
 		defer logflags.Close()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -687,6 +820,13 @@ func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 		if acceptMulti {
 			fmt.Fprintf(os.Stderr, "Warning: accept multiclient mode not supported with trace")
 		}
+		switch traceOutputFormat {
+		case "text", "json":
+			// ok
+		default:
+			fmt.Fprintf(os.Stderr, "unknown output format %q, must be one of: text, json\n", traceOutputFormat)
+			return 1
+		}
 
 		var regexp string
 		var processArgs []string
@@ -732,6 +872,14 @@ func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 			fmt.Fprintln(os.Stderr, "Need to specify a trace depth of at least 1")
 			return 1
 		}
+		if traceCond != "" {
+			if traceUseEBPF {
+				fmt.Fprintf(os.Stderr, "Warning: condition ignored with --ebpf\n")
+			} else if _, err := parser.ParseExpr(traceCond); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid condition %q: %v\n", traceCond, err)
+				return 1
+			}
+		}
 
 		// Make a local in-memory connection that client and server use to communicate
 		listener, clientConn := service.ListenerPipe()
@@ -752,6 +900,7 @@ func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 				Backend:              backend,
 				CheckGoVersion:       checkGoVersion,
 				DebugInfoDirectories: conf.DebugInfoDirectories,
+				MaxStackDepth:        conf.MaxStackDepth,
 			},
 		})
 		if err := server.Run(); err != nil {
@@ -796,6 +945,7 @@ func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 					FunctionName:     funcs[i],
 					Tracepoint:       true,
 					Line:             -1,
+					Cond:             traceCond,
 					Stacktrace:       stackdepth,
 					LoadArgs:         &loadCfg,
 					TraceFollowCalls: traceFollowCalls,
@@ -813,11 +963,17 @@ func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 					fmt.Fprintf(os.Stderr, "unable to set tracepoint on function %s: %#v\n", funcs[i], err)
 					continue
 				}
+				// The stack is only printed for calls, don't pay for unwinding it
+				// on returns unless --follow-calls needs it to compute the depth.
+				retstackdepth := 0
+				if traceFollowCalls > 0 {
+					retstackdepth = stackdepth
+				}
 				for i := range addrs {
 					_, err = client.CreateBreakpoint(&api.Breakpoint{
 						Addr:             addrs[i],
 						TraceReturn:      true,
-						Stacktrace:       stackdepth,
+						Stacktrace:       retstackdepth,
 						Line:             -1,
 						LoadArgs:         &loadCfg,
 						TraceFollowCalls: traceFollowCalls,
@@ -843,6 +999,9 @@ func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 		t.SetTraceNonInteractive()
 		t.TraceVerbosity = traceVerbose
 		t.RedirectTo(os.Stderr)
+		if traceOutputFormat == "json" {
+			t.SetTraceJSONOutput(os.Stderr)
+		}
 		defer t.Close()
 		if traceUseEBPF {
 			done := make(chan struct{})
@@ -859,9 +1018,15 @@ func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 							fmt.Fprintf(os.Stderr, "Error retrieving buffered tracepoints: %v\n", err)
 							return
 						}
-						for _, t := range tracepoints {
+						for _, tp := range tracepoints {
+							if traceOutputFormat == "json" {
+								if err := t.WriteTraceEvent(int64(tp.GoroutineID), tp.FunctionName, tp.IsRet, tp.InputParams, tp.ReturnParams, nil); err != nil {
+									fmt.Fprintf(os.Stderr, "Error writing trace event: %v\n", err)
+								}
+								continue
+							}
 							var paramList []string
-							for _, p := range t.InputParams {
+							for _, p := range tp.InputParams {
 								// Format based on verbosity level
 								formatted := api.FormatTraceVariable(p, traceVerbose)
 								// Add parameter names for verbosity >= 1
@@ -890,17 +1055,17 @@ func traceCmd(cmd *cobra.Command, args []string, conf *config.Config) int {
 								fmt.Fprintf(os.Stderr, "%s ", time.Now().Format(time.RFC3339Nano))
 							}
 
-							if t.IsRet {
-								retVals := make([]string, 0, len(t.ReturnParams))
-								for _, p := range t.ReturnParams {
+							if tp.IsRet {
+								retVals := make([]string, 0, len(tp.ReturnParams))
+								for _, p := range tp.ReturnParams {
 									retVals = append(retVals, api.FormatTraceVariable(p, traceVerbose))
 								}
-								fmt.Fprintf(os.Stderr, ">> goroutine(%d): %s => (%s)\n", t.GoroutineID, t.FunctionName, strings.Join(retVals, ","))
+								fmt.Fprintf(os.Stderr, ">> goroutine(%d): %s => (%s)\n", tp.GoroutineID, tp.FunctionName, strings.Join(retVals, ","))
 							} else {
 								if traceVerbose >= 3 {
-									fmt.Fprintf(os.Stderr, "> goroutine(%d): %s(\n%s)\n", t.GoroutineID, t.FunctionName, paramStr)
+									fmt.Fprintf(os.Stderr, "> goroutine(%d): %s(\n%s)\n", tp.GoroutineID, tp.FunctionName, paramStr)
 								} else {
-									fmt.Fprintf(os.Stderr, "> goroutine(%d): %s(%s)\n", t.GoroutineID, t.FunctionName, paramStr)
+									fmt.Fprintf(os.Stderr, "> goroutine(%d): %s(%s)\n", tp.GoroutineID, tp.FunctionName, paramStr)
 								}
 							}
 						}
@@ -961,7 +1126,11 @@ func getPackageDir(pkg []string) string {
 	return listout.Dir
 }
 
-func attachCmd(_ *cobra.Command, args []string) {
+func attachCmd(cmd *cobra.Command, args []string) {
+	// The owner of the process can only be checked on Linux, elsewhere the
+	// check is only requested (and a warning printed) if the flag was
+	// explicitly set.
+	attachCheckUser = checkLocalConnUser && (runtime.GOOS == "linux" || cmd.Flags().Changed("only-same-user"))
 	var pid int
 	if len(args) > 0 {
 		var err error
@@ -980,7 +1149,7 @@ func coreCmd(_ *cobra.Command, args []string) {
 }
 
 func connectCmd(_ *cobra.Command, args []string) {
-	if err := logflags.Setup(logFlag, logOutput, logDest); err != nil {
+	if err := setupLogging(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 		return
@@ -1025,6 +1194,62 @@ func waitForDisconnectSignal(disconnectChan chan struct{}) {
 	}
 }
 
+// versionInfo is the machine readable output of 'dlv version --output json'.
+type versionInfo struct {
+	Version       string                    `json:"version"`
+	Build         string                    `json:"build"`
+	GoVersion     string                    `json:"goVersion"`
+	GOOS          string                    `json:"goos"`
+	GOARCH        string                    `json:"goarch"`
+	Backend       string                    `json:"backend"`
+	Backends      []debugger.BackendSupport `json:"backends"`
+	BuildSettings map[string]string         `json:"buildSettings,omitempty"`
+	Deps          []versionDep              `json:"deps,omitempty"`
+}
+
+type versionDep struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Replace string `json:"replace,omitempty"`
+}
+
+func printVersionJSON(verbose bool) int {
+	vi := versionInfo{
+		Version:   version.DelveVersion.Number(),
+		Build:     version.DelveVersion.BuildID(),
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		Backend:   debugger.ResolveBackend(backend),
+		Backends:  debugger.Backends(),
+	}
+	if verbose {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			vi.BuildSettings = make(map[string]string)
+			for _, setting := range info.Settings {
+				vi.BuildSettings[setting.Key] = setting.Value
+			}
+			for _, dep := range info.Deps {
+				d := versionDep{Path: dep.Path, Version: dep.Version}
+				if dep.Replace != nil {
+					d.Replace = dep.Replace.Path
+					if dep.Replace.Version != "" {
+						d.Replace += "@" + dep.Replace.Version
+					}
+				}
+				vi.Deps = append(vi.Deps, d)
+			}
+		}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(vi); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
 func splitArgs(cmd *cobra.Command, args []string) ([]string, []string) {
 	if cmd.ArgsLenAtDash() >= 0 {
 		return args[:cmd.ArgsLenAtDash()], args[cmd.ArgsLenAtDash():]
@@ -1034,13 +1259,32 @@ func splitArgs(cmd *cobra.Command, args []string) ([]string, []string) {
 
 func connect(addr string, clientConn net.Conn, conf *config.Config) int {
 	// Create and start a terminal - attach to running instance
-	var client *rpc2.RPCClient
 	if clientConn == nil {
 		if clientConn = netDial(addr); clientConn == nil {
 			return 1 // already logged
 		}
+		tlsConfig, err := clientTLSConfig()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if tlsConfig != nil {
+			if clientConn, err = tlsClient(clientConn, addr, tlsConfig); err != nil {
+				fmt.Fprintf(os.Stderr, "could not connect to %s: %v\n", addr, err)
+				return 1
+			}
+		}
+	}
+	clientToken, err := readClientToken()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	client, err := rpc2.NewClientFromConnWithToken(clientConn, clientToken)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not connect to %s: %v\n", addr, err)
+		return 1
 	}
-	client = rpc2.NewClientFromConn(clientConn)
 	if client.IsMulticlient() {
 		state, _ := client.GetStateNonBlocking()
 		// The error return of GetState will usually be the ErrProcessExited,
@@ -1057,6 +1301,7 @@ func connect(addr string, clientConn net.Conn, conf *config.Config) int {
 	}
 	term := terminal.New(client, conf)
 	term.InitFile = initFile
+	term.ContinueOnStart = continueOnStart
 	status, err := term.Run()
 	if err != nil {
 		fmt.Println(err)
@@ -1065,7 +1310,7 @@ func connect(addr string, clientConn net.Conn, conf *config.Config) int {
 }
 
 func execute(attachPid int, processArgs []string, conf *config.Config, coreFile string, kind debugger.ExecuteKind, dlvArgs []string, buildFlags string) int {
-	if err := logflags.Setup(logFlag, logOutput, logDest); err != nil {
+	if err := setupLogging(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return 1
 	}
@@ -1077,15 +1322,28 @@ func execute(attachPid int, processArgs []string, conf *config.Config, coreFile
 	if headless && (initFile != "") {
 		fmt.Fprint(os.Stderr, "Warning: init file ignored with --headless\n")
 	}
-	if continueOnStart {
-		if !headless {
-			fmt.Fprint(os.Stderr, "Error: --continue only works with --headless; use an init file\n")
-			return 1
-		}
-		if !acceptMulti {
-			fmt.Fprint(os.Stderr, "Error: --continue requires --accept-multiclient\n")
-			return 1
-		}
+	if continueOnStart && headless && !acceptMulti {
+		fmt.Fprint(os.Stderr, "Error: --continue requires --accept-multiclient\n")
+		return 1
+	}
+
+	tlsConfig, err := serverTLSConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	clientToken, err := readClientToken()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if tlsConfig != nil && !headless {
+		fmt.Fprint(os.Stderr, "Warning: TLS options ignored without --headless\n")
+		tlsConfig = nil
+	}
+	if continueOnStart && tlsConfig != nil && tlsConfig.ClientCAs != nil {
+		fmt.Fprint(os.Stderr, "Error: --continue can not be used with --tls-client-ca\n")
+		return 1
 	}
 
 	if !headless && acceptMulti {
@@ -1154,6 +1412,8 @@ func execute(attachPid int, processArgs []string, conf *config.Config, coreFile
 			APIVersion:         apiVersion,
 			CheckLocalConnUser: checkLocalConnUser,
 			DisconnectChan:     disconnectChan,
+			ClientToken:        clientToken,
+			TLSConfig:          tlsConfig,
 			Debugger: debugger.Config{
 				AttachPid:             attachPid,
 				WorkingDir:            workingDir,
@@ -1175,6 +1435,9 @@ func execute(attachPid int, processArgs []string, conf *config.Config, coreFile
 				AttachWaitFor:         attachWaitFor,
 				AttachWaitForInterval: attachWaitForInterval,
 				AttachWaitForDuration: attachWaitForDuration,
+				AttachDebugInfo:       attachDebugInfo,
+				CheckAttachUser:       attachCheckUser,
+				MaxStackDepth:         conf.MaxStackDepth,
 			},
 		})
 	default:
@@ -1205,7 +1468,29 @@ func execute(attachPid int, processArgs []string, conf *config.Config, coreFile
 			if _, isuds := listener.(*net.UnixListener); isuds {
 				addr = "unix:" + addr
 			}
-			client := rpc2.NewClientFromConn(netDial(addr))
+			conn := netDial(addr)
+			if tlsConfig != nil {
+				// Connecting to ourselves, only trust our own certificate.
+				serverCert := tlsConfig.Certificates[0].Certificate[0]
+				conn, err = tlsClient(conn, addr, &tls.Config{
+					InsecureSkipVerify: true,
+					VerifyConnection: func(cs tls.ConnectionState) error {
+						if len(cs.PeerCertificates) == 0 || !bytes.Equal(cs.PeerCertificates[0].Raw, serverCert) {
+							return errors.New("unexpected server certificate")
+						}
+						return nil
+					},
+				})
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					return 1
+				}
+			}
+			client, err := rpc2.NewClientFromConnWithToken(conn, clientToken)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
 			client.Disconnect(true) // true = continue after disconnect
 		}
 		waitForDisconnectSignal(disconnectChan)
@@ -1225,12 +1510,14 @@ func parseRedirects(redirects []string) ([3]string, error) {
 	names := [3]string{"stdin", "stdout", "stderr"}
 	for _, redirect := range redirects {
 		idx := 0
+	namesLoop:
 		for i, name := range names {
-			pfx := name + ":"
-			if strings.HasPrefix(redirect, pfx) {
-				idx = i
-				redirect = redirect[len(pfx):]
-				break
+			for _, sep := range []string{":", "="} {
+				if pfx := name + sep; strings.HasPrefix(redirect, pfx) {
+					idx = i
+					redirect = redirect[len(pfx):]
+					break namesLoop
+				}
 			}
 		}
 		if r[idx] != "" {
@@ -1280,6 +1567,150 @@ func netDial(addr string) net.Conn {
 	return conn
 }
 
+// setupLogging configures logging as specified by the --log, --log-output,
+// --log-dest, --log-max-size and --log-max-files flags.
+func setupLogging() error {
+	maxSize, err := parseByteSize(logMaxSize)
+	if err != nil {
+		return fmt.Errorf("invalid --log-max-size: %v", err)
+	}
+	return logflags.SetupRotating(logFlag, logOutput, logDest, maxSize, logMaxFiles)
+}
+
+// parseByteSize parses a size expressed as a number of bytes, optionally
+// followed by one of the suffixes KB, MB or GB (powers of 1024).
+func parseByteSize(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	mult := int64(1)
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	for _, sfx := range []struct {
+		name string
+		mult int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(upper, sfx.name) {
+			upper = strings.TrimSpace(upper[:len(upper)-len(sfx.name)])
+			mult = sfx.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("could not parse size %q", s)
+	}
+	return n * mult, nil
+}
+
+// clientTokenEnvVar is the environment variable read by readClientToken
+// when --client-token-file is not specified.
+const clientTokenEnvVar = "DELVE_CLIENT_TOKEN"
+
+// readClientToken returns the client token, read from the file specified
+// with --client-token-file or from the DELVE_CLIENT_TOKEN environment
+// variable. The token is never accepted as a command line argument to keep
+// it out of the process list.
+// The environment variable is unset, so that the target process does not
+// inherit it.
+func readClientToken() (string, error) {
+	envToken := os.Getenv(clientTokenEnvVar)
+	os.Unsetenv(clientTokenEnvVar)
+	if clientTokenFile == "" {
+		return envToken, nil
+	}
+	buf, err := os.ReadFile(clientTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read client token: %w", err)
+	}
+	token := strings.TrimSpace(string(buf))
+	if token == "" {
+		return "", fmt.Errorf("client token file %s is empty", clientTokenFile)
+	}
+	return token, nil
+}
+
+// serverTLSConfig returns the TLS configuration for the headless server
+// described by --tls-cert, --tls-key and --tls-client-ca, or nil if TLS was
+// not requested.
+func serverTLSConfig() (*tls.Config, error) {
+	if tlsCert == "" && tlsKey == "" {
+		if tlsClientCA != "" {
+			return nil, errors.New("--tls-client-ca requires --tls-cert and --tls-key")
+		}
+		return nil, nil
+	}
+	if tlsCert == "" || tlsKey == "" {
+		return nil, errors.New("--tls-cert and --tls-key must be specified together")
+	}
+	cert, err := tls.LoadX509KeyPair(tlsCert, tlsKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not load TLS certificate: %w", err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if tlsClientCA != "" {
+		if cfg.ClientCAs, err = loadCertPool(tlsClientCA); err != nil {
+			return nil, err
+		}
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// clientTLSConfig returns the TLS configuration used by 'connect' as
+// described by --tls-ca, --tls-cert and --tls-key, or nil if TLS was not
+// requested.
+func clientTLSConfig() (*tls.Config, error) {
+	if tlsCA == "" && tlsCert == "" && tlsKey == "" {
+		return nil, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if tlsCA != "" {
+		var err error
+		if cfg.RootCAs, err = loadCertPool(tlsCA); err != nil {
+			return nil, err
+		}
+	}
+	if tlsCert != "" || tlsKey != "" {
+		if tlsCert == "" || tlsKey == "" {
+			return nil, errors.New("--tls-cert and --tls-key must be specified together")
+		}
+		cert, err := tls.LoadX509KeyPair(tlsCert, tlsKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not load TLS certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(buf) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// tlsClient wraps conn, connected to addr, with TLS and performs the handshake.
+func tlsClient(conn net.Conn, addr string, cfg *tls.Config) (net.Conn, error) {
+	if cfg.ServerName == "" && !strings.HasPrefix(addr, unixAddrPrefix) {
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			cfg = cfg.Clone()
+			cfg.ServerName = host
+		}
+	}
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
 func must(err error) {
 	if err != nil {
 		log.Fatal(err)
@@ -13,6 +13,7 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
 	"strconv"
@@ -24,6 +25,7 @@ import (
 	"github.com/go-delve/delve/pkg/goversion"
 	protest "github.com/go-delve/delve/pkg/proc/test"
 	"github.com/go-delve/delve/pkg/terminal"
+	"github.com/go-delve/delve/pkg/version"
 	"github.com/go-delve/delve/service/dap"
 	"github.com/go-delve/delve/service/dap/daptest"
 	"github.com/go-delve/delve/service/rpc2"
@@ -877,6 +879,96 @@ func TestTrace2(t *testing.T) {
 	assertNoError(cmd.Wait(), t, "cmd.Wait()")
 }
 
+func TestTraceCond(t *testing.T) {
+	t.Parallel()
+	dlvbin := protest.GetDlvBinary(t)
+
+	fixtures := protest.FindFixturesDir()
+	cmd := exec.Command(dlvbin, "trace", "--cond", "i == 3", "--output", filepath.Join(t.TempDir(), "__debug"), filepath.Join(fixtures, "ebpf_trace.go"), "callme")
+	cmd.Dir = filepath.Join(fixtures, "buildtest")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	assertNoError(cmd.Run(), t, "running trace")
+	output := stderr.String()
+
+	expected := "> goroutine(1): main.callme(3)\n>> goroutine(1): main.callme => (100)\n"
+	if !strings.Contains(output, expected) {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+	if n := strings.Count(output, "main.callme"); n != 2 {
+		t.Fatalf("expected 2 tracepoint hits, got %d:\n%s", n, output)
+	}
+}
+
+func TestTraceCondReturn(t *testing.T) {
+	// The condition is only evaluated when the function is called, the
+	// return of a traced call is traced even if the condition no longer holds
+	// and returns of other calls are not.
+	t.Parallel()
+	dlvbin := protest.GetDlvBinary(t)
+
+	fixtures := protest.FindFixturesDir()
+	for _, tc := range []struct {
+		cond, expected string
+	}{
+		{"n == 3", "> goroutine(1): main.countdown(3)\n>> goroutine(1): main.countdown => (3)\n"},
+		{"n == 0", "> goroutine(1): main.countdown(0)\n>> goroutine(1): main.countdown => (0)\n"},
+	} {
+		cmd := exec.Command(dlvbin, "trace", "--cond", tc.cond, "--output", filepath.Join(t.TempDir(), "__debug"), filepath.Join(fixtures, "tracecond.go"), "main.countdown")
+		cmd.Dir = filepath.Join(fixtures, "buildtest")
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		assertNoError(cmd.Run(), t, "running trace")
+		output := stderr.String()
+
+		if !strings.Contains(output, tc.expected) {
+			t.Errorf("--cond %q expected:\n%s\ngot:\n%s", tc.cond, tc.expected, output)
+		}
+		if n := strings.Count(output, "main.countdown"); n != 2 {
+			t.Errorf("--cond %q expected 2 tracepoint hits, got %d:\n%s", tc.cond, n, output)
+		}
+	}
+}
+
+func TestTraceJSON(t *testing.T) {
+	t.Parallel()
+	dlvbin := protest.GetDlvBinary(t)
+
+	fixtures := protest.FindFixturesDir()
+	cmd := exec.Command(dlvbin, "trace", "--output-format", "json", "--output", filepath.Join(t.TempDir(), "__debug"), filepath.Join(fixtures, "traceprog.go"), "callme")
+	cmd.Dir = filepath.Join(fixtures, "buildtest")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	assertNoError(err, t, "running trace")
+	output := stderr.Bytes()
+
+	// the events must not be mixed with the output of the target process
+	if strings.Contains(string(stdout), "{") {
+		t.Fatalf("trace events written to stdout:\n%s", stdout)
+	}
+
+	var events []terminal.TraceEvent
+	for _, line := range strings.Split(string(output), "\n") {
+		if !strings.HasPrefix(line, "{") {
+			// warnings and other messages
+			continue
+		}
+		var ev terminal.TraceEvent
+		assertNoError(json.Unmarshal([]byte(line), &ev), t, "json.Unmarshal")
+		ev.Time = ""
+		events = append(events, ev)
+	}
+
+	expected := []terminal.TraceEvent{
+		{Seq: 1, GoroutineID: 1, Function: "main.callme", Args: []terminal.TraceEventVar{{Name: "i", Type: "int", Value: "2"}}},
+		{Seq: 2, GoroutineID: 1, Function: "main.callme", Return: true, ReturnValues: []terminal.TraceEventVar{{Name: "~r0", Type: "int", Value: "4"}}},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected:\n%#v\ngot:\n%#v\noutput:\n%s", expected, events, output)
+	}
+}
+
 func TestTraceDirRecursion(t *testing.T) {
 	t.Parallel()
 	dlvbin := protest.GetDlvBinary(t)
@@ -1249,8 +1341,9 @@ func TestTracePrintStack(t *testing.T) {
 	output, err := io.ReadAll(rdr)
 	assertNoError(err, t, "ReadAll")
 
-	if !bytes.Contains(output, []byte("Stack:")) && !bytes.Contains(output, []byte("main.main")) {
-		t.Fatal("stacktrace not printed")
+	expected := regexp.MustCompile(`> goroutine\(1\): main.foo\(99, 9801\)\n\t#1 main.main at .*issue573.go:\d+\n\t#2 runtime.main at .*proc.go:\d+\n>> goroutine\(1\): main.foo => \(9900\)\n`)
+	if !expected.Match(output) {
+		t.Fatalf("stacktrace not printed or printed incorrectly:\n%s", output)
 	}
 }
 
@@ -1795,6 +1888,51 @@ func TestVersion(t *testing.T) {
 	}
 }
 
+func TestVersionJSON(t *testing.T) {
+	t.Parallel()
+	dlvbin := protest.GetDlvBinary(t)
+
+	got, err := exec.Command(dlvbin, "version", "-v", "--output", "json").Output()
+	if err != nil {
+		t.Fatalf("error executing `dlv version`: %v\n%s\n", err, got)
+	}
+	var vi struct {
+		Version   string
+		GoVersion string
+		Backend   string
+		Backends  []struct {
+			Name      string
+			Supported bool
+		}
+		BuildSettings map[string]string
+		Deps          []struct{ Path string }
+	}
+	if err := json.Unmarshal(got, &vi); err != nil {
+		t.Fatalf("could not parse output: %v\n%s", err, got)
+	}
+	if vi.Version != version.DelveVersion.Number() {
+		t.Errorf("wrong version %q", vi.Version)
+	}
+	if vi.GoVersion != runtime.Version() {
+		t.Errorf("wrong go version %q", vi.GoVersion)
+	}
+	if vi.Backend == "" || len(vi.Backends) != 3 {
+		t.Errorf("wrong backends %q %v", vi.Backend, vi.Backends)
+	}
+	if vi.BuildSettings["-compiler"] == "" {
+		t.Errorf("build settings missing: %v", vi.BuildSettings)
+	}
+	found := false
+	for _, dep := range vi.Deps {
+		if dep.Path == "github.com/google/go-dap" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("go-dap dependency missing: %v", vi.Deps)
+	}
+}
+
 func TestStaticcheck(t *testing.T) {
 	t.Parallel()
 	if ver, ok := goversion.Parse(runtime.Version()); ok && ver.IsDevelBuild() {
@@ -1906,6 +2044,25 @@ func TestDefaultBinary(t *testing.T) {
 	wait2()
 }
 
+func TestContinueInteractive(t *testing.T) {
+	// Tests that --continue in interactive mode resumes the target after
+	// running the init file.
+	dlvbin := protest.GetDlvBinary(t)
+	fixture := protest.BuildFixture(t, "continuetestprog", 0)
+
+	initFile := filepath.Join(t.TempDir(), "init")
+	assertNoError(os.WriteFile(initFile, []byte("break main.sayhi\n"), 0o600), t, "writing init file")
+
+	cmd := exec.Command(dlvbin, "exec", "--allow-non-terminal-interactive=true", "--continue", "--init", initFile, fixture.Path)
+	cmd.Stdin = strings.NewReader("exit\n")
+	out, err := cmd.CombinedOutput()
+	t.Logf("%s", out)
+	assertNoError(err, t, "dlv exec")
+	if !strings.Contains(string(out), "> [Breakpoint 1] main.sayhi()") {
+		t.Errorf("target was not continued to the breakpoint set by the init file")
+	}
+}
+
 func TestUnixDomainSocket(t *testing.T) {
 	t.Parallel()
 	tmpdir := t.TempDir()
@@ -95,7 +95,8 @@ type Config struct {
 	SourceListLineCount *int `yaml:"source-list-line-count,omitempty"`
 
 	// DebugInfoDirectories is the list of directories Delve will use
-	// in order to resolve external debug info files.
+	// in order to resolve external debug info files. The global debug
+	// directory, /usr/lib/debug, is always searched after them.
 	DebugInfoDirectories []string `yaml:"debug-info-directories"`
 
 	// Position controls how the current position in the program is displayed.
@@ -117,6 +118,27 @@ type Config struct {
 	// output.
 	TraceShowTimestamp bool `yaml:"trace-show-timestamp"`
 
+	// StepSkipNoDebug causes the 'step' command to step over calls to
+	// functions that do not have debug information.
+	StepSkipNoDebug bool `yaml:"step-skip-no-debug"`
+
+	// SingleGoroutineStepping causes 'next', 'step' and 'stepout' to ignore
+	// breakpoints hit by goroutines other than the current one.
+	SingleGoroutineStepping bool `yaml:"single-goroutine-stepping"`
+
+	// LoadChanBuffer causes the commands print, locals, args and vars to also
+	// read the elements currently in the buffer of channels.
+	LoadChanBuffer bool `yaml:"load-chan-buffer"`
+
+	// LoadSyncMap causes the commands print, locals, args and vars to also
+	// read the key/value pairs stored in sync.Map values.
+	LoadSyncMap bool `yaml:"load-sync-map"`
+
+	// MaxStackDepth is the maximum number of frames unwound by the debugger
+	// before assuming that the stack is corrupted, it is only used when the
+	// debugger is started. If it is 0 proc.DefaultMaxStackDepth is used.
+	MaxStackDepth int `yaml:"max-stack-depth,omitempty"`
+
 	// Prompt is the string printed before each command. If empty, the
 	// default prompt "(dlv) " is used.
 	Prompt string `yaml:"prompt,omitempty"`
@@ -162,6 +184,11 @@ See also Documentation/cli/substitutepath.md for how the rules are applied.
 	"source-list-line-count":    "Number of lines to list above and below the cursor when printing source code.\n",
 	"tab":                       "Changes what is printed when a tab character is encountered in source code.\n",
 	"trace-show-timestamp":      "If true timestamps are shown in the trace output.\n",
+	"step-skip-no-debug":        "If true the 'step' command will step over calls to functions without debug information.\n",
+	"single-goroutine-stepping": "If true 'next', 'step' and 'stepout' will only stop on the current goroutine, breakpoints hit by other goroutines are ignored until the command completes. Other goroutines are not blocked while stepping.\n",
+	"load-chan-buffer":          "If true the elements in the buffer of channels are printed along with the channel.\n",
+	"load-sync-map":             "If true the key/value pairs stored in sync.Map values are printed.\n",
+	"max-stack-depth":           "Maximum number of frames unwound before the stack is assumed to be corrupted, stacktraces requested with a greater depth are truncated. Only read when the debugger starts.\n",
 
 	"debug-info-directories": `	config debug-info-directories -add <path>
 	config debug-info-directories -rm <path>
@@ -377,10 +404,26 @@ substitute-path:
 # Uncomment the following line to make the whatis command also print the DWARF location expression of its argument.
 # show-location-expr: true
 
+# Uncomment the following line to make the step command step over calls to functions without debug information.
+# step-skip-no-debug: true
+
+# Uncomment the following line to make next, step and stepout ignore breakpoints hit by other goroutines.
+# single-goroutine-stepping: true
+
+# Uncomment the following line to print the elements in the buffer of channels.
+# load-chan-buffer: true
+
+# Uncomment the following line to print the key/value pairs stored in sync.Map values.
+# load-sync-map: true
+
+# Maximum number of frames unwound before the stack is assumed to be corrupted.
+# max-stack-depth: 1000
+
 # Allow user to specify output syntax flavor of assembly, one of this list "intel"(default), "gnu", "go".
 # disassemble-flavor: intel
 
-# List of directories to use when searching for separate debug info files.
+# List of directories to use when searching for separate debug info files,
+# /usr/lib/debug is always searched after them.
 debug-info-directories: ["/usr/lib/debug/.build-id"]
 
 # Uncomment to change how the current program position is displayed.
@@ -467,7 +510,7 @@ func WriteConfigDocumentation(w io.Writer) {
 		case "aliases":
 			fmt.Fprint(w, "aliases | Map fo command aliases `command: [ \"alias1\", \"alias2\" ]`.\n")
 		case "debug-info-directories":
-			fmt.Fprint(w, "debug-info-directories | List of directories to use when searching for separate debug info files.\n")
+			fmt.Fprint(w, "debug-info-directories | List of directories to use when searching for separate debug info files, /usr/lib/debug is always searched after them.\n")
 		case "position":
 			fmt.Fprint(w, "position | Controls how the current position in the program is displayed (source | disassembly | default).\n")
 		case "prompt":
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 
 	"github.com/go-delve/delve/pkg/dwarf"
 )
@@ -56,5 +57,8 @@ func (addr *DebugAddr) Get(idx uint64) (uint64, error) {
 		return 0, errors.New("debug_addr section not present")
 	}
 	off := idx*uint64(addr.ptrSz) + addr.addrBase
+	if off+uint64(addr.ptrSz) > uint64(len(addr.data)) {
+		return 0, fmt.Errorf("debug_addr index %d out of range", idx)
+	}
 	return dwarf.ReadUintRaw(bytes.NewReader(addr.data[off:]), addr.byteOrder, addr.ptrSz)
 }
@@ -168,6 +168,32 @@ func (lineInfo *DebugLineInfo) AllPCsForFileLines(f string, m map[int][]uint64)
 	}
 }
 
+// FilesForPCs sets the value of each key of m to the file of the row of the
+// line table with that address. Addresses that do not appear in the line
+// table are left unchanged.
+func (lineInfo *DebugLineInfo) FilesForPCs(m map[uint64]string) {
+	if lineInfo == nil {
+		return
+	}
+
+	sm := newStateMachine(lineInfo, lineInfo.Instructions, lineInfo.ptrSize)
+
+	for {
+		if err := sm.next(); err != nil {
+			if lineInfo.Logf != nil {
+				lineInfo.Logf("FilesForPCs error: %v", err)
+			}
+			break
+		}
+		if !sm.valid {
+			continue
+		}
+		if file, ok := m[sm.address]; ok && file == "" {
+			m[sm.address] = sm.file
+		}
+	}
+}
+
 var ErrNoSource = errors.New("no source available")
 
 // AllPCsBetween returns all PC addresses between begin and end (including both begin and end)
@@ -379,6 +405,40 @@ func (lineInfo *DebugLineInfo) FirstStmt(start, end uint64) (pc uint64, file str
 	}
 }
 
+// LastStmtOnFirstLine looks in the half open interval [start, end) for the
+// last PC address marked as stmt that belongs to the same line as start,
+// stopping at the first address belonging to a different line.
+// The line table of some functions, for example instantiations of generic
+// functions, contains multiple stmt entries for the line of the function
+// header: one for the entry point and one after the stack bounds check and
+// the frame setup.
+func (lineInfo *DebugLineInfo) LastStmtOnFirstLine(start, end uint64) (pc uint64, file string, line int, ok bool) {
+	sm := lineInfo.stateMachineForEntry(start)
+	firstLine := -1
+	for {
+		if sm.valid {
+			if sm.address >= end || sm.address < start {
+				return pc, file, line, ok
+			}
+			if firstLine < 0 {
+				firstLine = sm.line
+			}
+			if sm.line != firstLine {
+				return pc, file, line, ok
+			}
+			if sm.isStmt {
+				pc, file, line, ok = sm.address, sm.file, sm.line, true
+			}
+		}
+		if err := sm.next(); err != nil {
+			if lineInfo.Logf != nil {
+				lineInfo.Logf("LastStmtOnFirstLine error: %v", err)
+			}
+			return pc, file, line, ok
+		}
+	}
+}
+
 func (lineInfo *DebugLineInfo) FirstFile() string {
 	sm := newStateMachine(lineInfo, lineInfo.Instructions, lineInfo.ptrSize)
 	for {
@@ -273,3 +273,71 @@ func TestMultipleSequences(t *testing.T) {
 		t.Errorf("PrologueEndPC failed %x", pc)
 	}
 }
+
+func TestLastStmtOnFirstLine(t *testing.T) {
+	t.Parallel()
+	// Line table of a function where the line of the function header has
+	// two stmt entries, like instantiations of generic functions do.
+
+	instr := bytes.NewBuffer(nil)
+	ptrSize := ptrSizeByRuntimeArch()
+
+	instr.WriteByte(0)
+	leb128.EncodeUnsigned(instr, 9) // 1 + ptr_size
+	instr.WriteByte(DW_LINE_set_address)
+	pdwarf.WriteUint(instr, binary.LittleEndian, ptrSize, 0x400000)
+
+	advance := func(pc uint64, line int64) {
+		instr.WriteByte(DW_LNS_advance_pc)
+		leb128.EncodeUnsigned(instr, pc)
+		if line != 0 {
+			instr.WriteByte(DW_LNS_advance_line)
+			leb128.EncodeSigned(instr, line)
+		}
+	}
+
+	instr.WriteByte(DW_LNS_advance_line)
+	leb128.EncodeSigned(instr, 4)
+	instr.WriteByte(DW_LNS_copy) // thefile.go:5 0x400000 stmt
+	advance(0x10, 0)
+	instr.WriteByte(DW_LNS_copy) // thefile.go:5 0x400010 stmt
+	advance(0x4, 0)
+	instr.WriteByte(DW_LNS_negate_stmt)
+	instr.WriteByte(DW_LNS_copy) // thefile.go:5 0x400014
+	advance(0x8, 1)
+	instr.WriteByte(DW_LNS_negate_stmt)
+	instr.WriteByte(DW_LNS_copy) // thefile.go:6 0x40001c stmt
+	advance(0x8, -1)
+	instr.WriteByte(DW_LNS_copy) // thefile.go:5 0x400024 stmt
+	advance(0x4, 0)
+	instr.WriteByte(0)
+	leb128.EncodeUnsigned(instr, 1)
+	instr.WriteByte(DW_LINE_end_sequence)
+
+	lines := &DebugLineInfo{
+		Prologue: &DebugLinePrologue{
+			UnitLength:     1,
+			Version:        2,
+			MinInstrLength: 1,
+			InitialIsStmt:  1,
+			LineBase:       -3,
+			LineRange:      12,
+			OpcodeBase:     13,
+			StdOpLengths:   []uint8{0, 1, 1, 1, 1, 0, 0, 0, 1, 0, 0, 1},
+		},
+		IncludeDirs:       []string{},
+		FileNames:         []*FileEntry{{Path: "thefile.go"}},
+		Instructions:      instr.Bytes(),
+		ptrSize:           ptrSize,
+		stateMachineCache: make(map[uint64]*StateMachine),
+	}
+
+	pc, _, line, ok := lines.LastStmtOnFirstLine(0x400000, 0x400028)
+	if !ok || pc != 0x400010 || line != 5 {
+		t.Errorf("LastStmtOnFirstLine: got %#x %d %v, expected 0x400010 5 true", pc, line, ok)
+	}
+	pc, _, _, ok = lines.FirstStmt(0x400000, 0x400028)
+	if !ok || pc != 0x400000 {
+		t.Errorf("FirstStmt: got %#x %v, expected 0x400000 true", pc, ok)
+	}
+}
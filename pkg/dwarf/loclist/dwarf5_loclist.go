@@ -131,6 +131,8 @@ func (it *loclistsIterator) next() bool {
 		if it.err == nil {
 			it.end, it.err = it.debugAddr.Get(endIdx)
 		}
+		it.start += it.staticBase
+		it.end += it.staticBase
 		it.onRange = true
 
 	case _DW_LLE_startx_length:
@@ -139,6 +141,7 @@ func (it *loclistsIterator) next() bool {
 		it.readInstr()
 
 		it.start, it.err = it.debugAddr.Get(startIdx)
+		it.start += it.staticBase
 		it.end = it.start + length
 		it.onRange = true
 
@@ -165,12 +168,15 @@ func (it *loclistsIterator) next() bool {
 		it.start, it.err = dwarf.ReadUintRaw(it.buf, it.rdr.byteOrder, it.rdr.ptrSz)
 		it.end, it.err = dwarf.ReadUintRaw(it.buf, it.rdr.byteOrder, it.rdr.ptrSz)
 		it.readInstr()
+		it.start += it.staticBase
+		it.end += it.staticBase
 		it.onRange = true
 
 	case _DW_LLE_start_length:
 		it.start, it.err = dwarf.ReadUintRaw(it.buf, it.rdr.byteOrder, it.rdr.ptrSz)
 		length, _ := leb128.DecodeUnsigned(it.buf)
 		it.readInstr()
+		it.start += it.staticBase
 		it.end = it.start + length
 		it.onRange = true
 
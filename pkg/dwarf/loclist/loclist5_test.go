@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"testing"
 
+	"github.com/go-delve/delve/pkg/dwarf/godwarf"
 	"github.com/go-delve/delve/pkg/dwarf/leb128"
 )
 
@@ -118,3 +119,102 @@ func TestLoclist5(t *testing.T) {
 		}
 	}
 }
+
+func TestLoclist5DebugAddr(t *testing.T) {
+	t.Parallel()
+	const staticBase = 0x400000
+
+	addrbuf := new(bytes.Buffer)
+	binary.Write(addrbuf, binary.LittleEndian, uint32(0)) // length (ignored)
+	binary.Write(addrbuf, binary.LittleEndian, uint16(5)) // version
+	addrbuf.WriteByte(4)                                  // address size
+	addrbuf.WriteByte(0)                                  // segment selector size
+	addrBase := addrbuf.Len()
+	for _, addr := range []uint32{0x1000, 0x1100, 0x2000} {
+		binary.Write(addrbuf, binary.LittleEndian, addr)
+	}
+	debugAddr := godwarf.ParseAddr(addrbuf.Bytes()).GetSubsection(uint64(addrBase))
+
+	buf := new(bytes.Buffer)
+
+	p32 := func(n uint32) { binary.Write(buf, binary.LittleEndian, n) }
+	p16 := func(n uint16) { binary.Write(buf, binary.LittleEndian, n) }
+	p8 := func(n uint8) { binary.Write(buf, binary.LittleEndian, n) }
+	uleb := func(n uint64) { leb128.EncodeUnsigned(buf, n) }
+
+	p32(0x0) // length (use 0 because it is ignored)
+	p16(0x5) // version
+	p8(4)    // address size
+	p8(0)    // segment selector size
+	p32(0)   // offset_entry_count
+
+	off := buf.Len()
+
+	// (startx endx) addr[0] .. addr[1]: 1
+	p8(_DW_LLE_startx_endx)
+	uleb(0)
+	uleb(1)
+	uleb(4)
+	p32(1)
+
+	// (startx length) addr[1] .. addr[1]+0x80: 2
+	p8(_DW_LLE_startx_length)
+	uleb(1)
+	uleb(0x80)
+	uleb(4)
+	p32(2)
+
+	// base address -> addr[2]
+	p8(_DW_LLE_base_addressx)
+	uleb(2)
+
+	// (offset) addr[2]+0x10 .. addr[2]+0x20: 3
+	p8(_DW_LLE_offset_pair)
+	uleb(0x10)
+	uleb(0x20)
+	uleb(4)
+	p32(3)
+
+	// loclist end
+	p8(_DW_LLE_end_of_list)
+
+	testCases := []struct {
+		pc  uint64
+		tgt *Entry
+	}{
+		{staticBase + 0x1000, &Entry{staticBase + 0x1000, staticBase + 0x1100, []byte{1, 0, 0, 0}}},
+		{staticBase + 0x1150, &Entry{staticBase + 0x1100, staticBase + 0x1180, []byte{2, 0, 0, 0}}},
+		{staticBase + 0x2018, &Entry{staticBase + 0x2010, staticBase + 0x2020, []byte{3, 0, 0, 0}}},
+		{0x1000, nil},
+	}
+
+	ll := NewDwarf5Reader(buf.Bytes())
+
+	for _, tc := range testCases {
+		e, err := ll.Find(off, staticBase, staticBase, tc.pc, debugAddr)
+		if err != nil {
+			t.Errorf("error returned for %#x: %v", tc.pc, err)
+			continue
+		}
+		if tc.tgt == nil {
+			if e != nil {
+				t.Errorf("expected no entry for %#x, got %#v", tc.pc, e)
+			}
+			continue
+		}
+		if e == nil || e.LowPC != tc.tgt.LowPC || e.HighPC != tc.tgt.HighPC || !bytes.Equal(e.Instr, tc.tgt.Instr) {
+			t.Errorf("output mismatch for %#x,\nexpected %#v,\ngot     %#v", tc.pc, tc.tgt, e)
+		}
+	}
+
+	// Out of range indexes must be reported as errors.
+	buf.Truncate(off)
+	p8(_DW_LLE_startx_endx)
+	uleb(10)
+	uleb(11)
+	uleb(0)
+	p8(_DW_LLE_end_of_list)
+	if _, err := NewDwarf5Reader(buf.Bytes()).Find(off, staticBase, staticBase, staticBase+0x1000, debugAddr); err == nil {
+		t.Errorf("expected error for out of range debug_addr index")
+	}
+}
@@ -140,6 +140,9 @@ var AMD64NameToDwarf = func() map[string]int {
 	r["st5"] = 38
 	r["st6"] = 39
 	r["st7"] = 40
+	r["pc"] = AMD64_Rip
+	r["sp"] = AMD64_Rsp
+	r["bp"] = AMD64_Rbp
 	return r
 }()
 
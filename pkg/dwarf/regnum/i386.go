@@ -80,6 +80,9 @@ var I386NameToDwarf = func() map[string]int {
 	r["st5"] = 16
 	r["st6"] = 17
 	r["st7"] = 18
+	r["pc"] = I386_Eip
+	r["sp"] = I386_Esp
+	r["bp"] = I386_Ebp
 	return r
 }()
 
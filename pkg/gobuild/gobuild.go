@@ -3,6 +3,7 @@
 package gobuild
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"runtime"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/go-delve/delve/pkg/config"
 	"github.com/go-delve/delve/pkg/logflags"
@@ -39,6 +41,74 @@ func Remove(path string) {
 	}
 }
 
+// AddTags returns buildflags with a -tags flag for the comma separated
+// list of build tags in tags appended to it.
+// An error is returned if tags contains an invalid build tag or if
+// buildflags already contains a -tags flag.
+func AddTags(buildflags, tags string) (string, error) {
+	if tags == "" {
+		return buildflags, nil
+	}
+	for _, tag := range strings.Split(tags, ",") {
+		if err := validateTag(tag); err != nil {
+			return "", err
+		}
+	}
+	for _, flag := range config.SplitQuotedFields(buildflags, '\'') {
+		if flag == "-tags" || strings.HasPrefix(flag, "-tags=") || flag == "--tags" || strings.HasPrefix(flag, "--tags=") {
+			return "", errors.New("build tags specified both with --tags and in --build-flags")
+		}
+	}
+	if buildflags != "" {
+		buildflags += " "
+	}
+	return buildflags + "-tags=" + tags, nil
+}
+
+// validateTag returns an error if tag is not a valid build tag, see
+// go/build/constraint.
+func validateTag(tag string) error {
+	if tag == "" {
+		return errors.New("empty build tag")
+	}
+	for _, ch := range tag {
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' && ch != '.' {
+			return fmt.Errorf("invalid build tag %q", tag)
+		}
+	}
+	return nil
+}
+
+// StripsDebugInfo returns true if buildflags contains linker flags that
+// remove debug information from the executable (-s or -w).
+func StripsDebugInfo(buildflags string) bool {
+	bfv := config.SplitQuotedFields(buildflags, '\'')
+	for i, flag := range bfv {
+		var ldflags string
+		switch {
+		case flag == "-ldflags" || flag == "--ldflags":
+			if i+1 < len(bfv) {
+				ldflags = bfv[i+1]
+			}
+		case strings.HasPrefix(flag, "-ldflags="):
+			ldflags = flag[len("-ldflags="):]
+		case strings.HasPrefix(flag, "--ldflags="):
+			ldflags = flag[len("--ldflags="):]
+		}
+		for _, ldflag := range config.SplitQuotedFields(ldflags, '\'') {
+			// -ldflags can be prefixed with a package pattern, as in all=-s
+			if idx := strings.IndexByte(ldflag, '='); idx >= 0 && !strings.HasPrefix(ldflag, "-") {
+				ldflag = ldflag[idx+1:]
+			}
+			switch ldflag {
+			case "-s", "-w", "-s=true", "-w=true":
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // GoBuild builds non-test files in 'pkgs' with the specified 'buildflags'
 // and writes the output at 'debugname'.
 func GoBuild(debugname string, pkgs []string, buildflags any) error {
@@ -29,3 +29,59 @@ func TestGoBuildArgsDashC(t *testing.T) {
 		})
 	}
 }
+
+func TestAddTags(t *testing.T) {
+	testCases := []struct {
+		buildflags, tags, tgt string
+		err                   bool
+	}{
+		{"", "", "", false},
+		{"-mod=vendor", "", "-mod=vendor", false},
+		{"", "foo", "-tags=foo", false},
+		{"-mod=vendor", "foo,bar_baz,go1.21", "-mod=vendor -tags=foo,bar_baz,go1.21", false},
+		{"", "foo,", "", true},
+		{"", "foo bar", "", true},
+		{"", "foo-bar", "", true},
+		{"-tags=foo", "bar", "", true},
+		{"-tags foo", "bar", "", true},
+	}
+
+	for _, tc := range testCases {
+		out, err := AddTags(tc.buildflags, tc.tags)
+		if tc.err {
+			if err == nil {
+				t.Errorf("AddTags(%q, %q): expected error, got %q", tc.buildflags, tc.tags, out)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("AddTags(%q, %q): unexpected error %v", tc.buildflags, tc.tags, err)
+			continue
+		}
+		if out != tc.tgt {
+			t.Errorf("AddTags(%q, %q): expected %q got %q", tc.buildflags, tc.tags, tc.tgt, out)
+		}
+	}
+}
+
+func TestStripsDebugInfo(t *testing.T) {
+	testCases := []struct {
+		in  string
+		tgt bool
+	}{
+		{"", false},
+		{"-tags=foo -mod=vendor", false},
+		{"-ldflags='-X main.version=1'", false},
+		{"-ldflags='-s -w'", true},
+		{"-ldflags=-w", true},
+		{"-ldflags -s", true},
+		{"-ldflags='all=-s'", true},
+		{"-ldflags='-linkmode internal'", false},
+	}
+
+	for _, tc := range testCases {
+		if out := StripsDebugInfo(tc.in); out != tc.tgt {
+			t.Errorf("StripsDebugInfo(%q): expected %v got %v", tc.in, tc.tgt, out)
+		}
+	}
+}
@@ -34,11 +34,14 @@ func (err *ErrLocationNotFound) Error() string {
 }
 
 // NormalLocationSpec represents a basic location spec.
-// This can be a file:line or func:line.
+// This can be a file:line, func:line or file:startline-endline.
 type NormalLocationSpec struct {
 	Base       string
 	FuncBase   *FuncLocationSpec
 	LineOffset int
+	// LineEnd is the last line of a file:startline-endline spec, zero if
+	// the spec is not a line range.
+	LineEnd int
 }
 
 // RegexLocationSpec represents a regular expression
@@ -147,6 +150,16 @@ func parseLocationSpecDefault(locStr, rest string) (LocationSpec, error) {
 
 	rest = v[1]
 
+	if start, end, isRange := strings.Cut(rest, "-"); isRange && start != "" {
+		var err1, err2 error
+		spec.LineOffset, err1 = strconv.Atoi(start)
+		spec.LineEnd, err2 = strconv.Atoi(end)
+		if err1 != nil || err2 != nil || spec.LineOffset <= 0 || spec.LineEnd < spec.LineOffset {
+			return nil, malformed("invalid line range")
+		}
+		return spec, nil
+	}
+
 	var err error
 	spec.LineOffset, err = strconv.Atoi(rest)
 	if err != nil || spec.LineOffset < 0 {
@@ -419,6 +432,13 @@ func (loc *NormalLocationSpec) Find(t *proc.Target, processArgs []string, scope
 	}
 
 	// len(candidateFiles) + len(candidateFuncs) == 1
+	if loc.LineEnd > 0 {
+		if len(candidateFiles) != 1 {
+			return nil, "", errors.New("line ranges can only be specified for files")
+		}
+		return findLineRange(t, candidateFiles[0], loc.LineOffset, loc.LineEnd)
+	}
+
 	var addrs []uint64
 	var err error
 	if len(candidateFiles) == 1 {
@@ -442,6 +462,37 @@ func (loc *NormalLocationSpec) Find(t *proc.Target, processArgs []string, scope
 	return []api.Location{addressesToLocation(addrs)}, "", nil
 }
 
+// findLineRange returns one location for each line between start and end
+// (inclusive) of file that has executable code, lines that map to the same
+// addresses as a previous line of the range are skipped.
+func findLineRange(t *proc.Target, file string, start, end int) ([]api.Location, string, error) {
+	var r []api.Location
+	seen := map[uint64]bool{}
+lineLoop:
+	for line := start; line <= end; line++ {
+		addrs, err := proc.FindFileLocation(t, file, line)
+		if err != nil {
+			if _, isCouldNotFindLine := err.(*proc.ErrCouldNotFindLine); isCouldNotFindLine {
+				continue
+			}
+			return nil, "", err
+		}
+		for _, addr := range addrs {
+			if seen[addr] {
+				continue lineLoop
+			}
+		}
+		for _, addr := range addrs {
+			seen[addr] = true
+		}
+		r = append(r, addressesToLocation(addrs))
+	}
+	if len(r) == 0 {
+		return nil, "", fmt.Errorf("could not find statements between lines %d and %d of %s", start, end, file)
+	}
+	return r, "", nil
+}
+
 func (loc *NormalLocationSpec) findFuncCandidates(bi *proc.BinaryInfo, limit int) []string {
 	candidateFuncs := map[string]struct{}{}
 	// See if it matches generic functions first
@@ -192,10 +192,26 @@ var errLogstrWithoutLog = errors.New("--log-output specified without --log")
 // If logDest is not empty logs will be redirected to the file descriptor or
 // file path specified by logDest.
 func Setup(logFlag bool, logstr, logDest string) error {
+	return SetupRotating(logFlag, logstr, logDest, 0, 0)
+}
+
+// SetupRotating is like Setup but, if maxSize is greater than zero, the log
+// file specified by logDest is rotated every time it grows past maxSize
+// bytes, keeping at most maxFiles files.
+func SetupRotating(logFlag bool, logstr, logDest string, maxSize int64, maxFiles int) error {
 	if logDest != "" {
 		n, err := strconv.Atoi(logDest)
 		if err == nil {
+			if maxSize > 0 {
+				return errors.New("log rotation requires a file path as log destination")
+			}
 			logOut = os.NewFile(uintptr(n), "delve-logs")
+		} else if maxSize > 0 {
+			rf, err := newRotatingFile(logDest, maxSize, maxFiles)
+			if err != nil {
+				return fmt.Errorf("could not create log file: %v", err)
+			}
+			logOut = rf
 		} else {
 			fh, err := os.Create(logDest)
 			if err != nil {
@@ -203,6 +219,8 @@ func Setup(logFlag bool, logstr, logDest string) error {
 			}
 			logOut = fh
 		}
+	} else if maxSize > 0 {
+		return errors.New("log rotation requires a file path as log destination")
 	}
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 	if !logFlag {
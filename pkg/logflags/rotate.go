@@ -0,0 +1,97 @@
+package logflags
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rotatingFile is a log file that is rotated once it grows past maxSize
+// bytes. Rotated files are renamed to path.1, path.2, ... with path.1 being
+// the most recent, at most maxFiles files (including the current one) are
+// kept.
+// All loggers share the same rotatingFile, writes are serialized so that
+// rotation never splits a log line between files.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	fh       *os.File
+	size     int64
+}
+
+func newRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	if maxFiles < 1 {
+		maxFiles = 1
+	}
+	fh, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles, fh: fh}, nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.fh == nil {
+		return 0, os.ErrClosed
+	}
+	if rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil && rf.fh == nil {
+			return 0, err
+		}
+	}
+	n, err := rf.fh.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// rotate closes the current file, shifts the names of the old files and
+// opens a new empty file. If any of this fails the file that was being
+// written is reopened, so that logging can continue, and the error is
+// returned. Must be called with rf.mu held.
+func (rf *rotatingFile) rotate() error {
+	oldPath := rf.path
+	err := rf.fh.Close()
+	rf.fh = nil
+	if err == nil && rf.maxFiles > 1 {
+		for i := rf.maxFiles - 2; i >= 1; i-- {
+			// Older files may not exist yet.
+			_ = os.Rename(rf.backupName(i), rf.backupName(i+1))
+		}
+		err = os.Rename(rf.path, rf.backupName(1))
+		if err == nil {
+			oldPath = rf.backupName(1)
+		}
+	}
+	if err == nil {
+		var fh *os.File
+		fh, err = os.Create(rf.path)
+		if err == nil {
+			rf.fh = fh
+			rf.size = 0
+			return nil
+		}
+	}
+	if fh, reopenErr := os.OpenFile(oldPath, os.O_WRONLY|os.O_APPEND, 0); reopenErr == nil {
+		rf.fh = fh
+	}
+	return err
+}
+
+func (rf *rotatingFile) backupName(i int) string {
+	return fmt.Sprintf("%s.%d", rf.path, i)
+}
+
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.fh == nil {
+		return nil
+	}
+	err := rf.fh.Close()
+	rf.fh = nil
+	return err
+}
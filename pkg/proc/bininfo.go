@@ -59,6 +59,10 @@ type BinaryInfo struct {
 
 	DebugInfoDirectories []string
 
+	// debugInfoFile, if set, is the file that debug info for the executable
+	// is loaded from, instead of the executable itself.
+	debugInfoFile string
+
 	// Functions is a list of all DW_TAG_subprogram entries in debug_info, sorted by entry point
 	Functions []Function
 	// Sources is a list of all source files found in debug_line.
@@ -153,6 +157,11 @@ var (
 		macho.CpuAmd64: true,
 		macho.CpuArm64: true,
 	}
+
+	darwinArchName = map[macho.Cpu]string{
+		macho.CpuAmd64: "amd64",
+		macho.CpuArm64: "arm64",
+	}
 )
 
 // ErrFunctionNotFound is returned when failing to find the
@@ -407,6 +416,14 @@ func FirstPCAfterPrologue(p Process, fn *Function, sameline bool) (uint64, error
 		// breakpoint with file:line and with the function name always result on
 		// the same instruction being selected.
 		if pc2, _, _, ok := fn.cu.lineInfo.FirstStmt(fn.Entry, fn.End); ok {
+			if pc2 == fn.Entry && strings.Contains(fn.Name, "[") {
+				// The entry point of instantiations of generic functions is marked
+				// as stmt. Skip to the last stmt instruction on the same line which
+				// is after the stack bounds check.
+				if pc3, _, _, ok := fn.cu.lineInfo.LastStmtOnFirstLine(fn.Entry, fn.End); ok {
+					return pc3, nil
+				}
+			}
 			return pc2, nil
 		}
 	}
@@ -489,6 +506,7 @@ type compileUnit struct {
 	lineInfo  *line.DebugLineInfo // debug_line segment associated with this compile unit
 	optimized optimizedFlags      // this compile unit is optimized
 	producer  string              // producer attribute
+	flags     string              // compiler flags, taken from the producer attribute of Go compile units
 
 	offset dwarf.Offset // offset of the entry describing the compile unit
 
@@ -638,6 +656,43 @@ func (fn *Function) NameWithoutTypeParams() string {
 	return pkg + "." + rcv + "." + base
 }
 
+// instParams returns the instantiation parameters of the function (the
+// shapes of its type arguments), or of its receiver type for methods.
+func (fn *Function) instParams() []string {
+	_, rcv, base, hasInst := fn.parse()
+	if !hasInst {
+		return nil
+	}
+	s := rcv
+	if !strings.Contains(s, "[") {
+		s = base
+	}
+	start := strings.Index(s, "[")
+	if start < 0 {
+		return nil
+	}
+	var r []string
+	depth := 0
+	last := start + 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '[', '{', '(':
+			depth++
+		case ']', '}', ')':
+			depth--
+			if depth == 0 {
+				return append(r, s[last:i])
+			}
+		case ',':
+			if depth == 1 {
+				r = append(r, s[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return nil
+}
+
 func clearInstParams(s string) string {
 	start := strings.Index(s, "[")
 	if start < 0 {
@@ -659,6 +714,12 @@ func packageName(name string) string {
 	return ""
 }
 
+// IsCgo returns true if the function does not belong to a Go compile
+// unit, for example a C function called through cgo.
+func (fn *Function) IsCgo() bool {
+	return fn.cu != nil && !fn.cu.isgo
+}
+
 // Optimized returns true if the function was optimized by the compiler.
 func (fn *Function) Optimized() bool {
 	return fn.cu.optimized != 0
@@ -857,6 +918,10 @@ type packageVar struct {
 	addr   uint64
 }
 
+// globalDebugDirectory is the standard directory where separate debug info
+// files are installed.
+const globalDebugDirectory = "/usr/lib/debug"
+
 type buildIDHeader struct {
 	Namesz uint32
 	Descsz uint32
@@ -892,13 +957,20 @@ func NewBinaryInfo(goos, goarch string) *BinaryInfo {
 }
 
 // LoadBinaryInfo will load and store the information from the binary at 'path'.
-func (bi *BinaryInfo) LoadBinaryInfo(path string, entryPoint uint64, debugInfoDirs []string) error {
+// If debugInfoFile is not empty debug info for the executable is read from
+// it instead, its build ID must match the build ID of the executable.
+func (bi *BinaryInfo) LoadBinaryInfo(path string, entryPoint uint64, debugInfoDirs []string, debugInfoFile string) error {
 	fi, err := os.Stat(path)
 	if err == nil {
 		bi.lastModified = fi.ModTime()
 	}
 
+	if debugInfoFile != "" && bi.GOOS != "linux" && bi.GOOS != "freebsd" {
+		return fmt.Errorf("loading debug info from a separate file is not supported on %s", bi.GOOS)
+	}
+
 	bi.DebugInfoDirectories = debugInfoDirs
+	bi.debugInfoFile = debugInfoFile
 
 	return bi.AddImage(path, entryPoint)
 }
@@ -1571,7 +1643,17 @@ func (bi *BinaryInfo) getModuleData(mem MemoryReadWriter) ([]ModuleData, error)
 //
 // Alternatively, if the debug file cannot be found be the build-id, Delve
 // will look in directories specified by the debug-info-directories config value.
+//
+// The global debug directory (/usr/lib/debug) is always searched, after the
+// directories in debugInfoDirectories, so that debug info installed by
+// distribution packages is found without configuration. Files whose build
+// ID doesn't match the one of the executable are skipped.
 func (bi *BinaryInfo) openSeparateDebugInfo(image *Image, exe *elf.File, debugInfoDirectories []string) (*os.File, *elf.File, error) {
+	implicitGlobalDebugDirectory := !slices.Contains(debugInfoDirectories, globalDebugDirectory)
+	if implicitGlobalDebugDirectory {
+		debugInfoDirectories = append(slices.Clip(debugInfoDirectories), globalDebugDirectory)
+	}
+
 	exePath := image.Path
 	exeName := filepath.Base(image.Path)
 	if strings.HasPrefix(image.Path, "/proc") {
@@ -1586,11 +1668,14 @@ func (bi *BinaryInfo) openSeparateDebugInfo(image *Image, exe *elf.File, debugIn
 
 	check := func(potentialDebugFilePath string) bool {
 		_, err := os.Stat(potentialDebugFilePath)
-		if err == nil {
-			debugFilePath = potentialDebugFilePath
-			return true
+		if err != nil {
+			return false
 		}
-		return false
+		if !bi.separateDebugInfoMatches(image, exe, potentialDebugFilePath) {
+			return false
+		}
+		debugFilePath = potentialDebugFilePath
+		return true
 	}
 
 	find := func(f func(string) bool, suffix string) {
@@ -1657,7 +1742,11 @@ func (bi *BinaryInfo) openSeparateDebugInfo(image *Image, exe *elf.File, debugIn
 		// .debug extension) in every debug info directory.  This behavior also
 		// deviates from the ones specified by GDB, but we keep it for backwards
 		// compatibility.
-		find(func(dir string) bool { return !strings.Contains(dir, "build-id") }, fmt.Sprintf("%s.debug", exeName))
+		// Since this lookup only uses the name of the executable it isn't done
+		// in the global debug directory, unless it was explicitly configured.
+		find(func(dir string) bool {
+			return !strings.Contains(dir, "build-id") && !(implicitGlobalDebugDirectory && dir == globalDebugDirectory)
+		}, fmt.Sprintf("%s.debug", exeName))
 	}
 
 	// We cannot find the debug information locally on the system. Try and see if we're on a system that
@@ -1701,6 +1790,69 @@ func (bi *BinaryInfo) openSeparateDebugInfo(image *Image, exe *elf.File, debugIn
 	return sepFile, elfFile, nil
 }
 
+// separateDebugInfoMatches returns false if the separate debug info file
+// at path has a build ID different from the one of the executable exe, see
+// debugInfoBuildIDs.
+func (bi *BinaryInfo) separateDebugInfoMatches(image *Image, exe *elf.File, path string) bool {
+	elfFile, err := elf.Open(path)
+	if err != nil {
+		return true
+	}
+	defer elfFile.Close()
+	exeID, sepID := bi.debugInfoBuildIDs(image, exe, path, elfFile)
+	if exeID != "" && sepID != "" && exeID != sepID {
+		bi.logger.Errorf("build ID check failed for %s (want %s got %s)", path, exeID, sepID)
+		return false
+	}
+	return true
+}
+
+// debugInfoBuildIDs returns the build IDs of the executable exe and of the
+// separate debug info file sep, at path. The GNU build ID is used if both
+// files have one, otherwise the Go build ID is used.
+func (bi *BinaryInfo) debugInfoBuildIDs(image *Image, exe *elf.File, path string, sep *elf.File) (exeID, sepID string) {
+	sepImage := &Image{Path: path}
+	bi.loadBuildID(sepImage, sep)
+	exeID, sepID = image.BuildID, sepImage.BuildID
+	if exeID == "" || sepID == "" {
+		exeID, sepID = goBuildIDElf(exe), goBuildIDElf(sep)
+	}
+	return exeID, sepID
+}
+
+// openDebugInfoFile opens the file at path, specified by the user, as the
+// separate debug info file of the executable exe. The build ID of the
+// file must match the one of the executable, the GNU build ID is used if
+// both files have one, otherwise the Go build ID is compared.
+func (bi *BinaryInfo) openDebugInfoFile(image *Image, exe *elf.File, path string) (*os.File, *elf.File, error) {
+	sepFile, err := os.OpenFile(path, 0, os.ModePerm)
+	if err != nil {
+		return nil, nil, errors.New("can't open debug info file: " + err.Error())
+	}
+
+	elfFile, err := elf.NewFile(sepFile)
+	if err != nil {
+		sepFile.Close()
+		return nil, nil, fmt.Errorf("can't open debug info file %q: %v", path, err.Error())
+	}
+
+	if elfFile.Machine != exe.Machine {
+		sepFile.Close()
+		return nil, nil, fmt.Errorf("debug info file %q is for %v, the executable is for %v", path, elfFile.Machine, exe.Machine)
+	}
+
+	exeID, sepID := bi.debugInfoBuildIDs(image, exe, path, elfFile)
+	switch {
+	case exeID == "" || sepID == "":
+		bi.logger.Warnf("could not verify that %s matches %s: missing build ID", path, image.Path)
+	case exeID != sepID:
+		sepFile.Close()
+		return nil, nil, fmt.Errorf("build ID mismatch: debug info file %q has build ID %s, executable %q has build ID %s", path, sepID, image.Path, exeID)
+	}
+
+	return sepFile, elfFile, nil
+}
+
 // loadBinaryInfoElf specifically loads information from an ELF binary.
 func loadBinaryInfoElf(bi *BinaryInfo, image *Image, path string, addr uint64, wg *sync.WaitGroup) error {
 	exe, err := os.OpenFile(path, 0, os.ModePerm)
@@ -1740,7 +1892,20 @@ func loadBinaryInfoElf(bi *BinaryInfo, image *Image, path string, addr uint64, w
 	bi.loadBuildID(image, elfFile)
 	var debugInfoBytes []byte
 	var dwerr error
-	image.dwarf, dwerr = elfFile.DWARF()
+	if image.index == 0 && bi.debugInfoFile != "" {
+		var sepFile *os.File
+		sepFile, dwarfFile, err = bi.openDebugInfoFile(image, elfFile, bi.debugInfoFile)
+		if err != nil {
+			return err
+		}
+		image.sepDebugCloser = sepFile
+		image.dwarf, err = dwarfFile.DWARF()
+		if err != nil {
+			return fmt.Errorf("could not read debug info from %q: %v", bi.debugInfoFile, err)
+		}
+	} else {
+		image.dwarf, dwerr = elfFile.DWARF()
+	}
 	if dwerr != nil {
 		var sepFile *os.File
 		var serr error
@@ -1857,6 +2022,104 @@ func (bi *BinaryInfo) loadSymbolName(image *Image, file *elf.File, wg *sync.Wait
 			bi.SymNames[symSec.Value+image.StaticBase] = &s
 		}
 	}
+	bi.loadPLTSymbolNames(image, file)
+}
+
+const (
+	_R_X86_64_GLOB_DAT = 6
+	_R_X86_64_JMP_SLOT = 7
+)
+
+// loadPLTSymbolNames adds a symbol called name@plt to SymNames for every
+// PLT stub of file, so that calls to dynamically linked functions can be
+// resolved by the disassembler.
+// Instead of assuming a specific layout the stubs are found by looking for
+// indirect jumps through GOT slots that have a JUMP_SLOT or GLOB_DAT
+// relocation. This works for lazy binding (.plt), eager binding (.plt.got)
+// and for the second PLT used with IBT (.plt.sec).
+// Only implemented for amd64, on other architectures calls to PLT stubs are
+// displayed as addresses.
+func (bi *BinaryInfo) loadPLTSymbolNames(image *Image, file *elf.File) {
+	if file.Machine != elf.EM_X86_64 || file.Class != elf.ELFCLASS64 {
+		return
+	}
+	dynsyms, err := file.DynamicSymbols()
+	if err != nil {
+		return
+	}
+
+	// map from GOT slot address to the name of the symbol it will contain
+	slots := make(map[uint64]string)
+	for _, name := range []string{".rela.plt", ".rela.dyn"} {
+		sec := file.Section(name)
+		if sec == nil || sec.Type != elf.SHT_RELA {
+			continue
+		}
+		data, err := sec.Data()
+		if err != nil {
+			continue
+		}
+		const relaSize = 24
+		for off := 0; off+relaSize <= len(data); off += relaSize {
+			var rela elf.Rela64
+			if err := binary.Read(bytes.NewReader(data[off:off+relaSize]), file.ByteOrder, &rela); err != nil {
+				break
+			}
+			typ, symidx := elf.R_TYPE64(rela.Info), elf.R_SYM64(rela.Info)
+			if (typ != _R_X86_64_JMP_SLOT && typ != _R_X86_64_GLOB_DAT) || symidx == 0 || int(symidx) > len(dynsyms) {
+				continue
+			}
+			// DynamicSymbols omits the null symbol at index 0
+			if sym := dynsyms[symidx-1]; sym.Name != "" {
+				slots[rela.Off] = sym.Name
+			}
+		}
+	}
+	if len(slots) == 0 {
+		return
+	}
+
+	pltJmpPrefixes := [][]byte{
+		{0xf3, 0x0f, 0x1e, 0xfa, 0xf2}, // endbr64; bnd
+		{0xf3, 0x0f, 0x1e, 0xfa},       // endbr64
+		{0xf2},                         // bnd
+	}
+	for _, name := range []string{".plt", ".plt.sec", ".plt.got"} {
+		sec := file.Section(name)
+		if sec == nil {
+			continue
+		}
+		data, err := sec.Data()
+		if err != nil {
+			continue
+		}
+		// look for 'jmp *disp32(%rip)', optionally preceded by a bnd prefix and
+		// by endbr64.
+		for off := 0; off+6 <= len(data); off++ {
+			if data[off] != 0xff || data[off+1] != 0x25 {
+				continue
+			}
+			slot := sec.Addr + uint64(off) + 6 + uint64(int64(int32(file.ByteOrder.Uint32(data[off+2:]))))
+			symname, ok := slots[slot]
+			if !ok {
+				continue
+			}
+			// PLT entries are aligned to 8 bytes, this avoids mistaking the tail
+			// of the previous entry for a prefix.
+			start := off
+			for _, prefix := range pltJmpPrefixes {
+				if s := off - len(prefix); s >= 0 && (sec.Addr+uint64(s))%8 == 0 && bytes.Equal(data[s:off], prefix) {
+					start = s
+					break
+				}
+			}
+			addr := sec.Addr + uint64(start)
+			if _, exists := bi.SymNames[addr+image.StaticBase]; !exists {
+				bi.SymNames[addr+image.StaticBase] = &elf.Symbol{Name: symname + "@plt", Info: _STT_FUNC, Value: addr}
+			}
+			off += 5
+		}
+	}
 }
 
 func (bi *BinaryInfo) loadBuildID(image *Image, file *elf.File) {
@@ -1891,6 +2154,32 @@ func (bi *BinaryInfo) loadBuildID(image *Image, file *elf.File) {
 	image.BuildID = hex.EncodeToString(descBinary)
 }
 
+// goBuildIDElf returns the Go build ID of file, read from the
+// .note.go.buildid section, or the empty string if it doesn't have one.
+func goBuildIDElf(file *elf.File) string {
+	sec := file.Section(".note.go.buildid")
+	if sec == nil {
+		return ""
+	}
+
+	br := sec.Open()
+	bh := new(buildIDHeader)
+	if err := binary.Read(br, binary.LittleEndian, bh); err != nil {
+		return ""
+	}
+
+	name := make([]byte, bh.Namesz)
+	if err := binary.Read(br, binary.LittleEndian, name); err != nil || string(name) != "Go\x00\x00" {
+		return ""
+	}
+
+	desc := make([]byte, bh.Descsz)
+	if err := binary.Read(br, binary.LittleEndian, desc); err != nil {
+		return ""
+	}
+	return string(desc)
+}
+
 func (bi *BinaryInfo) getDebugLink(exe *elf.File) (debugLink string, crc uint32) {
 	gnuDebugLink := exe.Section(".gnu_debuglink")
 	if gnuDebugLink == nil {
@@ -2129,7 +2418,7 @@ func (bi *BinaryInfo) parseDebugFramePE(image *Image, exe *pe.File, debugInfoByt
 
 // loadBinaryInfoMacho specifically loads information from a Mach-O binary.
 func loadBinaryInfoMacho(bi *BinaryInfo, image *Image, path string, entryPoint uint64, wg *sync.WaitGroup) error {
-	exe, err := macho.Open(path)
+	exe, closer, err := openExecutablePathMacho(path, bi.Arch.Name)
 	if err != nil {
 		return err
 	}
@@ -2148,7 +2437,7 @@ func loadBinaryInfoMacho(bi *BinaryInfo, image *Image, path string, entryPoint u
 		image.StaticBase = entryPoint - machoOff
 	}
 
-	image.closer = exe
+	image.closer = closer
 	if !supportedDarwinArch[exe.Cpu] {
 		return &ErrUnsupportedArch{os: "darwin", cpuArch: exe.Cpu}
 	}
@@ -2191,6 +2480,41 @@ func loadBinaryInfoMacho(bi *BinaryInfo, image *Image, path string, entryPoint u
 	return nil
 }
 
+// machoFatSlice is the slice of a universal (fat) Mach-O binary selected
+// by openExecutablePathMacho, closing it closes the universal binary.
+type machoFatSlice struct {
+	*macho.File
+	fat *macho.FatFile
+}
+
+func (s *machoFatSlice) Close() error {
+	return s.fat.Close()
+}
+
+// openExecutablePathMacho opens the Mach-O binary at path. If it is a
+// universal binary the slice for goarch is returned.
+func openExecutablePathMacho(path, goarch string) (*macho.File, io.Closer, error) {
+	fat, err := macho.OpenFat(path)
+	if err != nil {
+		if err != macho.ErrNotFat {
+			return nil, nil, err
+		}
+		exe, err := macho.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return exe, exe, nil
+	}
+	for i := range fat.Arches {
+		if darwinArchName[fat.Arches[i].Cpu] == goarch {
+			exe := fat.Arches[i].File
+			return exe, &machoFatSlice{exe, fat}, nil
+		}
+	}
+	fat.Close()
+	return nil, nil, fmt.Errorf("universal binary %s does not contain a slice for %s", path, goarch)
+}
+
 func (bi *BinaryInfo) setGStructOffsetMacho() {
 	// In go1.11 it's 0x30, before 0x8a0, see:
 	// https://github.com/golang/go/issues/23617
@@ -2237,8 +2561,13 @@ func (bi *BinaryInfo) macOSDebugFrameBugWorkaround() {
 		// come from
 		return
 	}
-	exe, ok := bi.Images[0].closer.(*macho.File)
-	if !ok {
+	var exe *macho.File
+	switch closer := bi.Images[0].closer.(type) {
+	case *macho.File:
+		exe = closer
+	case *machoFatSlice:
+		exe = closer.File
+	default:
 		return
 	}
 	if bi.Arch.Name == "arm64" {
@@ -2365,11 +2694,10 @@ func loadBinaryInfoGoRuntimeElf(bi *BinaryInfo, image *Image, path string, elfFi
 
 	cu := &compileUnit{}
 	cu.image = image
-	symTable, symTabAddr, err := readPcLnTableElf(elfFile, path)
+	symTable, symTabAddr, err := readPcLnTableElf(elfFile, path, 0)
 	if err != nil {
 		return err
 	}
-	image.symTable = symTable
 
 	// In Go 1.26+, moduledata is in the .go.module section.
 	// In earlier versions, we need to search for it in .noptrdata.
@@ -2391,6 +2719,17 @@ func loadBinaryInfoGoRuntimeElf(bi *BinaryInfo, image *Image, path string, elfFi
 			return err
 		}
 	}
+	if noPtrSection := elfFile.Section(".noptrdata"); noPtrSection != nil {
+		textStart, ok := findTextStart(md, noPtrSection.Addr, bi.Arch.ptrSize)
+		if ok && textStart != elfFile.Section(".text").Addr {
+			symTable, _, err = readPcLnTableElf(elfFile, path, textStart)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	image.symTable = symTable
+
 	roDataAddr := elfFile.Section(".rodata").Addr
 	goFuncVal, err := findGoFuncVal(md, roDataAddr, bi.Arch.ptrSize)
 	if err != nil {
@@ -2707,6 +3046,7 @@ func (bi *BinaryInfo) loadDebugInfoMaps(image *Image, debugInfoBytes, debugLineB
 							bi.regabi = true
 						}
 					}
+					cu.flags = strings.TrimSpace(cu.producer[semicolon+1:])
 					cu.producer = cu.producer[:semicolon]
 				}
 			}
@@ -3168,22 +3508,25 @@ func (bi *BinaryInfo) symLookup(addr uint64) (string, uint64) {
 	if sym, ok := bi.SymNames[addr]; ok {
 		return sym.Name, addr
 	}
-	i := sort.Search(len(bi.packageVars), func(i int) bool {
-		return bi.packageVars[i].addr >= addr
-	})
-	if i >= len(bi.packageVars) {
-		return "", 0
-	}
-	if bi.packageVars[i].addr > addr {
-		// report previous variable + offset if i-th variable starts after addr
-		i--
-	}
-	if i >= 0 && bi.packageVars[i].addr != 0 {
-		return bi.packageVars[i].name, bi.packageVars[i].addr
+	if pkgvar := bi.packageVarAt(addr); pkgvar != nil {
+		// report the variable + offset if the variable starts before addr
+		return pkgvar.name, pkgvar.addr
 	}
 	return "", 0
 }
 
+// packageVarAt returns the last package variable starting at or before
+// addr, nil if there isn't one.
+func (bi *BinaryInfo) packageVarAt(addr uint64) *packageVar {
+	i := sort.Search(len(bi.packageVars), func(i int) bool {
+		return bi.packageVars[i].addr > addr
+	}) - 1
+	if i < 0 || bi.packageVars[i].addr == 0 {
+		return nil
+	}
+	return &bi.packageVars[i]
+}
+
 type PackageBuildInfo struct {
 	ImportPath    string
 	DirectoryPath string
@@ -3233,6 +3576,117 @@ func (bi *BinaryInfo) ListPackagesBuildInfo(includeFiles bool) []*PackageBuildIn
 	return r
 }
 
+// SourceFileInfo describes a source file and the compile unit it belongs to.
+type SourceFileInfo struct {
+	Path        string
+	Package     string // name of the package, empty for non-Go compile units
+	CompileUnit string // name of the compile unit, the import path for Go compile units
+}
+
+// ListSourceFiles returns the list of source files of all images along with
+// the compile unit each file belongs to.
+// A file can appear in the line table of more than one compile unit, for
+// example because functions defined in it were inlined elsewhere, it is
+// assigned to the compile unit containing the code of the functions defined
+// in it. Files that do not contain the entry point of any function are
+// assigned to the first compile unit referencing them and files that are
+// not referenced by any compile unit are returned with an empty CompileUnit.
+func (bi *BinaryInfo) ListSourceFiles() []SourceFileInfo {
+	entries := make(map[*compileUnit]map[uint64]string)
+	for i := range bi.Functions {
+		fn := &bi.Functions[i]
+		if fn.Entry == 0 || fn.cu == nil || fn.cu.lineInfo == nil {
+			continue
+		}
+		if entries[fn.cu] == nil {
+			entries[fn.cu] = make(map[uint64]string)
+		}
+		entries[fn.cu][fn.Entry] = ""
+	}
+	owner := make(map[string]*compileUnit)
+	for _, image := range bi.Images {
+		for _, cu := range image.compileUnits {
+			if entries[cu] == nil {
+				continue
+			}
+			cu.lineInfo.FilesForPCs(entries[cu])
+			for _, file := range entries[cu] {
+				if file != "" && owner[file] == nil {
+					owner[file] = cu
+				}
+			}
+		}
+	}
+	for _, image := range bi.Images {
+		for _, cu := range image.compileUnits {
+			if cu.lineInfo == nil {
+				continue
+			}
+			for _, file := range cu.lineInfo.FileNames {
+				if owner[file.Path] == nil {
+					owner[file.Path] = cu
+				}
+			}
+		}
+	}
+
+	r := make([]SourceFileInfo, 0, len(bi.Sources))
+	for _, path := range bi.Sources {
+		sfi := SourceFileInfo{Path: path}
+		if cu := owner[path]; cu != nil {
+			sfi.CompileUnit = strings.ReplaceAll(cu.name, "\\", "/")
+			if cu.isgo && cu.entry != nil {
+				sfi.Package, _ = cu.entry.Val(godwarf.AttrGoPackageName).(string)
+			}
+		}
+		r = append(r, sfi)
+	}
+	return r
+}
+
+// ProducerInfo describes a DW_AT_producer attribute and the source files
+// of the compile units that have it.
+type ProducerInfo struct {
+	Producer string
+	Files    map[string]struct{}
+}
+
+// ListProducers returns the list of distinct producer attributes (compiler
+// version and flags) of the compile units of all images, along with the
+// files they cover.
+func (bi *BinaryInfo) ListProducers() []*ProducerInfo {
+	m := make(map[string]*ProducerInfo)
+	for _, image := range bi.Images {
+		for _, cu := range image.compileUnits {
+			if cu.producer == "" {
+				continue
+			}
+			producer := cu.producer
+			if cu.flags != "" {
+				producer += "; " + cu.flags
+			}
+			pi := m[producer]
+			if pi == nil {
+				pi = &ProducerInfo{Producer: producer, Files: make(map[string]struct{})}
+				m[producer] = pi
+			}
+			if cu.lineInfo != nil {
+				for _, file := range cu.lineInfo.FileNames {
+					pi.Files[file.Path] = struct{}{}
+				}
+			}
+		}
+	}
+
+	r := make([]*ProducerInfo, 0, len(m))
+	for _, pi := range m {
+		r = append(r, pi)
+	}
+
+	sort.Slice(r, func(i, j int) bool { return r[i].Producer < r[j].Producer })
+	return r
+}
+
 // cuFilePath takes a compilation unit "cu" and a file index reference
 // "fileidx" and returns the corresponding file name entry from the
 // DWARF line table associated with the unit; "entry" is the offset of
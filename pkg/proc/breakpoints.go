@@ -56,8 +56,11 @@ type Breakpoint struct {
 
 	WatchExpr     string
 	WatchType     WatchType
-	HWBreakIndex  uint8 // hardware breakpoint index
-	watchStackOff int64 // for watchpoints of stack variables, offset of the address from top of the stack
+	HWBreakIndex  uint8     // hardware breakpoint index
+	watchStackOff int64     // for watchpoints of stack variables, offset of the address from top of the stack
+	watchFn       *Function // for watchpoints of stack variables, function of the frame containing the variable
+	watchFrameOff int64     // for watchpoints of stack variables, offset of the address from the CFA of its frame
+	watchGoid     int64     // for watchpoints of stack variables, goroutine owning the stack
 
 	// Breaklets is the list of overlapping breakpoints on this physical breakpoint.
 	// There can be at most one UserBreakpoint in this list but multiple internal breakpoints are allowed.
@@ -88,6 +91,12 @@ type Breaklet struct {
 	// Cond: if not nil the breakpoint will be triggered only if evaluating Cond returns true
 	Cond ast.Expr
 
+	// condOps is Cond compiled, condOpsExpr is the expression condOps was
+	// compiled from. Compiled conditions are cached so that conditional
+	// tracepoints and breakpoints hit often are cheaper to evaluate.
+	condOps     []evalop.Op
+	condOpsExpr ast.Expr
+
 	// DeferReturns: when kind == NextDeferBreakpoint this breakpoint
 	// will also check if the caller is runtime.gopanic or if the return
 	// address is in the DeferReturns array.
@@ -109,9 +118,14 @@ type Breaklet struct {
 	// The callback can have side-effects.
 	callback func(th Thread, p *Target) (bool, error)
 
-	// For WatchOutOfScopeBreakpoints and StackResizeBreakpoints the watchpoint
-	// field contains the watchpoint related to this out of scope sentinel.
+	// For WatchOutOfScopeBreakpoints, StackResizeBreakpoints and
+	// WatchRearmBreakpoints the watchpoint field contains the watchpoint
+	// related to this sentinel.
 	watchpoint *Breakpoint
+
+	// For WatchAllocBreakpoints and WatchAllocReturnBreakpoints fieldWatch
+	// is the field watchpoint that set them.
+	fieldWatch *LogicalBreakpoint
 }
 
 // SetCallback sets the call back field, this was primarily added to prevent exporting callback field
@@ -164,6 +178,21 @@ const (
 	// notification function to detect shared library loading/unloading.
 	SharedLibBreakpoint
 
+	// WatchRearmBreakpoint is a breakpoint used to set a stack watchpoint
+	// again when the function containing the watched variable is called, after
+	// the watchpoint went out of scope (see LogicalBreakpoint.WatchFollow).
+	WatchRearmBreakpoint
+
+	// WatchAllocBreakpoint is a breakpoint set on runtime.mallocgc to detect
+	// the allocation of instances of a type with a field watchpoint (see
+	// SetBreakpoint.FieldWatch).
+	WatchAllocBreakpoint
+
+	// WatchAllocReturnBreakpoint is a breakpoint set on the return address
+	// of runtime.mallocgc, after a WatchAllocBreakpoint was hit, to set a
+	// watchpoint on the field of the newly allocated object.
+	WatchAllocReturnBreakpoint
+
 	steppingMask = NextBreakpoint | NextDeferBreakpoint | StepBreakpoint | StepIntoNewProcBreakpoint | NextInactivatedBreakpoint | StepIntoRangeOverFuncBodyBreakpoint
 )
 
@@ -247,6 +276,12 @@ func (bp *Breakpoint) VerboseDescr() []string {
 			r = append(r, "StepIntoRangeOverFuncBodyBreakpoint Cond=%q", astutil.ExprToString(breaklet.Cond))
 		case SharedLibBreakpoint:
 			r = append(r, "SharedLibBreakpoint")
+		case WatchRearmBreakpoint:
+			r = append(r, fmt.Sprintf("WatchRearmBreakpoint Cond=%q", astutil.ExprToString(breaklet.Cond)))
+		case WatchAllocBreakpoint:
+			r = append(r, "WatchAllocBreakpoint")
+		case WatchAllocReturnBreakpoint:
+			r = append(r, fmt.Sprintf("WatchAllocReturnBreakpoint Cond=%q", astutil.ExprToString(breaklet.Cond)))
 		default:
 			r = append(r, fmt.Sprintf("Unknown %d", breaklet.Kind))
 		}
@@ -284,18 +319,27 @@ type returnBreakpointInfo struct {
 }
 
 // CheckCondition evaluates bp's condition on thread.
-func (bp *Breakpoint) checkCondition(tgt *Target, thread Thread, bpstate *BreakpointState) {
+// If skipUser is set the user breaklets of bp are ignored: their conditions
+// are not evaluated and their hit counts are not incremented.
+func (bp *Breakpoint) checkCondition(tgt *Target, thread Thread, bpstate *BreakpointState, skipUser bool) {
 	*bpstate = BreakpointState{Breakpoint: bp, Active: false, Stepping: false, SteppingInto: false, CondError: nil}
 	for _, breaklet := range bp.Breaklets {
+		if skipUser && breaklet.Kind == UserBreakpoint {
+			continue
+		}
 		bpstate.checkCond(tgt, breaklet, thread)
 	}
 }
 
 func (bpstate *BreakpointState) checkCond(tgt *Target, breaklet *Breaklet, thread Thread) {
+	if breaklet.Kind == UserBreakpoint && fieldWatchInstanceFreed(tgt, bpstate.Breakpoint, thread) {
+		return
+	}
+
 	var condErr error
 	active := true
 	if breaklet.Cond != nil {
-		active, condErr = evalBreakpointCondition(tgt, thread, breaklet.Cond)
+		active, condErr = evalBreakpointCondition(tgt, thread, breaklet.Cond, breaklet)
 	}
 
 	if condErr != nil && bpstate.CondError == nil {
@@ -310,13 +354,25 @@ func (bpstate *BreakpointState) checkCond(tgt *Target, breaklet *Breaklet, threa
 		var goroutineID int64
 		lbp := bpstate.Breakpoint.Logical
 		if lbp != nil {
-			if g, err := GetG(thread); err == nil {
+			g, err := GetG(thread)
+			if len(lbp.GoroutineLabels) > 0 && (err != nil || g == nil || !goroutineHasLabels(g, lbp.GoroutineLabels)) {
+				return
+			}
+			if err == nil {
 				goroutineID = g.ID
+			}
+			if entry := lbp.traceEntry; entry != nil && entry.cond != nil && !entry.returnTracedCall(tgt, thread, goroutineID) {
+				return
+			}
+			if err == nil {
 				lbp.HitCount[goroutineID]++
 			}
 			lbp.TotalHitCount++
 		}
 		active = checkHitCond(lbp, goroutineID)
+		if active && lbp != nil && lbp.tracedCalls != nil && lbp.cond != nil {
+			lbp.addTracedCall(tgt, thread, goroutineID)
+		}
 
 	case StepBreakpoint, NextBreakpoint, NextDeferBreakpoint:
 		nextDeferOk := true
@@ -340,7 +396,7 @@ func (bpstate *BreakpointState) checkCond(tgt *Target, breaklet *Breaklet, threa
 			}
 		}
 
-	case StackResizeBreakpoint, PluginOpenBreakpoint, StepIntoNewProcBreakpoint, StepIntoRangeOverFuncBodyBreakpoint, SharedLibBreakpoint:
+	case StackResizeBreakpoint, PluginOpenBreakpoint, StepIntoNewProcBreakpoint, StepIntoRangeOverFuncBodyBreakpoint, SharedLibBreakpoint, WatchRearmBreakpoint, WatchAllocBreakpoint, WatchAllocReturnBreakpoint:
 		// no further checks
 
 	case NextInactivatedBreakpoint:
@@ -375,6 +431,57 @@ func (bpstate *BreakpointState) checkCond(tgt *Target, breaklet *Breaklet, threa
 	}
 }
 
+// goroutineHasLabels returns true if g has all the labels in labels, with
+// the same values.
+func goroutineHasLabels(g *G, labels map[string]string) bool {
+	glabels := g.Labels()
+	for k, v := range labels {
+		if gv, ok := glabels[k]; !ok || gv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// tracedCall identifies a call that triggered a conditional tracepoint by
+// its goroutine and the CFA of its frame.
+type tracedCall struct {
+	goid int64
+	cfa  int64
+}
+
+func tracedCallForThread(tgt *Target, thread Thread, goid int64) (tracedCall, bool) {
+	frames, err := ThreadStacktrace(tgt, thread, 0)
+	if err != nil || len(frames) == 0 {
+		return tracedCall{}, false
+	}
+	return tracedCall{goid: goid, cfa: frames[0].Regs.CFA}, true
+}
+
+// addTracedCall records that the call executing on thread triggered
+// tracepoint lbp.
+func (lbp *LogicalBreakpoint) addTracedCall(tgt *Target, thread Thread, goid int64) {
+	call, ok := tracedCallForThread(tgt, thread, goid)
+	if !ok {
+		return
+	}
+	lbp.tracedCalls[call] = struct{}{}
+}
+
+// returnTracedCall returns true if the call returning on thread triggered
+// tracepoint lbp and forgets about it.
+func (lbp *LogicalBreakpoint) returnTracedCall(tgt *Target, thread Thread, goid int64) bool {
+	call, ok := tracedCallForThread(tgt, thread, goid)
+	if !ok {
+		return false
+	}
+	if _, traced := lbp.tracedCalls[call]; !traced {
+		return false
+	}
+	delete(lbp.tracedCalls, call)
+	return true
+}
+
 // checkHitCond evaluates bp's hit condition on thread.
 func checkHitCond(lbp *LogicalBreakpoint, goroutineID int64) bool {
 	if lbp == nil || lbp.hitCond == nil {
@@ -488,7 +595,9 @@ func (bp *Breakpoint) UserBreaklet() *Breaklet {
 	return nil
 }
 
-func evalBreakpointCondition(tgt *Target, thread Thread, cond ast.Expr) (bool, error) {
+// evalBreakpointCondition evaluates cond on thread. If breaklet is not nil
+// the compiled condition is cached in it.
+func evalBreakpointCondition(tgt *Target, thread Thread, cond ast.Expr, breaklet *Breaklet) (bool, error) {
 	if cond == nil {
 		return true, nil
 	}
@@ -499,11 +608,19 @@ func evalBreakpointCondition(tgt *Target, thread Thread, cond ast.Expr) (bool, e
 			return true, err
 		}
 	}
-	flags := scope.evalopFlags()
-	flags |= evalop.BreakpointCondition
-	ops, err := evalop.CompileAST(scopeToEvalLookup{scope}, cond, flags)
-	if err != nil {
-		return true, err
+	var ops []evalop.Op
+	if breaklet != nil && breaklet.condOpsExpr == cond {
+		ops = breaklet.condOps
+	} else {
+		flags := scope.evalopFlags()
+		flags |= evalop.BreakpointCondition
+		ops, err = evalop.CompileAST(scopeToEvalLookup{scope}, cond, flags)
+		if err != nil {
+			return true, err
+		}
+		if breaklet != nil {
+			breaklet.condOps, breaklet.condOpsExpr = ops, cond
+		}
 	}
 	stack := &evalStack{}
 	stack.eval(scope, ops)
@@ -550,6 +667,16 @@ type BreakpointMap struct {
 	// WatchOutOfScope is the list of watchpoints that went out of scope during
 	// the last resume operation
 	WatchOutOfScope []*Breakpoint
+
+	// watchFollow is the list of watchpoints with WatchFollow set that went
+	// out of scope and need a WatchRearmBreakpoint.
+	watchFollow []*Breakpoint
+	// watchRearm is the list of watchpoints that should be set again because
+	// their WatchRearmBreakpoint was hit.
+	watchRearm []watchRearmRequest
+	// watchAlloc is the list of WatchAllocBreakpoints and
+	// WatchAllocReturnBreakpoints hit.
+	watchAlloc []watchAllocRequest
 }
 
 // NewBreakpointMap creates a new BreakpointMap.
@@ -781,6 +908,9 @@ func (t *Target) SetWatchpoint(logicalID int, scope *EvalScope, expr string, wty
 
 	if stackWatch {
 		bp.watchStackOff = int64(bp.Addr) - int64(scope.g.stack.hi)
+		bp.watchFn = scope.Fn
+		bp.watchFrameOff = int64(bp.Addr) - scope.Regs.CFA
+		bp.watchGoid = scope.g.ID
 		err := t.setStackWatchBreakpoints(scope, bp)
 		if err != nil {
 			return bp, err
@@ -1006,7 +1136,7 @@ func (t *Target) finishClearBreakpoint(bp *Breakpoint) (bool, error) {
 	}
 
 	delete(t.Breakpoints().M, bp.Addr)
-	if bp.WatchExpr != "" && bp.Logical != nil {
+	if bp.WatchExpr != "" && bp.Logical != nil && bp.Logical.Set.FieldWatch == nil {
 		delete(t.Breakpoints().Logical, bp.Logical.LogicalID)
 	}
 	return true, nil
@@ -1172,6 +1302,7 @@ type LogicalBreakpoint struct {
 
 	Tracepoint  bool // Tracepoint flag
 	TraceReturn bool
+	TraceEval   bool     // Print Variables on the same line as the trace
 	Goroutine   bool     // Retrieve goroutine information
 	Stacktrace  int      // Number of stack frames to retrieve
 	Variables   []string // Variables to evaluate
@@ -1180,10 +1311,20 @@ type LogicalBreakpoint struct {
 
 	CustomCommands []string // Custom starlark commands to execute when the breakpoint is hit
 
+	// WatchFollow, for watchpoints on stack variables, causes the watchpoint
+	// to be set again the next time the function containing the variable is
+	// called, instead of being cleared, when it goes out of scope.
+	WatchFollow bool
+
 	HitCount      map[int64]uint64 // Number of times a breakpoint has been reached in a certain goroutine
 	TotalHitCount uint64           // Number of times a breakpoint has been reached
 	HitCondPerG   bool             // Use per goroutine hitcount as HitCond operand, instead of total hitcount
 
+	// GoroutineLabels: if not empty the breakpoint will be triggered only by
+	// goroutines that have all the specified pprof labels with the specified
+	// values.
+	GoroutineLabels map[string]string
+
 	// hitCond: if not nil the breakpoint will be triggered only if the evaluated HitCond returns
 	// true with the TotalHitCount.
 	hitCond *struct {
@@ -1199,6 +1340,14 @@ type LogicalBreakpoint struct {
 	// condUsesHitCounts is true when 'cond' uses breakpoint hitcounts
 	condUsesHitCounts bool
 
+	// traceEntry, for return tracepoints, is the tracepoint set on the entry
+	// of the function, see FollowTraceEntry.
+	traceEntry *LogicalBreakpoint
+	// tracedCalls are the calls that triggered this tracepoint and have not
+	// returned yet, it is only maintained for tracepoints followed by return
+	// tracepoints.
+	tracedCalls map[tracedCall]struct{}
+
 	UserData any // Any additional information about the breakpoint
 	// Name of root function from where tracing needs to be done
 	RootFuncName string
@@ -1214,6 +1363,7 @@ type SetBreakpoint struct {
 	Expr         func(*Target) []uint64
 	ExprString   string
 	PidAddrs     []PidAddr
+	FieldWatch   *FieldWatch
 }
 
 type PidAddr struct {
@@ -1221,6 +1371,16 @@ type PidAddr struct {
 	Addr uint64
 }
 
+// FollowTraceEntry makes lbp, a return tracepoint, follow entry, the
+// tracepoint set on the entry of the same function: if entry has a
+// condition lbp is only triggered by the calls that triggered entry.
+func (lbp *LogicalBreakpoint) FollowTraceEntry(entry *LogicalBreakpoint) {
+	lbp.traceEntry = entry
+	if entry.tracedCalls == nil {
+		entry.tracedCalls = make(map[tracedCall]struct{})
+	}
+}
+
 // Enabled returns true if the breakpoint is enabled.
 func (lbp *LogicalBreakpoint) Enabled() bool {
 	return lbp.enabled
@@ -157,6 +157,7 @@ func (r *offsetReaderAt) ReadMemory(buf []byte, addr uint64) (n int, err error)
 // process represents a core file.
 type process struct {
 	mem     proc.MemoryReader
+	memmap  []proc.MemoryMapEntry // memory mappings recorded in the core file, if available
 	Threads map[int]*thread
 	pid     int
 
@@ -460,8 +461,22 @@ func (p *process) FindThread(threadID int) (proc.Thread, bool) {
 	return t, ok
 }
 
+// MemoryMap returns the memory mappings recorded in the core file, if the
+// core file format does not record them the memory regions contained in
+// the core file are returned instead, all of them reported as readable.
 func (p *process) MemoryMap() ([]proc.MemoryMapEntry, error) {
-	return nil, proc.ErrMemoryMapNotSupported
+	if p.memmap != nil {
+		return p.memmap, nil
+	}
+	mem, ok := p.mem.(*SplicedMemory)
+	if !ok {
+		return nil, proc.ErrMemoryMapNotSupported
+	}
+	r := make([]proc.MemoryMapEntry, 0, len(mem.readers))
+	for _, entry := range mem.readers {
+		r = append(r, proc.MemoryMapEntry{Addr: entry.offset, Size: entry.length, Read: true})
+	}
+	return r, nil
 }
 
 func (p *process) DumpProcessNotes(notes []elfwriter.Note, threadDone func()) (threadsDone bool, out []elfwriter.Note, err error) {
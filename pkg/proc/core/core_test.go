@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"encoding/binary"
 	"flag"
 	"fmt"
 	"go/constant"
@@ -12,13 +13,18 @@ import (
 	"reflect"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
+	"unicode/utf16"
+	"unsafe"
 
 	"github.com/go-delve/delve/pkg/goversion"
 	"github.com/go-delve/delve/pkg/proc"
+	"github.com/go-delve/delve/pkg/proc/core/minidump"
 	"github.com/go-delve/delve/pkg/proc/test"
+	"github.com/go-delve/delve/pkg/proc/winutil"
 )
 
 var buildMode string
@@ -501,6 +507,128 @@ mainSearch:
 	t.Logf("s = %#v\n", v2)
 }
 
+func TestCoreSearchMemory(t *testing.T) {
+	t.Parallel()
+	mustSupportCore(t)
+
+	grp, _ := withCoreFile(t, "coresearchmem", "")
+	p := grp.Selected
+
+	gs, _, err := proc.GoroutinesInfo(p, 0, 0)
+	assertNoError(err, t, "GoroutinesInfo")
+
+	var mainFrame *proc.Stackframe
+mainSearch:
+	for _, g := range gs {
+		stack, err := proc.GoroutineStacktrace(p, g, 10, 0)
+		assertNoError(err, t, "Stacktrace()")
+		for _, frame := range stack {
+			if frame.Current.Fn != nil && frame.Current.Fn.Name == "main.main" {
+				mainFrame = &frame
+				break mainSearch
+			}
+		}
+	}
+	if mainFrame == nil {
+		t.Fatal("could not find main.main frame")
+	}
+
+	scope := proc.FrameToScope(p, p.Memory(), nil, p.CurrentThread().ThreadID(), *mainFrame)
+	global, err := scope.EvalExpression("main.global", proc.LoadConfig{})
+	assertNoError(err, t, "EvalExpression(main.global)")
+	obj, err := scope.EvalExpression("obj", proc.LoadConfig{})
+	assertNoError(err, t, "EvalExpression(obj)")
+
+	pattern, align, err := proc.SearchPattern(obj)
+	assertNoError(err, t, "SearchPattern(obj)")
+	results, err := p.SearchMemory(pattern, align, 0, true)
+	assertNoError(err, t, "SearchMemory(obj)")
+	t.Logf("%#v", results)
+	// obj is referenced by main.global and twice by the backing array of refs.
+	if len(results) < 3 {
+		t.Errorf("expected at least 3 references to obj, got %d", len(results))
+	}
+	found := false
+	for _, res := range results {
+		if res.Addr == global.Addr {
+			found = true
+			if res.Symbol != "main.global" || res.SymbolAddr != global.Addr || res.Type == nil || res.Type.String() != "*main.T" {
+				t.Errorf("wrong resolution for main.global: %#v", res)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("reference from main.global not found")
+	}
+
+	n, err := scope.EvalExpression("obj.n", proc.LoadConfig{})
+	assertNoError(err, t, "EvalExpression(obj.n)")
+	pattern, align, err = proc.SearchPattern(n)
+	assertNoError(err, t, "SearchPattern(obj.n)")
+	results, err = p.SearchMemory(pattern, align, 0, false)
+	assertNoError(err, t, "SearchMemory(obj.n)")
+	found = false
+	for _, res := range results {
+		if res.Addr == obj.Children[0].Addr {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("obj.n not found at %#x: %#v", obj.Children[0].Addr, results)
+	}
+}
+
+func TestCoreMemoryMap(t *testing.T) {
+	t.Parallel()
+	mustSupportCore(t)
+
+	grp, _ := withCoreFile(t, "coresearchmem", "")
+	p := grp.Selected
+
+	memmap, err := p.MemoryMap()
+	assertNoError(err, t, "MemoryMap")
+	find := func(addr uint64) *proc.MemoryMapEntry {
+		for i := range memmap {
+			if addr >= memmap[i].Addr && addr < memmap[i].Addr+memmap[i].Size {
+				return &memmap[i]
+			}
+		}
+		t.Fatalf("no mapping contains %#x: %#v", addr, memmap)
+		return nil
+	}
+
+	fn := p.BinInfo().LookupFunc()["main.main"][0]
+	text := find(fn.Entry)
+	t.Logf("text mapping: %#v", text)
+	if !text.Read || text.Write || !text.Exec {
+		t.Errorf("wrong permissions for text mapping: %#v", text)
+	}
+	if filepath.Base(text.Filename) != filepath.Base(p.BinInfo().Images[0].Path) {
+		t.Errorf("wrong file name for text mapping %q", text.Filename)
+	}
+
+	scope, err := proc.ThreadScope(p, p.CurrentThread())
+	assertNoError(err, t, "ThreadScope")
+	global, err := scope.EvalExpression("main.global", proc.LoadConfig{})
+	assertNoError(err, t, "EvalExpression(main.global)")
+	data := find(global.Addr)
+	t.Logf("data mapping: %#v", data)
+	if !data.Read || !data.Write || data.Exec {
+		t.Errorf("wrong permissions for data mapping: %#v", data)
+	}
+
+	// anonymous mappings that are not in the core file are not reported
+	buf := make([]byte, 1)
+	for _, mme := range memmap {
+		if mme.Filename != "" {
+			continue
+		}
+		if _, err := p.Memory().ReadMemory(buf, mme.Addr); err != nil {
+			t.Errorf("could not read anonymous mapping %#v: %v", mme, err)
+		}
+	}
+}
+
 func TestMinidump(t *testing.T) {
 	t.Parallel()
 	if runtime.GOOS != "windows" || runtime.GOARCH != "amd64" {
@@ -555,6 +683,131 @@ func TestMinidump(t *testing.T) {
 	}
 }
 
+func TestMinidumpStreams(t *testing.T) {
+	// Checks that the MemoryList, ModuleList and Exception streams of a
+	// minidump are used, the minidump is built by hand so that this test can
+	// run on every platform.
+	le := binary.LittleEndian
+	const numStreams = 5
+	var body []byte
+	add := func(b []byte) uint32 {
+		rva := uint32(32 + 12*numStreams + len(body))
+		body = append(body, b...)
+		return rva
+	}
+	context := func(rip uint64) []byte {
+		var ctx winutil.AMD64CONTEXT
+		ctx.Rip = rip
+		return append([]byte(nil), unsafe.Slice((*byte)(unsafe.Pointer(&ctx)), unsafe.Sizeof(ctx))...)
+	}
+	str := func(s string) []byte {
+		b := le.AppendUint32(nil, uint32(2*len(s)))
+		for _, ch := range utf16.Encode([]rune(s)) {
+			b = le.AppendUint16(b, ch)
+		}
+		return b
+	}
+	location := func(b []byte, data []byte) []byte {
+		b = le.AppendUint32(b, uint32(len(data)))
+		return le.AppendUint32(b, add(data))
+	}
+
+	sysinfo := le.AppendUint16(nil, uint16(minidump.CpuArchitectureAMD64))
+	sysinfo = append(sysinfo, make([]byte, 54)...)
+
+	threads := le.AppendUint32(nil, 2)
+	for _, th := range []struct {
+		id       uint32
+		rip, rsp uint64
+	}{{1, 0x1000, 0x5000}, {2, 0x2000, 0x6000}} {
+		threads = le.AppendUint32(threads, th.id)
+		threads = append(threads, make([]byte, 12)...) // suspend count, priority class, priority
+		threads = le.AppendUint64(threads, 0)          // TEB
+		threads = le.AppendUint64(threads, th.rsp)
+		threads = location(threads, []byte{byte(th.id), 0, 0, 0, 0, 0, 0, 0})
+		threads = location(threads, context(th.rip))
+	}
+
+	exception := le.AppendUint32(nil, 2) // thread ID
+	exception = le.AppendUint32(exception, 0)
+	exception = le.AppendUint32(exception, 0xc0000005) // access violation
+	exception = le.AppendUint32(exception, 0)
+	exception = le.AppendUint64(exception, 0)
+	exception = le.AppendUint64(exception, 0x2100)
+	exception = append(exception, make([]byte, 8+15*8)...)
+	exception = location(exception, context(0x2100))
+
+	modules := le.AppendUint32(nil, 2)
+	for _, mod := range []struct {
+		base uint64
+		name string
+	}{{0x7ff000000, `C:\Windows\System32\ntdll.dll`}, {0x400000, `C:\dir\program.exe`}} {
+		modules = le.AppendUint64(modules, mod.base)
+		modules = le.AppendUint32(modules, 0x1000) // size
+		modules = append(modules, make([]byte, 8)...)
+		modules = le.AppendUint32(modules, add(str(mod.name)))
+		modules = append(modules, make([]byte, 13*4+8+8+16)...)
+	}
+
+	memory := le.AppendUint32(nil, 1)
+	memory = le.AppendUint64(memory, 0x9000)
+	memory = location(memory, []byte{0xde, 0xad, 0xbe, 0xef})
+
+	var directory []byte
+	for _, stream := range []struct {
+		typ  minidump.StreamType
+		data []byte
+	}{
+		{minidump.SystemInfoStream, sysinfo},
+		{minidump.ThreadListStream, threads},
+		{minidump.ExceptionStream, exception},
+		{minidump.ModuleListStream, modules},
+		{minidump.MemoryListStream, memory},
+	} {
+		directory = le.AppendUint32(directory, uint32(stream.typ))
+		directory = location(directory, stream.data)
+	}
+
+	header := le.AppendUint32(nil, 0x504d444d) // 'MDMP'
+	header = le.AppendUint16(header, 0xa793)
+	header = le.AppendUint16(header, 0)
+	header = le.AppendUint32(header, numStreams)
+	header = le.AppendUint32(header, 32)
+	header = append(header, make([]byte, 16)...)
+
+	mdmpPath := filepath.Join(t.TempDir(), "test.dmp")
+	assertNoError(os.WriteFile(mdmpPath, slices.Concat(header, directory, body), 0o644), t, "WriteFile")
+
+	p, currentThread, err := readAMD64Minidump(mdmpPath, "program.exe")
+	assertNoError(err, t, "readAMD64Minidump")
+
+	if len(p.Threads) != 2 {
+		t.Fatalf("wrong number of threads %d", len(p.Threads))
+	}
+	if currentThread.ThreadID() != 2 {
+		t.Errorf("current thread is %d, expected the thread of the exception", currentThread.ThreadID())
+	}
+	regs, err := currentThread.Registers()
+	assertNoError(err, t, "Registers")
+	if regs.PC() != 0x2100 {
+		t.Errorf("wrong PC %#x, expected the PC of the exception", regs.PC())
+	}
+	if p.entryPoint != 0x400000 {
+		t.Errorf("wrong entry point %#x, expected the base address of program.exe", p.entryPoint)
+	}
+	for _, tc := range []struct {
+		addr uint64
+		tgt  []byte
+	}{{0x9000, []byte{0xde, 0xad, 0xbe, 0xef}}, {0x6000, []byte{2, 0}}} {
+		buf := make([]byte, len(tc.tgt))
+		_, err := p.ReadMemory(buf, tc.addr)
+		assertNoError(err, t, fmt.Sprintf("ReadMemory(%#x)", tc.addr))
+		if !bytes.Equal(buf, tc.tgt) {
+			t.Errorf("wrong memory at %#x: %x expected %x", tc.addr, buf, tc.tgt)
+		}
+	}
+}
+
 func procdump(t *testing.T, exePath string) string {
 	exeDir := filepath.Dir(exePath)
 	cmd := exec.Command("procdump64", "-accepteula", "-ma", "-n", "1", "-s", "3", "-x", exeDir, exePath, "quit")
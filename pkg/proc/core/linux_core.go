@@ -166,6 +166,7 @@ func readLinuxOrPlatformIndependentCore(corePath, exePath string) (*process, pro
 
 	p := &process{
 		mem:         memory,
+		memmap:      buildMemoryMap(coreFile, notes),
 		Threads:     map[int]*thread{},
 		entryPoint:  entryPoint,
 		bi:          bi,
@@ -347,7 +348,7 @@ func readNote(r io.ReadSeeker, machineType elf.Machine) (*note, error) {
 		// No good documentation reference, but the structure is
 		// simply a header, including entry count, followed by that
 		// many entries, and then the file name of each entry,
-		// null-delimited. Not reading the names here.
+		// null-delimited.
 		data := &linuxNTFile{}
 		if err := binary.Read(descReader, binary.LittleEndian, &data.linuxNTFileHdr); err != nil {
 			return nil, fmt.Errorf("reading NT_FILE header: %v", err)
@@ -359,6 +360,8 @@ func readNote(r io.ReadSeeker, machineType elf.Machine) (*note, error) {
 			}
 			data.entries = append(data.entries, entry)
 		}
+		names, _ := io.ReadAll(descReader)
+		data.names = strings.SplitN(string(names), "\x00", len(data.entries)+1)
 		note.Desc = data
 	case _NT_X86_XSTATE:
 		if machineType == _EM_X86_64 {
@@ -451,6 +454,49 @@ func buildMemory(core, exeELF *elf.File, exe io.ReaderAt, notes []*note) proc.Me
 	return memory
 }
 
+// buildMemoryMap returns the memory mappings of the process described by
+// the PT_LOAD program headers of the core file, file names are taken from
+// the NT_FILE note.
+// Segments with no data in the core file (Filesz == 0) are only reported
+// if they are backed by a file, anonymous ones (usually PROT_NONE
+// reservations or memory excluded by coredump_filter) can not be read.
+func buildMemoryMap(core *elf.File, notes []*note) []proc.MemoryMapEntry {
+	var fileNote *linuxNTFile
+	for _, note := range notes {
+		if note.Type == _NT_FILE {
+			fileNote = note.Desc.(*linuxNTFile)
+		}
+	}
+
+	r := []proc.MemoryMapEntry{}
+	for _, prog := range core.Progs {
+		if prog.Type != elf.PT_LOAD || prog.Memsz == 0 {
+			continue
+		}
+		mme := proc.MemoryMapEntry{
+			Addr:  prog.Vaddr,
+			Size:  prog.Memsz,
+			Read:  prog.Flags&elf.PF_R != 0,
+			Write: prog.Flags&elf.PF_W != 0,
+			Exec:  prog.Flags&elf.PF_X != 0,
+		}
+		if fileNote != nil {
+			for i, entry := range fileNote.entries {
+				if prog.Vaddr >= entry.Start && prog.Vaddr < entry.End && i < len(fileNote.names) {
+					mme.Filename = fileNote.names[i]
+					mme.Offset = entry.FileOfs*fileNote.PageSize + (prog.Vaddr - entry.Start)
+					break
+				}
+			}
+		}
+		if prog.Filesz == 0 && mme.Filename == "" {
+			continue
+		}
+		r = append(r, mme)
+	}
+	return r
+}
+
 func findEntryPoint(notes []*note, ptrSize int) uint64 {
 	for _, note := range notes {
 		if note.Type == _NT_AUXV {
@@ -541,6 +587,7 @@ type linuxSiginfo struct {
 type linuxNTFile struct {
 	linuxNTFileHdr
 	entries []*linuxNTFileEntry
+	names   []string // file name of each entry, may be shorter than entries if the note is truncated
 }
 
 // LinuxNTFileHdr is a header struct for NTFile.
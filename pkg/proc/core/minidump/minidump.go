@@ -40,8 +40,9 @@ func (buf *minidumpBuf) u16() uint16 {
 	if buf.err != nil {
 		return 0
 	}
-	if buf.off+stride >= len(buf.buf) {
+	if buf.off+stride > len(buf.buf) {
 		buf.err = fmt.Errorf("minidump %s truncated at offset %#x while %s", buf.kind, buf.off, buf.ctx)
+		return 0
 	}
 	r := binary.LittleEndian.Uint16(buf.buf[buf.off : buf.off+stride])
 	buf.off += stride
@@ -53,8 +54,9 @@ func (buf *minidumpBuf) u32() uint32 {
 	if buf.err != nil {
 		return 0
 	}
-	if buf.off+stride >= len(buf.buf) {
+	if buf.off+stride > len(buf.buf) {
 		buf.err = fmt.Errorf("minidump %s truncated at offset %#x while %s", buf.kind, buf.off, buf.ctx)
+		return 0
 	}
 	r := binary.LittleEndian.Uint32(buf.buf[buf.off : buf.off+stride])
 	buf.off += stride
@@ -66,8 +68,9 @@ func (buf *minidumpBuf) u64() uint64 {
 	if buf.err != nil {
 		return 0
 	}
-	if buf.off+stride >= len(buf.buf) {
+	if buf.off+stride > len(buf.buf) {
 		buf.err = fmt.Errorf("minidump %s truncated at offset %#x while %s", buf.kind, buf.off, buf.ctx)
+		return 0
 	}
 	r := binary.LittleEndian.Uint64(buf.buf[buf.off : buf.off+stride])
 	buf.off += stride
@@ -110,6 +113,10 @@ type Minidump struct {
 	Threads []Thread
 	Modules []Module
 
+	// Exception is the exception that caused the minidump to be written, if
+	// the minidump contains an Exception stream.
+	Exception *Exception
+
 	Pid uint32
 
 	MemoryRanges []MemoryRange
@@ -138,6 +145,20 @@ type Thread struct {
 	Context       winutil.AMD64CONTEXT
 }
 
+// Exception represents the contents of the Exception stream.
+// See: https://docs.microsoft.com/en-us/windows/win32/api/minidumpapiset/ns-minidumpapiset-minidump_exception_stream
+type Exception struct {
+	ThreadID uint32
+	Code     uint32
+	Flags    uint32
+	Address  uint64
+
+	// Context is the context of the thread when the exception happened,
+	// the context stored in the ThreadList stream for the same thread is
+	// the one of the exception handler.
+	Context winutil.AMD64CONTEXT
+}
+
 // Module represents an entry in the ModuleList stream.
 // See: https://docs.microsoft.com/en-us/windows/win32/api/minidumpapiset/ns-minidumpapiset-minidump_module
 type Module struct {
@@ -175,7 +196,8 @@ type VSFixedFileInfo struct {
 
 // MemoryRange represents a region of memory saved to the core file, it's constructed after either:
 // 1. parsing an entry in the Memory64List stream.
-// 2. parsing the stack field of an entry in the ThreadList stream.
+// 2. parsing an entry in the MemoryList stream.
+// 3. parsing the stack field of an entry in the ThreadList stream.
 type MemoryRange struct {
 	Addr uint64
 	Data []byte
@@ -388,9 +410,12 @@ func Open(path string, logfn func(fmt string, args ...any)) (*Minidump, error) {
 				}
 			}
 		case ExceptionStream:
-			//TODO(aarzilli): this stream contains the exception that made the
-			//process stop and caused the minidump to be taken. If we ever start
-			//caring about this we should parse this.
+			readException(&mdmp, streamBuf(stream, buf, "exception"))
+			if logfn != nil && mdmp.Exception != nil {
+				logfn("\tThreadID:%#x Code:%#x Address:%#x", mdmp.Exception.ThreadID, mdmp.Exception.Code, mdmp.Exception.Address)
+			}
+		case MemoryListStream:
+			readMemoryList(&mdmp, streamBuf(stream, buf, "memory list"), logfn)
 		case Memory64ListStream:
 			readMemory64List(&mdmp, streamBuf(stream, buf, "memory64 list"), logfn)
 		case MemoryInfoListStream:
@@ -545,10 +570,14 @@ func readThreadList(mdmp *Minidump, buf *minidumpBuf) {
 
 		readMemoryDescriptor(mdmp, buf)                    // thread stack
 		_, rawThreadContext := readLocationDescriptor(buf) // thread context
-		thread.Context = *((*winutil.AMD64CONTEXT)(unsafe.Pointer(&rawThreadContext[0])))
 		if buf.err != nil {
 			return
 		}
+		if len(rawThreadContext) < int(unsafe.Sizeof(thread.Context)) {
+			buf.err = fmt.Errorf("thread context of size %#x is too small, while %s", len(rawThreadContext), buf.ctx)
+			return
+		}
+		thread.Context = *((*winutil.AMD64CONTEXT)(unsafe.Pointer(&rawThreadContext[0])))
 	}
 }
 
@@ -580,6 +609,8 @@ func readModuleList(mdmp *Minidump, buf *minidumpBuf) {
 
 		_, module.CVRecord = readLocationDescriptor(buf)
 		_, module.MiscRecord = readLocationDescriptor(buf)
+		buf.u64() // reserved0
+		buf.u64() // reserved1
 
 		if buf.err != nil {
 			return
@@ -625,6 +656,56 @@ func readMemory64List(mdmp *Minidump, buf *minidumpBuf, logfn func(fmt string, a
 	}
 }
 
+// readMemoryList reads a _MINIDUMP_MEMORY_LIST structure, which is used
+// instead of _MINIDUMP_MEMORY64_LIST by minidumps that don't contain the
+// full memory of the process.
+// See: https://docs.microsoft.com/en-us/windows/win32/api/minidumpapiset/ns-minidumpapiset-minidump_memory_list
+func readMemoryList(mdmp *Minidump, buf *minidumpBuf, logfn func(fmt string, args ...any)) {
+	rangesNum := buf.u32()
+	if buf.err != nil {
+		return
+	}
+
+	for i := range rangesNum {
+		buf.ctx = fmt.Sprintf("reading memory list entry %d", i)
+		readMemoryDescriptor(mdmp, buf)
+		if buf.err != nil {
+			return
+		}
+
+		if logfn != nil {
+			m := &mdmp.MemoryRanges[len(mdmp.MemoryRanges)-1]
+			logfn("\tMemory %d addr:%#x size:%#x", i, m.Addr, len(m.Data))
+		}
+	}
+}
+
+// readException reads a _MINIDUMP_EXCEPTION_STREAM structure.
+func readException(mdmp *Minidump, buf *minidumpBuf) {
+	var exc Exception
+	exc.ThreadID = buf.u32()
+	buf.u32() // alignment
+	exc.Code = buf.u32()
+	exc.Flags = buf.u32()
+	buf.u64() // exception record
+	exc.Address = buf.u64()
+	buf.u32() // number of parameters
+	buf.u32() // alignment
+	for range 15 {
+		buf.u64() // exception information
+	}
+	_, rawThreadContext := readLocationDescriptor(buf)
+	if buf.err != nil {
+		return
+	}
+	if len(rawThreadContext) < int(unsafe.Sizeof(exc.Context)) {
+		buf.err = fmt.Errorf("thread context of size %#x is too small, while %s", len(rawThreadContext), buf.ctx)
+		return
+	}
+	exc.Context = *((*winutil.AMD64CONTEXT)(unsafe.Pointer(&rawThreadContext[0])))
+	mdmp.Exception = &exc
+}
+
 func readMemoryInfoList(mdmp *Minidump, buf *minidumpBuf, logfn func(fmt string, args ...any)) {
 	startOff := buf.off
 	sizeOfHeader := int(buf.u32())
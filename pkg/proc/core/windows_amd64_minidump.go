@@ -1,6 +1,8 @@
 package core
 
 import (
+	"strings"
+
 	"github.com/go-delve/delve/pkg/logflags"
 	"github.com/go-delve/delve/pkg/proc"
 	"github.com/go-delve/delve/pkg/proc/core/minidump"
@@ -29,8 +31,8 @@ func readAMD64Minidump(minidumpPath, exePath string) (*process, proc.Thread, err
 	}
 
 	entryPoint := uint64(0)
-	if len(mdmp.Modules) > 0 {
-		entryPoint = mdmp.Modules[0].BaseOfImage
+	if exeModule := findExeModule(mdmp, exePath); exeModule != nil {
+		entryPoint = exeModule.BaseOfImage
 	}
 
 	p := &process{
@@ -44,15 +46,48 @@ func readAMD64Minidump(minidumpPath, exePath string) (*process, proc.Thread, err
 
 	for i := range mdmp.Threads {
 		th := &mdmp.Threads[i]
+		if mdmp.Exception != nil && mdmp.Exception.ThreadID == th.ID {
+			// Use the context of the thread at the time of the exception
+			// instead of the context of the exception handler.
+			th.Context = mdmp.Exception.Context
+		}
 		p.Threads[int(th.ID)] = &thread{&windowsAMD64Thread{th}, p, proc.CommonThread{}}
 	}
 	var currentThread proc.Thread
-	if len(mdmp.Threads) > 0 {
+	if mdmp.Exception != nil {
+		if th, ok := p.Threads[int(mdmp.Exception.ThreadID)]; ok {
+			currentThread = th
+		}
+	}
+	if currentThread == nil && len(mdmp.Threads) > 0 {
 		currentThread = p.Threads[int(mdmp.Threads[0].ID)]
 	}
 	return p, currentThread, nil
 }
 
+// findExeModule returns the module of mdmp corresponding to the executable
+// file exePath. The executable is usually the first module, but this isn't
+// guaranteed, so the module with the same file name is preferred.
+func findExeModule(mdmp *minidump.Minidump, exePath string) *minidump.Module {
+	if len(mdmp.Modules) == 0 {
+		return nil
+	}
+	// module names are Windows paths, exePath could be either
+	baseName := func(path string) string {
+		if i := strings.LastIndexAny(path, "\\/"); i >= 0 {
+			return path[i+1:]
+		}
+		return path
+	}
+	exeName := baseName(exePath)
+	for i := range mdmp.Modules {
+		if strings.EqualFold(baseName(mdmp.Modules[i].Name), exeName) {
+			return &mdmp.Modules[i]
+		}
+	}
+	return &mdmp.Modules[0]
+}
+
 type windowsAMD64Thread struct {
 	th *minidump.Thread
 }
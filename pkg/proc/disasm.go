@@ -18,6 +18,10 @@ type AsmInstruction struct {
 	Kind AsmInstructionKind
 
 	Inst archInst
+
+	// DestSymbol is the name of the ELF symbol at DestLoc, set when DestLoc
+	// isn't a function with debug symbols, for example a PLT stub.
+	DestSymbol string
 }
 
 type AsmInstructionKind uint8
@@ -161,6 +165,11 @@ func disassemble(memrw MemoryReadWriter, regs Registers, breakpoints *Breakpoint
 		inst.AtPC = (regs != nil) && (curpc == pc)
 
 		bi.Arch.asmDecode(&inst, mem, dregs, memrw, bi)
+		if inst.DestLoc != nil && inst.DestLoc.Fn == nil {
+			if sym := bi.SymNames[inst.DestLoc.PC]; sym != nil {
+				inst.DestSymbol = sym.Name
+			}
+		}
 
 		r = append(r, inst)
 
@@ -50,6 +50,20 @@ type MemoryMapEntry struct {
 	Offset   uint64
 }
 
+// MemoryMap returns the memory mappings of the target process.
+func (t *Target) MemoryMap() ([]MemoryMapEntry, error) {
+	return t.proc.MemoryMap()
+}
+
+// cachedMemoryMap is like MemoryMap but only reads the memory mappings once
+// every time the target stops.
+func (t *Target) cachedMemoryMap() ([]MemoryMapEntry, error) {
+	if t.memmap == nil && t.memmapErr == nil {
+		t.memmap, t.memmapErr = t.proc.MemoryMap()
+	}
+	return t.memmap, t.memmapErr
+}
+
 func (state *DumpState) setErr(err error) {
 	if err == nil {
 		return
@@ -41,3 +41,53 @@ func (bi *BinaryInfo) HasDebugPinner() bool {
 func DebugPinCount() int {
 	return debugPinCount
 }
+
+// UndoAsyncPreempt exports undoAsyncPreempt (for tests)
+func UndoAsyncPreempt(thread Thread) (bool, error) {
+	return undoAsyncPreempt(thread)
+}
+
+// FirstStmtWithoutPrologue returns the first instruction of fn marked as
+// stmt, if fn has neither a prologue_end marker nor a prologue recognized by
+// disassembling it (for tests)
+func FirstStmtWithoutPrologue(p Process, fn *Function) (uint64, bool) {
+	if fn.cu.lineInfo == nil {
+		return 0, false
+	}
+	if _, _, _, ok := fn.cu.lineInfo.PrologueEndPC(fn.Entry, fn.End); ok {
+		return 0, false
+	}
+	if pc, err := firstPCAfterPrologueDisassembly(p, fn, false); err != nil || pc != fn.Entry {
+		return 0, false
+	}
+	pc, _, _, ok := fn.cu.lineInfo.FirstStmt(fn.Entry, fn.End)
+	return pc, ok
+}
+
+// FramePointerStacktrace returns the stacktrace of g requested with
+// StacktraceFramePointers and the number of frames that were unwound by
+// following the frame pointer chain (for tests)
+func FramePointerStacktrace(tgt *Target, g *G, depth int) ([]Stackframe, int, error) {
+	it, err := goroutineStackIterator(tgt, g, StacktraceFramePointers)
+	if err != nil {
+		return nil, 0, err
+	}
+	frames, err := it.stacktrace(depth, nil)
+	return frames, it.fpFrames, err
+}
+
+// UnboundedStacktrace unwinds the stack of g until its end, like the
+// internal users of stackIterator do, and returns the frames and the error
+// that stopped unwinding (for tests)
+func UnboundedStacktrace(tgt *Target, g *G) ([]Stackframe, error) {
+	it, err := goroutineStackIterator(tgt, g, 0)
+	if err != nil {
+		return nil, err
+	}
+	var frames []Stackframe
+	it.stacktraceFunc(func(frame Stackframe) bool {
+		frames = append(frames, frame)
+		return true
+	})
+	return frames, it.Err()
+}
@@ -9,6 +9,7 @@ import (
 	"go/parser"
 	"go/token"
 	"reflect"
+	"regexp"
 	"runtime/debug"
 	"sort"
 	"strings"
@@ -22,7 +23,10 @@ import (
 	"github.com/go-delve/delve/pkg/proc/evalop"
 )
 
-var errOperationOnSpecialFloat = errors.New("operations on non-finite floats not implemented")
+var (
+	errOperationOnSpecialFloat = errors.New("operations on non-finite floats not implemented")
+	errMapKeyNotFound          = errors.New("key not found")
+)
 
 const (
 	goDictionaryName = ".dict"
@@ -227,6 +231,58 @@ func (s scopeToEvalLookup) FindTypeExpr(expr ast.Expr) (godwarf.Type, error) {
 	return s.BinInfo.findTypeExpr(expr)
 }
 
+func (s scopeToEvalLookup) FindTypeExprInCurrentPackage(expr ast.Expr) (godwarf.Type, error) {
+	typ, err := s.BinInfo.findTypeExpr(expr)
+	if err == nil || s.Fn == nil {
+		return typ, err
+	}
+	// Like in Go source code, type names that are not qualified by a package
+	// name can refer to the types of the package of the current function.
+	pkg := s.Fn.PackageName()
+	if pkg == "" {
+		return typ, err
+	}
+	if typ2, err2 := s.BinInfo.findTypeExpr(s.qualifyTypeExpr(expr, pkg)); err2 == nil {
+		return typ2, nil
+	}
+	return typ, err
+}
+
+// qualifyTypeExpr returns a copy of expr where the unqualified type names
+// that do not exist are qualified with package path pkg.
+func (s scopeToEvalLookup) qualifyTypeExpr(expr ast.Expr, pkg string) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, err := s.BinInfo.findType(e.Name); err == nil {
+			return e
+		}
+		return &ast.SelectorExpr{X: &ast.Ident{Name: pkg}, Sel: e}
+	case *ast.StarExpr:
+		r := *e
+		r.X = s.qualifyTypeExpr(e.X, pkg)
+		return &r
+	case *ast.ParenExpr:
+		r := *e
+		r.X = s.qualifyTypeExpr(e.X, pkg)
+		return &r
+	case *ast.ArrayType:
+		r := *e
+		r.Elt = s.qualifyTypeExpr(e.Elt, pkg)
+		return &r
+	case *ast.ChanType:
+		r := *e
+		r.Value = s.qualifyTypeExpr(e.Value, pkg)
+		return &r
+	case *ast.MapType:
+		r := *e
+		r.Key = s.qualifyTypeExpr(e.Key, pkg)
+		r.Value = s.qualifyTypeExpr(e.Value, pkg)
+		return &r
+	default:
+		return expr
+	}
+}
+
 func (scope scopeToEvalLookup) HasBuiltin(name string) bool {
 	return supportedBuiltins[name] != nil
 }
@@ -488,12 +544,13 @@ func (scope *EvalScope) simpleLocals(flags localsFlags, wantedName string) ([]*V
 			val = newVariable(val.Name, addr, val.DwarfType, scope.BinInfo, scope.Mem)
 		}
 
-		if isCapturedVar && int(val.DeclLine) == scope.Line && scope.Fn != nil && entry.Tag == dwarf.TagVariable && (flags&localsFakeFunctionEntryScope == 0) {
+		if isCapturedVar && scope.Fn != nil && entry.Tag == dwarf.TagVariable && (flags&localsFakeFunctionEntryScope == 0) {
 			// For variables captured by closures if we are "early" in the function
 			// read the value from the closure struct instead of their location.
-			// First check that we are actually between the entry point of the
-			// function and the end of the prologue in case the code has not been
-			// gofmt'd.
+			// Check that we are actually between the entry point of the function
+			// and the end of the prologue, we can't rely on the declaration line
+			// of the variable because variables captured by value keep the
+			// declaration line of the original variable.
 			firstPCAfterPrologue := uint64(0)
 			if scope.Fn.cu.lineInfo != nil {
 				fn := scope.Fn
@@ -733,6 +790,65 @@ func (scope *EvalScope) FunctionArguments(cfg LoadConfig) ([]*Variable, error) {
 	return vars, nil
 }
 
+// TypeArguments returns the concrete types of the type arguments of the
+// instantiation of the generic function executing in scope, in the order
+// in which the type parameters are declared. It returns nil if the
+// function is not generic. Entries are nil for type arguments whose type
+// can not be determined because the function never uses it.
+func (scope *EvalScope) TypeArguments() ([]godwarf.Type, error) {
+	if scope.Fn == nil {
+		return nil, errors.New("unable to find function context")
+	}
+	shapes := scope.Fn.instParams()
+	if len(shapes) == 0 {
+		return nil, nil
+	}
+	image := scope.image()
+	dwarfTree, err := image.getDwarfTree(scope.Fn.offset)
+	if err != nil {
+		return nil, err
+	}
+	if scope.dictAddr == 0 {
+		scope.dictAddr = readLocalPtrVar(dwarfTree, goDictionaryName, scope.target, scope.BinInfo, image, scope.Regs, scope.Mem)
+	}
+
+	// The dictionary does not store the type arguments directly, instead
+	// every type that the function needs is described by a typedef child of
+	// the function's DIE, whose underlying type is the shape of the type and
+	// whose DW_AT_go_dict_index attribute is the index of the dictionary
+	// entry containing the real type. Type parameters are the typedefs whose
+	// underlying type is the shape of one of the instantiation parameters.
+	var ptyps []*godwarf.ParametricType
+	for _, entry := range dwarfTree.Children {
+		if entry.Tag != dwarf.TagTypedef {
+			continue
+		}
+		typ, err := godwarf.ReadType(image.dwarf, image.index, entry.Offset, image.typeCache)
+		if err != nil {
+			continue
+		}
+		if ptyp, ok := typ.(*godwarf.ParametricType); ok && ptyp.Type != nil {
+			ptyps = append(ptyps, ptyp)
+		}
+	}
+
+	r := make([]godwarf.Type, len(shapes))
+	for i, shape := range shapes {
+		for j, ptyp := range ptyps {
+			if ptyp == nil || ptyp.Type.String() != shape {
+				continue
+			}
+			ptyps[j] = nil
+			r[i], err = resolveParametricType(scope.BinInfo, scope.Mem, ptyp, scope.dictAddr)
+			if err != nil {
+				return nil, fmt.Errorf("could not read type argument %d: %v", i, err)
+			}
+			break
+		}
+	}
+	return r, nil
+}
+
 func filterVariables(vars []*Variable, pred func(v *Variable) bool) []*Variable {
 	r := make([]*Variable, 0, len(vars))
 	for i := range vars {
@@ -782,12 +898,51 @@ func (scope *EvalScope) PackageVariables(cfg LoadConfig) ([]*Variable, error) {
 	return vars, nil
 }
 
+// findGlobal returns the package variable, function or constant varName
+// of package pkgName. The pkgName argument can be either a package path or
+// a package name, the empty string refers to the package of the current
+// function.
+// If pkgName is a package name shared by more than one package the package
+// of the current function is preferred, otherwise the lookup fails when
+// varName is defined in more than one of them.
 func (scope *EvalScope) findGlobal(pkgName, varName string) (*Variable, error) {
+	curPkg := ""
+	if scope.Fn != nil {
+		curPkg = scope.Fn.PackageName()
+	}
+	if pkgName == "" {
+		if curPkg == "" {
+			return nil, &errCouldNotFindSymbol{varName}
+		}
+		pkgName = curPkg
+	}
+	var found *Variable
+	var foundPath string
 	for _, pkgPath := range scope.BinInfo.PackageMap[pkgName] {
+		if pkgPath == foundPath {
+			continue
+		}
 		v, err := scope.findGlobalInternal(pkgPath + "." + varName)
-		if err != nil || v != nil {
-			return v, err
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
 		}
+		if pkgPath == curPkg {
+			return v, nil
+		}
+		if found != nil {
+			return nil, fmt.Errorf("ambiguous package name %s: %s is defined in %s and %s, use the package path to select one (for example \"%s\".%s)", pkgName, varName, foundPath, pkgPath, pkgPath, varName)
+		}
+		found, foundPath = v, pkgPath
+	}
+	if found != nil {
+		return found, nil
+	}
+	if strings.Contains(pkgName, "/") {
+		// Symbol names use the escaped form of package paths.
+		pkgName = escapePackagePath(pkgName)
 	}
 	v, err := scope.findGlobalInternal(pkgName + "." + varName)
 	if err != nil || v != nil {
@@ -809,9 +964,28 @@ func isSymbolNotFound(e error) bool {
 	return errors.As(e, &e2)
 }
 
+// findGlobalInternal returns the package variable, function or constant
+// with the fully qualified name name. Symbols whose name is exactly name are
+// preferred to symbols whose name only ends with "/"+name.
 func (scope *EvalScope) findGlobalInternal(name string) (*Variable, error) {
+	for _, exact := range []bool{true, false} {
+		v, err := scope.findGlobalMatch(name, exact)
+		if err != nil || v != nil {
+			return v, err
+		}
+	}
+	return nil, nil
+}
+
+func (scope *EvalScope) findGlobalMatch(name string, exact bool) (*Variable, error) {
+	match := func(symname string) bool {
+		if exact {
+			return symname == name
+		}
+		return strings.HasSuffix(symname, "/"+name)
+	}
 	for _, pkgvar := range scope.BinInfo.packageVars {
-		if pkgvar.name == name || strings.HasSuffix(pkgvar.name, "/"+name) {
+		if match(pkgvar.name) {
 			reader := pkgvar.cu.image.dwarfReader
 			reader.Seek(pkgvar.offset)
 			entry, err := reader.Next()
@@ -822,7 +996,7 @@ func (scope *EvalScope) findGlobalInternal(name string) (*Variable, error) {
 		}
 	}
 	for _, fn := range scope.BinInfo.Functions {
-		if fn.Name == name || strings.HasSuffix(fn.Name, "/"+name) {
+		if match(fn.Name) {
 			//TODO(aarzilli): convert function entry into a function type?
 			r := newVariable(fn.Name, fn.Entry, &godwarf.FuncType{}, scope.BinInfo, scope.Mem)
 			r.Value = constant.MakeString(fn.Name)
@@ -836,7 +1010,7 @@ func (scope *EvalScope) findGlobalInternal(name string) (*Variable, error) {
 	}
 	for dwref, ctyp := range scope.BinInfo.consts {
 		for _, cval := range ctyp.values {
-			if cval.fullName == name || strings.HasSuffix(cval.fullName, "/"+name) {
+			if match(cval.fullName) {
 				t, err := scope.BinInfo.Images[dwref.imageIndex].Type(dwref.offset)
 				if err != nil {
 					return nil, err
@@ -1193,6 +1367,12 @@ func (stack *evalStack) executeOp() {
 	case *evalop.TypeAssert:
 		scope.evalTypeAssert(op, stack)
 
+	case *evalop.TypeName:
+		scope.evalTypeName(op, stack)
+
+	case *evalop.TypeNameCompare:
+		scope.evalTypeNameCompare(op, stack)
+
 	case *evalop.PointerDeref:
 		scope.evalPointerDeref(op, stack)
 
@@ -1238,6 +1418,10 @@ func (stack *evalStack) executeOp() {
 		x := stack.stack[len(stack.stack)-1]
 		stack.push(x)
 
+	case *evalop.Pick:
+		x := stack.stack[len(stack.stack)-op.N-1]
+		stack.push(x)
+
 	case *evalop.BuiltinCall:
 		vars := make([]*Variable, len(op.Args))
 		for i := len(op.Args) - 1; i >= 0; i-- {
@@ -1257,6 +1441,12 @@ func (stack *evalStack) executeOp() {
 		if actualArg.Name == "" {
 			actualArg.Name = astutil.ExprToString(op.ArgExpr)
 		}
+		if op.Reinterpret {
+			actualArg, stack.err = reinterpretPointerArg(actualArg, fncall.formalArgs[op.ArgNum].typ)
+			if stack.err != nil {
+				break
+			}
+		}
 		stack.err = funcCallCopyOneArg(scope, fncall, actualArg, &fncall.formalArgs[op.ArgNum], curthread)
 
 	case *evalop.CallInjectionComplete:
@@ -1289,6 +1479,38 @@ func (stack *evalStack) executeOp() {
 	case *evalop.ConvertAllocToString:
 		scope.convertAllocToString(stack)
 
+	case *evalop.PushAppendAllocArgs:
+		scope.pushAppendAllocArgs(stack, op)
+
+	case *evalop.ConvertAllocToSlice:
+		scope.convertAllocToSlice(stack, op.Type)
+
+	case *evalop.AppendCopy:
+		scope.appendCopy(stack)
+
+	case *evalop.AppendSetElem:
+		v := stack.pop()
+		slicev := stack.pop()
+		elemv, err := slicev.sliceAccess(int(slicev.Len) - op.N)
+		if err != nil {
+			stack.err = err
+			break
+		}
+		stack.err = scope.setValue(elemv, v, astutil.ExprToString(op.Node))
+		stack.push(slicev)
+
+	case *evalop.SetIndex:
+		scope.setIndex(stack, op)
+
+	case *evalop.PushMapAssignTypes:
+		scope.pushMapAssignTypes(stack)
+
+	case *evalop.MapAssignStoreKey:
+		scope.mapAssignStoreKey(stack, op)
+
+	case *evalop.MapAssignStoreElem:
+		scope.mapAssignStoreElem(stack, op)
+
 	case *evalop.SetValue:
 		lhv := stack.pop()
 		rhv := stack.pop()
@@ -1318,18 +1540,7 @@ func (stack *evalStack) executeOp() {
 		stack.push(newVariable(evalop.BreakpointHitCountVarNameQualified, fakeAddressUnresolv, godwarf.FakeSliceType(godwarf.FakeBasicType("uint", 64)), scope.BinInfo, scope.Mem))
 
 	case *evalop.PushRuntimeType:
-		typeAddr, _, _, err := dwarfToRuntimeType(scope.BinInfo, scope.Mem, op.Type)
-		if err != nil {
-			stack.err = err
-			break
-		}
-		rttyp, err := scope.BinInfo.findType(scope.BinInfo.runtimeTypeTypename())
-		if err != nil {
-			stack.err = err
-			break
-		}
-		v := newVariable("", typeAddr, rttyp, scope.BinInfo, scope.Mem)
-		stack.push(v.pointerToVariable())
+		stack.pushErr(scope.runtimeTypeVariable(op.Type))
 
 	case *evalop.PushNewFakeVariable:
 		stack.pushNewFakeVariable(scope, op.Type)
@@ -1437,14 +1648,34 @@ func (stack *evalStack) pushIdent(scope *EvalScope, name string) (found bool) {
 }
 
 func (stack *evalStack) pushNewFakeVariable(scope *EvalScope, typ godwarf.Type) {
+	stack.pushErr(scope.newFakeVariable(typ))
+}
+
+// newFakeVariable returns a new zeroed variable of type typ stored in
+// debugger memory.
+func (scope *EvalScope) newFakeVariable(typ godwarf.Type) (*Variable, error) {
 	cm, err := CreateCompositeMemory(scope.Mem, scope.BinInfo.Arch, *new(op.DwarfRegisters), []op.Piece{{Kind: op.ImmPiece, Bytes: make([]byte, typ.Size()), Size: int(typ.Size())}}, typ.Size())
 	if err != nil {
-		stack.err = err
-		return
+		return nil, err
 	}
 	v := newVariable("", cm.base, typ, scope.BinInfo, cm)
 	v.Flags = VariableFakeAddress
-	stack.push(v)
+	return v, nil
+}
+
+// runtimeTypeVariable returns a pointer to the *runtime._type
+// corresponding to typ.
+func (scope *EvalScope) runtimeTypeVariable(typ godwarf.Type) (*Variable, error) {
+	typeAddr, _, _, err := dwarfToRuntimeType(scope.BinInfo, scope.Mem, typ)
+	if err != nil {
+		return nil, err
+	}
+	rttyp, err := scope.BinInfo.findType(scope.BinInfo.runtimeTypeTypename())
+	if err != nil {
+		return nil, err
+	}
+	v := newVariable("", typeAddr, rttyp, scope.BinInfo, scope.Mem)
+	return v.pointerToVariable(), nil
 }
 
 func (scope *EvalScope) evalAST(t ast.Expr) (*Variable, error) {
@@ -1525,10 +1756,11 @@ func (scope *EvalScope) evalTypeCast(op *evalop.TypeCast, stack *evalStack) {
 	if typeCastCompatibleTypes(argv.RealType, typ) {
 		ptyp, isptr := typ.(*godwarf.PtrType)
 		_, isvoid := argv.DwarfType.(*godwarf.VoidType)
-		if (argv.Kind == reflect.Ptr || isvoid) && argv.loaded && len(argv.Children) > 0 && isptr {
+		if (argv.Kind == reflect.Ptr || argv.Kind == reflect.UnsafePointer || isvoid) && argv.loaded && len(argv.Children) > 0 && isptr {
 			cv := argv.Children[0]
 			argv.Children[0] = *newVariable(cv.Name, cv.Addr, ptyp.Type, cv.bi, cv.mem)
 			argv.Children[0].OnlyAddr = true
+			argv.Children[0].Unreadable = cv.Unreadable
 		}
 		argv.RealType = typ
 		argv.DwarfType = op.DwarfType
@@ -1563,14 +1795,19 @@ func (scope *EvalScope) evalTypeCast(op *evalop.TypeCast, stack *evalStack) {
 		n, _ := constant.Int64Val(argv.Value)
 
 		mem := scope.Mem
+		fake := false
 		if scope.target != nil {
 			if mem2 := scope.target.findFakeMemory(uint64(n)); mem2 != nil {
 				mem = mem2
+				fake = true
 			}
 		}
 
 		v.Children = []Variable{*(newVariable("", uint64(n), ttyp.Type, scope.BinInfo, mem))}
 		v.Children[0].OnlyAddr = true
+		if !fake && !scope.addrMapped(uint64(n)) {
+			v.Children[0].Unreadable = fmt.Errorf("address %#x is not mapped", uint64(n))
+		}
 		stack.push(v)
 		return
 
@@ -1596,7 +1833,7 @@ func (scope *EvalScope) evalTypeCast(op *evalop.TypeCast, stack *evalStack) {
 			v.Value = constant.MakeUint64(uint64(x))
 			stack.push(v)
 			return
-		case reflect.Ptr:
+		case reflect.Ptr, reflect.UnsafePointer:
 			v.Value = constant.MakeUint64(argv.Children[0].Addr)
 			stack.push(v)
 			return
@@ -1795,6 +2032,24 @@ func (scope *EvalScope) evalTypeCast(op *evalop.TypeCast, stack *evalStack) {
 	stack.err = converr
 }
 
+// addrMapped returns false if the memory map of the target is available and
+// addr does not belong to any of its mappings.
+func (scope *EvalScope) addrMapped(addr uint64) bool {
+	if scope.target == nil || addr == 0 {
+		return true
+	}
+	memmap, err := scope.target.cachedMemoryMap()
+	if err != nil {
+		return true
+	}
+	for _, entry := range memmap {
+		if addr >= entry.Addr && addr < entry.Addr+entry.Size {
+			return true
+		}
+	}
+	return false
+}
+
 // typeCastCompatibleTypes returns true if typ1 and typ2 are compatible for
 // a type cast where only the type of the variable is changed.
 func typeCastCompatibleTypes(typ1, typ2 godwarf.Type) bool {
@@ -2140,11 +2395,15 @@ func (scope *EvalScope) evalTypeAssert(op *evalop.TypeAssert, stack *evalStack)
 		stack.err = xv.Children[0].Unreadable
 		return
 	}
+	typ := op.DwarfType
 	if xv.Children[0].Addr == 0 {
-		stack.err = fmt.Errorf("interface conversion: %s is nil, not %s", xv.DwarfType.String(), astutil.ExprToString(op.Node.Type))
+		typname := astutil.ExprToString(op.Node.Type)
+		if typ != nil {
+			typname = typ.Common().Name
+		}
+		stack.err = fmt.Errorf("interface conversion: %s is nil, not %s", xv.DwarfType.String(), typname)
 		return
 	}
-	typ := op.DwarfType
 	if typ != nil && xv.Children[0].DwarfType.Common().Name != typ.Common().Name {
 		stack.err = fmt.Errorf("interface conversion: %s is %s, not %s", xv.DwarfType.Common().Name, xv.Children[0].TypeString(), typ.Common().Name)
 		return
@@ -2157,6 +2416,71 @@ func (scope *EvalScope) evalTypeAssert(op *evalop.TypeAssert, stack *evalStack)
 	stack.push(&xv.Children[0])
 }
 
+// Evaluates expressions <subexpr>.(type), the result is the name of the
+// dynamic type of the interface or "nil".
+func (scope *EvalScope) evalTypeName(op *evalop.TypeName, stack *evalStack) {
+	xv := stack.pop()
+	if xv.Kind != reflect.Interface {
+		stack.err = fmt.Errorf("expression %q not an interface", astutil.ExprToString(op.Node.X))
+		return
+	}
+	xv.loadInterface(0, false, loadFullValue)
+	if xv.Unreadable != nil {
+		stack.err = xv.Unreadable
+		return
+	}
+	if xv.Children[0].Unreadable != nil {
+		stack.err = xv.Children[0].Unreadable
+		return
+	}
+	name := "nil"
+	if xv.Children[0].Addr != 0 {
+		name = xv.Children[0].TypeString()
+	}
+	stack.push(newConstant(constant.MakeString(name), scope.BinInfo, scope.Mem))
+}
+
+// Evaluates comparisons between <subexpr>.(type) and a string, see
+// typeNameMatches.
+func (scope *EvalScope) evalTypeNameCompare(op *evalop.TypeNameCompare, stack *evalStack) {
+	yv := stack.pop()
+	xv := stack.pop()
+	if yv.Unreadable != nil {
+		stack.err = yv.Unreadable
+		return
+	}
+	if yv.Kind != reflect.String {
+		stack.err = fmt.Errorf("can not compare the dynamic type of an interface to %s", yv.TypeString())
+		return
+	}
+	if yv.Value == nil {
+		yv.loadValue(loadFullValue)
+		if yv.Unreadable != nil {
+			stack.err = yv.Unreadable
+			return
+		}
+	}
+	r := typeNameMatches(constant.StringVal(xv.Value), constant.StringVal(yv.Value))
+	if op.Node.Op == token.NEQ {
+		r = !r
+	}
+	stack.push(newConstant(constant.MakeBool(r), scope.BinInfo, scope.Mem))
+}
+
+// pkgPathPrefixRx matches the part of a package path preceding the package
+// name.
+var pkgPathPrefixRx = regexp.MustCompile(`[\w.~-]+/`)
+
+// typeNameMatches returns true if name is the name of the dynamic type
+// dyn, either written with full package paths (as delve prints it, for
+// example "*io/fs.PathError") or only with package names (as it is
+// printed by fmt's %T verb, for example "*fs.PathError").
+func typeNameMatches(dyn, name string) bool {
+	name = strings.ReplaceAll(name, " ", "")
+	dyn = strings.ReplaceAll(dyn, " ", "")
+	return dyn == name || pkgPathPrefixRx.ReplaceAllString(dyn, "") == name
+}
+
 // Evaluates expressions <subexpr>[<subexpr>] (subscript access to arrays, slices and maps)
 func (scope *EvalScope) evalIndex(op *evalop.Index, stack *evalStack) {
 	idxev := stack.pop()
@@ -2242,7 +2566,8 @@ func (scope *EvalScope) evalIndex(op *evalop.Index, stack *evalStack) {
 	}
 }
 
-// Evaluates expressions <subexpr>[<subexpr>:<subexpr>]
+// Evaluates expressions <subexpr>[<subexpr>:<subexpr>] and
+// <subexpr>[<subexpr>:<subexpr>:<subexpr>]
 // HACK: slicing a map expression with [0:0] will return the whole map
 func (scope *EvalScope) evalReslice(op *evalop.Reslice, stack *evalStack) {
 	low, err := stack.pop().asInt()
@@ -2258,6 +2583,18 @@ func (scope *EvalScope) evalReslice(op *evalop.Reslice, stack *evalStack) {
 			return
 		}
 	}
+	maxIdx := int64(-1)
+	if op.HasMax {
+		maxIdx, err = stack.pop().asInt()
+		if err != nil {
+			stack.err = err
+			return
+		}
+		if maxIdx < 0 {
+			stack.err = fmt.Errorf("invalid slice index %d (index must be non-negative)", maxIdx)
+			return
+		}
+	}
 	xev := stack.pop()
 	if xev.Unreadable != nil {
 		stack.err = xev.Unreadable
@@ -2267,13 +2604,18 @@ func (scope *EvalScope) evalReslice(op *evalop.Reslice, stack *evalStack) {
 		high = xev.Len
 	}
 
+	if op.HasMax && xev.Kind == reflect.String {
+		stack.err = errors.New("invalid operation: 3-index slice of string")
+		return
+	}
+
 	switch xev.Kind {
 	case reflect.Slice, reflect.Array, reflect.String:
 		if xev.Base == 0 {
 			stack.err = fmt.Errorf("can not slice %q", astutil.ExprToString(op.Node.X))
 			return
 		}
-		stack.pushErr(xev.reslice(low, high, op.TrustLen))
+		stack.pushErr(xev.reslice(low, high, maxIdx, op.TrustLen))
 		return
 	case reflect.Map:
 		if op.Node.High != nil {
@@ -2290,7 +2632,7 @@ func (scope *EvalScope) evalReslice(op *evalop.Reslice, stack *evalStack) {
 		return
 	case reflect.Ptr:
 		if xev.Flags&VariableCPtr != 0 {
-			stack.pushErr(xev.reslice(low, high, op.TrustLen))
+			stack.pushErr(xev.reslice(low, high, maxIdx, op.TrustLen))
 			return
 		}
 		fallthrough
@@ -2921,7 +3263,7 @@ func (v *Variable) mapAccess(idx *Variable) (*Variable, error) {
 		return nil, v.Unreadable
 	}
 	// go would return zero for the map value type here, we do not have the ability to create zeroes
-	return nil, errors.New("key not found")
+	return nil, errMapKeyNotFound
 }
 
 // LoadResliced returns a new array, slice or map that starts at index start and contains
@@ -2933,7 +3275,7 @@ func (v *Variable) LoadResliced(start int, cfg LoadConfig) (newV *Variable, err
 		if high > v.Len {
 			high = v.Len
 		}
-		newV, err = v.reslice(low, high, false)
+		newV, err = v.reslice(low, high, -1, false)
 		if err != nil {
 			return nil, err
 		}
@@ -2949,33 +3291,52 @@ func (v *Variable) LoadResliced(start int, cfg LoadConfig) (newV *Variable, err
 	return newV, nil
 }
 
-func (v *Variable) reslice(low int64, high int64, trustLen bool) (*Variable, error) {
-	wrong := false
+// reslice returns v[low:high], or v[low:high:maxIdx] if maxIdx is not negative.
+func (v *Variable) reslice(low, high, maxIdx int64, trustLen bool) (*Variable, error) {
+	for _, idx := range []int64{low, high} {
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid slice index %d (index must be non-negative)", idx)
+		}
+	}
 	cptrNeedsFakeSlice := false
 	if v.Flags&VariableCPtr == 0 {
-		if v.Kind == reflect.Slice {
-			wrong = low < 0 || low > v.Cap || high < 0 || high > v.Cap
+		boundName, bound := "capacity", v.Cap
+		switch v.Kind {
+		case reflect.String:
+			boundName, bound = "length", v.Len
+		case reflect.Array:
+			bound = v.Len
+		}
+		if maxIdx >= 0 {
+			switch {
+			case maxIdx > bound:
+				return nil, fmt.Errorf("slice bounds out of range [::%d] with %s %d", maxIdx, boundName, bound)
+			case high > maxIdx:
+				return nil, fmt.Errorf("slice bounds out of range [:%d:%d]", high, maxIdx)
+			case low > high:
+				return nil, fmt.Errorf("slice bounds out of range [%d:%d:]", low, high)
+			}
 		} else {
-			wrong = low < 0 || low > v.Len || high < 0 || high > v.Len
+			switch {
+			case high > bound:
+				return nil, fmt.Errorf("slice bounds out of range [:%d] with %s %d", high, boundName, bound)
+			case low > high:
+				return nil, fmt.Errorf("slice bounds out of range [%d:%d]", low, high)
+			}
 		}
 	} else {
-		wrong = low < 0 || high < 0
-		if high == 0 {
+		if high == 0 && maxIdx < 0 {
 			high = low
 		}
+		if low > high || (maxIdx >= 0 && high > maxIdx) {
+			return nil, errors.New("index out of bounds")
+		}
 		cptrNeedsFakeSlice = v.Kind != reflect.String
 	}
-	if wrong {
-		return nil, errors.New("index out of bounds")
-	}
 
 	base := v.Base + uint64(low*v.stride)
 	len := high - low
 
-	if high-low < 0 {
-		return nil, errors.New("index out of bounds")
-	}
-
 	typ := v.DwarfType
 	if _, isarr := v.DwarfType.(*godwarf.ArrayType); isarr || cptrNeedsFakeSlice {
 		typ = godwarf.FakeSliceType(v.fieldType)
@@ -2987,9 +3348,12 @@ func (v *Variable) reslice(low int64, high int64, trustLen bool) (*Variable, err
 	}
 
 	r := v.newVariable("", 0, typ, mem)
-	if v.Flags&VariableCPtr == 0 {
+	switch {
+	case maxIdx >= 0:
+		r.Cap = maxIdx - low
+	case v.Flags&VariableCPtr == 0:
 		r.Cap = v.Cap - low
-	} else {
+	default:
 		r.Cap = len
 	}
 	r.Len = len
@@ -3168,6 +3532,10 @@ func lookupMethod(v *Variable, isptr bool, pkg, receiver, name string) (*Variabl
 
 	for _, check := range checks {
 		if fns := v.bi.LookupFunc()[fmt.Sprintf(check.fmt, pkg, receiver, name)]; len(fns) == 1 {
+			if fns[0].Entry == 0 {
+				// The method was only ever inlined, there is no body we could call.
+				return nil, fmt.Errorf("function %s is inlined", fns[0].Name)
+			}
 			r, err := functionToVariable(fns[0], v.bi, v.mem)
 			if err != nil {
 				return nil, err
@@ -3211,6 +3579,17 @@ func fakeArrayType(n uint64, fieldType godwarf.Type) godwarf.Type {
 		Count:         int64(n)}
 }
 
+func fakeMapType(keyType, elemType godwarf.Type, ptrSize int) godwarf.Type {
+	return &godwarf.MapType{
+		TypedefType: godwarf.TypedefType{
+			CommonType: godwarf.CommonType{
+				ReflectKind: reflect.Map,
+				ByteSize:    int64(ptrSize),
+				Name:        fmt.Sprintf("map[%s]%s", keyType.String(), elemType.String())}},
+		KeyType:  keyType,
+		ElemType: elemType}
+}
+
 var errMethodEvalUnsupported = errors.New("evaluating methods not supported on this version of Go")
 
 func (fn *Function) fakeType(bi *BinaryInfo, removeReceiver bool) (*godwarf.FuncType, error) {
@@ -40,6 +40,11 @@ type compileCtx struct {
 
 type evalLookup interface {
 	FindTypeExpr(ast.Expr) (godwarf.Type, error)
+	// FindTypeExprInCurrentPackage is like FindTypeExpr but type names that
+	// are not qualified by a package name can also refer to types of the
+	// package of the current function. It should only be used where the
+	// expression can not be anything other than a type.
+	FindTypeExprInCurrentPackage(ast.Expr) (godwarf.Type, error)
 	HasBuiltin(string) bool
 	PtrSize() int
 }
@@ -117,13 +122,16 @@ func CompileSet(lookup evalLookup, lhexpr, rhexpr string, flags Flags) ([]Op, er
 		return nil, err
 	}
 
-	err = ctx.compileAST(lhe, false)
+	if idx, isindex := removeParen(lhe).(*ast.IndexExpr); isindex && flags&CanSet != 0 {
+		err = ctx.compileSetIndex(idx, rhe)
+	} else {
+		err = ctx.compileAST(lhe, false)
+		ctx.pushOp(&SetValue{lhe: lhe, Rhe: rhe})
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	ctx.pushOp(&SetValue{lhe: lhe, Rhe: rhe})
-
 	ctx.compileDebugPinnerSetupTeardown()
 
 	err = ctx.depthCheck(0)
@@ -151,12 +159,151 @@ func (ctx *compileCtx) compileAllocLiteralString() {
 	jmp.Target = len(ctx.ops)
 }
 
+// compileMake compiles a call to the make builtin, by calling
+// runtime.makeslice. Only slices are supported.
+func (ctx *compileCtx) compileMake(node *ast.CallExpr) error {
+	if !ctx.allowCalls {
+		return ErrFuncCallNotAllowed
+	}
+	if len(node.Args) < 2 || len(node.Args) > 3 {
+		return fmt.Errorf("wrong number of arguments to make: %d", len(node.Args))
+	}
+	typ, err := ctx.FindTypeExpr(node.Args[0])
+	if err != nil {
+		return err
+	}
+	styp, ok := godwarf.ResolveTypedef(typ).(*godwarf.SliceType)
+	if !ok {
+		return fmt.Errorf("make of %s not implemented", typ.String())
+	}
+
+	ctx.pushOp(&PushRuntimeType{styp.ElemType})
+	for _, arg := range node.Args[1:] {
+		err := ctx.compileAST(arg, false)
+		if err != nil {
+			return err
+		}
+	}
+	if len(node.Args) == 2 {
+		ctx.pushOp(&Dup{}) // cap = len
+	}
+	// stack after: [ cap, len, elemType ]
+
+	ctx.compileMakeslice(node.Args[0], typ)
+	return nil
+}
+
+// compileAppend compiles a call to the append builtin. The result is always
+// stored in a new backing array allocated by calling runtime.makeslice.
+func (ctx *compileCtx) compileAppend(node *ast.CallExpr) error {
+	if !ctx.allowCalls {
+		return ErrFuncCallNotAllowed
+	}
+	if len(node.Args) < 1 {
+		return errors.New("not enough arguments to append")
+	}
+	if node.Ellipsis.IsValid() {
+		return errors.New("append with ... not implemented")
+	}
+
+	err := ctx.compileAST(node.Args[0], false)
+	if err != nil {
+		return err
+	}
+	elems := node.Args[1:]
+	ctx.pushOp(&PushAppendAllocArgs{NumElems: len(elems), Node: node})
+	// stack after: [ cap, len, elemType, oldSlice ]
+
+	ctx.compileMakeslice(node.Args[0], nil)
+	// stack after: [ newSlice, oldSlice ]
+	ctx.pushOp(&AppendCopy{})
+
+	for i, elem := range elems {
+		err := ctx.compileAST(elem, false)
+		if err != nil {
+			return fmt.Errorf("error evaluating %q as argument %d in function append: %v", astutil.ExprToString(elem), i+2, err)
+		}
+		err = ctx.maybeMaterialize(elem)
+		if err != nil {
+			return err
+		}
+		ctx.pushOp(&AppendSetElem{N: len(elems) - i, Node: elem})
+	}
+	return nil
+}
+
+// compileMakeslice calls runtime.makeslice with the element type, length
+// and capacity that are on top of the stack, and replaces them with a
+// slice that uses the allocation as its backing array.
+func (ctx *compileCtx) compileMakeslice(typeExpr ast.Expr, typ godwarf.Type) {
+	ctx.compileSpecialCall("runtime.makeslice", []ast.Expr{
+		typeExpr,
+		&ast.Ident{Name: "len"},
+		&ast.Ident{Name: "cap"},
+	}, []Op{
+		&Pick{2},
+		&Pick{1},
+		&Pick{0},
+	}, specialCallDoPinning)
+	ctx.pushOp(&ConvertAllocToSlice{Type: typ})
+}
+
+// compileSetIndex compiles the assignment of rhe, which must already be on
+// the stack, to the index expression lhe. If lhe indexes a map and the key
+// does not exist it is inserted by calling runtime.mapassign.
+func (ctx *compileCtx) compileSetIndex(lhe *ast.IndexExpr, rhe ast.Expr) error {
+	err := ctx.compileAST(lhe.X, false)
+	if err != nil {
+		return err
+	}
+	err = ctx.compileAST(lhe.Index, false)
+	if err != nil {
+		return err
+	}
+	err = ctx.maybeMaterialize(lhe.Index)
+	if err != nil {
+		return err
+	}
+	// stack after: [ key, map, value ]
+
+	ctx.pushOp(&SetIndex{Node: lhe, Rhe: rhe})
+	jmp := &Jump{When: JumpIfTrue, Pop: true}
+	ctx.pushOp(jmp)
+
+	ctx.pushOp(&PushMapAssignTypes{})
+	// stack after: [ keyType, mapType, key, map, value ]
+	ctx.compileSpecialCall("runtime.newobject", []ast.Expr{
+		&ast.Ident{Name: "keyType"},
+	}, []Op{
+		&Pick{0},
+	}, specialCallDoPinning)
+	ctx.pushOp(&MapAssignStoreKey{Node: lhe})
+	// stack after: [ keyPtr, mapType, key, map, value ]
+	ctx.compileSpecialCall("runtime.mapassign", []ast.Expr{
+		&ast.Ident{Name: "mapType"},
+		lhe.X,
+		&ast.Ident{Name: "keyPtr"},
+	}, []Op{
+		&Pick{1},
+		&Pick{3},
+		&Pick{0},
+	}, specialCallReinterpretArgs)
+	ctx.pushOp(&MapAssignStoreElem{Rhe: rhe})
+
+	jmp.Target = len(ctx.ops)
+	ctx.pushOp(&Pop{})
+	ctx.pushOp(&Pop{})
+	ctx.pushOp(&Pop{})
+	return nil
+}
+
 type specialCallFlags uint8
 
 const (
 	specialCallDoPinning specialCallFlags = 1 << iota
 	specialCallIsStringAlloc
 	specialCallComplainAboutStringAlloc
+	specialCallReinterpretArgs
 )
 
 func (ctx *compileCtx) compileSpecialCall(fnname string, argAst []ast.Expr, args []Op, flags specialCallFlags) {
@@ -176,7 +323,7 @@ func (ctx *compileCtx) compileSpecialCall(fnname string, argAst []ast.Expr, args
 		if args[i] != nil {
 			ctx.pushOp(args[i])
 		}
-		ctx.pushOp(&CallInjectionCopyArg{id: id, ArgNum: i})
+		ctx.pushOp(&CallInjectionCopyArg{id: id, ArgNum: i, Reinterpret: flags&specialCallReinterpretArgs != 0})
 	}
 
 	doPinning = doPinning && (ctx.flags&HasDebugPinner != 0)
@@ -380,9 +527,6 @@ func (ctx *compileCtx) compileAST(t ast.Expr, toplevel bool) error {
 		return ctx.compileBinary(node.X, node.Index, nil, &Index{node})
 
 	case *ast.SliceExpr:
-		if node.Slice3 {
-			return errors.New("3-index slice expressions not supported")
-		}
 		return ctx.compileReslice(node)
 
 	case *ast.StarExpr:
@@ -403,6 +547,11 @@ func (ctx *compileCtx) compileAST(t ast.Expr, toplevel bool) error {
 		case token.INC, token.DEC, token.ARROW:
 			return fmt.Errorf("operator %s not supported", node.Op.String())
 		}
+		if node.Op == token.EQL || node.Op == token.NEQ {
+			if tn, other := typeNameComparison(node); tn != nil {
+				return ctx.compileBinary(tn, other, nil, &TypeNameCompare{node})
+			}
+		}
 		// short circuits logical operators
 		var sop *Jump
 		switch node.Op {
@@ -572,7 +721,7 @@ func (ctx *compileCtx) compileTypeCast(node *ast.CallExpr, ambiguousErr error) e
 	fnnode = removeParen(fnnode)
 
 	targetTypeStr := astutil.ExprToString(removeParen(node.Fun))
-	styp, err := ctx.FindTypeExpr(fnnode)
+	styp, err := ctx.FindTypeExprInCurrentPackage(fnnode)
 	if err != nil {
 		switch targetTypeStr {
 		case "[]byte", "[]uint8":
@@ -621,11 +770,17 @@ func (ctx *compileCtx) compileTypeAssert(node *ast.TypeAssertExpr) error {
 	if err != nil {
 		return err
 	}
+	if node.Type == nil {
+		// <expression>.(type) evaluates to the name of the dynamic type of the
+		// interface.
+		ctx.pushOp(&TypeName{node})
+		return nil
+	}
 	// Accept .(data) as a type assertion that always succeeds, so that users
 	// can access the data field of an interface without actually having to
 	// type the concrete type.
 	if idtyp, isident := node.Type.(*ast.Ident); !isident || idtyp.Name != "data" {
-		typ, err := ctx.FindTypeExpr(node.Type)
+		typ, err := ctx.FindTypeExprInCurrentPackage(node.Type)
 		if err != nil {
 			return err
 		}
@@ -659,6 +814,15 @@ func (ctx *compileCtx) compileReslice(node *ast.SliceExpr) error {
 	}
 
 	trustLen := true
+	hasMax := false
+	if node.Max != nil {
+		hasMax = true
+		err = ctx.compileAST(node.Max, false)
+		if err != nil {
+			return err
+		}
+	}
+
 	hasHigh := false
 	if node.High != nil {
 		hasHigh = true
@@ -683,7 +847,7 @@ func (ctx *compileCtx) compileReslice(node *ast.SliceExpr) error {
 		ctx.pushOp(&PushConst{constant.MakeInt64(0)})
 	}
 
-	ctx.pushOp(&Reslice{Node: node, HasHigh: hasHigh, TrustLen: trustLen})
+	ctx.pushOp(&Reslice{Node: node, HasHigh: hasHigh, HasMax: hasMax, TrustLen: trustLen})
 	return nil
 }
 
@@ -692,6 +856,12 @@ func (ctx *compileCtx) compileFunctionCall(node *ast.CallExpr, toplevel bool) er
 		if ctx.HasBuiltin(fnnode.Name) {
 			return ctx.compileBuiltinCall(fnnode.Name, node.Args)
 		}
+		switch fnnode.Name {
+		case "make":
+			return ctx.compileMake(node)
+		case "append":
+			return ctx.compileAppend(node)
+		}
 	}
 	if !ctx.allowCalls {
 		return ErrFuncCallNotAllowed
@@ -909,6 +1079,25 @@ func isStringLiteralThatNeedsAlloc(expr ast.Expr) bool {
 	return false
 }
 
+// typeNameComparison returns the <expression>.(type) operand of a
+// comparison and the expression it is compared to, if either side of node
+// is an <expression>.(type) expression.
+func typeNameComparison(node *ast.BinaryExpr) (tn *ast.TypeAssertExpr, other ast.Expr) {
+	isTypeName := func(n ast.Expr) *ast.TypeAssertExpr {
+		if ta, ok := removeParen(n).(*ast.TypeAssertExpr); ok && ta.Type == nil {
+			return ta
+		}
+		return nil
+	}
+	if tn := isTypeName(node.X); tn != nil {
+		return tn, node.Y
+	}
+	if tn := isTypeName(node.Y); tn != nil {
+		return tn, node.X
+	}
+	return nil, nil
+}
+
 func removeParen(n ast.Expr) ast.Expr {
 	for {
 		p, ok := n.(*ast.ParenExpr)
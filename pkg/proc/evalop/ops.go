@@ -97,6 +97,24 @@ type TypeAssert struct {
 
 func (*TypeAssert) depthCheck() (npop, npush int) { return 1, 1 }
 
+// TypeName replaces the topmost stack variable v, which must be an
+// interface, with a string containing the name of its dynamic type.
+// It implements v.(type).
+type TypeName struct {
+	Node *ast.TypeAssertExpr
+}
+
+func (*TypeName) depthCheck() (npop, npush int) { return 1, 1 }
+
+// TypeNameCompare pops two strings from the stack, the dynamic type name
+// produced by TypeName and a type name specified by the user, and pushes
+// the result of comparing them with Node.Op (== or !=).
+type TypeNameCompare struct {
+	Node *ast.BinaryExpr
+}
+
+func (*TypeNameCompare) depthCheck() (npop, npush int) { return 2, 1 }
+
 // PointerDeref replaces the topmost stack variable v with *v.
 type PointerDeref struct {
 	Node *ast.StarExpr
@@ -130,19 +148,25 @@ func (*TypeCast) depthCheck() (npop, npush int) { return 1, 1 }
 // If HasHigh is set it pops three variables, low, high and v, and pushes
 // v[low:high].
 // Otherwise it pops two variables, low and v, and pushes v[low:].
+// If HasMax is also set it pops a fourth variable, max, after high and
+// pushes v[low:high:max].
 // If TrustLen is set when the variable resulting from the reslice is loaded it will be fully loaded.
 type Reslice struct {
 	HasHigh  bool
+	HasMax   bool
 	TrustLen bool
 	Node     *ast.SliceExpr
 }
 
 func (op *Reslice) depthCheck() (npop, npush int) {
+	npop = 2
 	if op.HasHigh {
-		return 3, 1
-	} else {
-		return 2, 1
+		npop++
 	}
+	if op.HasMax {
+		npop++
+	}
+	return npop, 1
 }
 
 // Index pops two variables, idx and v, and pushes v[idx].
@@ -220,6 +244,14 @@ type Dup struct {
 
 func (*Dup) depthCheck() (npop, npush int) { return 1, 2 }
 
+// Pick pushes a copy of the n-th element of the stack (counting from the
+// top, starting at 0) on top of the stack.
+type Pick struct {
+	N int
+}
+
+func (op *Pick) depthCheck() (npop, npush int) { return op.N + 1, op.N + 2 }
+
 // BuiltinCall pops len(Args) argument from the stack, calls the specified
 // builtin on them and pushes the result back on the stack.
 type BuiltinCall struct {
@@ -251,10 +283,13 @@ type CallInjectionSetTarget struct {
 func (*CallInjectionSetTarget) depthCheck() (npop, npush int) { return 1, 0 }
 
 // CallInjectionCopyArg copies one argument for call injection.
+// If Reinterpret is set pointer-shaped arguments are copied as is, even if
+// their type doesn't match the type of the formal argument.
 type CallInjectionCopyArg struct {
-	id      int
-	ArgNum  int
-	ArgExpr ast.Expr
+	id          int
+	ArgNum      int
+	ArgExpr     ast.Expr
+	Reinterpret bool
 }
 
 func (*CallInjectionCopyArg) depthCheck() (npop, npush int) { return 1, 0 }
@@ -306,6 +341,85 @@ type ConvertAllocToString struct {
 
 func (*ConvertAllocToString) depthCheck() (npop, npush int) { return 2, 1 }
 
+// PushAppendAllocArgs reads the slice on top of the stack, without popping
+// it, and pushes the *runtime._type of its element type followed by the
+// length and capacity that runtime.makeslice should be called with to hold
+// the result of appending NumElems elements to it.
+type PushAppendAllocArgs struct {
+	NumElems int
+	Node     *ast.CallExpr
+}
+
+func (*PushAppendAllocArgs) depthCheck() (npop, npush int) { return 1, 4 }
+
+// ConvertAllocToSlice pops four variables from the stack: the return value
+// of runtime.makeslice, the capacity, the length and the *runtime._type of
+// the element type, then pushes a slice of type Type that uses the
+// allocated memory as its backing array.
+// If Type is nil the slice will have the same type as the variable left on
+// top of the stack.
+type ConvertAllocToSlice struct {
+	Type godwarf.Type
+}
+
+func (*ConvertAllocToSlice) depthCheck() (npop, npush int) { return 4, 1 }
+
+// AppendCopy pops two slices from the stack, a newly allocated slice and
+// the slice being appended to, copies the elements of the second into the
+// first and pushes the first back on the stack.
+type AppendCopy struct {
+}
+
+func (*AppendCopy) depthCheck() (npop, npush int) { return 2, 1 }
+
+// AppendSetElem pops a value and a slice from the stack, sets the element
+// of the slice at index len-N to the value and pushes the slice back on the
+// stack.
+type AppendSetElem struct {
+	N    int
+	Node ast.Expr
+}
+
+func (*AppendSetElem) depthCheck() (npop, npush int) { return 2, 1 }
+
+// SetIndex looks at the value, the indexed variable and the index on top
+// of the stack, without popping them. If the indexed variable is a map that
+// doesn't contain the index it pushes false, signalling that the key must
+// be inserted with runtime.mapassign, otherwise it sets the indexed element
+// to the value and pushes true.
+type SetIndex struct {
+	Node *ast.IndexExpr
+	Rhe  ast.Expr
+}
+
+func (*SetIndex) depthCheck() (npop, npush int) { return 3, 4 }
+
+// PushMapAssignTypes looks at the map and key on top of the stack, without
+// popping them, and pushes the *runtime._type of the map followed by the
+// *runtime._type of its key.
+type PushMapAssignTypes struct {
+}
+
+func (*PushMapAssignTypes) depthCheck() (npop, npush int) { return 2, 4 }
+
+// MapAssignStoreKey pops the return value of runtime.newobject and the key
+// type from the stack and copies the key, which is below the map type, into
+// the newly allocated memory. It then pushes back the pointer to the key.
+type MapAssignStoreKey struct {
+	Node *ast.IndexExpr
+}
+
+func (*MapAssignStoreKey) depthCheck() (npop, npush int) { return 5, 4 }
+
+// MapAssignStoreElem pops the return value of runtime.mapassign, the
+// pointer to the key and the map type from the stack, then sets the newly
+// inserted map element to the value, which is below the map and key.
+type MapAssignStoreElem struct {
+	Rhe ast.Expr
+}
+
+func (*MapAssignStoreElem) depthCheck() (npop, npush int) { return 6, 3 }
+
 // SetValue pops to variables from the stack, lhv and rhv, and sets lhv to
 // rhv.
 type SetValue struct {
@@ -0,0 +1,287 @@
+package proc
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-delve/delve/pkg/astutil"
+	"github.com/go-delve/delve/pkg/dwarf/godwarf"
+)
+
+// This file implements field watchpoints, watchpoints set on a field of
+// every instance of a struct type.
+//
+// Delve can not scan the heap for the instances that already exist, instead
+// we set a WatchAllocBreakpoint on the entry point of runtime.mallocgc, and
+// of its size specialized variants, when it is called to allocate one
+// instance of the watched type we set a WatchAllocReturnBreakpoint on its
+// return address and, when that is hit, we read the address of the new
+// object from the result register and set a watchpoint on the field. This
+// only works with the register based calling convention, because arguments
+// and results of mallocgc are read directly from registers.
+//
+// At most FieldWatch.Max instances are watched at the same time, in any
+// case the number of watchpoints is limited by the number of hardware
+// debug registers. Instances allocated after the limit has been reached
+// are not watched.
+//
+// Delve can not tell when the garbage collector frees an instance, instead
+// a watchpoint is removed when the memory of the instance it watches is
+// allocated again: the allocator zeroes reused memory, which triggers the
+// watchpoint while the thread is executing runtime.mallocgc. If the new
+// object is also an instance of the watched type it will be watched again
+// by the mechanism described above. Instances that are freed but whose
+// memory is never reused keep their watchpoint.
+
+// FieldWatch describes a watchpoint on a field of all instances of a type.
+type FieldWatch struct {
+	Type      string    // name of the struct type
+	Field     string    // name of the watched field
+	WatchType WatchType // type of memory access to watch
+	Max       int       // maximum number of instances watched at the same time, 0 means no limit
+}
+
+// watchAllocRequest is a WatchAllocBreakpoint or WatchAllocReturnBreakpoint
+// hit, processed after all breakpoint conditions have been evaluated.
+type watchAllocRequest struct {
+	lbp  *LogicalBreakpoint
+	info *fieldWatchInfo
+
+	// thread that called runtime.mallocgc, for WatchAllocBreakpoints
+	thread Thread
+
+	// the WatchAllocReturnBreakpoint that was hit and the address of the new
+	// object, for WatchAllocReturnBreakpoints
+	retbp       *Breakpoint
+	retbreaklet *Breaklet
+	retAddr     uint64
+
+	// the watchpoint on an instance that was freed, see fieldWatchInstanceFreed
+	freed *Breakpoint
+}
+
+// fieldWatchInfo is the result of resolving a FieldWatch on a target.
+type fieldWatchInfo struct {
+	typeName string
+	rtype    uint64 // address of the runtime type
+	size     int64  // size of the type
+	off      int64  // offset of the field
+	fieldSz  int64  // size of the field
+}
+
+func resolveFieldWatch(t *Target, fw *FieldWatch) (*fieldWatchInfo, error) {
+	bi := t.BinInfo()
+	if !bi.regabi {
+		return nil, errors.New("field watchpoints require a program compiled with the register based calling convention")
+	}
+	typeName := fw.Type
+	typ, err := bi.findType(typeName)
+	if err != nil && !strings.Contains(typeName, ".") {
+		typeName = "main." + typeName
+		typ, err = bi.findType(typeName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not find type %s: %v", fw.Type, err)
+	}
+	styp, ok := godwarf.ResolveTypedef(typ).(*godwarf.StructType)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct type", typeName)
+	}
+	var field *godwarf.StructField
+	for _, f := range styp.Field {
+		if f.Name == fw.Field {
+			field = f
+			break
+		}
+	}
+	if field == nil {
+		return nil, fmt.Errorf("%s has no field %s", typeName, fw.Field)
+	}
+	fieldSz := field.Type.Size()
+	if fieldSz <= 0 || fieldSz > int64(bi.Arch.PtrSize()) {
+		return nil, fmt.Errorf("can not watch field of type %s", field.Type.String())
+	}
+	rtype, _, found, err := dwarfToRuntimeType(bi, t.Memory(), typ)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("could not find runtime type of %s", typeName)
+	}
+	return &fieldWatchInfo{typeName: typeName, rtype: rtype, size: typ.Size(), off: field.ByteOffset, fieldSz: fieldSz}, nil
+}
+
+// mallocFnRegex matches the size specialized versions of runtime.mallocgc,
+// which the compiler calls directly when allocating small objects. They
+// all have the same arguments as mallocgc.
+var mallocFnRegex = regexp.MustCompile(`^runtime\.mallocgc(SmallScanNoHeader|SmallNoScan|Tiny)SC\d+$`)
+
+// setFieldWatchAllocBreakpoint sets the WatchAllocBreakpoints for the field
+// watchpoint lbp.
+func setFieldWatchAllocBreakpoint(t *Target, lbp *LogicalBreakpoint) error {
+	info, err := resolveFieldWatch(t, lbp.Set.FieldWatch)
+	if err != nil {
+		return err
+	}
+	bi := t.BinInfo()
+	argRegs := bi.Arch.argumentRegs
+	callback := func(th Thread, _ *Target) (bool, error) {
+		regs, err := th.Registers()
+		if err != nil {
+			return false, err
+		}
+		dregs := bi.Arch.RegistersToDwarfRegisters(0, regs)
+		// mallocgc(size uintptr, typ *_type, needzero bool)
+		if dregs.Uint64Val(uint64(argRegs[1])) == info.rtype && int64(dregs.Uint64Val(uint64(argRegs[0]))) == info.size {
+			t.Breakpoints().watchAlloc = append(t.Breakpoints().watchAlloc, watchAllocRequest{lbp: lbp, info: info, thread: th})
+		}
+		return false, nil // the user is not interested in this breakpoint
+	}
+	found := false
+	for i := range bi.Functions {
+		fn := &bi.Functions[i]
+		if fn.Name != "runtime.mallocgc" && !mallocFnRegex.MatchString(fn.Name) {
+			continue
+		}
+		bp, err := t.SetBreakpoint(0, fn.Entry, WatchAllocBreakpoint, nil)
+		if err != nil {
+			return err
+		}
+		breaklet := bp.Breaklets[len(bp.Breaklets)-1]
+		breaklet.fieldWatch = lbp
+		breaklet.callback = callback
+		found = true
+	}
+	if !found {
+		return errors.New("could not find runtime.mallocgc")
+	}
+	return nil
+}
+
+// handleWatchAlloc processes a watchAllocRequest.
+func (t *Target) handleWatchAlloc(req watchAllocRequest) error {
+	if req.freed != nil {
+		if t.Breakpoints().M[req.freed.Addr] != req.freed {
+			return nil
+		}
+		return t.ClearBreakpoint(req.freed.Addr)
+	}
+	if req.retbp != nil {
+		return t.setFieldWatchpoint(req)
+	}
+
+	g, err := GetG(req.thread)
+	if err != nil {
+		return err
+	}
+	_, retframe, err := topframe(t, g, req.thread)
+	if err != nil {
+		return err
+	}
+	if retframe.Current.PC == 0 {
+		return errors.New("could not find return address of runtime.mallocgc")
+	}
+	cond := astutil.And(sameGoroutineCondition(t.BinInfo(), g, req.thread.ThreadID()), frameoffCondition(&retframe))
+	bp, err := t.SetBreakpoint(0, retframe.Current.PC, WatchAllocReturnBreakpoint, cond)
+	if err != nil {
+		return err
+	}
+	retReg := uint64(t.BinInfo().Arch.argumentRegs[0])
+	breaklet := bp.Breaklets[len(bp.Breaklets)-1]
+	breaklet.fieldWatch = req.lbp
+	breaklet.callback = func(th Thread, _ *Target) (bool, error) {
+		regs, err := th.Registers()
+		if err != nil {
+			return false, err
+		}
+		addr := t.BinInfo().Arch.RegistersToDwarfRegisters(0, regs).Uint64Val(retReg)
+		t.Breakpoints().watchAlloc = append(t.Breakpoints().watchAlloc, watchAllocRequest{lbp: req.lbp, info: req.info, retbp: bp, retbreaklet: breaklet, retAddr: addr})
+		return false, nil
+	}
+	return nil
+}
+
+// setFieldWatchpoint removes the WatchAllocReturnBreakpoint of req and sets
+// a watchpoint on the field of the object allocated.
+func (t *Target) setFieldWatchpoint(req watchAllocRequest) error {
+	for i, breaklet := range req.retbp.Breaklets {
+		if breaklet == req.retbreaklet {
+			req.retbp.Breaklets[i] = nil
+		}
+	}
+	if _, err := t.finishClearBreakpoint(req.retbp); err != nil {
+		return err
+	}
+
+	lbp := req.lbp
+	if t.Breakpoints().Logical[lbp.LogicalID] != lbp || !lbp.enabled || req.retAddr == 0 {
+		return nil
+	}
+	fw, info := lbp.Set.FieldWatch, req.info
+	if fw.Max > 0 {
+		n := 0
+		for _, bp := range t.Breakpoints().M {
+			if bp.Logical == lbp && bp.WatchType != 0 {
+				n++
+			}
+		}
+		if n >= fw.Max {
+			return nil
+		}
+	}
+	bp, err := t.setBreakpointInternal(lbp.LogicalID, req.retAddr+uint64(info.off), UserBreakpoint, fw.WatchType.withSize(uint8(info.fieldSz)), nil)
+	if err != nil {
+		if _, isexists := err.(BreakpointExistsError); isexists {
+			return nil
+		}
+		return err
+	}
+	bp.WatchExpr = fmt.Sprintf("(*%s)(%#x).%s", info.typeName, req.retAddr, fw.Field)
+	return nil
+}
+
+// fieldWatchInstanceFreed returns true if bp is the watchpoint of a field
+// watchpoint and it was triggered by runtime.mallocgc on thread, meaning
+// that the instance it was watching has been freed and its memory is being
+// allocated again. The watchpoint is scheduled for removal.
+func fieldWatchInstanceFreed(t *Target, bp *Breakpoint, thread Thread) bool {
+	if bp.WatchType == 0 || bp.Logical == nil || bp.Logical.Set.FieldWatch == nil {
+		return false
+	}
+	// The allocator clears the memory in a callee of mallocgc (for example
+	// memclrNoHeapPointers) or in mallocgc itself.
+	const maxAllocDepth = 4
+	frames, err := ThreadStacktrace(t, thread, maxAllocDepth)
+	if err != nil {
+		return false
+	}
+	for _, frame := range frames {
+		if frame.Current.Fn != nil && (frame.Current.Fn.Name == "runtime.mallocgc" || mallocFnRegex.MatchString(frame.Current.Fn.Name)) {
+			t.Breakpoints().watchAlloc = append(t.Breakpoints().watchAlloc, watchAllocRequest{lbp: bp.Logical, freed: bp})
+			return true
+		}
+	}
+	return false
+}
+
+// clearFieldWatchBreakpoints clears all accessory breakpoints for the field
+// watchpoint lbp.
+func (t *Target) clearFieldWatchBreakpoints(lbp *LogicalBreakpoint) error {
+	for _, bp := range t.Breakpoints().M {
+		changed := false
+		for i, breaklet := range bp.Breaklets {
+			if breaklet.fieldWatch == lbp {
+				bp.Breaklets[i] = nil
+				changed = true
+			}
+		}
+		if changed {
+			if _, err := t.finishClearBreakpoint(bp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -12,6 +12,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-delve/delve/pkg/astutil"
 	"github.com/go-delve/delve/pkg/dwarf/godwarf"
@@ -139,10 +141,33 @@ type callInjection struct {
 //lint:ignore U1000 this variable is only used by tests
 var debugPinCount int
 
+// EvalConfig contains options for EvalExpressionWithCalls.
+type EvalConfig struct {
+	// CheckEscape is true if the escape check should be performed.
+	// See service/api.DebuggerCommand.UnsafeCall in service/api/types.go.
+	CheckEscape bool
+
+	// CallTimeout, if not zero, is the maximum amount of time to wait for
+	// the injected function calls to complete. When it expires the target is
+	// stopped and ErrFunctionCallTimeout is returned, the calls are left in
+	// progress and will complete when the target is resumed.
+	CallTimeout time.Duration
+}
+
+// ErrFunctionCallTimeout is returned by EvalExpressionWithCalls when the
+// injected function call does not complete before the timeout expires.
+type ErrFunctionCallTimeout struct {
+	Timeout time.Duration
+}
+
+func (err ErrFunctionCallTimeout) Error() string {
+	return fmt.Sprintf("function call timed out after %v, the call will complete when the program is continued", err.Timeout)
+}
+
 // EvalExpressionWithCalls is like EvalExpression but allows function calls in 'expr'.
 // Because this can only be done in the current goroutine, unlike
 // EvalExpression, EvalExpressionWithCalls is not a method of EvalScope.
-func EvalExpressionWithCalls(grp *TargetGroup, g *G, expr string, retLoadCfg LoadConfig, checkEscape bool) error {
+func EvalExpressionWithCalls(grp *TargetGroup, g *G, expr string, retLoadCfg LoadConfig, cfg EvalConfig) error {
 	debugPinCount = 0
 	t := grp.Selected
 	bi := t.BinInfo()
@@ -179,7 +204,7 @@ func EvalExpressionWithCalls(grp *TargetGroup, g *G, expr string, retLoadCfg Loa
 	scope.callCtx = &callContext{
 		grp:         grp,
 		p:           t,
-		checkEscape: checkEscape,
+		checkEscape: cfg.CheckEscape,
 		retLoadCfg:  retLoadCfg,
 	}
 	scope.loadCfg = &retLoadCfg
@@ -204,12 +229,51 @@ func EvalExpressionWithCalls(grp *TargetGroup, g *G, expr string, retLoadCfg Loa
 
 	stack.eval(scope, ops)
 	if stack.callInjectionContinue && stack.err == nil {
-		return grp.Continue()
+		if cfg.CallTimeout <= 0 {
+			return grp.Continue()
+		}
+		return continueWithCallTimeout(grp, g, cfg.CallTimeout)
 	}
 
 	return finishEvalExpressionWithCalls(t, g, stack)
 }
 
+// continueWithCallTimeout resumes the target, stopping it if the function
+// call injected on g has not completed after timeout.
+// The call injection is left pending: it is not possible to abort an
+// injected call without the cooperation of the runtime, the call completes
+// through the call injection protocol once the target is resumed.
+func continueWithCallTimeout(grp *TargetGroup, g *G, timeout time.Duration) error {
+	var (
+		mu       sync.Mutex
+		done     bool
+		timedOut bool
+	)
+	timer := time.AfterFunc(timeout, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			return
+		}
+		timedOut = true
+		if err := grp.RequestManualStop(); err != nil {
+			fncallLog("could not stop target after call timeout: %v", err)
+		}
+	})
+	err := grp.Continue()
+	mu.Lock()
+	done = true
+	mu.Unlock()
+	timer.Stop()
+	if err != nil || !timedOut {
+		return err
+	}
+	if callinj := grp.Selected.fncallForG[g.ID]; callinj != nil && callinj.evalStack != nil {
+		return ErrFunctionCallTimeout{Timeout: timeout}
+	}
+	return nil
+}
+
 func finishEvalExpressionWithCalls(t *Target, g *G, stack *evalStack) error {
 	fncallLog("stashing return values for %d in thread=%d", g.ID, g.Thread.ThreadID())
 	g.Thread.Common().CallReturn = true
@@ -511,7 +575,7 @@ func funcCallEvalFuncExpr(scope *EvalScope, stack *evalStack, fncall *functionCa
 	if fnvar.Kind != reflect.Func {
 		return fmt.Errorf("expression %q is not a function", astutil.ExprToString(fncall.expr.Fun))
 	}
-	fnvar.loadValue(LoadConfig{false, 0, 0, 0, 0, 0})
+	fnvar.loadValue(LoadConfig{})
 	if fnvar.Unreadable != nil {
 		return fnvar.Unreadable
 	}
@@ -1123,6 +1187,235 @@ func (scope *EvalScope) convertAllocToString(stack *evalStack) {
 	stack.push(v)
 }
 
+func (scope *EvalScope) pushAppendAllocArgs(stack *evalStack, op *evalop.PushAppendAllocArgs) {
+	slicev := stack.peek()
+	if slicev.Unreadable != nil {
+		stack.err = slicev.Unreadable
+		return
+	}
+	styp, ok := slicev.RealType.(*godwarf.SliceType)
+	if !ok || slicev.Kind != reflect.Slice {
+		stack.err = fmt.Errorf("first argument to append must be a slice; have %s (type %s)", astutil.ExprToString(op.Node.Args[0]), slicev.TypeString())
+		return
+	}
+	rtypev, err := scope.runtimeTypeVariable(styp.ElemType)
+	if err != nil {
+		stack.err = err
+		return
+	}
+	newlen := slicev.Len + int64(op.NumElems)
+	newcap := slicev.Cap
+	if newlen > newcap {
+		newcap = max(newlen, 2*slicev.Cap)
+	}
+	stack.push(rtypev)
+	stack.push(newConstant(constant.MakeInt64(newlen), scope.BinInfo, scope.Mem))
+	stack.push(newConstant(constant.MakeInt64(newcap), scope.BinInfo, scope.Mem))
+}
+
+func (scope *EvalScope) convertAllocToSlice(stack *evalStack, typ godwarf.Type) {
+	makeslicev := stack.pop()
+	capv := stack.pop()
+	lenv := stack.pop()
+	stack.pop() // element type
+
+	makeslicev.loadValue(loadFullValue)
+
+	if makeslicev.Unreadable != nil {
+		stack.err = makeslicev.Unreadable
+		return
+	}
+
+	if makeslicev.DwarfType.String() != "*void" {
+		stack.err = fmt.Errorf("unexpected return type for makeslice call: %v", makeslicev.DwarfType.String())
+		return
+	}
+
+	if len(makeslicev.Children) != 1 {
+		stack.err = errors.New("internal error, could not interpret return value of makeslice call")
+		return
+	}
+
+	n, err := lenv.asInt()
+	if err != nil {
+		stack.err = err
+		return
+	}
+	c, err := capv.asInt()
+	if err != nil {
+		stack.err = err
+		return
+	}
+
+	if typ == nil {
+		typ = stack.peek().DwarfType
+	}
+
+	v, err := scope.newFakeVariable(typ)
+	if err != nil {
+		stack.err = err
+		return
+	}
+	err = v.writeSlice(n, c, makeslicev.Children[0].Addr)
+	if err != nil {
+		stack.err = err
+		return
+	}
+	v = newVariable("", v.Addr, typ, scope.BinInfo, v.mem)
+	v.Flags = VariableFakeAddress
+	stack.push(v)
+}
+
+func (scope *EvalScope) appendCopy(stack *evalStack) {
+	newv := stack.pop()
+	oldv := stack.pop()
+	if oldv.Len > 0 {
+		buf := make([]byte, oldv.Len*oldv.stride)
+		_, err := DereferenceMemory(oldv.mem).ReadMemory(buf, oldv.Base)
+		if err != nil {
+			stack.err = err
+			return
+		}
+		_, err = scope.Mem.WriteMemory(newv.Base, buf)
+		if err != nil {
+			stack.err = err
+			return
+		}
+	}
+	stack.push(newv)
+}
+
+func (scope *EvalScope) setIndex(stack *evalStack, op *evalop.SetIndex) {
+	keyv := stack.peek()
+	mapv := stack.stack[len(stack.stack)-2]
+	v := stack.stack[len(stack.stack)-3]
+	if mapv.Unreadable == nil && mapv.maybeDereference().Kind == reflect.Map {
+		mapv = mapv.maybeDereference()
+		keyv.loadValue(loadFullValue)
+		if keyv.Unreadable != nil {
+			stack.err = keyv.Unreadable
+			return
+		}
+		_, err := mapv.mapAccess(keyv)
+		if err == errMapKeyNotFound {
+			if mapv.Base == 0 {
+				stack.err = fmt.Errorf("assignment to entry in nil map %s", astutil.ExprToString(op.Node.X))
+				return
+			}
+			stack.push(newConstant(constant.MakeBool(false), scope.BinInfo, scope.Mem))
+			return
+		}
+	}
+
+	stack.push(mapv)
+	stack.push(keyv)
+	scope.evalIndex(&evalop.Index{Node: op.Node}, stack)
+	if stack.err != nil {
+		return
+	}
+	stack.err = scope.setValue(stack.pop(), v, astutil.ExprToString(op.Rhe))
+	stack.push(newConstant(constant.MakeBool(true), scope.BinInfo, scope.Mem))
+}
+
+func (scope *EvalScope) pushMapAssignTypes(stack *evalStack) {
+	mapv := stack.stack[len(stack.stack)-2].maybeDereference()
+	mtypev, err := scope.runtimeTypeVariable(mapv.RealType)
+	if err != nil {
+		stack.err = err
+		return
+	}
+	rtyp := mtypev.Children[0]
+	if rtyp.Addr == 0 {
+		stack.err = fmt.Errorf("could not find runtime type of %s", mapv.TypeString())
+		return
+	}
+	// In all versions of the runtime the map type descriptor starts with a
+	// runtime._type followed by a pointer to the type descriptor of the key.
+	keyTypeAddr, err := readUintRaw(scope.Mem, rtyp.Addr+uint64(rtyp.RealType.Size()), int64(scope.BinInfo.Arch.PtrSize()))
+	if err != nil {
+		stack.err = err
+		return
+	}
+	stack.push(mtypev)
+	stack.push(newVariable("", keyTypeAddr, rtyp.DwarfType, scope.BinInfo, scope.Mem).pointerToVariable())
+}
+
+func (scope *EvalScope) mapAssignStoreKey(stack *evalStack, op *evalop.MapAssignStoreKey) {
+	newobjv := stack.pop()
+	stack.pop() // key type
+	keyv := stack.stack[len(stack.stack)-2]
+	mapv := stack.stack[len(stack.stack)-3].maybeDereference()
+
+	newobjv.loadValue(loadFullValue)
+	if newobjv.Unreadable != nil {
+		stack.err = newobjv.Unreadable
+		return
+	}
+	if len(newobjv.Children) != 1 {
+		stack.err = errors.New("internal error, could not interpret return value of newobject call")
+		return
+	}
+
+	dstv := newVariable("", newobjv.Children[0].Addr, mapv.RealType.(*godwarf.MapType).KeyType, scope.BinInfo, scope.Mem)
+	stack.err = scope.setValue(dstv, keyv, astutil.ExprToString(op.Node.Index))
+	stack.push(newobjv)
+}
+
+func (scope *EvalScope) mapAssignStoreElem(stack *evalStack, op *evalop.MapAssignStoreElem) {
+	elemptrv := stack.pop()
+	stack.pop() // key pointer
+	stack.pop() // map type
+	mapv := stack.stack[len(stack.stack)-2].maybeDereference()
+	v := stack.stack[len(stack.stack)-3]
+
+	elemptrv.loadValue(loadFullValue)
+	if elemptrv.Unreadable != nil {
+		stack.err = elemptrv.Unreadable
+		return
+	}
+	if len(elemptrv.Children) != 1 || elemptrv.Children[0].Addr == 0 {
+		stack.err = errors.New("internal error, could not interpret return value of mapassign call")
+		return
+	}
+
+	elemv := newVariable("", elemptrv.Children[0].Addr, mapv.RealType.(*godwarf.MapType).ElemType, scope.BinInfo, scope.Mem)
+	stack.err = scope.setValue(elemv, v, astutil.ExprToString(op.Rhe))
+}
+
+// reinterpretPointerArg returns a copy of the pointer-shaped variable v
+// with type typ, which must be a pointer type. It is used to pass arguments
+// to runtime functions that take raw pointers to runtime data structures.
+func reinterpretPointerArg(v *Variable, typ godwarf.Type) (*Variable, error) {
+	ptyp, isptr := godwarf.ResolveTypedef(typ).(*godwarf.PtrType)
+	if !isptr {
+		return v, nil
+	}
+	var addr uint64
+	switch v.Kind {
+	case reflect.Ptr, reflect.UnsafePointer:
+		v.loadValue(loadSingleValue)
+		if v.Unreadable != nil {
+			return nil, v.Unreadable
+		}
+		if len(v.Children) != 1 {
+			return nil, fmt.Errorf("could not read pointer value of %s", v.Name)
+		}
+		addr = v.Children[0].Addr
+	case reflect.Map, reflect.Chan:
+		var err error
+		addr, err = readUintRaw(v.mem, v.Addr, int64(v.bi.Arch.PtrSize()))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return v, nil
+	}
+	r := newVariable(v.Name, 0, typ, v.bi, v.mem)
+	r.Children = []Variable{*newVariable("", addr, ptyp.Type, v.bi, v.mem)}
+	r.loaded = true
+	return r, nil
+}
+
 func isCallInjectionStop(t *Target, thread Thread, loc *Location) bool {
 	if loc.Fn == nil {
 		return false
@@ -1279,6 +1572,9 @@ func debugCallProtocolReg(archName string, version int) (uint64, bool) {
 // (through call injection) even if they are optimized.
 var runtimeWhitelist = map[string]bool{
 	"runtime.mallocgc":             true,
+	"runtime.makeslice":            true,
+	"runtime.newobject":            true,
+	"runtime.mapassign":            true,
 	evalop.DebugPinnerFunctionName: true,
 	"runtime.(*Pinner).Unpin":      true,
 	"runtime.(*Pinner).Pin":        true,
@@ -128,6 +128,32 @@ type ContinueOnceContext struct {
 	// signalled to stop as a result of a Halt API call. Used to disambiguate
 	// why a thread is found to have stopped.
 	manualStopRequested bool
+	// signalPolicy is the policy for signals that do not use
+	// DefaultSignalPolicy.
+	signalPolicy map[int]SignalPolicy
+}
+
+// SignalPolicy describes what happens when the target process receives a
+// signal.
+type SignalPolicy struct {
+	Stop  bool // the target process stops
+	Print bool // an EventSignal is sent
+	Pass  bool // the signal is delivered to the target process
+}
+
+// DefaultSignalPolicy is the policy of signals that were never configured
+// with SetSignalPolicy: they are delivered to the target process without
+// stopping it.
+var DefaultSignalPolicy = SignalPolicy{Pass: true}
+
+// SignalPolicy returns the policy for signal sig.
+func (cctx *ContinueOnceContext) SignalPolicy(sig int) SignalPolicy {
+	cctx.StopMu.Lock()
+	defer cctx.StopMu.Unlock()
+	if policy, ok := cctx.signalPolicy[sig]; ok {
+		return policy
+	}
+	return DefaultSignalPolicy
 }
 
 // CheckAndClearManualStopRequest will check for a manual
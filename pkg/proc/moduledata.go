@@ -1,16 +1,24 @@
 package proc
 
+import (
+	"go/constant"
+	"sort"
+)
+
 // ModuleData counterpart to runtime.moduleData
 type ModuleData struct {
 	text, etext   uint64
 	types, etypes uint64
 	typemapVar    *Variable
+	pluginpathVar *Variable
+	variable      *Variable
 }
 
 func LoadModuleData(bi *BinaryInfo, mem MemoryReadWriter) ([]ModuleData, error) {
 	// +rtype -var firstmoduledata moduledata
 	// +rtype -field moduledata.text uintptr
 	// +rtype -field moduledata.types uintptr
+	// +rtype -field moduledata.pluginpath string
 
 	scope := globalScope(nil, bi, bi.Images[0], mem)
 	var md *Variable
@@ -29,10 +37,11 @@ func LoadModuleData(bi *BinaryInfo, mem MemoryReadWriter) ([]ModuleData, error)
 			etextField   = "etext"
 			nextField    = "next"
 			typemapField = "typemap"
+			pluginField  = "pluginpath"
 		)
 		vars := map[string]*Variable{}
 
-		for _, fieldName := range []string{typesField, etypesField, textField, etextField, nextField, typemapField} {
+		for _, fieldName := range []string{typesField, etypesField, textField, etextField, nextField, typemapField, pluginField} {
 			var err error
 			vars[fieldName], err = md.structField(fieldName)
 			if err != nil {
@@ -54,7 +63,9 @@ func LoadModuleData(bi *BinaryInfo, mem MemoryReadWriter) ([]ModuleData, error)
 		r = append(r, ModuleData{
 			types: touint(typesField), etypes: touint(etypesField),
 			text: touint(textField), etext: touint(etextField),
-			typemapVar: vars[typemapField],
+			typemapVar:    vars[typemapField],
+			pluginpathVar: vars[pluginField],
+			variable:      md,
 		})
 		if err != nil {
 			return nil, err
@@ -77,3 +88,135 @@ func findModuleDataForType(mds []ModuleData, typeAddr uint64) *ModuleData {
 	}
 	return nil
 }
+
+func findModuleDataForPC(mds []ModuleData, pc uint64) *ModuleData {
+	for i := range mds {
+		if pc >= mds[i].text && pc < mds[i].etext {
+			return &mds[i]
+		}
+	}
+	return nil
+}
+
+// funcdata returns the address of the FUNCDATA with index idx of the
+// function containing pc, or 0 if the function doesn't have it.
+// See runtime.findfunc and runtime.funcdata.
+func (md *ModuleData) funcdata(pc uint64, idx uint8) (uint64, error) {
+	// +rtype -field moduledata.ftab []functab
+	// +rtype -field moduledata.pclntable []byte
+	// +rtype -field moduledata.gofunc uintptr
+	// +rtype -field functab.entryoff uint32
+	// +rtype -field functab.funcoff uint32
+	// +rtype -field _func.npcdata uint32
+	// +rtype -field _func.nfuncdata uint8
+
+	const functabSize = 8 // sizeof(runtime.functab)
+
+	if pc < md.text || pc >= md.etext {
+		return 0, nil
+	}
+	bi, mem := md.variable.bi, md.variable.mem
+
+	var fields [3]*Variable
+	for i, fieldName := range []string{"ftab", "pclntable", "gofunc"} {
+		var err error
+		fields[i], err = md.variable.structField(fieldName)
+		if err != nil {
+			return 0, err
+		}
+	}
+	ftab, pclntable := fields[0], fields[1]
+	fields[2].loadValue(loadSingleValue)
+	gofunc, err := fields[2].asUint()
+	if err != nil {
+		return 0, err
+	}
+
+	// The last entry of ftab is a sentinel for the end of the last function.
+	off := uint32(pc - md.text)
+	var readErr error
+	entryoff := func(i int) uint32 {
+		n, err := readUintRaw(mem, ftab.Base+uint64(i)*functabSize, 4)
+		if err != nil {
+			readErr = err
+		}
+		return uint32(n)
+	}
+	i := sort.Search(int(ftab.Len), func(i int) bool { return entryoff(i) > off }) - 1
+	if readErr != nil {
+		return 0, readErr
+	}
+	if i < 0 || i >= int(ftab.Len)-1 {
+		return 0, nil
+	}
+	funcoff, err := readUintRaw(mem, ftab.Base+uint64(i)*functabSize+4, 4)
+	if err != nil {
+		return 0, err
+	}
+
+	typ, err := bi.findType("runtime._func")
+	if err != nil {
+		return 0, err
+	}
+	fn := newVariable("", pclntable.Base+funcoff, typ, bi, mem)
+	var npcdata, nfuncdata uint64
+	for _, f := range []struct {
+		name string
+		dst  *uint64
+	}{{"npcdata", &npcdata}, {"nfuncdata", &nfuncdata}} {
+		v, err := fn.structField(f.name)
+		if err != nil {
+			return 0, err
+		}
+		v.loadValue(loadSingleValue)
+		*f.dst, err = v.asUint()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if uint64(idx) >= nfuncdata {
+		return 0, nil
+	}
+
+	// The _func header is followed by npcdata uint32 pcdata offsets and then
+	// by nfuncdata uint32 offsets from moduledata.gofunc.
+	fdoff, err := readUintRaw(mem, fn.Addr+uint64(typ.Size())+npcdata*4+uint64(idx)*4, 4)
+	if err != nil {
+		return 0, err
+	}
+	if uint32(fdoff) == ^uint32(0) {
+		return 0, nil
+	}
+	return gofunc + fdoff, nil
+}
+
+// Plugin is a Go plugin loaded by the target process.
+type Plugin struct {
+	PluginPath string // path of the plugin package, as recorded by the runtime
+	Image      *Image // image of the shared object containing the plugin, nil if it isn't known
+	Text       uint64 // start address of the text section of the plugin
+}
+
+// Plugins returns the list of Go plugins loaded by the target process, in
+// the order they were loaded.
+func Plugins(bi *BinaryInfo, mem MemoryReadWriter) ([]Plugin, error) {
+	mds, err := LoadModuleData(bi, mem)
+	if err != nil {
+		return nil, err
+	}
+	r := []Plugin{}
+	for i := range mds {
+		pp := mds[i].pluginpathVar
+		pp.loadValue(loadFullValue)
+		if pp.Unreadable != nil {
+			return nil, pp.Unreadable
+		}
+		pluginPath := constant.StringVal(pp.Value)
+		if pluginPath == "" {
+			// the main executable or a shared library built with -linkshared
+			continue
+		}
+		r = append(r, Plugin{PluginPath: pluginPath, Image: bi.moduleDataToImage(&mds[i]), Text: mds[i].text})
+	}
+	return r, nil
+}
@@ -0,0 +1,13 @@
+package proc
+
+import "golang.org/x/sys/unix"
+
+// hostNanotime returns the current value of the clock used by
+// runtime.nanotime for processes running on this machine.
+func hostNanotime() (int64, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return 0, err
+	}
+	return ts.Nano(), nil
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package proc
+
+func hostNanotime() (int64, error) {
+	return 0, ErrNanotimeUnavailable
+}
@@ -58,7 +58,10 @@ smapsLinesLoop:
 			}
 		}
 		if strings.HasPrefix(dev, "00:") {
-			filename = ""
+			if !strings.HasPrefix(filename, "[") {
+				// keep pseudo-paths like [heap] and [stack]
+				filename = ""
+			}
 			offset = 0
 		}
 
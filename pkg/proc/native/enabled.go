@@ -0,0 +1,6 @@
+//go:build !darwin || macnative
+
+package native
+
+// Enabled is true if the native backend was compiled into this build of Delve.
+const Enabled = true
@@ -13,13 +13,16 @@ import (
 
 var ErrNativeBackendDisabled = errors.New("native backend disabled during compilation")
 
+// Enabled is true if the native backend was compiled into this build of Delve.
+const Enabled = false
+
 // Launch returns ErrNativeBackendDisabled.
 func Launch(_ []string, _ string, _ proc.LaunchFlags, _ []string, _ string, _ string, _ proc.OutputRedirect, _ proc.OutputRedirect) (*proc.TargetGroup, error) {
 	return nil, ErrNativeBackendDisabled
 }
 
 // Attach returns ErrNativeBackendDisabled.
-func Attach(_ int, _ *proc.WaitFor, _ []string) (*proc.TargetGroup, error) {
+func Attach(_ int, _ *proc.WaitFor, _ []string, _ string) (*proc.TargetGroup, error) {
 	return nil, ErrNativeBackendDisabled
 }
 
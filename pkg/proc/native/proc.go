@@ -217,6 +217,7 @@ func (dbp *nativeProcess) EraseBreakpoint(bp *proc.Breakpoint) error {
 type processGroup struct {
 	procs     []*nativeProcess
 	addTarget proc.AddTargetFunc
+	cctx      *proc.ContinueOnceContext // set while ContinueOnce is running
 }
 
 func (procgrp *processGroup) numValid() int {
@@ -274,6 +275,11 @@ func (procgrp *processGroup) ContinueOnce(cctx *proc.ContinueOnceContext) (proc.
 		return nil, proc.StopExited, proc.ErrProcessExited{Pid: procgrp.procs[0].pid}
 	}
 
+	procgrp.cctx = cctx
+	defer func() {
+		procgrp.cctx = nil
+	}()
+
 	for {
 		err := procgrp.resume()
 		if err != nil {
@@ -283,6 +289,8 @@ func (procgrp *processGroup) ContinueOnce(cctx *proc.ContinueOnceContext) (proc.
 			if valid, _ := dbp.Valid(); valid {
 				for _, th := range dbp.threads {
 					th.CurrentBreakpoint.Clear()
+					th.Common().Signal = 0
+					th.Common().Exception = nil
 				}
 			}
 		}
@@ -348,7 +356,7 @@ func (dbp *nativeProcess) initializeBasic() (string, error) {
 
 // initialize will ensure that all relevant information is loaded
 // so the process is ready to be debugged.
-func (dbp *nativeProcess) initialize(path string, debugInfoDirs []string) (*proc.TargetGroup, error) {
+func (dbp *nativeProcess) initialize(path string, debugInfoDirs []string, debugInfoFile string) (*proc.TargetGroup, error) {
 	cmdline, err := dbp.initializeBasic()
 	if err != nil {
 		return nil, err
@@ -360,6 +368,7 @@ func (dbp *nativeProcess) initialize(path string, debugInfoDirs []string) (*proc
 	procgrp := &processGroup{}
 	grp, addTarget := proc.NewGroup(procgrp, proc.NewTargetGroupConfig{
 		DebugInfoDirs: debugInfoDirs,
+		DebugInfoFile: debugInfoFile,
 
 		// We disable asyncpreempt for the following reasons:
 		//  - on Windows asyncpreempt is incompatible with debuggers, see:
@@ -381,6 +390,8 @@ func (dbp *nativeProcess) initialize(path string, debugInfoDirs []string) (*proc
 
 		StopReason: stopReason,
 		CanDump:    runtime.GOOS == "linux" || runtime.GOOS == "freebsd" || (runtime.GOOS == "windows" && runtime.GOARCH == "amd64"),
+
+		CanSetSignalPolicy: runtime.GOOS == "linux",
 	})
 	procgrp.addTarget = addTarget
 	tgt, err := procgrp.add(dbp, dbp.pid, dbp.memthread, path, stopReason, cmdline)
@@ -131,7 +131,7 @@ func Launch(cmd []string, wd string, flags proc.LaunchFlags, _ []string, _ strin
 	dbp.os.initialized = true
 	dbp.memthread = trapthread
 
-	tgt, err := dbp.initialize(argv0Go, []string{})
+	tgt, err := dbp.initialize(argv0Go, []string{}, "")
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +144,7 @@ func waitForSearchProcess(string, map[int]struct{}) (int, error) {
 }
 
 // Attach to an existing process with the given PID.
-func Attach(pid int, waitFor *proc.WaitFor, _ []string) (*proc.TargetGroup, error) {
+func Attach(pid int, waitFor *proc.WaitFor, _ []string, _ string) (*proc.TargetGroup, error) {
 	if waitFor.Valid() {
 		return nil, proc.ErrWaitForNotImplemented
 	}
@@ -173,7 +173,7 @@ func Attach(pid int, waitFor *proc.WaitFor, _ []string) (*proc.TargetGroup, erro
 		return nil, err
 	}
 
-	tgt, err := dbp.initialize("", []string{})
+	tgt, err := dbp.initialize("", []string{}, "")
 	if err != nil {
 		detachWithoutGroup(dbp, false)
 		return nil, err
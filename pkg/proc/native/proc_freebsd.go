@@ -127,7 +127,7 @@ func Launch(cmd []string, wd string, flags proc.LaunchFlags, debugInfoDirs []str
 	if err != nil {
 		return nil, fmt.Errorf("waiting for target execve failed: %s", err)
 	}
-	tgt, err := dbp.initialize(cmd[0], debugInfoDirs)
+	tgt, err := dbp.initialize(cmd[0], debugInfoDirs, "")
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +137,9 @@ func Launch(cmd []string, wd string, flags proc.LaunchFlags, debugInfoDirs []str
 // Attach to an existing process with the given PID. Once attached, if
 // the DWARF information cannot be found in the binary, Delve will look
 // for external debug files in the directories passed in.
-func Attach(pid int, waitFor *proc.WaitFor, debugInfoDirs []string) (*proc.TargetGroup, error) {
+// If debugInfoFile is not empty the DWARF information is loaded from it
+// instead.
+func Attach(pid int, waitFor *proc.WaitFor, debugInfoDirs []string, debugInfoFile string) (*proc.TargetGroup, error) {
 	if waitFor.Valid() {
 		var err error
 		pid, err = WaitFor(waitFor)
@@ -158,7 +160,7 @@ func Attach(pid int, waitFor *proc.WaitFor, debugInfoDirs []string) (*proc.Targe
 		return nil, err
 	}
 
-	tgt, err := dbp.initialize(findExecutable("", dbp.pid), debugInfoDirs)
+	tgt, err := dbp.initialize(findExecutable("", dbp.pid), debugInfoDirs, debugInfoFile)
 	if err != nil {
 		detachWithoutGroup(dbp, false)
 		return nil, err
@@ -131,7 +131,7 @@ func Launch(cmd []string, wd string, flags proc.LaunchFlags, debugInfoDirs []str
 	if err != nil {
 		return nil, fmt.Errorf("waiting for target execve failed: %s", err)
 	}
-	tgt, err := dbp.initialize(cmd[0], debugInfoDirs)
+	tgt, err := dbp.initialize(cmd[0], debugInfoDirs, "")
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +142,9 @@ func Launch(cmd []string, wd string, flags proc.LaunchFlags, debugInfoDirs []str
 // Attach to an existing process with the given PID. Once attached, if
 // the DWARF information cannot be found in the binary, Delve will look
 // for external debug files in the directories passed in.
-func Attach(pid int, waitFor *proc.WaitFor, debugInfoDirs []string) (*proc.TargetGroup, error) {
+// If debugInfoFile is not empty the DWARF information is loaded from it
+// instead.
+func Attach(pid int, waitFor *proc.WaitFor, debugInfoDirs []string, debugInfoFile string) (*proc.TargetGroup, error) {
 	if waitFor.Valid() {
 		var err error
 		pid, err = WaitFor(waitFor)
@@ -163,7 +165,7 @@ func Attach(pid int, waitFor *proc.WaitFor, debugInfoDirs []string) (*proc.Targe
 		return nil, err
 	}
 
-	tgt, err := dbp.initialize(findExecutable("", dbp.pid), debugInfoDirs)
+	tgt, err := dbp.initialize(findExecutable("", dbp.pid), debugInfoDirs, debugInfoFile)
 	if err != nil {
 		_ = detachWithoutGroup(dbp, false)
 		return nil, err
@@ -596,7 +598,6 @@ func trapWaitInternal(procgrp *processGroup, pid int, options trapWaitOptions) (
 			return th, nil
 		}
 
-		// TODO(dp) alert user about unexpected signals here.
 		if halt && !th.os.running {
 			// We are trying to stop the process, queue this signal to be delivered
 			// to the thread when we resume.
@@ -606,7 +607,26 @@ func trapWaitInternal(procgrp *processGroup, pid int, options trapWaitOptions) (
 			th.os.delayedSignal = int(status.StopSignal())
 			th.os.running = false
 			return th, nil
-		} else if err := th.resumeWithSig(int(status.StopSignal())); err != nil {
+		}
+		sig := int(status.StopSignal())
+		policy := proc.DefaultSignalPolicy
+		if procgrp.cctx != nil && sig != int(sys.SIGSTOP) {
+			policy = procgrp.cctx.SignalPolicy(sig)
+		}
+		if !halt && (policy.Stop || policy.Print) {
+			// Report the signal to the target layer, it will be delivered (if
+			// the policy allows it) when the thread is resumed.
+			th.Common().Signal = sig
+			if policy.Pass {
+				th.os.delayedSignal = sig
+			}
+			th.os.running = false
+			return th, nil
+		}
+		if !policy.Pass {
+			sig = 0
+		}
+		if err := th.resumeWithSig(sig); err != nil {
 			if err != sys.ESRCH {
 				return nil, err
 			}
@@ -750,7 +770,9 @@ func (procgrp *processGroup) stop(cctx *proc.ContinueOnceContext, trapthread *na
 			th.os.setbp = false
 		}
 	}
-	trapthread.os.setbp = true
+	// a thread stopped by a signal that was reported because of its policy
+	// is not stopped on a breakpoint.
+	trapthread.os.setbp = trapthread.Common().Signal == 0
 
 	// check if any other thread simultaneously received a SIGTRAP
 	for {
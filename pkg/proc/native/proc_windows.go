@@ -63,7 +63,7 @@ func Launch(cmd []string, wd string, flags proc.LaunchFlags, _ []string, _ strin
 	dbp.pid = p.Pid
 	dbp.childProcess = true
 
-	tgt, err := dbp.initialize(argv0Go, []string{})
+	tgt, err := dbp.initialize(argv0Go, []string{}, "")
 	if err != nil {
 		detachWithoutGroup(dbp, true)
 		return nil, err
@@ -143,7 +143,7 @@ func findExePath(pid int) (string, error) {
 var debugPrivilegeRequested = false
 
 // Attach to an existing process with the given PID.
-func Attach(pid int, waitFor *proc.WaitFor, _ []string) (*proc.TargetGroup, error) {
+func Attach(pid int, waitFor *proc.WaitFor, _ []string, _ string) (*proc.TargetGroup, error) {
 	var aperr error
 	if !debugPrivilegeRequested {
 		debugPrivilegeRequested = true
@@ -178,7 +178,7 @@ func Attach(pid int, waitFor *proc.WaitFor, _ []string) (*proc.TargetGroup, erro
 	if err != nil {
 		return nil, err
 	}
-	tgt, err := dbp.initialize(exepath, []string{})
+	tgt, err := dbp.initialize(exepath, []string{}, "")
 	if err != nil {
 		detachWithoutGroup(dbp, true)
 		return nil, err
@@ -491,6 +491,9 @@ func (procgrp *processGroup) waitForDebugEvent(flags waitForDebugEventFlags) (th
 				// mask it or it might crash the program.
 				continueStatus = _DBG_CONTINUE
 			default:
+				if thread, found := dbp.threads[tid]; found {
+					thread.Common().Exception = convertExceptionRecord(&exception.ExceptionRecord, exception.FirstChance != 0)
+				}
 				continueStatus = _DBG_EXCEPTION_NOT_HANDLED
 			}
 		case _EXIT_PROCESS_DEBUG_EVENT:
@@ -516,6 +519,47 @@ func (procgrp *processGroup) waitForDebugEvent(flags waitForDebugEventFlags) (th
 	}
 }
 
+// convertExceptionRecord converts an exception record received with an
+// EXCEPTION_DEBUG_EVENT into a proc.ExceptionInfo.
+func convertExceptionRecord(rec *_EXCEPTION_RECORD, firstChance bool) *proc.ExceptionInfo {
+	r := &proc.ExceptionInfo{
+		Code:        rec.ExceptionCode,
+		Address:     uint64(rec.ExceptionAddress),
+		FirstChance: firstChance,
+	}
+	switch rec.ExceptionCode {
+	case _EXCEPTION_ACCESS_VIOLATION, _EXCEPTION_IN_PAGE_ERROR:
+		r.Description = "access violation"
+		if rec.ExceptionCode == _EXCEPTION_IN_PAGE_ERROR {
+			r.Description = "in page error"
+		}
+		// See the documentation of EXCEPTION_RECORD, the first parameter is
+		// the type of access and the second one the inaccessible address.
+		if rec.NumberParameters >= 2 {
+			switch rec.ExceptionInformation[0] {
+			case 0:
+				r.Operation = "read"
+			case 1:
+				r.Operation = "write"
+			case 8:
+				r.Operation = "execute"
+			}
+			r.FaultAddress = uint64(rec.ExceptionInformation[1])
+		}
+	case _EXCEPTION_ILLEGAL_INSTRUCTION:
+		r.Description = "illegal instruction"
+	case _EXCEPTION_INT_DIVIDE_BY_ZERO:
+		r.Description = "integer divide by zero"
+	case _EXCEPTION_INT_OVERFLOW:
+		r.Description = "integer overflow"
+	case _EXCEPTION_STACK_OVERFLOW:
+		r.Description = "stack overflow"
+	case _EXCEPTION_ARRAY_BOUNDS_EXCEEDED:
+		r.Description = "array bounds exceeded"
+	}
+	return r
+}
+
 func trapWait(procgrp *processGroup, pid int) (*nativeThread, error) {
 	var err error
 	var tid int
@@ -102,6 +102,14 @@ const (
 	_EXCEPTION_BREAKPOINT  = 0x80000003
 	_EXCEPTION_SINGLE_STEP = 0x80000004
 
+	_EXCEPTION_ACCESS_VIOLATION      = 0xc0000005
+	_EXCEPTION_IN_PAGE_ERROR         = 0xc0000006
+	_EXCEPTION_ILLEGAL_INSTRUCTION   = 0xc000001d
+	_EXCEPTION_INT_DIVIDE_BY_ZERO    = 0xc0000094
+	_EXCEPTION_INT_OVERFLOW          = 0xc0000095
+	_EXCEPTION_STACK_OVERFLOW        = 0xc00000fd
+	_EXCEPTION_ARRAY_BOUNDS_EXCEEDED = 0xc000008c
+
 	_EXCEPTION_MAXIMUM_PARAMETERS = 15
 
 	_MEM_FREE    = 0x10000
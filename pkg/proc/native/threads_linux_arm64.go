@@ -148,10 +148,18 @@ func (t *nativeThread) writeHardwareBreakpoint(addr uint64, wtype proc.WatchType
 	if err != nil {
 		return err
 	}
+	if wpstate.num == 0 {
+		return errors.New("hardware watchpoints not supported by this CPU")
+	}
 	if idx >= wpstate.num {
 		return errors.New("hardware breakpoints exhausted")
 	}
 
+	sz := uint64(wtype.Size())
+	if err := checkWatchpointRange(addr, sz); err != nil {
+		return err
+	}
+
 	const (
 		readBreakpoint  = 0x1
 		writeBreakpoint = 0x2
@@ -168,7 +176,7 @@ func (t *nativeThread) writeHardwareBreakpoint(addr uint64, wtype proc.WatchType
 		typ |= writeBreakpoint
 	}
 
-	len := uint64((1 << wtype.Size()) - 1) // arm wants the length expressed as address bitmask
+	len := uint64((1 << sz) - 1) // arm wants the length expressed as address bitmask
 
 	priv := uint64(3)
 
@@ -178,6 +186,25 @@ func (t *nativeThread) writeHardwareBreakpoint(addr uint64, wtype proc.WatchType
 	return t.setWatchpoints(wpstate)
 }
 
+// checkWatchpointRange returns an error if a watchpoint of sz bytes at addr
+// can not be expressed with a single hardware watchpoint.
+// DBGWVRn_EL1 holds a doubleword aligned address and the BAS field of
+// DBGWCRn_EL1 selects contiguous bytes inside that doubleword, the kernel
+// aligns the address we pass down and shifts BAS by the offset. Therefore
+// the watched range must not cross a doubleword boundary.
+func checkWatchpointRange(addr, sz uint64) error {
+	switch sz {
+	case 1, 2, 4, 8:
+		// ok
+	default:
+		return fmt.Errorf("watchpoint size %d not supported by this CPU", sz)
+	}
+	if addr%8+sz > 8 {
+		return fmt.Errorf("watchpoint at %#x (%d bytes) crosses a doubleword boundary", addr, sz)
+	}
+	return nil
+}
+
 func (t *nativeThread) clearHardwareBreakpoint(addr uint64, wtype proc.WatchType, idx uint8) error {
 	wpstate, err := t.getWatchpoints()
 	if err != nil {
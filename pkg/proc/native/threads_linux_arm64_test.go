@@ -0,0 +1,27 @@
+package native
+
+import "testing"
+
+func TestCheckWatchpointRange(t *testing.T) {
+	tests := []struct {
+		addr, sz uint64
+		ok       bool
+	}{
+		{0x1000, 8, true},
+		{0x1004, 4, true},
+		{0x1006, 2, true},
+		{0x1007, 1, true},
+		{0x1003, 4, true},
+		{0x1005, 4, false},
+		{0x1004, 8, false},
+		{0x1007, 2, false},
+		{0x1000, 3, false},
+		{0x1000, 16, false},
+	}
+	for _, tc := range tests {
+		err := checkWatchpointRange(tc.addr, tc.sz)
+		if (err == nil) != tc.ok {
+			t.Errorf("checkWatchpointRange(%#x, %d): got error %v, expected ok=%v", tc.addr, tc.sz, err, tc.ok)
+		}
+	}
+}
@@ -3,13 +3,17 @@ package proc
 import (
 	"debug/elf"
 	"debug/macho"
+	"encoding/binary"
 	"errors"
 	"fmt"
 
 	"github.com/go-delve/delve/pkg/internal/gosym"
 )
 
-func readPcLnTableElf(exe *elf.File, path string) (*gosym.Table, uint64, error) {
+// readPcLnTableElf reads the pclntab of exe. Function addresses in the
+// pclntab are relative to textStart, if it is zero the start of the .text
+// section is used.
+func readPcLnTableElf(exe *elf.File, path string, textStart uint64) (*gosym.Table, uint64, error) {
 	// Default section label is .gopclntab
 	sectionLabel := ".gopclntab"
 
@@ -27,7 +31,10 @@ func readPcLnTableElf(exe *elf.File, path string) (*gosym.Table, uint64, error)
 		return nil, 0, errors.New("found section but could not read .gopclntab")
 	}
 
-	addr := exe.Section(".text").Addr
+	addr := textStart
+	if addr == 0 {
+		addr = exe.Section(".text").Addr
+	}
 	lineTable := gosym.NewLineTable(tableData, addr)
 	symTable, err := gosym.NewTable([]byte{}, lineTable)
 	if err != nil {
@@ -36,6 +43,34 @@ func readPcLnTableElf(exe *elf.File, path string) (*gosym.Table, uint64, error)
 	return symTable, section.Addr, nil
 }
 
+// findTextStart returns the address of runtime.text, which function
+// addresses in the pclntab are relative to, by looking for the text field
+// of runtime.moduledata. In externally linked binaries, which includes all
+// plugins, C code is placed before runtime.text at the start of the .text
+// section.
+func findTextStart(moduleData []byte, noptrdataAddr uint64, ptrsize int) (uint64, bool) {
+	// Layout of struct members is:
+	// type moduledata struct {
+	// 	...
+	// 	text, etext           uintptr
+	// 	noptrdata, enoptrdata uintptr
+	// 	...
+	// }
+	// and the value of noptrdata is the address of the .noptrdata section.
+	word := func(off int) uint64 {
+		if ptrsize == 4 {
+			return uint64(binary.LittleEndian.Uint32(moduleData[off:]))
+		}
+		return binary.LittleEndian.Uint64(moduleData[off:])
+	}
+	for off := 2 * ptrsize; off+ptrsize <= len(moduleData); off += ptrsize {
+		if word(off) == noptrdataAddr {
+			return word(off - 2*ptrsize), true
+		}
+	}
+	return 0, false
+}
+
 func readPcLnTableMacho(exe *macho.File, path string) (*gosym.Table, uint64, error) {
 	// Default section label is __gopclntab
 	sectionLabel := "__gopclntab"
@@ -4,6 +4,7 @@ import (
 	"go/constant"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/go-delve/delve/pkg/dwarf/regnum"
@@ -114,7 +115,7 @@ func TestCallInjectionFlagCorruption(t *testing.T) {
 		t.Logf("rflags before = %#x", rflagsBeforeCall)
 
 		// Inject call to main.g()
-		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "g()", normalLoadConfig, true), t, "Call")
+		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "g()", normalLoadConfig, proc.EvalConfig{CheckEscape: true}), t, "Call")
 
 		// Check RFLAGS register after the call
 		rflagsAfterCall := p.BinInfo().Arch.RegistersToDwarfRegisters(0, getRegisters(p, t)).Uint64Val(regnum.AMD64_Rflags)
@@ -129,3 +130,34 @@ func TestCallInjectionFlagCorruption(t *testing.T) {
 		assertLineNumber(p, t, 17, "expected line :17") // since we passed "0" as argument we should be going into the false branch at line :17
 	})
 }
+
+func TestDisassemblePLTStubs(t *testing.T) {
+	// Calls to PLT stubs should be resolved to the name of the dynamically
+	// linked function they jump to.
+	if runtime.GOOS != "linux" {
+		t.Skip("PLT stubs are only resolved for ELF executables")
+	}
+	protest.MustHaveCgo(t)
+	withTestProcess("cgotest", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		found := false
+		for _, fn := range p.BinInfo().Functions {
+			if !strings.HasPrefix(fn.Name, "C.") || fn.Entry == 0 {
+				continue
+			}
+			text, err := proc.Disassemble(p.Memory(), nil, p.Breakpoints(), p.BinInfo(), fn.Entry, fn.End)
+			assertNoError(err, t, "Disassemble")
+			for _, instr := range text {
+				if !instr.IsCall() || !strings.HasSuffix(instr.DestSymbol, "@plt") {
+					continue
+				}
+				found = true
+				if txt := instr.Text(proc.IntelFlavour, p.BinInfo()); !strings.Contains(txt, instr.DestSymbol) {
+					t.Errorf("%#x: PLT stub name %q not used in instruction text %q", instr.Loc.PC, instr.DestSymbol, txt)
+				}
+			}
+		}
+		if !found {
+			t.Error("no call to a PLT stub resolved")
+		}
+	})
+}
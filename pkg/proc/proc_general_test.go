@@ -1,10 +1,15 @@
 package proc
 
 import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 	"unsafe"
 
 	protest "github.com/go-delve/delve/pkg/proc/test"
@@ -134,7 +139,7 @@ func TestDwarfVersion(t *testing.T) {
 	bi := NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
 	// Use a fake entry point so LoadBinaryInfo does not error in case the binary is PIE.
 	const fakeEntryPoint = 1
-	assertNoError(bi.LoadBinaryInfo(fixture.Path, fakeEntryPoint, nil), t, "LoadBinaryInfo")
+	assertNoError(bi.LoadBinaryInfo(fixture.Path, fakeEntryPoint, nil, ""), t, "LoadBinaryInfo")
 	for _, cu := range bi.Images[0].compileUnits {
 		if cu.Version != 4 && cu.Version != 5 {
 			t.Errorf("compile unit %q at %#x has bad version %d", cu.name, cu.entry.Offset, cu.Version)
@@ -151,7 +156,7 @@ func TestRegabiFlagSentinel(t *testing.T) {
 	bi := NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
 	// Use a fake entry point so LoadBinaryInfo does not error in case the binary is PIE.
 	const fakeEntryPoint = 1
-	assertNoError(bi.LoadBinaryInfo(fixture.Path, fakeEntryPoint, nil), t, "LoadBinaryInfo")
+	assertNoError(bi.LoadBinaryInfo(fixture.Path, fakeEntryPoint, nil, ""), t, "LoadBinaryInfo")
 	if !bi.regabi {
 		t.Errorf("regabi flag not set %s GOEXPERIMENT=%s", runtime.Version(), os.Getenv("GOEXPERIMENT"))
 	}
@@ -306,3 +311,91 @@ func TestGenericFunctionNameWithoutParameters(t *testing.T) {
 		}
 	}
 }
+
+func TestMachoUniversalBinary(t *testing.T) {
+	// Builds a universal binary containing an amd64 and an arm64 slice, like
+	// 'lipo -create' would, and checks that the slice matching the
+	// architecture of the target is loaded.
+	fixturesDir := protest.FindFixturesDir()
+	tmpdir := t.TempDir()
+	arches := []string{"amd64", "arm64"}
+	thin := make([]string, len(arches))
+	for i, arch := range arches {
+		thin[i] = filepath.Join(tmpdir, "testnextprog."+arch)
+		cmd := exec.Command("go", "build", "-gcflags=all=-N -l", "-o", thin[i], filepath.Join(fixturesDir, "testnextprog.go"))
+		cmd.Env = append(os.Environ(), "GOOS=darwin", "GOARCH="+arch, "CGO_ENABLED=0")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("could not build %s fixture: %v\n%s", arch, err, out)
+		}
+	}
+
+	const align = 14
+	var hdr, body bytes.Buffer
+	binary.Write(&hdr, binary.BigEndian, []uint32{macho.MagicFat, uint32(len(thin))})
+	off := uint32(1 << align)
+	for i := range thin {
+		exe, err := macho.Open(thin[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		cpu, subcpu := exe.Cpu, exe.SubCpu
+		exe.Close()
+		buf, err := os.ReadFile(thin[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		binary.Write(&hdr, binary.BigEndian, []uint32{uint32(cpu), subcpu, off, uint32(len(buf)), align})
+		body.Write(make([]byte, int(off)-(1<<align)-body.Len()))
+		body.Write(buf)
+		off += (uint32(len(buf)) + (1 << align) - 1) &^ (1<<align - 1)
+	}
+	hdr.Write(make([]byte, (1<<align)-hdr.Len()))
+	fat := filepath.Join(tmpdir, "testnextprog.universal")
+	if err := os.WriteFile(fat, append(hdr.Bytes(), body.Bytes()...), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	mainEntry := func(path, arch string) uint64 {
+		t.Helper()
+		bi := NewBinaryInfo("darwin", arch)
+		defer bi.Close()
+		if err := bi.LoadBinaryInfo(path, 0, nil, ""); err != nil {
+			t.Fatalf("LoadBinaryInfo(%s, %s): %v", path, arch, err)
+		}
+		fns := bi.LookupFunc()["main.main"]
+		if len(fns) != 1 {
+			t.Fatalf("main.main not found in %s (%s)", path, arch)
+		}
+		return fns[0].Entry
+	}
+
+	for i, arch := range arches {
+		if fatEntry, thinEntry := mainEntry(fat, arch), mainEntry(thin[i], arch); fatEntry != thinEntry {
+			t.Errorf("wrong slice loaded for %s: main.main at %#x, expected %#x", arch, fatEntry, thinEntry)
+		}
+	}
+
+	bi := NewBinaryInfo("darwin", "386")
+	defer bi.Close()
+	if err := bi.LoadBinaryInfo(fat, 0, nil, ""); err == nil {
+		t.Errorf("loading universal binary without a matching slice did not fail")
+	}
+}
+
+func TestGoroutineBlockedFor(t *testing.T) {
+	for _, tc := range []struct {
+		g    G
+		now  int64
+		want time.Duration
+	}{
+		{G{Status: Gwaiting, WaitSince: 100}, 150, 50},
+		{G{Status: Gsyscall, WaitSince: 100}, 150, 50},
+		{G{Status: Grunning, WaitSince: 100}, 150, 0},
+		{G{Status: Gwaiting, WaitSince: 0}, 150, 0},
+		{G{Status: Gwaiting, WaitSince: 200}, 150, 0},
+	} {
+		if got := tc.g.BlockedFor(tc.now); got != tc.want {
+			t.Errorf("BlockedFor(%d) for status %d waitsince %d: got %v, want %v", tc.now, tc.g.Status, tc.g.WaitSince, got, tc.want)
+		}
+	}
+}
@@ -58,11 +58,11 @@ func TestGnuDebuglink(t *testing.T) {
 
 	// open original executable
 	normalBinInfo := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
-	assertNoError(normalBinInfo.LoadBinaryInfo(fixture.Path, 0, []string{"/debugdir"}), t, "LoadBinaryInfo (normal exe)")
+	assertNoError(normalBinInfo.LoadBinaryInfo(fixture.Path, 0, []string{"/debugdir"}, ""), t, "LoadBinaryInfo (normal exe)")
 
 	// open .gnu_debuglink executable
 	debuglinkBinInfo := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
-	assertNoError(debuglinkBinInfo.LoadBinaryInfo(debuglinkPath, 0, []string{"/debugdir"}), t, "LoadBinaryInfo (gnu_debuglink exe)")
+	assertNoError(debuglinkBinInfo.LoadBinaryInfo(debuglinkPath, 0, []string{"/debugdir"}, ""), t, "LoadBinaryInfo (gnu_debuglink exe)")
 
 	if len(normalBinInfo.Functions) != len(debuglinkBinInfo.Functions) {
 		t.Fatalf("function list mismatch")
@@ -77,6 +77,108 @@ func TestGnuDebuglink(t *testing.T) {
 	}
 }
 
+func TestDebugInfoFile(t *testing.T) {
+	mustHaveObjcopy(t)
+	fixture := protest.BuildFixture(t, "math", 0)
+	otherFixture := protest.BuildFixture(t, "testnextprog", 0)
+
+	// make a stripped copy of the executable
+	strippedPath := fixture.Path + "-stripped"
+	cmd := exec.Command("objcopy", "--strip-debug", fixture.Path, strippedPath)
+	out, err := cmd.CombinedOutput()
+	assertNoError(err, t, fmt.Sprintf("%s: %s", cmd, out))
+	defer os.Remove(strippedPath)
+
+	normalBinInfo := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
+	assertNoError(normalBinInfo.LoadBinaryInfo(fixture.Path, 0, nil, ""), t, "LoadBinaryInfo (normal exe)")
+
+	strippedBinInfo := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
+	assertNoError(strippedBinInfo.LoadBinaryInfo(strippedPath, 0, nil, fixture.Path), t, "LoadBinaryInfo (stripped exe)")
+
+	if len(normalBinInfo.Functions) != len(strippedBinInfo.Functions) {
+		t.Fatalf("function list mismatch")
+	}
+	if pcs := strippedBinInfo.AllPCsForFileLines(fixture.Source, []int{7}); len(pcs[7]) == 0 {
+		t.Fatalf("could not find %s:7 using separate debug info", fixture.Source)
+	}
+
+	mismatchBinInfo := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
+	err = mismatchBinInfo.LoadBinaryInfo(strippedPath, 0, nil, otherFixture.Path)
+	if err == nil || !strings.Contains(err.Error(), "build ID mismatch") {
+		t.Fatalf("expected build ID mismatch error, got %v", err)
+	}
+}
+
+func TestSeparateDebugInfoBuildIDCheck(t *testing.T) {
+	// A file found through the build ID method whose build ID doesn't match
+	// the build ID of the executable must not be used.
+	mustHaveObjcopy(t)
+	fixture := protest.BuildFixture(t, "math", 0)
+	otherFixture := protest.BuildFixture(t, "testvariables2", 0)
+
+	normalBinInfo := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
+	assertNoError(normalBinInfo.LoadBinaryInfo(fixture.Path, 0, nil, ""), t, "LoadBinaryInfo (normal exe)")
+	buildID := normalBinInfo.Images[0].BuildID
+	if len(buildID) <= 2 {
+		t.Skip("executable has no build ID")
+	}
+
+	debugDir := t.TempDir()
+	strippedPath := filepath.Join(debugDir, "math-stripped")
+	cmd := exec.Command("objcopy", "--strip-debug", fixture.Path, strippedPath)
+	out, err := cmd.CombinedOutput()
+	assertNoError(err, t, fmt.Sprintf("%s: %s", cmd, out))
+
+	buildIDDir := filepath.Join(debugDir, ".build-id", buildID[:2])
+	assertNoError(os.MkdirAll(buildIDDir, 0o755), t, "MkdirAll")
+	buf, err := os.ReadFile(otherFixture.Path)
+	assertNoError(err, t, "ReadFile")
+	assertNoError(os.WriteFile(filepath.Join(buildIDDir, buildID[2:]+".debug"), buf, 0o644), t, "WriteFile")
+
+	bi := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
+	assertNoError(bi.LoadBinaryInfo(strippedPath, 0, []string{debugDir}, ""), t, "LoadBinaryInfo (stripped exe)")
+	if _, err := bi.FindType("main.astruct"); err == nil {
+		t.Fatal("debug info with the wrong build ID was loaded")
+	}
+}
+
+func TestSeparateDebugInfoGoBuildIDCheck(t *testing.T) {
+	// When the executable has no GNU build ID a file found through its name
+	// must not be used if its Go build ID doesn't match.
+	mustHaveObjcopy(t)
+	fixture := protest.BuildFixture(t, "math", 0)
+	otherFixture := protest.BuildFixture(t, "testvariables2", 0)
+
+	debugDir := t.TempDir()
+	strippedPath := filepath.Join(debugDir, "math-stripped")
+	cmd := exec.Command("objcopy", "--strip-debug", "--remove-section=.note.gnu.build-id", fixture.Path, strippedPath)
+	out, err := cmd.CombinedOutput()
+	assertNoError(err, t, fmt.Sprintf("%s: %s", cmd, out))
+
+	buf, err := os.ReadFile(otherFixture.Path)
+	assertNoError(err, t, "ReadFile")
+	assertNoError(os.WriteFile(filepath.Join(debugDir, "math-stripped.debug"), buf, 0o644), t, "WriteFile")
+
+	bi := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
+	bi.LoadBinaryInfo(strippedPath, 0, []string{debugDir}, "")
+	if bi.Images[0].BuildID != "" {
+		t.Fatalf("executable still has a GNU build ID")
+	}
+	if _, err := bi.FindType("main.astruct"); err == nil {
+		t.Fatal("debug info with the wrong Go build ID was loaded")
+	}
+
+	buf, err = os.ReadFile(fixture.Path)
+	assertNoError(err, t, "ReadFile")
+	assertNoError(os.WriteFile(filepath.Join(debugDir, "math-stripped.debug"), buf, 0o644), t, "WriteFile")
+
+	bi = proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
+	assertNoError(bi.LoadBinaryInfo(strippedPath, 0, []string{debugDir}, ""), t, "LoadBinaryInfo (stripped exe)")
+	if pcs := bi.AllPCsForFileLines(fixture.Source, []int{7}); len(pcs[7]) == 0 {
+		t.Fatalf("could not find %s:7 using separate debug info", fixture.Source)
+	}
+}
+
 func stripAndCopyDebugInfo(f protest.Fixture, t *testing.T) {
 	name := filepath.Base(f.Path)
 	// Copy the debug information to an external file.
@@ -40,7 +40,7 @@ import (
 	"github.com/go-delve/delve/service/api"
 )
 
-var normalLoadConfig = proc.LoadConfig{true, 1, 64, 64, -1, 0}
+var normalLoadConfig = proc.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, MaxStructFields: -1}
 var testBackend, buildMode string
 
 func init() {
@@ -360,6 +360,34 @@ func TestNextInstruction(t *testing.T) {
 	})
 }
 
+func TestStepInstructions(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("testprog", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFileBreakpoint(p, t, fixture.Source, 19)
+		assertNoError(grp.Continue(), t, "Continue()")
+
+		for range 3 {
+			assertNoError(grp.StepInstruction(false), t, "StepInstruction()")
+		}
+		pc := currentPC(p, t)
+
+		assertNoError(grp.Continue(), t, "Continue()")
+		assertLineNumber(p, t, 19, "wrong line after continue")
+		assertNoError(grp.StepInstructions(false, 3), t, "StepInstructions()")
+		if pc2 := currentPC(p, t); pc2 != pc {
+			t.Errorf("StepInstructions(3) stopped at %#x, three StepInstruction calls stopped at %#x", pc2, pc)
+		}
+
+		// Stepping stops early when a breakpoint is reached
+		setFileBreakpoint(p, t, fixture.Source, 20)
+		assertNoError(grp.StepInstructions(true, 1000), t, "StepInstructions()")
+		assertLineNumber(p, t, 20, "StepInstructions did not stop at breakpoint")
+		if p.StopReason != proc.StopBreakpoint {
+			t.Errorf("wrong stop reason %v", p.StopReason)
+		}
+	})
+}
+
 func TestBreakpoint(t *testing.T) {
 	protest.AllowRecording(t)
 	withTestProcess("testprog", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
@@ -523,6 +551,43 @@ func TestNextConcurrentVariant2(t *testing.T) {
 	})
 }
 
+func TestNextSingleGoroutineStepping(t *testing.T) {
+	// Like TestNextConcurrentVariant2 but with SingleGoroutineStepping set
+	// the breakpoint on main.sayhi should never be reported, or counted as
+	// hit, while nexting.
+	testcases := []nextTest{
+		{8, 9},
+		{9, 10},
+		{10, 11},
+	}
+	protest.AllowRecording(t)
+	withTestProcess("parallel_next", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		bp := setFunctionBreakpoint(p, t, "main.sayhi")
+		assertNoError(grp.Continue(), t, "Continue")
+		grp.SingleGoroutineStepping = true
+		hitCount := bp.Logical.TotalHitCount
+		initV := evalVariable(p, t, "n")
+		initVval, _ := constant.Int64Val(initV.Value)
+		for _, tc := range testcases {
+			t.Logf("test case %v", tc)
+			assertLineNumber(p, t, tc.begin, "Program not stopped at correct spot")
+			assertNoError(grp.Next(), t, "Next() returned an error")
+			if bpstate := p.CurrentThread().Breakpoint(); bpstate.Breakpoint != nil {
+				t.Fatalf("breakpoint hit during next: %#v", bpstate.Breakpoint)
+			}
+			v := evalVariable(p, t, "n")
+			vval, _ := constant.Int64Val(v.Value)
+			if vval != initVval {
+				t.Fatal("Did not end up on same goroutine")
+			}
+			assertLineNumber(p, t, tc.end, "Program did not continue to the expected location")
+		}
+		if bp.Logical.TotalHitCount != hitCount {
+			t.Errorf("breakpoint hits counted while stepping: %d (expected %d)", bp.Logical.TotalHitCount, hitCount)
+		}
+	})
+}
+
 func TestNextNetHTTP(t *testing.T) {
 	testcases := []nextTest{
 		{14, 15},
@@ -763,6 +828,87 @@ func TestStacktrace(t *testing.T) {
 	})
 }
 
+func TestStacktraceMaxDepth(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("deepstack", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+		grp.MaxStackDepth = 50
+		g, err := proc.GetG(p.CurrentThread())
+		assertNoError(err, t, "GetG()")
+
+		// unwinding the whole stack stops at the maximum depth
+		frames, err := proc.UnboundedStacktrace(p, g)
+		var errTooDeep proc.ErrStackTooDeep
+		if !errors.As(err, &errTooDeep) || errTooDeep.MaxDepth != 50 {
+			t.Fatalf("wrong error: %v", err)
+		}
+		if len(frames) != 50 {
+			t.Fatalf("wrong number of frames %d", len(frames))
+		}
+
+		// stacktraces requested with a greater depth end with the marker frame
+		frames, err = proc.GoroutineStacktrace(p, g, 100, 0)
+		assertNoError(err, t, "Stacktrace()")
+		if len(frames) != 51 {
+			t.Fatalf("wrong number of frames %d", len(frames))
+		}
+		if !errors.As(frames[50].Err, &errTooDeep) || errTooDeep.MaxDepth != 50 {
+			t.Fatalf("wrong error for last frame: %v", frames[50].Err)
+		}
+		for i := range frames[:50] {
+			if frames[i].Err != nil {
+				t.Fatalf("unexpected error for frame %d: %v", i, frames[i].Err)
+			}
+		}
+
+		frames, err = proc.GoroutineStacktrace(p, g, 20, 0)
+		assertNoError(err, t, "Stacktrace()")
+		if len(frames) != 21 || frames[20].Err != nil {
+			t.Fatalf("wrong stacktrace below the maximum depth: %d frames, last error %v", len(frames), frames[len(frames)-1].Err)
+		}
+	})
+}
+
+func TestStacktraceFramePointers(t *testing.T) {
+	if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
+		t.Skip("frame pointer unwinding not supported")
+	}
+	protest.AllowRecording(t)
+	withTestProcess("stacktraceprog", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFunctionBreakpoint(p, t, "main.stacktraceme")
+		for range 2 {
+			assertNoError(grp.Continue(), t, "Continue()")
+			gs, _, err := proc.GoroutinesInfo(p, 0, 0)
+			assertNoError(err, t, "GoroutinesInfo")
+			for _, g := range gs {
+				frames, err := proc.GoroutineStacktrace(p, g, 50, 0)
+				assertNoError(err, t, "GoroutineStacktrace")
+				fastFrames, fpFrames, err := proc.FramePointerStacktrace(p, g, 50)
+				assertNoError(err, t, "GoroutineStacktrace (frame pointers)")
+				if len(fastFrames) > 2 && fpFrames == 0 {
+					logStacktrace(t, p, fastFrames)
+					t.Errorf("goroutine %d: no frame was unwound using frame pointers", g.ID)
+				}
+				if len(frames) != len(fastFrames) {
+					logStacktrace(t, p, frames)
+					logStacktrace(t, p, fastFrames)
+					t.Fatalf("goroutine %d: different number of frames", g.ID)
+				}
+				for i := range frames {
+					if frames[i].Call.PC != fastFrames[i].Call.PC || frames[i].Regs.CFA != fastFrames[i].Regs.CFA {
+						logStacktrace(t, p, frames)
+						logStacktrace(t, p, fastFrames)
+						t.Fatalf("goroutine %d: mismatch at frame %d", g.ID, i)
+					}
+					if fastFrames[i].FramePointerFallback != "" {
+						t.Errorf("goroutine %d: unexpected fallback at frame %d: %s", g.ID, i, fastFrames[i].FramePointerFallback)
+					}
+				}
+			}
+		}
+	})
+}
+
 func TestStacktrace2(t *testing.T) {
 	withTestProcess("retstack", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
 		assertNoError(grp.Continue(), t, "Continue()")
@@ -1868,6 +2014,29 @@ func TestPanicBreakpoint(t *testing.T) {
 	})
 }
 
+func TestExceptionInfo(t *testing.T) {
+	skipUnlessOn(t, "only implemented on windows", "windows")
+	withTestProcess("nilptrderef", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+		bp := p.CurrentThread().Breakpoint()
+		if bp.Breakpoint == nil || bp.Logical.Name != proc.UnrecoveredPanic {
+			t.Fatalf("not on unrecovered-panic breakpoint: %v", bp)
+		}
+		var e *proc.ExceptionInfo
+		for _, th := range p.ThreadList() {
+			if th.Common().Exception != nil {
+				e = th.Common().Exception
+			}
+		}
+		if e == nil {
+			t.Fatal("no exception recorded")
+		}
+		if e.Code != 0xc0000005 || e.Operation != "read" || e.FaultAddress != 8 || !e.FirstChance {
+			t.Fatalf("wrong exception info %#v", e)
+		}
+	})
+}
+
 func TestCmdLineArgs(t *testing.T) {
 	expectSuccess := func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
 		err := grp.Continue()
@@ -2181,6 +2350,25 @@ func TestGoroutineLabels(t *testing.T) {
 	})
 }
 
+func TestBreakpointGoroutineLabels(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("bplabels", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		bp := setFunctionBreakpoint(p, t, "main.handler")
+		bp.Logical.GoroutineLabels = map[string]string{"service": "payments"}
+		assertNoError(grp.Continue(), t, "Continue()")
+		if n := evalVariable(p, t, "n"); n.Value.String() != "2" {
+			t.Fatalf("wrong value of n: %s", n.Value)
+		}
+		if bp.Logical.TotalHitCount != 1 {
+			t.Fatalf("wrong hit count %d", bp.Logical.TotalHitCount)
+		}
+		err := grp.Continue()
+		if _, exited := err.(proc.ErrProcessExited); !exited {
+			t.Fatalf("expected process to exit, got %v", err)
+		}
+	})
+}
+
 func TestStepOut(t *testing.T) {
 	testseq2(t, "testnextprog", "main.helloworld", []seqTest{{contContinue, 13}, {contStepout, 35}})
 }
@@ -2476,6 +2664,13 @@ func TestIssue594(t *testing.T) {
 		if ln != 21 {
 			t.Fatalf("Program stopped at %s:%d, expected :21", f, ln)
 		}
+		// The exception caused by the recovered nil pointer dereference must
+		// not be reported.
+		for _, th := range p.ThreadList() {
+			if e := th.Common().Exception; e != nil {
+				t.Errorf("thread %d has a stale exception %#v", th.ThreadID(), e)
+			}
+		}
 	})
 }
 
@@ -2567,7 +2762,7 @@ func BenchmarkTrace(b *testing.B) {
 			assertNoError(grp.Continue(), b, "Continue()")
 			s, err := proc.GoroutineScope(p, p.CurrentThread())
 			assertNoError(err, b, "Scope()")
-			_, err = s.FunctionArguments(proc.LoadConfig{false, 0, 64, 0, 3, 0})
+			_, err = s.FunctionArguments(proc.LoadConfig{MaxStringLen: 64, MaxStructFields: 3})
 			assertNoError(err, b, "FunctionArguments()")
 		}
 		b.StopTimer()
@@ -2669,7 +2864,7 @@ func TestAttachDetach(t *testing.T) {
 
 	switch testBackend {
 	case "native":
-		p, err = native.Attach(cmd.Process.Pid, nil, []string{})
+		p, err = native.Attach(cmd.Process.Pid, nil, []string{}, "")
 	case "lldb":
 		path := ""
 		if runtime.GOOS == "darwin" {
@@ -3582,6 +3777,57 @@ func TestAllPCsForFileLines(t *testing.T) {
 	})
 }
 
+func TestStackframeExecutesPC(t *testing.T) {
+	// Stackframe.ExecutesPC must distinguish the instructions of a function
+	// from the instructions of the calls inlined into it.
+	withTestProcessArgs("testinline", t, ".", []string{}, protest.EnableInlining, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		mainfn := p.BinInfo().LookupFunc()["main.main"][0]
+		findPCs := func(line int) []uint64 {
+			t.Helper()
+			pcs, err := proc.FindFileLocation(p, fixture.Source, line)
+			assertNoError(err, t, "FindFileLocation")
+			r := []uint64{}
+			for _, pc := range pcs {
+				if p.BinInfo().PCToFunc(pc) == mainfn {
+					r = append(r, pc)
+				}
+			}
+			return r
+		}
+		inlpcs := findPCs(7)
+		if len(inlpcs) < 2 {
+			t.Fatalf("expected at least two inlined locations for %s:7 (got %#x)", fixture.Source, inlpcs)
+		}
+		mainpcs := findPCs(20)
+		if len(mainpcs) < 1 {
+			t.Fatalf("no locations for %s:20", fixture.Source)
+		}
+
+		_, err := p.SetBreakpoint(0, inlpcs[0], proc.UserBreakpoint, nil)
+		assertNoError(err, t, "SetBreakpoint")
+		assertNoError(grp.Continue(), t, "Continue")
+		frames, err := proc.ThreadStacktrace(p, p.CurrentThread(), 1)
+		assertNoError(err, t, "ThreadStacktrace")
+		if !frames[0].Inlined || frames[1].Inlined {
+			t.Fatalf("unexpected stacktrace %v", frames)
+		}
+
+		check := func(frame int, pc uint64, tgt bool) {
+			t.Helper()
+			ok, err := frames[frame].ExecutesPC(pc)
+			assertNoError(err, t, "ExecutesPC")
+			if ok != tgt {
+				t.Errorf("frame %d ExecutesPC(%#x) = %v, expected %v", frame, pc, ok, tgt)
+			}
+		}
+		check(0, inlpcs[0], true)
+		check(0, inlpcs[1], false)
+		check(0, mainpcs[0], false)
+		check(1, inlpcs[0], false)
+		check(1, mainpcs[0], true)
+	})
+}
+
 func TestInlinedStacktraceAndVariables(t *testing.T) {
 	if ver, _ := goversion.Parse(runtime.Version()); ver.Major >= 0 && !ver.AfterOrEqual(goversion.GoVersion{Major: 1, Minor: 10, Rev: -1}) {
 		// Versions of go before 1.10 do not have DWARF information for inlined calls
@@ -3701,6 +3947,53 @@ func TestInlinedStacktraceAndVariables(t *testing.T) {
 	})
 }
 
+func TestInlinedFrameScope(t *testing.T) {
+	// Variables must be resolved against the lexical blocks of the inlined
+	// function of the selected frame, even though all frames share the same
+	// physical stack frame.
+	withTestProcessArgs("inlinescope", t, ".", []string{}, protest.EnableInlining, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFileBreakpoint(p, t, fixture.Source, 9)
+		assertNoError(grp.Continue(), t, "Continue")
+
+		frames, err := proc.ThreadStacktrace(p, p.CurrentThread(), 3)
+		assertNoError(err, t, "ThreadStacktrace")
+		if err := checkFrame(frames[0], "main.inner", fixture.Source, 9, true); err != nil {
+			t.Fatalf("Wrong frame 0: %v", err)
+		}
+		if err := checkFrame(frames[1], "main.outer", fixture.Source, 16, true); err != nil {
+			t.Fatalf("Wrong frame 1: %v", err)
+		}
+		if err := checkFrame(frames[2], "main.main", fixture.Source, 23, false); err != nil {
+			t.Fatalf("Wrong frame 2: %v", err)
+		}
+
+		for _, tc := range []struct {
+			frame   int
+			visible map[string]int64
+			hidden  []string
+		}{
+			{0, map[string]int64{"n": 4, "x": 8, "onlyInner": 9}, []string{"onlyOuter", "onlyMain", "r"}},
+			{1, map[string]int64{"n": 3, "x": 4}, []string{"onlyInner", "onlyOuter", "onlyMain", "r"}},
+			{2, map[string]int64{"x": 100, "onlyMain": 101}, []string{"n", "onlyInner", "onlyOuter", "r"}},
+		} {
+			scope, err := proc.ConvertEvalScope(p, -1, tc.frame, 0)
+			assertNoError(err, t, fmt.Sprintf("ConvertEvalScope(frame %d)", tc.frame))
+			for name, tgt := range tc.visible {
+				v, err := scope.EvalExpression(name, normalLoadConfig)
+				assertNoError(err, t, fmt.Sprintf("EvalExpression(%s) in frame %d", name, tc.frame))
+				if n, _ := constant.Int64Val(v.Value); n != tgt {
+					t.Errorf("wrong value of %s in frame %d: expected %d got %d", name, tc.frame, tgt, n)
+				}
+			}
+			for _, name := range tc.hidden {
+				if v, err := scope.EvalExpression(name, normalLoadConfig); err == nil {
+					t.Errorf("%s should not be visible in frame %d: %v", name, tc.frame, v)
+				}
+			}
+		}
+	})
+}
+
 func TestInlineFunctionList(t *testing.T) {
 	// We should be able to list all functions, even inlined ones.
 	ver, _ := goversion.Parse(runtime.Version())
@@ -3988,6 +4281,37 @@ func TestReadDefer(t *testing.T) {
 	})
 }
 
+func TestGoroutineDefersOpenCoded(t *testing.T) {
+	if !goversion.VersionAfterOrEqual(runtime.Version(), 1, 22) {
+		t.Skip("open-coded defers are only read on Go 1.22 and later")
+	}
+	// When optimizations are enabled the compiler uses open-coded defers for
+	// main.call1 and main.call2.
+	withTestProcessArgs("deferstack", t, ".", []string{}, protest.EnableOptimization, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue")
+		defers := p.SelectedGoroutine().Defers(p)
+		var got []string
+		for _, d := range defers {
+			if d.Unreadable != nil {
+				t.Fatalf("unreadable defer: %v", d.Unreadable)
+			}
+			if !d.OpenCoded {
+				t.Errorf("defer %#x not open-coded", d.DwrapPC)
+			}
+			_, _, dfn := d.DeferredFunc(p)
+			if dfn == nil {
+				t.Fatalf("could not find deferred function %#x", d.DwrapPC)
+			}
+			if strings.HasPrefix(dfn.Name, "main.") {
+				got = append(got, dfn.Name)
+			}
+		}
+		if tgt := []string{"main.f2", "main.f3", "main.f1", "main.f2"}; !slices.Equal(got, tgt) {
+			t.Errorf("wrong deferred calls, expected %v got %v", tgt, got)
+		}
+	})
+}
+
 func TestReadDeferArgs(t *testing.T) {
 	if goversion.VersionAfterOrEqual(runtime.Version(), 1, 17) {
 		// When regabi is enabled in Go 1.17 and later, reading arguments of
@@ -4057,7 +4381,7 @@ func TestIssue1374(t *testing.T) {
 		setFileBreakpoint(p, t, fixture.Source, 7)
 		assertNoError(grp.Continue(), t, "First Continue")
 		assertLineNumber(p, t, 7, "Did not continue to correct location (first continue),")
-		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "getNum()", normalLoadConfig, true), t, "Call")
+		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "getNum()", normalLoadConfig, proc.EvalConfig{CheckEscape: true}), t, "Call")
 		err := grp.Continue()
 		if _, isexited := err.(proc.ErrProcessExited); !isexited {
 			regs, _ := p.CurrentThread().Registers()
@@ -4210,6 +4534,48 @@ func TestListImages(t *testing.T) {
 	})
 }
 
+func TestPlugins(t *testing.T) {
+	// Lists the loaded plugins and sets a breakpoint on a plugin built
+	// without debug info.
+	protest.MustHaveCgo(t)
+	pluginFixtures := protest.WithPlugins(t, protest.AllNonOptimized, "plugin1/")
+	pluginFixtures = append(pluginFixtures, protest.BuildFixture(t, "plugin2/", protest.AllNonOptimized|protest.BuildModePlugin|protest.LinkStrip|protest.LinkDisableDWARF))
+
+	withTestProcessArgs("plugintest", t, ".", []string{pluginFixtures[0].Path, pluginFixtures[1].Path}, protest.AllNonOptimized, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		plugins, err := proc.Plugins(p.BinInfo(), p.Memory())
+		assertNoError(err, t, "Plugins() before loading plugins")
+		if len(plugins) != 0 {
+			t.Fatalf("expected no plugins, got %d", len(plugins))
+		}
+
+		assertNoError(grp.Continue(), t, "first continue")
+		assertNoError(grp.Continue(), t, "second continue")
+		plugins, err = proc.Plugins(p.BinInfo(), p.Memory())
+		assertNoError(err, t, "Plugins()")
+		if len(plugins) != 2 {
+			t.Fatalf("expected 2 plugins, got %d", len(plugins))
+		}
+		for i := range plugins {
+			if !strings.HasSuffix(plugins[i].PluginPath, fmt.Sprintf("/plugin%d", i+1)) {
+				t.Errorf("wrong plugin path %q for plugin %d", plugins[i].PluginPath, i)
+			}
+			if plugins[i].Image == nil || plugins[i].Image.Path != pluginFixtures[i].Path {
+				t.Fatalf("wrong image for plugin %d (%s)", i, plugins[i].PluginPath)
+			}
+		}
+		if plugins[0].Image.Stripped() || !plugins[1].Image.Stripped() {
+			t.Errorf("wrong stripped status: %v %v", plugins[0].Image.Stripped(), plugins[1].Image.Stripped())
+		}
+
+		fn2 := "github.com/go-delve/delve/_fixtures/plugin2.Fn2"
+		setFunctionBreakpoint(p, t, fn2)
+		assertNoError(grp.Continue(), t, "third continue")
+		if fn := p.BinInfo().PCToFunc(currentPC(p, t)); fn == nil || fn.Name != fn2 {
+			t.Fatalf("not stopped at %s: %v", fn2, fn)
+		}
+	})
+}
+
 func TestAncestors(t *testing.T) {
 	t.Setenv("GODEBUG", "tracebackancestors=100")
 	withTestProcess("testnextprog", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
@@ -4239,6 +4605,37 @@ func TestAncestors(t *testing.T) {
 	})
 }
 
+func TestGoroutineCreationStacktrace(t *testing.T) {
+	check := func(t *testing.T, p *proc.Target, wantFrames bool) {
+		frames, err := proc.GoroutineCreationStacktrace(p, p.SelectedGoroutine(), 50)
+		assertNoError(err, t, "GoroutineCreationStacktrace")
+		logStacktrace(t, p, frames)
+		if len(frames) == 0 || frames[0].Current.Fn == nil || frames[0].Current.Fn.Name != "main.main" || frames[0].Current.Line != 40 {
+			t.Fatalf("wrong first frame for creation stacktrace %#v", frames)
+		}
+		if wantFrames != (len(frames) > 1) {
+			t.Fatalf("wrong number of frames %d", len(frames))
+		}
+	}
+
+	t.Run("gopc", func(t *testing.T) {
+		withTestProcess("testnextprog", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+			setFunctionBreakpoint(p, t, "main.testgoroutine")
+			assertNoError(grp.Continue(), t, "Continue()")
+			check(t, p, false)
+		})
+	})
+
+	t.Run("tracebackancestors", func(t *testing.T) {
+		t.Setenv("GODEBUG", "tracebackancestors=100")
+		withTestProcess("testnextprog", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+			setFunctionBreakpoint(p, t, "main.testgoroutine")
+			assertNoError(grp.Continue(), t, "Continue()")
+			check(t, p, true)
+		})
+	})
+}
+
 func testCallConcurrentCheckReturns(p *proc.Target, t *testing.T, gid1, gid2 int64) int {
 	found := 0
 	for _, thread := range p.ThreadList() {
@@ -4280,7 +4677,7 @@ func TestCallConcurrent(t *testing.T) {
 
 		gid1 := p.SelectedGoroutine().ID
 		t.Logf("starting injection in %d / %d", p.SelectedGoroutine().ID, p.CurrentThread().ThreadID())
-		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "Foo(10, 1)", normalLoadConfig, false), t, "EvalExpressionWithCalls()")
+		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "Foo(10, 1)", normalLoadConfig, proc.EvalConfig{}), t, "EvalExpressionWithCalls()")
 
 		returned := testCallConcurrentCheckReturns(p, t, gid1, -1)
 
@@ -4295,7 +4692,7 @@ func TestCallConcurrent(t *testing.T) {
 
 		gid2 := p.SelectedGoroutine().ID
 		t.Logf("starting second injection in %d / %d", p.SelectedGoroutine().ID, p.CurrentThread().ThreadID())
-		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "Foo(10, 2)", normalLoadConfig, false), t, "EvalExpressionWithCalls")
+		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "Foo(10, 2)", normalLoadConfig, proc.EvalConfig{}), t, "EvalExpressionWithCalls")
 
 		for {
 			returned += testCallConcurrentCheckReturns(p, t, gid1, gid2)
@@ -4328,6 +4725,22 @@ func TestPluginStepping(t *testing.T) {
 		{contNext, "plugintest2.go:42"}})
 }
 
+func TestStepSkipNoDebug(t *testing.T) {
+	// Stepping into a call to a function without debug information should
+	// step over it when StepSkipNoDebug is set.
+	protest.MustHaveCgo(t)
+	pluginFixtures := protest.WithPlugins(t, protest.AllNonOptimized, "plugin1/")
+	pluginFixtures = append(pluginFixtures, protest.BuildFixture(t, "plugin2/", protest.AllNonOptimized|protest.BuildModePlugin|protest.LinkStrip|protest.LinkDisableDWARF))
+
+	withTestProcessArgs("plugintest2", t, ".", []string{pluginFixtures[0].Path, pluginFixtures[1].Path}, protest.AllNonOptimized, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFileBreakpoint(p, t, fixture.Source, 42)
+		assertNoError(grp.Continue(), t, "Continue()")
+		grp.StepSkipNoDebug = true
+		assertNoError(grp.Step(), t, "Step()")
+		assertLineNumber(p, t, 43, "Step()")
+	})
+}
+
 func TestBreakpointMaterializedEvent(t *testing.T) {
 	protest.MustHaveCgo(t)
 	pluginFixtures := protest.WithPlugins(t, protest.AllNonOptimized, "plugin1/", "plugin2/")
@@ -4543,7 +4956,7 @@ func TestIssue1925(t *testing.T) {
 	protest.MustSupportFunctionCalls(t, testBackend)
 	withTestProcess("testvariables2", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
 		assertNoError(grp.Continue(), t, "Continue()")
-		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "afunc(2)", normalLoadConfig, true), t, "Call")
+		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "afunc(2)", normalLoadConfig, proc.EvalConfig{CheckEscape: true}), t, "Call")
 		t.Logf("%v\n", p.SelectedGoroutine().CurrentLoc)
 		if loc := p.SelectedGoroutine().CurrentLoc; loc.File != fixture.Source {
 			t.Errorf("wrong location for selected goroutine after call: %s:%d", loc.File, loc.Line)
@@ -4696,7 +5109,7 @@ func TestIssue2319(t *testing.T) {
 
 	// Load up the binary and make sure there are no crashes.
 	bi := proc.NewBinaryInfo("linux", "amd64")
-	assertNoError(bi.LoadBinaryInfo(fixture.Path, 0, nil), t, "LoadBinaryInfo")
+	assertNoError(bi.LoadBinaryInfo(fixture.Path, 0, nil, ""), t, "LoadBinaryInfo")
 }
 
 func TestDump(t *testing.T) {
@@ -5007,6 +5420,40 @@ func TestWatchpointsBasic(t *testing.T) {
 	})
 }
 
+func TestWatchpointInt64(t *testing.T) {
+	// A write watchpoint on an int64 variable stops at the write site.
+	skipOn(t, "not implemented", "freebsd")
+	skipOn(t, "not implemented", "386")
+	skipOn(t, "not implemented", "ppc64le")
+	skipOn(t, "not implemented", "riscv64")
+	skipOn(t, "not implemented", "loong64")
+	skipOn(t, "see https://github.com/go-delve/delve/issues/2768", "windows")
+	protest.AllowRecording(t)
+
+	withTestProcess("watchint64", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFunctionBreakpoint(p, t, "main.main")
+		assertNoError(grp.Continue(), t, "Continue 0")
+
+		scope, err := proc.GoroutineScope(p, p.CurrentThread())
+		assertNoError(err, t, "GoroutineScope")
+
+		bp, err := p.SetWatchpoint(0, scope, "main.counter", proc.WatchWrite, nil)
+		assertNoError(err, t, "SetWatchpoint")
+		if sz := bp.WatchType.Size(); sz != 8 {
+			t.Fatalf("wrong watchpoint size %d", sz)
+		}
+
+		assertNoError(grp.Continue(), t, "Continue 1")
+		assertLineNumberIn(p, t, []int{13, 14}, "Continue 1")
+		if curbp := p.CurrentThread().Breakpoint().Breakpoint; curbp == nil || curbp.LogicalID() != bp.LogicalID() {
+			t.Fatal("watchpoint not hit")
+		}
+		if n, _ := constant.Int64Val(evalVariable(p, t, "main.counter").Value); n != 1<<40 {
+			t.Fatalf("wrong value of main.counter after the write: %d", n)
+		}
+	})
+}
+
 func TestWatchpointCounts(t *testing.T) {
 	skipOn(t, "not implemented", "freebsd")
 	skipOn(t, "not implemented", "386")
@@ -5054,6 +5501,40 @@ func TestWatchpointCounts(t *testing.T) {
 	})
 }
 
+func TestWatchpointCondition(t *testing.T) {
+	skipOn(t, "not implemented", "freebsd")
+	skipOn(t, "not implemented", "386")
+	skipOn(t, "see https://github.com/go-delve/delve/issues/2768", "windows")
+	skipOn(t, "not implemented", "ppc64le")
+	skipOn(t, "not implemented", "riscv64")
+	skipOn(t, "not implemented", "loong64")
+	protest.AllowRecording(t)
+
+	withTestProcess("databpcountstest", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFunctionBreakpoint(p, t, "main.main")
+		assertNoError(grp.Continue(), t, "Continue 0")
+
+		scope, err := proc.GoroutineScope(p, p.CurrentThread())
+		assertNoError(err, t, "GoroutineScope")
+
+		cond, err := parser.ParseExpr("globalvar1 == 42")
+		assertNoError(err, t, "ParseExpr")
+		bp, err := p.SetWatchpoint(0, scope, "globalvar1", proc.WatchWrite, cond)
+		assertNoError(err, t, "SetWatchpoint(write-only)")
+
+		assertNoError(grp.Continue(), t, "Continue 1")
+		if curbp := p.CurrentThread().Breakpoint().Breakpoint; curbp == nil || curbp.LogicalID() != bp.LogicalID() {
+			t.Fatal("watchpoint not hit")
+		}
+		if v, _ := constant.Int64Val(evalVariable(p, t, "globalvar1").Value); v != 42 {
+			t.Fatalf("wrong value of globalvar1 at watchpoint stop: %d", v)
+		}
+		if bp.Logical.TotalHitCount != 1 {
+			t.Fatalf("wrong TotalHitCount for the watchpoint: %d", bp.Logical.TotalHitCount)
+		}
+	})
+}
+
 func TestManualStopWhileStopped(t *testing.T) {
 	// Checks that RequestManualStop sent to a stopped thread does not cause the target process to die.
 	withTestProcess("loopprog", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
@@ -5287,6 +5768,51 @@ func TestWatchpointStackRecordedOutOfScopeBreakletIndex(t *testing.T) {
 	})
 }
 
+func TestWatchpointBackward(t *testing.T) {
+	// Continuing backwards with a write watchpoint set should stop on the
+	// instruction that performed the most recent write, before it is
+	// executed: the watched variable still has its old value and stepping
+	// forward by one instruction writes the new one.
+	skipUnlessOn(t, "only for recorded targets", "rr")
+	protest.AllowRecording(t)
+
+	withTestProcess("databpeasy", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFileBreakpoint(p, t, fixture.Source, 21) // Position 2 breakpoint
+		assertNoError(grp.Continue(), t, "Continue 0")
+		assertLineNumber(p, t, 21, "Continue 0") // Position 2
+
+		scope, err := proc.GoroutineScope(p, p.CurrentThread())
+		assertNoError(err, t, "GoroutineScope")
+
+		bp, err := p.SetWatchpoint(0, scope, "globalvar1", proc.WatchWrite, nil)
+		assertNoError(err, t, "SetWatchpoint")
+
+		assertNoError(grp.ChangeDirection(proc.Backward), t, "ChangeDirection(Backward)")
+		assertNoError(grp.Continue(), t, "Continue 1")
+		assertLineNumber(p, t, 18, "Continue 1") // globalvar1 = globalvar2 + 1
+
+		if curbp := p.CurrentThread().Breakpoint().Breakpoint; curbp == nil || curbp.LogicalID() != bp.LogicalID() {
+			t.Fatalf("not stopped on watchpoint: %#v", curbp)
+		}
+		if p.StopReason != proc.StopWatchpoint {
+			t.Errorf("wrong stop reason %v", p.StopReason)
+		}
+		if v := evalVariable(p, t, "globalvar1"); constant.Compare(v.Value, token.NEQ, constant.MakeInt64(0)) {
+			t.Errorf("wrong value of globalvar1 after reverse continue: %v (expected 0)", v.Value)
+		}
+		pc := currentPC(p, t)
+
+		assertNoError(grp.ChangeDirection(proc.Forward), t, "ChangeDirection(Forward)")
+		assertNoError(grp.StepInstruction(false), t, "StepInstruction")
+		if newpc := currentPC(p, t); newpc == pc {
+			t.Errorf("PC did not change after step instruction: %#x", newpc)
+		}
+		if v := evalVariable(p, t, "globalvar1"); constant.Compare(v.Value, token.NEQ, constant.MakeInt64(2)) {
+			t.Errorf("wrong value of globalvar1 after step instruction: %v (expected 2)", v.Value)
+		}
+	})
+}
+
 func TestSetOnFunctions(t *testing.T) {
 	// The set command between function variables should fail with an error
 	// Issue #2691
@@ -5592,7 +6118,7 @@ func TestEscapeCheckUnreadable(t *testing.T) {
 	withTestProcessArgs("reflecttypefncall", t, ".", []string{}, protest.AllNonOptimized, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
 		setFileBreakpoint(p, t, fixture.Source, 9)
 		assertNoError(grp.Continue(), t, "Continue")
-		proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "value.Type()", normalLoadConfig, true)
+		proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "value.Type()", normalLoadConfig, proc.EvalConfig{CheckEscape: true})
 	})
 }
 
@@ -5797,7 +6323,7 @@ func TestWaitForAttach(t *testing.T) {
 
 	switch testBackend {
 	case "native":
-		p, err = native.Attach(0, fixtureState.waitFor, []string{})
+		p, err = native.Attach(0, fixtureState.waitFor, []string{}, "")
 	case "lldb":
 		path := ""
 		if runtime.GOOS == "darwin" {
@@ -6146,7 +6672,7 @@ func TestDelveCatch(t *testing.T) {
 func TestTrimpathDetection(t *testing.T) {
 	f1 := protest.BuildFixture(t, "math", 0)
 	bi1 := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
-	assertNoError(bi1.LoadBinaryInfo(f1.Path, 0x10000, nil), t, "LoadBinaryInfo")
+	assertNoError(bi1.LoadBinaryInfo(f1.Path, 0x10000, nil, ""), t, "LoadBinaryInfo")
 	if bi1.Images[0].Trimpath {
 		t.Error("expected trimpath used to be false, was true")
 	}
@@ -6156,7 +6682,7 @@ func TestTrimpathDetection(t *testing.T) {
 	fmt.Printf("%#v\n", buildinfo)
 
 	b2 := proc.NewBinaryInfo(runtime.GOOS, runtime.GOARCH)
-	assertNoError(b2.LoadBinaryInfo(f2.Path, 0x10000, nil), t, "LoadBinaryInfo")
+	assertNoError(b2.LoadBinaryInfo(f2.Path, 0x10000, nil, ""), t, "LoadBinaryInfo")
 	if !b2.Images[0].Trimpath {
 		t.Error("expected trimpath used to be true, was false")
 	}
@@ -6247,3 +6773,268 @@ func TestNonGoBinaryWithGoDlopen(t *testing.T) {
 		}
 	}
 }
+
+func TestNextAsyncPreempt(t *testing.T) {
+	// Stepping from inside runtime.asyncPreempt should resume the goroutine
+	// on the instruction that was interrupted by the preemption instead of
+	// stepping through the preemption handler.
+	skipOn(t, "async preemption disabled", "windows")
+	skipOn(t, "async preemption disabled", "linux", "arm64")
+	skipOn(t, "async preemption disabled", "linux", "ppc64le")
+	skipOn(t, "async preemption disabled", "linux", "loong64")
+	skipOn(t, "not implemented", "386")
+	withTestProcess("asyncpreempt", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		fn := p.BinInfo().LookupFunc()["runtime.asyncPreempt"][0]
+		bp, err := p.SetBreakpoint(0, fn.Entry, proc.UserBreakpoint, nil)
+		assertNoError(err, t, "SetBreakpoint()")
+		assertNoError(grp.Continue(), t, "Continue()")
+		assertNoError(p.ClearBreakpoint(bp.Addr), t, "ClearBreakpoint()")
+		goid := p.SelectedGoroutine().ID
+		frames, err := proc.GoroutineStacktrace(p, p.SelectedGoroutine(), 1, 0)
+		assertNoError(err, t, "GoroutineStacktrace()")
+		if len(frames) < 2 || frames[1].Current.Fn == nil || frames[1].Current.Fn.Name != "main.spin" {
+			t.Skip("preemption did not interrupt main.spin")
+		}
+		interruptedLine := frames[1].Current.Line
+
+		assertNoError(grp.Next(), t, "Next()")
+		loc, err := proc.ThreadLocation(p.CurrentThread())
+		assertNoError(err, t, "ThreadLocation()")
+		if loc.Fn == nil || loc.Fn.Name != "main.spin" {
+			t.Fatalf("wrong location after next: %s:%d %#x", loc.File, loc.Line, loc.PC)
+		}
+		// next should complete on the line following the interrupted one, not
+		// in the middle of it.
+		if loc.Line == interruptedLine {
+			t.Fatalf("next stopped on the interrupted line %d", interruptedLine)
+		}
+		if pcs, _ := loc.Fn.AllPCs("", 0); !slices.Contains(pcs, loc.PC) {
+			t.Fatalf("next did not stop on a statement: %s:%d %#x", loc.File, loc.Line, loc.PC)
+		}
+		if p.SelectedGoroutine().ID != goid {
+			t.Fatalf("wrong goroutine after next: %d (expected %d)", p.SelectedGoroutine().ID, goid)
+		}
+	})
+}
+
+func TestStepInstructionUndoAsyncPreempt(t *testing.T) {
+	skipOn(t, "async preemption disabled", "windows")
+	skipOn(t, "async preemption disabled", "linux", "arm64")
+	skipOn(t, "async preemption disabled", "linux", "ppc64le")
+	skipOn(t, "async preemption disabled", "linux", "loong64")
+	skipOn(t, "not implemented", "386")
+	withTestProcess("asyncpreempt", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		fn := p.BinInfo().LookupFunc()["runtime.asyncPreempt"][0]
+		bp, err := p.SetBreakpoint(0, fn.Entry, proc.UserBreakpoint, nil)
+		assertNoError(err, t, "SetBreakpoint()")
+		assertNoError(grp.Continue(), t, "Continue()")
+		assertNoError(p.ClearBreakpoint(bp.Addr), t, "ClearBreakpoint()")
+		frames, err := proc.GoroutineStacktrace(p, p.SelectedGoroutine(), 1, 0)
+		assertNoError(err, t, "GoroutineStacktrace()")
+		if len(frames) < 2 {
+			t.Fatal("stacktrace too short")
+		}
+		resumePC := frames[1].Current.PC
+
+		// This is the state of the thread when the preemption signal is
+		// delivered during a single step.
+		undone, err := proc.UndoAsyncPreempt(p.CurrentThread())
+		assertNoError(err, t, "UndoAsyncPreempt()")
+		if !undone {
+			t.Fatal("preemption not undone")
+		}
+		regs, err := p.CurrentThread().Registers()
+		assertNoError(err, t, "Registers()")
+		if regs.PC() != resumePC {
+			t.Fatalf("wrong PC after undoing preemption %#x (expected %#x)", regs.PC(), resumePC)
+		}
+		if regs.SP() != uint64(frames[1].Regs.SP()) {
+			t.Fatalf("wrong SP after undoing preemption %#x (expected %#x)", regs.SP(), frames[1].Regs.SP())
+		}
+
+		assertNoError(grp.StepInstruction(false), t, "StepInstruction()")
+		loc, err := proc.ThreadLocation(p.CurrentThread())
+		assertNoError(err, t, "ThreadLocation()")
+		if loc.Fn == nil || loc.Fn.Name != frames[1].Current.Fn.Name {
+			t.Fatalf("wrong location after step-instruction: %s:%d %#x", loc.File, loc.Line, loc.PC)
+		}
+	})
+}
+
+func TestGenericFunctionPrologue(t *testing.T) {
+	// Breakpoints set on instantiations of generic functions should be placed
+	// after the prologue, with the arguments readable.
+	protest.AllowRecording(t)
+	withTestProcess("genericprologue", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFunctionBreakpointAll(p, t, "main.sum")
+		setFunctionBreakpointAll(p, t, "main.(*pair).set")
+
+		for _, tc := range []struct {
+			line int
+			args []string
+		}{
+			{9, []string{"a=1", "b=2", "s=[]int len: 2, cap: 2, [3,4]"}},
+			{9, []string{"a=1.5", "b=2.5", "s=[]float64 len: 0, cap: 0, nil"}},
+			{23, []string{`k="a"`, "v=1"}},
+		} {
+			assertNoError(grp.Continue(), t, "Continue")
+			assertLineNumber(p, t, tc.line, "")
+			frames, err := proc.ThreadStacktrace(p, p.CurrentThread(), 1)
+			assertNoError(err, t, "ThreadStacktrace")
+			if frames[0].Current.PC == frames[0].Current.Fn.Entry {
+				t.Errorf("breakpoint on %s set at the entry point", frames[0].Current.Fn.Name)
+			}
+			if len(frames) < 2 || frames[1].Current.Fn == nil || frames[1].Current.Fn.Name != "main.main" {
+				t.Errorf("wrong caller for %s", frames[0].Current.Fn.Name)
+			}
+			for _, arg := range tc.args {
+				name, _, _ := strings.Cut(arg, "=")
+				v := evalVariable(p, t, name)
+				if got := name + "=" + api.ConvertVar(v).SinglelineString(); got != arg {
+					t.Errorf("%s: got %s expected %s", frames[0].Current.Fn.Name, got, arg)
+				}
+			}
+		}
+	})
+}
+
+func TestNonGenericFunctionPrologue(t *testing.T) {
+	// Breakpoints on functions that are not generic and have no recognizable
+	// prologue must be set on their first stmt instruction, even when it is
+	// the entry point and there are other stmt instructions on the same line.
+	withTestProcess("genericprologue", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		n := 0
+		for i := range p.BinInfo().Functions {
+			fn := &p.BinInfo().Functions[i]
+			if strings.Contains(fn.Name, "[") {
+				continue
+			}
+			tgt, ok := proc.FirstStmtWithoutPrologue(p, fn)
+			if !ok {
+				continue
+			}
+			n++
+			if pc, err := proc.FirstPCAfterPrologue(p, fn, false); err != nil || pc != tgt {
+				t.Errorf("breakpoint on %s set at %#x (%v) instead of %#x", fn.Name, pc, err, tgt)
+			}
+		}
+		if n == 0 {
+			t.Fatal("no functions without a prologue found")
+		}
+	})
+}
+
+func TestSignalPolicy(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("only supported on linux")
+	}
+	if testBackend != "native" {
+		t.Skip("only supported with native backend")
+	}
+	const sigusr1 = 0xa
+	withTestProcess("signalprog", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.SetSignalPolicy(sigusr1, proc.SignalPolicy{Stop: true, Print: true, Pass: true}), t, "SetSignalPolicy")
+		events := []*proc.Event{}
+		grp.SetEventsFn(func(e *proc.Event) { events = append(events, e) })
+
+		assertNoError(grp.Continue(), t, "Continue")
+		if p.StopReason != proc.StopSignal {
+			t.Fatalf("wrong stop reason %v", p.StopReason)
+		}
+		if len(events) != 1 || events[0].Kind != proc.EventSignal || events[0].Signal != sigusr1 || !events[0].Stopped {
+			t.Fatalf("wrong events %#v", events)
+		}
+		if sig := p.CurrentThread().Common().Signal; sig != sigusr1 {
+			t.Fatalf("wrong signal on the current thread %d", sig)
+		}
+
+		err := grp.Continue()
+		if !errors.As(err, &proc.ErrProcessExited{}) {
+			t.Fatalf("expected process to exit: %v", err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("signal reported more than once %#v", events)
+		}
+	})
+}
+
+func TestFieldWatchpoint(t *testing.T) {
+	skipOn(t, "not implemented", "freebsd")
+	skipOn(t, "not implemented", "386")
+	skipOn(t, "not implemented", "ppc64le")
+	skipOn(t, "not implemented", "riscv64")
+	skipOn(t, "not implemented", "loong64")
+	skipOn(t, "see https://github.com/go-delve/delve/issues/2768", "windows")
+
+	withTestProcess("fieldwatch", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		lbp := &proc.LogicalBreakpoint{LogicalID: 1, HitCount: make(map[int64]uint64), Set: proc.SetBreakpoint{FieldWatch: &proc.FieldWatch{Type: "Counter", Field: "count", WatchType: proc.WatchWrite, Max: 2}}}
+		grp.LogicalBreakpoints[1] = lbp
+		assertNoError(grp.SetBreakpointEnabled(lbp, true), t, "SetBreakpointEnabled")
+
+		// Only two of the three instances are watched, the composite literal
+		// initializing the object could also write the watched field.
+		n := 0
+		for {
+			if err := grp.Continue(); err != nil {
+				if _, exited := err.(proc.ErrProcessExited); exited {
+					break
+				}
+				assertNoError(err, t, "Continue")
+			}
+			if curbp := p.CurrentThread().Breakpoint().Breakpoint; curbp == nil || curbp.LogicalID() != lbp.LogicalID {
+				t.Fatalf("stopped at wrong breakpoint %v", curbp)
+			}
+			_, ln := currentLineNumber(p, t)
+			switch ln {
+			case 11:
+			case 20:
+				n++
+			default:
+				t.Fatalf("stopped at wrong line %d", ln)
+			}
+		}
+		if n != 2 {
+			t.Fatalf("watchpoint hit %d times on line 20, expected 2", n)
+		}
+	})
+}
+
+func TestFieldWatchpointFreed(t *testing.T) {
+	// The watchpoint on an instance that was freed must be removed when its
+	// memory is allocated again, making room for a new instance.
+	skipOn(t, "not implemented", "freebsd")
+	skipOn(t, "not implemented", "386")
+	skipOn(t, "not implemented", "ppc64le")
+	skipOn(t, "not implemented", "riscv64")
+	skipOn(t, "not implemented", "loong64")
+	skipOn(t, "see https://github.com/go-delve/delve/issues/2768", "windows")
+
+	withTestProcess("fieldwatchfree", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		lbp := &proc.LogicalBreakpoint{LogicalID: 1, HitCount: make(map[int64]uint64), Set: proc.SetBreakpoint{FieldWatch: &proc.FieldWatch{Type: "Counter", Field: "count", WatchType: proc.WatchWrite, Max: 1}}}
+		grp.LogicalBreakpoints[1] = lbp
+		assertNoError(grp.SetBreakpointEnabled(lbp, true), t, "SetBreakpointEnabled")
+
+		lines := []int{}
+		for {
+			if err := grp.Continue(); err != nil {
+				if _, exited := err.(proc.ErrProcessExited); exited {
+					break
+				}
+				assertNoError(err, t, "Continue")
+			}
+			if curbp := p.CurrentThread().Breakpoint().Breakpoint; curbp == nil || curbp.LogicalID() != lbp.LogicalID {
+				t.Fatalf("stopped at wrong breakpoint %v", curbp)
+			}
+			_, ln := currentLineNumber(p, t)
+			lines = append(lines, ln)
+		}
+		if !slices.Contains(lines, 28) || !slices.Contains(lines, 39) {
+			t.Fatalf("watchpoint hit on lines %v, expected 28 and 39", lines)
+		}
+		for _, ln := range lines {
+			if (ln < 26 || ln > 28) && (ln < 37 || ln > 39) {
+				t.Fatalf("watchpoint hit on lines %v", lines)
+			}
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package proc
+
+import (
+	"bytes"
+	"debug/dwarf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go/constant"
+	"math"
+	"reflect"
+
+	"github.com/go-delve/delve/pkg/dwarf/godwarf"
+)
+
+// searchMemoryChunkSize is the amount of memory read at once by SearchMemory.
+const searchMemoryChunkSize = 1 << 20
+
+// MemorySearchResult is an address where SearchMemory found the pattern it
+// was searching for.
+type MemorySearchResult struct {
+	Addr uint64
+
+	// Symbol is the name of the package variable or function containing
+	// Addr, SymbolAddr is its start address.
+	Symbol     string
+	SymbolAddr uint64
+	// Type is the type of the package variable containing Addr.
+	Type godwarf.Type
+	// GoroutineID is the ID of the goroutine whose stack contains Addr, or 0.
+	GoroutineID int64
+}
+
+// SearchMemory scans the readable memory mappings of the target for
+// occurrences of pattern starting at addresses that are a multiple of
+// align. If limit is greater than zero at most limit results are returned.
+// If resolve is true each result is annotated with the package variable,
+// function or goroutine stack that contains it, when one can be found.
+// Regions that can not be read are skipped.
+func (t *Target) SearchMemory(pattern []byte, align, limit int, resolve bool) ([]MemorySearchResult, error) {
+	if len(pattern) == 0 {
+		return nil, errors.New("empty search pattern")
+	}
+	if align <= 0 {
+		align = 1
+	}
+	memmap, err := t.proc.MemoryMap()
+	if err != nil {
+		return nil, err
+	}
+	mem := t.Memory()
+
+	r := []MemorySearchResult{}
+	buf := make([]byte, searchMemoryChunkSize+len(pattern)-1)
+
+	for _, entry := range memmap {
+		if !entry.Read {
+			continue
+		}
+		end := entry.Addr + entry.Size
+		for chunkAddr := entry.Addr; chunkAddr < end; chunkAddr += searchMemoryChunkSize {
+			// Consecutive chunks overlap by len(pattern)-1 bytes so that
+			// occurrences crossing a chunk boundary are found, matches starting in
+			// the overlap are reported by the next chunk.
+			chunk := buf[:min(uint64(len(buf)), end-chunkAddr)]
+			if _, err := mem.ReadMemory(chunk, chunkAddr); err != nil {
+				break
+			}
+			for off := 0; ; {
+				idx := bytes.Index(chunk[off:], pattern)
+				if idx < 0 || off+idx >= searchMemoryChunkSize {
+					break
+				}
+				addr := chunkAddr + uint64(off+idx)
+				off += idx + 1
+				if addr%uint64(align) != 0 {
+					continue
+				}
+				r = append(r, MemorySearchResult{Addr: addr})
+				if limit > 0 && len(r) >= limit {
+					return t.resolveSearchResults(r, resolve), nil
+				}
+			}
+		}
+	}
+
+	return t.resolveSearchResults(r, resolve), nil
+}
+
+func (t *Target) resolveSearchResults(r []MemorySearchResult, resolve bool) []MemorySearchResult {
+	if !resolve || len(r) == 0 {
+		return r
+	}
+	bi := t.BinInfo()
+	// Resolution is best effort, if the list of goroutines can not be read
+	// results are simply not attributed to goroutine stacks.
+	gs, _, _ := GoroutinesInfo(t, 0, 0)
+	for i := range r {
+		addr := r[i].Addr
+		if fn := bi.PCToFunc(addr); fn != nil {
+			r[i].Symbol, r[i].SymbolAddr = fn.Name, fn.Entry
+			continue
+		}
+		if pkgvar, typ := packageVarContaining(bi, addr); pkgvar != nil {
+			r[i].Symbol, r[i].SymbolAddr, r[i].Type = pkgvar.name, pkgvar.addr, typ
+			continue
+		}
+		for _, g := range gs {
+			if g.stack.lo <= addr && addr < g.stack.hi {
+				r[i].GoroutineID = g.ID
+				break
+			}
+		}
+	}
+	return r
+}
+
+// packageVarContaining returns the package variable containing addr and
+// its type.
+func packageVarContaining(bi *BinaryInfo, addr uint64) (*packageVar, godwarf.Type) {
+	pkgvar := bi.packageVarAt(addr)
+	if pkgvar == nil {
+		return nil, nil
+	}
+	reader := pkgvar.cu.image.dwarfReader
+	reader.Seek(pkgvar.offset)
+	entry, err := reader.Next()
+	if err != nil {
+		return nil, nil
+	}
+	off, ok := entry.Val(dwarf.AttrType).(dwarf.Offset)
+	if !ok {
+		return nil, nil
+	}
+	typ, err := pkgvar.cu.image.Type(off)
+	if err != nil || addr >= pkgvar.addr+uint64(max(typ.Size(), 1)) {
+		return nil, nil
+	}
+	return pkgvar, typ
+}
+
+// SearchPattern returns the in-memory representation of the value of v,
+// to be passed to SearchMemory, and its natural alignment.
+// Pointers are represented by the address they point to, integers by
+// their value (using the pointer size for untyped constants) and strings
+// by their contents.
+func SearchPattern(v *Variable) ([]byte, int, error) {
+	if v.Unreadable != nil {
+		return nil, 0, v.Unreadable
+	}
+	ptrSize := v.bi.Arch.PtrSize()
+	size := ptrSize
+	if v.RealType != nil {
+		size = int(v.RealType.Size())
+	}
+
+	putUint := func(n uint64) ([]byte, int, error) {
+		if size <= 0 || size > 8 {
+			return nil, 0, fmt.Errorf("unsupported size %d", size)
+		}
+		buf := make([]byte, 8)
+		binary.LittleEndian.PutUint64(buf, n)
+		return buf[:size], size, nil
+	}
+
+	switch v.Kind {
+	case reflect.Ptr, reflect.UnsafePointer:
+		v.loadValue(loadSingleValue)
+		if v.Unreadable != nil {
+			return nil, 0, v.Unreadable
+		}
+		if len(v.Children) != 1 {
+			return nil, 0, errors.New("could not read pointer value")
+		}
+		size = ptrSize
+		return putUint(v.Children[0].Addr)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.loadValue(loadSingleValue)
+		if v.Value == nil {
+			return nil, 0, errors.New("could not read integer value")
+		}
+		n, _ := constant.Int64Val(v.Value)
+		return putUint(uint64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.loadValue(loadSingleValue)
+		if v.Value == nil {
+			return nil, 0, errors.New("could not read integer value")
+		}
+		n, _ := constant.Uint64Val(v.Value)
+		return putUint(n)
+	case reflect.Float32, reflect.Float64:
+		v.loadValue(loadSingleValue)
+		if v.Value == nil {
+			return nil, 0, errors.New("could not read floating point value")
+		}
+		f, _ := constant.Float64Val(v.Value)
+		if v.Kind == reflect.Float32 {
+			size = 4
+			return putUint(uint64(math.Float32bits(float32(f))))
+		}
+		size = 8
+		return putUint(math.Float64bits(f))
+	case reflect.String:
+		if v.Flags&VariableConstant != 0 {
+			return []byte(constant.StringVal(v.Value)), 1, nil
+		}
+		if v.Len > searchMemoryChunkSize {
+			return nil, 0, fmt.Errorf("string too long (%d bytes)", v.Len)
+		}
+		buf := make([]byte, v.Len)
+		if _, err := v.mem.ReadMemory(buf, v.Base); err != nil {
+			return nil, 0, err
+		}
+		return buf, 1, nil
+	default:
+		return nil, 0, fmt.Errorf("can not search for a value of kind %s", v.Kind)
+	}
+}
@@ -54,6 +54,11 @@ type Stackframe struct {
 	hasInlines bool
 	// Bottom is true if this is the bottom of the stack
 	Bottom bool
+	// FramePointerFallback, for stacktraces requested with
+	// StacktraceFramePointers, is set when the frame pointer chain could not
+	// be followed to unwind this frame and DWARF call frame information was
+	// used instead. It describes why.
+	FramePointerFallback string
 
 	// lastpc is a memory address guaranteed to belong to the last instruction
 	// executed in this stack frame.
@@ -101,6 +106,28 @@ func (frame *Stackframe) FramePointerOffset() int64 {
 	return int64(frame.Regs.BP()) - int64(frame.stackHi)
 }
 
+// ExecutesPC returns true if pc belongs to the function call executing in
+// frame and not to one of the calls inlined into it. If frame is itself an
+// inlined call pc must belong to that inlined call.
+func (frame *Stackframe) ExecutesPC(pc uint64) (bool, error) {
+	fn := frame.Current.Fn
+	if fn == nil || frame.Call.Fn == nil || pc < fn.Entry || pc >= fn.End {
+		return false, nil
+	}
+	if fn.cu.lineInfo == nil || fn.cu.image.Stripped() {
+		return !frame.Inlined, nil
+	}
+	dwarfTree, err := fn.cu.image.getDwarfTree(fn.offset)
+	if err != nil {
+		return false, err
+	}
+	inlstack := reader.InlineStack(dwarfTree, pc)
+	if !frame.Inlined {
+		return len(inlstack) == 0, nil
+	}
+	return len(inlstack) > 0 && inlstack[0].Offset == frame.Call.Fn.offset, nil
+}
+
 // contains returns true if off is between CFA and SP
 func (frame *Stackframe) contains(off int64) bool {
 	p := uint64(off + int64(frame.stackHi))
@@ -175,6 +202,16 @@ const (
 	// StacktraceG requests a stacktrace starting with the register
 	// values saved in the runtime.g structure.
 	StacktraceG
+
+	// StacktraceFramePointers requests a fast, but approximate, stacktrace
+	// obtained by following the chain of saved frame pointers. DWARF call
+	// frame information is only used to unwind the topmost frame and frames
+	// where the frame pointer chain looks broken, see
+	// Stackframe.FramePointerFallback.
+	// The frame base of the returned frames is not computed, they can not be
+	// used to evaluate local variables.
+	// Frame pointer unwinding is only supported on amd64 and arm64.
+	StacktraceFramePointers
 )
 
 // GoroutineStacktrace returns the stack trace for a goroutine.
@@ -198,6 +235,12 @@ func GoroutineStacktrace(tgt *Target, g *G, depth int, opts StacktraceOptions) (
 	if err != nil {
 		return nil, err
 	}
+	if opts&StacktraceFramePointers != 0 && !g.SystemStack {
+		// Read the used portion of the goroutine stack in a single operation.
+		if sp := it.regs.SP(); sp < g.stack.hi && g.stack.hi-sp <= maxFramePointersStackCache {
+			it.mem = cacheMemory(it.mem, sp, int(g.stack.hi-sp))
+		}
+	}
 	frames, err := it.stacktrace(depth, nil)
 	if err != nil {
 		return nil, err
@@ -211,6 +254,10 @@ func GoroutineStacktrace(tgt *Target, g *G, depth int, opts StacktraceOptions) (
 	return frames, nil
 }
 
+// maxFramePointersStackCache is the maximum size of goroutine stack that
+// will be read at once for StacktraceFramePointers.
+const maxFramePointersStackCache = 64 * 1024 * 1024
+
 // NullAddrError is an error for a null address.
 type NullAddrError struct{}
 
@@ -218,6 +265,22 @@ func (n NullAddrError) Error() string {
 	return "NULL address"
 }
 
+// DefaultMaxStackDepth is the default value of TargetGroup.MaxStackDepth.
+const DefaultMaxStackDepth = 1000
+
+// ErrStackTooDeep is returned, as the error of the last frame of a
+// stacktrace, when unwinding was stopped because the stack contained more
+// than the maximum number of frames allowed, see TargetGroup.MaxStackDepth.
+// This usually happens when the stack is corrupted and the unwinder is
+// looping.
+type ErrStackTooDeep struct {
+	MaxDepth int
+}
+
+func (err ErrStackTooDeep) Error() string {
+	return fmt.Sprintf("stack unwinding stopped after %d frames: possible corruption", err.MaxDepth)
+}
+
 // stackIterator holds information
 // required to iterate and walk the program
 // stack.
@@ -242,7 +305,12 @@ type stackIterator struct {
 	g0_sched_sp        uint64 // value of g0.sched.sp (see comments around its use)
 	g0_sched_sp_loaded bool   // g0_sched_sp was loaded from g0
 
-	count int
+	count    int
+	unwound  int // number of frames returned by Next
+	maxDepth int // maximum number of frames returned by Next
+
+	lastPC  uint64 // PC of the frame returned by the previous call to Next
+	lastCFA int64  // CFA of the frame returned by the previous call to Next
 
 	// canUseFP is true when the frame pointer has been validated as stable
 	// for FP-based unwinding. At the top of the stack the topmost function
@@ -251,6 +319,12 @@ type stackIterator struct {
 	// current frame's own frame pointer.
 	canUseFP bool
 
+	// fpFallback is the reason why frame pointer unwinding could not be used
+	// for the current frame, see Stackframe.FramePointerFallback.
+	fpFallback string
+	// fpFrames is the number of frames unwound using the frame pointer chain.
+	fpFrames int
+
 	opts StacktraceOptions
 }
 
@@ -259,7 +333,11 @@ func newStackIterator(tgt *Target, bi *BinaryInfo, mem MemoryReadWriter, regs op
 	if g != nil {
 		systemstack = g.SystemStack
 	}
-	return &stackIterator{pc: regs.PC(), regs: regs, top: true, target: tgt, bi: bi, mem: mem, err: nil, atend: false, stackhi: stackhi, systemstack: systemstack, g: g, opts: opts}
+	maxDepth := DefaultMaxStackDepth
+	if tgt != nil && tgt.grp != nil && tgt.grp.MaxStackDepth > 0 {
+		maxDepth = tgt.grp.MaxStackDepth
+	}
+	return &stackIterator{pc: regs.PC(), regs: regs, top: true, target: tgt, bi: bi, mem: mem, err: nil, atend: false, stackhi: stackhi, systemstack: systemstack, g: g, opts: opts, maxDepth: maxDepth}
 }
 
 // Next points the iterator to the next stack frame.
@@ -267,6 +345,11 @@ func (it *stackIterator) Next() bool {
 	if it.err != nil || it.atend {
 		return false
 	}
+	if it.unwound >= it.maxDepth {
+		it.err = ErrStackTooDeep{it.maxDepth}
+		return false
+	}
+	it.unwound++
 
 	if logflags.Stack() {
 		logger := logflags.StackLogger()
@@ -292,8 +375,18 @@ func (it *stackIterator) Next() bool {
 		}
 	}
 
+	it.fpFallback = ""
 	callFrameRegs, ret, retaddr := it.advanceRegs()
 	it.frame = it.newStackframe(ret, retaddr)
+	it.frame.FramePointerFallback = it.fpFallback
+
+	if it.unwound > 1 && it.frame.Current.PC == it.lastPC && it.frame.Regs.CFA == it.lastCFA {
+		// Unwinding did not make any progress, the stack is corrupted and we
+		// would keep returning the same frame until the maximum depth.
+		it.err = ErrStackTooDeep{it.unwound - 1}
+		return false
+	}
+	it.lastPC, it.lastCFA = it.frame.Current.PC, it.frame.Regs.CFA
 
 	if logflags.Stack() {
 		logger := logflags.StackLogger()
@@ -394,7 +487,7 @@ func (it *stackIterator) newStackframe(ret, retaddr uint64) Stackframe {
 	if fn == nil {
 		f = "?"
 		l = -1
-	} else {
+	} else if it.opts&StacktraceFramePointers == 0 {
 		it.regs.FrameBase = it.frameBase(fn)
 	}
 	r := Stackframe{Current: Location{PC: it.pc, File: f, Line: l, Fn: fn}, Regs: it.regs, Ret: ret, stackHi: it.stackhi, SystemStack: it.systemstack, lastpc: it.pc}
@@ -417,7 +510,7 @@ func (it *stackIterator) newStackframe(ret, retaddr uint64) Stackframe {
 			r.Call.File, r.Call.Line = r.Current.Fn.cu.lineInfo.PCToLine(r.Current.Fn.Entry, it.pc-1)
 		}
 	}
-	if fn != nil && !fn.cu.image.Stripped() && !r.SystemStack && it.g != nil {
+	if fn != nil && !fn.cu.image.Stripped() && !r.SystemStack && it.g != nil && it.opts&StacktraceFramePointers == 0 {
 		dwarfTree, _ := fn.cu.image.getDwarfTree(fn.offset)
 		if dwarfTree != nil {
 			c := readLocalPtrVar(dwarfTree, goClosurePtr, it.target, it.bi, fn.cu.image, r.Regs, it.mem)
@@ -598,6 +691,18 @@ func (it *stackIterator) advanceRegs() (callFrameRegs op.DwarfRegisters, ret uin
 			}
 		}
 
+		if it.opts&StacktraceFramePointers != 0 && (it.bi.Arch.Name == "amd64" || it.bi.Arch.Name == "arm64") {
+			// Frame pointer unwinding was explicitly requested: once the
+			// topmost frame has been unwound using DWARF the frame pointer chain
+			// is followed without checking that it is stable first.
+			// If the topmost function has not saved the frame pointer yet (or
+			// has already restored it) BP still belongs to its caller.
+			if !stable {
+				callFrameRegs.AddReg(callFrameRegs.BPRegNum, op.DwarfRegisterFromUint64(it.regs.BP()))
+			}
+			stable = true
+		}
+
 		if stable {
 			it.canUseFP = true
 			// AArch64: DWARF may omit X29 (BP) rules; inject savedBP when missing
@@ -625,21 +730,32 @@ func (it *stackIterator) tryFramePointerUnwind() (callFrameRegs op.DwarfRegister
 		return op.DwarfRegisters{}, 0, 0, false
 	}
 
+	fail := func(reason string) (op.DwarfRegisters, uint64, uint64, bool) {
+		if it.opts&StacktraceFramePointers != 0 {
+			it.fpFallback = reason
+		}
+		return op.DwarfRegisters{}, 0, 0, false
+	}
+
 	bp := it.regs.BP()
 	if bp == 0 {
+		// end of the frame pointer chain
 		return op.DwarfRegisters{}, 0, 0, false
 	}
 
 	if it.g != nil && !it.systemstack {
 		if bp < it.g.stack.lo || bp >= it.g.stack.hi {
-			return op.DwarfRegisters{}, 0, 0, false
+			return fail(fmt.Sprintf("frame pointer %#x outside of goroutine stack", bp))
 		}
 	}
 
 	fn := it.bi.PCToFunc(it.pc)
 	// Frame pointer conventions are only guaranteed for Go code.
-	if fn == nil || !fn.cu.isgo {
-		return op.DwarfRegisters{}, 0, 0, false
+	if fn == nil {
+		return fail(fmt.Sprintf("unknown function at %#x", it.pc))
+	}
+	if !fn.cu.isgo {
+		return fail("not Go code")
 	}
 
 	switch fn.Name {
@@ -658,19 +774,19 @@ func (it *stackIterator) tryFramePointerUnwind() (callFrameRegs op.DwarfRegister
 
 	savedBP, err := readUintRaw(it.mem, bp, int64(ptrSize))
 	if err != nil {
-		return op.DwarfRegisters{}, 0, 0, false
+		return fail(fmt.Sprintf("could not read saved frame pointer: %v", err))
 	}
 
 	retaddr = bp + ptrSize
 	ret, err = readUintRaw(it.mem, retaddr, int64(ptrSize))
 	if err != nil {
-		return op.DwarfRegisters{}, 0, 0, false
+		return fail(fmt.Sprintf("could not read return address: %v", err))
 	}
 
 	cfa := int64(bp + 2*ptrSize)
 
 	if ret == 0 || it.bi.PCToFunc(ret) == nil {
-		return op.DwarfRegisters{}, 0, 0, false
+		return fail(fmt.Sprintf("invalid return address %#x", ret))
 	}
 
 	it.regs.CFA = cfa
@@ -696,6 +812,7 @@ func (it *stackIterator) tryFramePointerUnwind() (callFrameRegs op.DwarfRegister
 		logflags.StackLogger().Debugf("advanceRegs (fp) at %#x: BP=%#x savedBP=%#x ret=%#x CFA=%#x", it.pc, bp, savedBP, ret, cfa)
 	}
 
+	it.fpFrames++
 	return callFrameRegs, ret, retaddr, true
 }
 
@@ -893,6 +1010,12 @@ type Defer struct {
 
 	rangefunc []*Defer // See explanation in $GOROOT/src/runtime/panic.go, comment to function runtime.deferrangefunc (this is the equivalent of the rangefunc variable and head fields, combined)
 
+	// OpenCoded is true for calls deferred with open-coded defers, these are
+	// not recorded in the defer chain of the goroutine, DeferPC is the entry
+	// point of the function that deferred the call and SP is the value of
+	// CFA for its frame.
+	OpenCoded bool
+
 	variable   *Variable
 	Unreadable error
 }
@@ -947,9 +1070,202 @@ func (g *G) readDefers(frames []Stackframe) {
 
 const maxRangeFuncDefers = 10
 
+// funcdataOpenCodedDeferInfo is the index of FUNCDATA_OpenCodedDeferInfo,
+// see $GOROOT/src/internal/abi/symtab.go.
+const funcdataOpenCodedDeferInfo = 4
+
+// maxGoroutineDefers is the maximum number of deferred calls returned by
+// (*G).Defers.
+const maxGoroutineDefers = 1000
+
+// Defers returns the deferred calls of the goroutine that haven't been
+// executed yet, starting with the most recently deferred one, which is the
+// first one that will run.
+// Calls deferred using open-coded defers (which the compiler uses for
+// optimized functions) are only returned for programs built with Go 1.22 or
+// later.
+// If an unreadable defer record is found it is returned as the last element
+// of the list.
+func (g *G) Defers(tgt *Target) []*Defer {
+	frames, err := GoroutineStacktrace(tgt, g, maxGoroutineDefers, StacktraceReadDefers)
+	if err != nil {
+		return g.linkedDefers()
+	}
+
+	var mds []ModuleData
+	if goversion.ProducerAfterOrEqual(g.variable.bi.Producer(), 1, 22) {
+		mds, _ = LoadModuleData(g.variable.bi, g.variable.mem)
+	}
+
+	// The runtime runs the open-coded defers of a frame before the defers
+	// in the defer chain that have the same SP, see runtime.(*_panic).nextDefer.
+	var r []*Defer
+	for i := range frames {
+		r = append(r, frames[i].openCodedDefers(mds, g.variable.bi, g.variable.mem)...)
+		r = append(r, frames[i].Defers...)
+		if len(r) >= maxGoroutineDefers {
+			return r[:maxGoroutineDefers]
+		}
+	}
+	return r
+}
+
+// linkedDefers returns the deferred calls recorded in the defer chain of
+// the goroutine.
+func (g *G) linkedDefers() []*Defer {
+	var r []*Defer
+	for d := g.Defer(); d != nil && len(r) < maxGoroutineDefers; d = d.Next() {
+		if d.Unreadable != nil {
+			r = append(r, d)
+			break
+		}
+		if len(d.rangefunc) > 0 {
+			r = append(r, d.rangefunc...)
+		} else {
+			r = append(r, d)
+		}
+	}
+	return r
+}
+
+// openCodedDefers returns the pending calls deferred with open-coded
+// defers by the function of frame, in the order they will be executed.
+// See runtime.(*_panic).initOpenCodedDefers.
+func (frame *Stackframe) openCodedDefers(mds []ModuleData, bi *BinaryInfo, mem MemoryReadWriter) []*Defer {
+	if frame.Err != nil || frame.Inlined || frame.SystemStack || frame.Call.Fn == nil {
+		return nil
+	}
+	md := findModuleDataForPC(mds, frame.Call.PC)
+	if md == nil {
+		return nil
+	}
+	fd, err := md.funcdata(frame.Call.PC, funcdataOpenCodedDeferInfo)
+	if err != nil || fd == 0 {
+		return nil
+	}
+	var buf [2 * binary.MaxVarintLen32]byte
+	if _, err := mem.ReadMemory(buf[:], fd); err != nil {
+		return nil
+	}
+	deferBitsOffset, n := binary.Uvarint(buf[:])
+	if n <= 0 {
+		return nil
+	}
+	slotsOffset, n2 := binary.Uvarint(buf[n:])
+	if n2 <= 0 {
+		return nil
+	}
+
+	// Compute varp the same way runtime.(*unwinder).resolveInternal does.
+	ptrSize := uint64(bi.Arch.PtrSize())
+	varp := uint64(frame.Regs.CFA)
+	if !bi.Arch.usesLR {
+		varp -= ptrSize
+	}
+	if varp > frame.Regs.SP() && (bi.Arch.Name == "amd64" || bi.Arch.Name == "arm64") {
+		varp -= ptrSize
+	}
+
+	var deferBits [1]byte
+	if _, err := mem.ReadMemory(deferBits[:], varp-deferBitsOffset); err != nil {
+		return nil
+	}
+	slots := varp - slotsOffset
+
+	var r []*Defer
+	for i := 7; i >= 0; i-- {
+		if deferBits[0]&(1<<i) == 0 {
+			continue
+		}
+		d := &Defer{DeferPC: frame.Call.Fn.Entry, SP: uint64(frame.Regs.CFA), OpenCoded: true}
+		closure, err := readUintRaw(mem, slots+uint64(i)*ptrSize, int64(ptrSize))
+		if err == nil {
+			d.DwrapPC, err = readUintRaw(mem, closure, int64(ptrSize))
+		}
+		if err != nil {
+			d.Unreadable = err
+		}
+		r = append(r, d)
+	}
+	return r
+}
+
+// maxGoroutinePanics is the maximum number of panics returned by
+// (*G).Panics.
+const maxGoroutinePanics = 100
+
+// Panic describes a panic (or a call to runtime.Goexit) in progress on a
+// goroutine.
+type Panic struct {
+	Value      *Variable // argument of the call to panic, nil for runtime.Goexit
+	Recovered  bool      // a deferred call recovered this panic
+	Repanicked bool      // a deferred call re-raised this panic after recovering it
+	Goexit     bool      // this is a call to runtime.Goexit rather than a panic
+
+	// PC and FP are the program counter and the frame pointer (i.e. the CFA)
+	// of the stack frame whose deferred calls are being run. If this panic is
+	// recovered this is the frame that will return normally to its caller.
+	// They are zero if no deferred calls have been run yet.
+	PC, FP uint64
+
+	Unreadable error
+}
+
+// Panics returns the panics in progress on the goroutine, starting with the
+// most recent one. The argument of each panic is loaded using cfg.
+func (g *G) Panics(cfg LoadConfig) []*Panic {
+	if g.variable.Unreadable != nil {
+		return nil
+	}
+	pvar, _ := g.variable.structField("_panic")
+	if pvar == nil {
+		return nil
+	}
+	pvar = pvar.maybeDereference()
+	var r []*Panic
+	for pvar.Addr != 0 && len(r) < maxGoroutinePanics {
+		v := pvar // +rtype _panic
+		p := &Panic{}
+		arg, _ := v.structField("arg") // +rtype any
+		v.loadValue(LoadConfig{MaxVariableRecurse: 1, MaxStructFields: -1})
+		if v.Unreadable != nil {
+			p.Unreadable = v.Unreadable
+			r = append(r, p)
+			break
+		}
+
+		p.PC, _ = constant.Uint64Val(v.fieldVariable("pc").Value)            // +rtype uintptr
+		p.FP = v.fieldVariable("fp").maybeDereference().Addr                 // +rtype unsafe.Pointer
+		p.Recovered = constant.BoolVal(v.fieldVariable("recovered").Value)   // +rtype bool
+		p.Repanicked = constant.BoolVal(v.fieldVariable("repanicked").Value) // +rtype bool
+		p.Goexit = constant.BoolVal(v.fieldVariable("goexit").Value)         // +rtype bool
+		fake := constant.BoolVal(v.fieldVariable("deferreturn").Value)       // +rtype bool
+
+		// panics with deferreturn set are used by runtime.deferreturn to run
+		// open-coded defers and are not real panics.
+		if !fake {
+			if !p.Goexit && arg != nil {
+				arg.loadValue(cfg)
+				p.Value = arg
+			}
+			r = append(r, p)
+		}
+
+		linkvar := v.fieldVariable("link").maybeDereference() // +rtype *_panic
+		if linkvar.Addr != 0 && linkvar.Addr <= pvar.Addr {
+			// panics are allocated on the stack of gopanic, earlier panics are at
+			// higher addresses
+			r = append(r, &Panic{Unreadable: errors.New("corrupted panic list")})
+			break
+		}
+		pvar = linkvar
+	}
+	return r
+}
+
 func (d *Defer) load(canrecur bool) {
 	v := d.variable // +rtype _defer
-	v.loadValue(LoadConfig{false, 1, 0, 0, -1, 0})
+	v.loadValue(LoadConfig{MaxVariableRecurse: 1, MaxStructFields: -1})
 	if v.Unreadable != nil {
 		d.Unreadable = v.Unreadable
 		return
@@ -22,6 +22,18 @@ import (
 //
 // These breakpoints are created by setStackWatchBreakpoints and cleared by
 // clearStackWatchBreakpoints.
+//
+// If the watchpoint has WatchFollow set, instead of clearing it when it goes
+// out of scope we set a WatchRearmBreakpoint on the function containing the
+// watched variable and set the watchpoint again, on the new frame, when it
+// is hit.
+
+// watchRearmRequest is a WatchRearmBreakpoint hit by thread, processed
+// after all breakpoint conditions have been evaluated.
+type watchRearmRequest struct {
+	watchpoint *Breakpoint
+	thread     Thread
+}
 
 // setStackWatchBreakpoints sets the out of scope sentinel breakpoints for
 // watchpoint and a stack resize breakpoint.
@@ -29,6 +41,10 @@ func (t *Target) setStackWatchBreakpoints(scope *EvalScope, watchpoint *Breakpoi
 	// Watchpoint Out-of-scope Sentinel
 
 	woos := func(_ Thread, _ *Target) (bool, error) {
+		if watchpoint.Logical != nil && watchpoint.Logical.WatchFollow && watchpoint.watchFn != nil {
+			t.Breakpoints().watchFollow = append(t.Breakpoints().watchFollow, watchpoint)
+			return false, nil
+		}
 		t.Breakpoints().WatchOutOfScope = append(t.Breakpoints().WatchOutOfScope, watchpoint)
 		return true, nil
 	}
@@ -119,6 +135,68 @@ func (t *Target) setStackWatchBreakpoints(scope *EvalScope, watchpoint *Breakpoi
 	return nil
 }
 
+// setWatchRearmBreakpoint clears watchpoint, which went out of scope, and
+// sets a WatchRearmBreakpoint after the prologue of the function containing
+// the watched variable.
+func (t *Target) setWatchRearmBreakpoint(watchpoint *Breakpoint) error {
+	lbp := watchpoint.Logical
+	if err := t.ClearBreakpoint(watchpoint.Addr); err != nil {
+		return err
+	}
+	// Clearing the last physical breakpoint of a watchpoint also deletes its
+	// logical breakpoint, which we want to keep.
+	t.Breakpoints().Logical[lbp.LogicalID] = lbp
+	pc, err := FirstPCAfterPrologue(t, watchpoint.watchFn, false)
+	if err != nil {
+		return err
+	}
+	bp, err := t.SetBreakpoint(0, pc, WatchRearmBreakpoint, goroutineCondition(watchpoint.watchGoid))
+	if err != nil {
+		return err
+	}
+	breaklet := bp.Breaklets[len(bp.Breaklets)-1]
+	breaklet.watchpoint = watchpoint
+	breaklet.callback = func(th Thread, _ *Target) (bool, error) {
+		t.Breakpoints().watchRearm = append(t.Breakpoints().watchRearm, watchRearmRequest{watchpoint, th})
+		return false, nil // the user is not interested in this breakpoint
+	}
+	return nil
+}
+
+// rearmWatchpoint sets watchpoint again, on the topmost frame of the
+// goroutine running on thread, and removes its WatchRearmBreakpoint.
+func (t *Target) rearmWatchpoint(watchpoint *Breakpoint, thread Thread) error {
+	if err := t.clearStackWatchBreakpoints(watchpoint); err != nil {
+		return err
+	}
+	lbp := watchpoint.Logical
+	if lbp == nil || t.Breakpoints().Logical[lbp.LogicalID] != lbp || !lbp.enabled {
+		// the watchpoint was cleared or disabled while waiting to be re-armed
+		return nil
+	}
+	scope, err := GoroutineScope(t, thread)
+	if err != nil {
+		return err
+	}
+	if scope.g == nil {
+		return errors.New("could not find goroutine")
+	}
+	// The watched variable could be out of its lexical scope here, since we
+	// are at the start of the function, but local variables of Go functions
+	// have a fixed position in the stack frame.
+	addr := uint64(scope.Regs.CFA + watchpoint.watchFrameOff)
+	bp, err := t.setBreakpointInternal(lbp.LogicalID, addr, UserBreakpoint, watchpoint.WatchType, nil)
+	if err != nil {
+		return err
+	}
+	bp.WatchExpr = watchpoint.WatchExpr
+	bp.watchStackOff = int64(bp.Addr) - int64(scope.g.stack.hi)
+	bp.watchFn = watchpoint.watchFn
+	bp.watchFrameOff = watchpoint.watchFrameOff
+	bp.watchGoid = watchpoint.watchGoid
+	return t.setStackWatchBreakpoints(scope, bp)
+}
+
 // clearStackWatchBreakpoints clears all accessory breakpoints for
 // watchpoint.
 func (t *Target) clearStackWatchBreakpoints(watchpoint *Breakpoint) error {
@@ -26,6 +26,14 @@ var (
 
 	// ErrProcessDetached indicates that we detached from the target process.
 	ErrProcessDetached = errors.New("detached from the process")
+
+	// ErrSignalPolicyNotSupported is returned by SetSignalPolicy when the
+	// backend does not support signal policies.
+	ErrSignalPolicyNotSupported = errors.New("signal policies are not supported by this backend")
+
+	// ErrNanotimeUnavailable is returned by Nanotime when the target's clock
+	// can not be read.
+	ErrNanotimeUnavailable = errors.New("the clock of the target is not available")
 )
 
 type LaunchFlags uint8
@@ -69,6 +77,10 @@ type Target struct {
 	gcache goroutineCache
 	// scache is a cache for stack traces.
 	scache stackCache
+	// memmap is a cache for the memory map of the target, see
+	// cachedMemoryMap. This must be cleared whenever the target is resumed.
+	memmap    []MemoryMapEntry
+	memmapErr error
 
 	iscgo *bool
 
@@ -83,6 +95,7 @@ type Target struct {
 	fakeMemoryRegistryMap map[string]*compositeMemory
 
 	partOfGroup bool
+	grp         *TargetGroup
 
 	// onInitialGoImage ensures that Go-specific breakpoints are only set up
 	// once when transitioning from no Go images to having a Go image.
@@ -146,6 +159,8 @@ func (sr StopReason) String() string {
 		return "watchpoint"
 	case StopSharedLibLoaded:
 		return "shared library loaded"
+	case StopSignal:
+		return "signal"
 	default:
 		return ""
 	}
@@ -163,6 +178,7 @@ const (
 	StopCallReturned                   // An injected call completed
 	StopWatchpoint                     // The target process hit one or more watchpoints
 	StopSharedLibLoaded                // A Go shared library was loaded
+	StopSignal                         // The target process received a signal whose policy requires stopping
 )
 
 // DisableAsyncPreemptEnv returns a process environment (like os.Environ)
@@ -187,11 +203,13 @@ func (grp *TargetGroup) newTarget(p ProcessInternal, pid int, currentThread Thre
 		return nil, err
 	}
 
-	err = p.BinInfo().LoadBinaryInfo(path, entryPoint, grp.cfg.DebugInfoDirs)
+	err = p.BinInfo().LoadBinaryInfo(path, entryPoint, grp.cfg.DebugInfoDirs, grp.cfg.DebugInfoFile)
 	if err != nil {
 		// If this is a non-Go binary, log a warning and continue.
 		// A Go shared object may be loaded later via dlopen.
-		if len(p.BinInfo().Images) > 0 && !p.BinInfo().Images[0].IsGo {
+		// Errors loading a debug info file explicitly specified by the user
+		// are always reported.
+		if len(p.BinInfo().Images) > 0 && !p.BinInfo().Images[0].IsGo && grp.cfg.DebugInfoFile == "" {
 			logflags.DebuggerLogger().Warnf("Initial binary is not a Go binary: %v", err)
 		} else {
 			return nil, &ErrBadBinaryInfo{Err: err}
@@ -210,6 +228,7 @@ func (grp *TargetGroup) newTarget(p ProcessInternal, pid int, currentThread Thre
 		currentThread: currentThread,
 		pid:           pid,
 		CmdLine:       cmdline,
+		grp:           grp,
 	}
 
 	if recman, ok := p.(RecordingManipulationInternal); ok {
@@ -288,12 +307,23 @@ func (t *Target) ClearCaches() {
 	t.clearFakeMemory()
 	clear(t.scache.m)
 	t.gcache.Clear()
+	t.memmap, t.memmapErr = nil, nil
 	t.BinInfo().moduleDataCache = nil
 	for _, thread := range t.ThreadList() {
 		thread.Common().g = nil
 	}
 }
 
+// Nanotime returns the current value of the target's monotonic clock, the
+// one used by runtime.nanotime. This is only possible for live processes
+// running on operating systems where this clock can be read by Delve.
+func (t *Target) Nanotime() (int64, error) {
+	if recorded, _ := t.recman.Recorded(); recorded {
+		return 0, ErrNanotimeUnavailable
+	}
+	return hostNanotime()
+}
+
 // Restart will start the process group over from the location specified by the "from" locspec.
 // This is only useful for recorded targets.
 // Restarting of a normal process happens at a higher level (debugger.Restart).
@@ -352,6 +382,45 @@ func (t *Target) SwitchThread(tid int) error {
 	return fmt.Errorf("thread %d does not exist", tid)
 }
 
+// SetRegister changes the value of the general purpose register called
+// name of thread tid to value.
+func (t *Target) SetRegister(tid int, name string, value uint64) error {
+	if ok, err := t.Valid(); !ok {
+		return err
+	}
+	thread, ok := t.FindThread(tid)
+	if !ok {
+		return fmt.Errorf("thread %d does not exist", tid)
+	}
+	arch := t.BinInfo().Arch
+	regnum, ok := arch.RegisterNameToDwarf(name)
+	if !ok {
+		return fmt.Errorf("unknown register %s", name)
+	}
+	regs, err := thread.Registers()
+	if err != nil {
+		return err
+	}
+	reg := arch.RegistersToDwarfRegisters(0, regs).Reg(uint64(regnum))
+	if reg == nil {
+		return fmt.Errorf("unknown register %s", name)
+	}
+	if len(reg.Bytes) > 8 {
+		return fmt.Errorf("register %s is not a general purpose register", name)
+	}
+	err = thread.SetReg(uint64(regnum), op.DwarfRegisterFromUint64(value))
+	if err != nil {
+		return err
+	}
+	// Changing registers can change the location of the thread and the
+	// goroutine running on it.
+	t.ClearCaches()
+	if g := t.selectedGoroutine; g != nil && g.Thread != nil && g.Thread.ThreadID() == tid {
+		t.selectedGoroutine, _ = GetG(thread)
+	}
+	return nil
+}
+
 // setAsyncPreemptOff enables or disables async goroutine preemption by
 // writing the value 'v' to runtime.debug.asyncpreemptoff.
 // A value of '1' means off, a value of '0' means on.
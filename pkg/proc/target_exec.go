@@ -23,6 +23,8 @@ import (
 
 const maxSkipAutogeneratedWrappers = 5 // maximum recursion depth for skipAutogeneratedWrappers
 
+const asyncPreemptFnName = "runtime.asyncPreempt"
+
 // ErrNoSourceForPC is returned when the given address
 // does not correspond with a source file location.
 type ErrNoSourceForPC struct {
@@ -43,7 +45,7 @@ func (grp *TargetGroup) Next() (err error) {
 		return errors.New("next while nexting")
 	}
 
-	if err = next(grp.Selected, false, false); err != nil {
+	if err = next(grp.Selected, false, false, false); err != nil {
 		grp.Selected.ClearSteppingBreakpoints()
 		return
 	}
@@ -78,6 +80,7 @@ func (grp *TargetGroup) Continue() error {
 			grp.finishManualStop()
 		}
 	}()
+	defer grp.discardHandledExceptions()
 	for {
 		err := grp.manageUnsatisfiableBreakpoints()
 		if err != nil {
@@ -114,7 +117,11 @@ func (grp *TargetGroup) Continue() error {
 			for _, thread := range it.ThreadList() {
 				if thread.Breakpoint().Breakpoint != nil {
 					it.currentThread = thread
-					thread.Breakpoint().Breakpoint.checkCondition(it.Target, thread, thread.Breakpoint())
+					bp := thread.Breakpoint().Breakpoint
+					// With SingleGoroutineStepping user breakpoints hit by goroutines
+					// other than the one being stepped are not counted as hits.
+					skipUser := grp.SingleGoroutineStepping && skippableWhileStepping(it.Target, thread, bp)
+					bp.checkCondition(it.Target, thread, thread.Breakpoint(), skipUser)
 				}
 			}
 			it.currentThread = curthread
@@ -126,6 +133,24 @@ func (grp *TargetGroup) Continue() error {
 				}
 				delete(it.Breakpoints().Logical, watchpoint.LogicalID())
 			}
+			for _, watchpoint := range it.Breakpoints().watchFollow {
+				if err := it.setWatchRearmBreakpoint(watchpoint); err != nil {
+					logflags.DebuggerLogger().Errorf("could not set re-arm breakpoint for out-of-scope watchpoint: %v", err)
+				}
+			}
+			it.Breakpoints().watchFollow = nil
+			for _, req := range it.Breakpoints().watchRearm {
+				if err := it.rearmWatchpoint(req.watchpoint, req.thread); err != nil {
+					logflags.DebuggerLogger().Errorf("could not re-arm watchpoint: %v", err)
+				}
+			}
+			it.Breakpoints().watchRearm = nil
+			for _, req := range it.Breakpoints().watchAlloc {
+				if err := it.handleWatchAlloc(req); err != nil {
+					logflags.DebuggerLogger().Errorf("could not set field watchpoint: %v", err)
+				}
+			}
+			it.Breakpoints().watchAlloc = nil
 			// Clear inactivated breakpoints
 			err := it.clearInactivatedSteppingBreakpoint()
 			if err != nil {
@@ -213,6 +238,8 @@ func (grp *TargetGroup) Continue() error {
 			return hcbpErr
 		}
 
+		stopOnSignal := grp.reportSignals()
+
 		curthread := dbp.CurrentThread()
 		curbp := curthread.Breakpoint()
 
@@ -237,7 +264,7 @@ func (grp *TargetGroup) Continue() error {
 				if err := dbp.ClearSteppingBreakpoints(); err != nil {
 					return err
 				}
-				if err := next(dbp, false, false); err != nil {
+				if err := next(dbp, false, false, false); err != nil {
 					return err
 				}
 				// Target execution continues...
@@ -274,6 +301,9 @@ func (grp *TargetGroup) Continue() error {
 				dbp.StopReason = StopSharedLibLoaded
 			}
 			return conditionErrors(grp)
+		case stopOnSignal:
+			dbp.StopReason = StopSignal
+			return conditionErrors(grp)
 		case stopReason == StopLaunched:
 			return nil
 		default:
@@ -287,6 +317,28 @@ func (grp *TargetGroup) Continue() error {
 	}
 }
 
+// reportSignals sends an EventSignal for every thread that received a
+// signal whose policy has Print set and returns true if the policy of any
+// of them has Stop set.
+func (grp *TargetGroup) reportSignals() bool {
+	stop := false
+	it := ValidTargets{Group: grp}
+	for it.Next() {
+		for _, th := range it.ThreadList() {
+			sig := th.Common().Signal
+			if sig == 0 {
+				continue
+			}
+			policy := grp.cctx.SignalPolicy(sig)
+			stop = stop || policy.Stop
+			if policy.Print && grp.eventsFn != nil {
+				grp.eventsFn(&Event{Kind: EventSignal, SignalEventDetails: &SignalEventDetails{Signal: sig, ThreadID: th.ThreadID(), Stopped: policy.Stop}})
+			}
+		}
+	}
+	return stop
+}
+
 func (grp *TargetGroup) finishManualStop() {
 	for _, dbp := range grp.targets {
 		if isvalid, _ := dbp.Valid(); !isvalid {
@@ -334,6 +386,35 @@ func isTraceOrTraceReturn(bp *Breakpoint) bool {
 	return bp.Logical.Tracepoint || bp.Logical.TraceReturn
 }
 
+// discardHandledExceptions clears the exceptions recorded on the threads of
+// targets that did not stop because of a crash. Those exceptions were
+// handled by the program, for example a nil pointer dereference that
+// caused a panic that was later recovered, and are no longer relevant.
+func (grp *TargetGroup) discardHandledExceptions() {
+	for _, dbp := range grp.targets {
+		if isvalid, _ := dbp.Valid(); !isvalid {
+			continue
+		}
+		if bp := dbp.CurrentThread().Breakpoint().Breakpoint; bp != nil && (bp.LogicalID() == unrecoveredPanicID || bp.LogicalID() == fatalThrowID) {
+			continue
+		}
+		for _, thread := range dbp.ThreadList() {
+			thread.Common().Exception = nil
+		}
+	}
+}
+
+// skippableWhileStepping returns true if bp is a user breakpoint, hit by
+// thread, that can be ignored because a stepping command is in progress on
+// a different goroutine.
+func skippableWhileStepping(tgt *Target, thread Thread, bp *Breakpoint) bool {
+	if bp.LogicalID() <= 0 || isTraceOrTraceReturn(bp) || !tgt.Breakpoints().HasSteppingBreakpoints() {
+		return false
+	}
+	onNext, err := onNextGoroutine(tgt, thread, tgt.Breakpoints())
+	return err == nil && !onNext
+}
+
 func conditionErrors(grp *TargetGroup) error {
 	var condErr error
 	for _, dbp := range grp.targets {
@@ -494,7 +575,7 @@ func (grp *TargetGroup) Step() (err error) {
 		return errors.New("next while nexting")
 	}
 
-	if err = next(grp.Selected, true, false); err != nil {
+	if err = next(grp.Selected, true, grp.StepSkipNoDebug, false); err != nil {
 		_ = grp.Selected.ClearSteppingBreakpoints()
 		return err
 	}
@@ -571,7 +652,7 @@ func (grp *TargetGroup) StepOut() error {
 	}()
 
 	if topframe.Inlined {
-		if err := next(dbp, false, true); err != nil {
+		if err := next(dbp, false, false, true); err != nil {
 			return err
 		}
 
@@ -619,6 +700,42 @@ func (grp *TargetGroup) StepOut() error {
 	return grp.Continue()
 }
 
+// StepInstructions calls StepInstruction count times. It stops early if
+// the current thread reaches a user breakpoint, or watchpoint, whose
+// condition is satisfied, if a manual stop is requested or if a step
+// resumes the target (for example to step over a call) and it stops for a
+// reason other than the step completing.
+func (grp *TargetGroup) StepInstructions(skipCalls bool, count int) error {
+	for i := 0; i < count; i++ {
+		if i > 0 && grp.cctx.CheckAndClearManualStopRequest() {
+			grp.Selected.StopReason = StopManual
+			return nil
+		}
+		if err := grp.StepInstruction(skipCalls); err != nil {
+			return err
+		}
+		dbp := grp.Selected
+		if dbp.StopReason != StopNextFinished || i == count-1 {
+			return nil
+		}
+		thread := dbp.CurrentThread()
+		bpstate := thread.Breakpoint()
+		if bpstate.Breakpoint == nil || bpstate.Breakpoint.LogicalID() <= 0 {
+			continue
+		}
+		bpstate.Breakpoint.checkCondition(dbp, thread, bpstate, false)
+		if bpstate.Active {
+			if bpstate.Breakpoint.WatchType != 0 {
+				dbp.StopReason = StopWatchpoint
+			} else {
+				dbp.StopReason = StopBreakpoint
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
 // StepInstruction will continue the current thread for exactly
 // one instruction. This method affects only the thread
 // associated with the selected goroutine. All other
@@ -648,9 +765,26 @@ func (grp *TargetGroup) StepInstruction(skipCalls bool) (err error) {
 		return err
 	}
 	isCall = len(instr) > 0 && instr[0].IsCall()
-	err = grp.procgrp.StepInstruction(thread.ThreadID())
-	if err != nil {
-		return err
+	wasAsyncPreempt := inAsyncPreempt(thread)
+	recorded, _ := dbp.recman.Recorded()
+	for i := 0; ; i++ {
+		err = grp.procgrp.StepInstruction(thread.ThreadID())
+		if err != nil {
+			return err
+		}
+		if wasAsyncPreempt || recorded || i >= maxAsyncPreemptRetries {
+			break
+		}
+		// Some backends do not delay signals during a single step, if the
+		// preemption signal was delivered the thread is now at the start of
+		// runtime.asyncPreempt. Undo the preemption and step again.
+		undone, err := undoAsyncPreempt(thread)
+		if err != nil {
+			return err
+		}
+		if !undone {
+			break
+		}
 	}
 	thread.Breakpoint().Clear()
 	err = thread.SetCurrentBreakpoint(false)
@@ -676,6 +810,8 @@ func (grp *TargetGroup) StepInstruction(skipCalls bool) (err error) {
 // a breakpoint of kind StepBreakpoint is set on the CALL instruction,
 // Continue will take care of setting a breakpoint to the destination
 // once the CALL is reached.
+// If skipNoDebug is also true calls to functions that don't have line
+// information are stepped over.
 //
 // Regardless of stepInto the following breakpoints will be set:
 //   - a breakpoint on the first deferred function with NextDeferBreakpoint
@@ -696,7 +832,7 @@ func (grp *TargetGroup) StepInstruction(skipCalls bool) (err error) {
 // for an inlined function call. Everything works the same as normal except
 // when removing instructions belonging to inlined calls we also remove all
 // instructions belonging to the current inlined call.
-func next(dbp *Target, stepInto, inlinedStepOut bool) error {
+func next(dbp *Target, stepInto, skipNoDebug, inlinedStepOut bool) error {
 	backward := dbp.recman.GetDirection() == Backward
 	selg := dbp.SelectedGoroutine()
 	curthread := dbp.CurrentThread()
@@ -706,6 +842,13 @@ func next(dbp *Target, stepInto, inlinedStepOut bool) error {
 		return err
 	}
 
+	if !backward && isAsyncPreemptFrame(&topframe) {
+		topframe, retframe, err = asyncPreemptInterruptedFrame(dbp, selg, curthread)
+		if err != nil {
+			return err
+		}
+	}
+
 	if topframe.Current.Fn == nil {
 		return &ErrNoSourceForPC{topframe.Current.PC}
 	}
@@ -773,7 +916,7 @@ func next(dbp *Target, stepInto, inlinedStepOut bool) error {
 	sameFrameCond := astutil.And(sameGCond, frameoffCondition(&topframe))
 
 	if stepInto && !backward {
-		err := setStepIntoBreakpoints(dbp, topframe.Current.Fn, text, topframe, sameGCond)
+		err := setStepIntoBreakpoints(dbp, topframe.Current.Fn, text, topframe, sameGCond, skipNoDebug)
 		if err != nil {
 			return err
 		}
@@ -947,7 +1090,83 @@ func next(dbp *Target, stepInto, inlinedStepOut bool) error {
 	return nil
 }
 
-func setStepIntoBreakpoints(dbp *Target, curfn *Function, text []AsmInstruction, topframe Stackframe, sameGCond ast.Expr) error {
+// maxAsyncPreemptRetries is the maximum number of times StepInstruction
+// will undo an asynchronous preemption and step again.
+const maxAsyncPreemptRetries = 5
+
+// inAsyncPreempt returns true if thread is stopped inside
+// runtime.asyncPreempt.
+func inAsyncPreempt(thread Thread) bool {
+	loc, _ := ThreadLocation(thread)
+	return loc != nil && loc.Fn != nil && loc.Fn.Name == asyncPreemptFnName
+}
+
+func isAsyncPreemptFrame(frame *Stackframe) bool {
+	return frame.Current.Fn != nil && frame.Current.Fn.Name == asyncPreemptFnName
+}
+
+// asyncPreemptInterruptedFrame returns the frame interrupted by an
+// asynchronous preemption and its return frame, it should be called when
+// the topmost frame is runtime.asyncPreempt.
+// Stepping through the preemption handler would stop the user in runtime
+// internals, instead we let the handler run and step the interrupted
+// function as if it had never been preempted.
+func asyncPreemptInterruptedFrame(dbp *Target, g *G, thread Thread) (Stackframe, Stackframe, error) {
+	var frames []Stackframe
+	var err error
+	if g == nil {
+		frames, err = ThreadStacktrace(dbp, thread, 2)
+	} else {
+		frames, err = GoroutineStacktrace(dbp, g, 2, StacktraceReadDefers)
+	}
+	if err != nil {
+		return Stackframe{}, Stackframe{}, err
+	}
+	switch len(frames) {
+	case 0, 1:
+		return Stackframe{}, Stackframe{}, errors.New("could not find frame interrupted by asynchronous preemption")
+	case 2:
+		return frames[1], Stackframe{}, nil
+	default:
+		return frames[1], frames[2], nil
+	}
+}
+
+// undoAsyncPreempt reverts the call to runtime.asyncPreempt injected by
+// the signal handler of the runtime, if thread is stopped on its first
+// instruction, so that the thread will execute the interrupted instruction
+// next. The preemption request stays pending and the goroutine will be
+// preempted at a later time. See runtime.doSigPreempt.
+// Architectures that use a link register are not supported.
+func undoAsyncPreempt(thread Thread) (bool, error) {
+	bi := thread.BinInfo()
+	if bi.Arch.usesLR {
+		return false, nil
+	}
+	regs, err := thread.Registers()
+	if err != nil {
+		return false, err
+	}
+	pc, sp := regs.PC(), regs.SP()
+	fn := bi.PCToFunc(pc)
+	if fn == nil || fn.Name != asyncPreemptFnName || pc != fn.Entry {
+		return false, nil
+	}
+	ptrSize := int64(bi.Arch.PtrSize())
+	resumePC, err := readUintRaw(thread.ProcessMemory(), sp, ptrSize)
+	if err != nil {
+		return false, err
+	}
+	if err := setPC(thread, resumePC); err != nil {
+		return false, err
+	}
+	if err := setSP(thread, sp+uint64(ptrSize)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func setStepIntoBreakpoints(dbp *Target, curfn *Function, text []AsmInstruction, topframe Stackframe, sameGCond ast.Expr, skipNoDebug bool) error {
 	gostmt := false
 	for _, instr := range text {
 		if instr.Loc.File != topframe.Current.File || instr.Loc.Line != topframe.Current.Line || !instr.IsCall() {
@@ -955,7 +1174,7 @@ func setStepIntoBreakpoints(dbp *Target, curfn *Function, text []AsmInstruction,
 		}
 
 		if instr.DestLoc != nil {
-			if err := setStepIntoBreakpoint(dbp, curfn, []AsmInstruction{instr}, sameGCond); err != nil {
+			if err := setStepIntoBreakpoint(dbp, curfn, []AsmInstruction{instr}, sameGCond, skipNoDebug); err != nil {
 				return err
 			}
 			if curfn != nil && curfn.Name != "runtime." && instr.DestLoc.Fn != nil && instr.DestLoc.Fn.Name == "runtime.newproc" {
@@ -972,6 +1191,9 @@ func setStepIntoBreakpoints(dbp *Target, curfn *Function, text []AsmInstruction,
 			}
 			breaklet := bp.Breaklets[len(bp.Breaklets)-1]
 			breaklet.callback = stepIntoCallback
+			if skipNoDebug {
+				breaklet.callback = stepIntoCallbackSkipNoDebug
+			}
 		}
 	}
 	if gostmt {
@@ -984,6 +1206,16 @@ func setStepIntoBreakpoints(dbp *Target, curfn *Function, text []AsmInstruction,
 // disassembles the current instruction to figure out its destination and
 // sets a breakpoint on it.
 func stepIntoCallback(curthread Thread, p *Target) (bool, error) {
+	return stepIntoCallbackInternal(curthread, p, false)
+}
+
+// stepIntoCallbackSkipNoDebug is like stepIntoCallback but doesn't set a
+// breakpoint if the destination doesn't have line information.
+func stepIntoCallbackSkipNoDebug(curthread Thread, p *Target) (bool, error) {
+	return stepIntoCallbackInternal(curthread, p, true)
+}
+
+func stepIntoCallbackInternal(curthread Thread, p *Target, skipNoDebug bool) (bool, error) {
 	if p.recman.GetDirection() != Forward {
 		// This should never happen, step into breakpoints with callbacks are only
 		// set when moving forward and direction changes are forbidden while
@@ -1009,7 +1241,7 @@ func stepIntoCallback(curthread Thread, p *Target) (bool, error) {
 	// here we either set a breakpoint into the destination of the CALL
 	// instruction or we determined that the called function is hidden,
 	// either way we need to resume execution
-	if err = setStepIntoBreakpoint(p, fn, text, sameGoroutineCondition(p.BinInfo(), g, curthread.ThreadID())); err != nil {
+	if err = setStepIntoBreakpoint(p, fn, text, sameGoroutineCondition(p.BinInfo(), g, curthread.ThreadID()), skipNoDebug); err != nil {
 		return false, err
 	}
 
@@ -1140,7 +1372,7 @@ func colorPCsBetween(pcs []uint64, color []removePC, c removePC, start, end uint
 	}
 }
 
-func setStepIntoBreakpoint(dbp *Target, curfn *Function, text []AsmInstruction, cond ast.Expr) error {
+func setStepIntoBreakpoint(dbp *Target, curfn *Function, text []AsmInstruction, cond ast.Expr, skipNoDebug bool) error {
 	if len(text) == 0 {
 		return nil
 	}
@@ -1185,6 +1417,11 @@ func setStepIntoBreakpoint(dbp *Target, curfn *Function, text []AsmInstruction,
 
 	fn, pc = skipAutogeneratedWrappersIn(dbp, fn, pc, false)
 
+	if skipNoDebug && (fn == nil || fn.cu.lineInfo == nil) {
+		// The call will be stepped over by the breakpoints set by next.
+		return nil
+	}
+
 	// We want to skip the function prologue but we should only do it if the
 	// destination address of the CALL instruction is the entry point of the
 	// function.
@@ -1599,7 +1836,7 @@ func (w *onNextGoroutineWalker) Visit(n ast.Node) ast.Visitor {
 	if binx, isbin := n.(*ast.BinaryExpr); isbin && binx.Op == token.EQL {
 		x := astutil.ExprToString(binx.X)
 		if x == "runtime.curg.goid" || x == "runtime.threadid" {
-			w.ret, w.err = evalBreakpointCondition(w.tgt, w.thread, n.(ast.Expr))
+			w.ret, w.err = evalBreakpointCondition(w.tgt, w.thread, n.(ast.Expr), nil)
 			return nil
 		}
 	}
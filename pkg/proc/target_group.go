@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go/parser"
 	"go/token"
+	"maps"
 	"regexp"
 	"strconv"
 	"strings"
@@ -38,6 +39,29 @@ type TargetGroup struct {
 	// will keep the stepping breakpoints instead of clearing them.
 	KeepSteppingBreakpoints KeepSteppingBreakpoints
 
+	// StepSkipNoDebug, if set, makes Step step over calls to functions that
+	// don't have line information instead of stepping into them.
+	StepSkipNoDebug bool
+
+	// SingleGoroutineStepping, if set, makes Next, Step and StepOut only
+	// stop on the goroutine that started them: breakpoints hit by other
+	// goroutines while stepping are ignored, without counting them as hits,
+	// and execution continues until the stepping goroutine reaches its
+	// destination.
+	// Other goroutines are not blocked while stepping: goroutines are not
+	// bound to threads and the runtime scheduler can not be controlled, so
+	// suspending the other threads could deadlock the stepping goroutine
+	// (for example if it waits on a lock held by another goroutine or on a
+	// stop-the-world garbage collection).
+	SingleGoroutineStepping bool
+
+	// MaxStackDepth is the maximum number of frames unwound before the stack
+	// is assumed to be corrupted, when it is exceeded unwinding stops and the
+	// stacktrace ends with a frame whose Err field is set to ErrStackTooDeep,
+	// this also applies to stacktraces requested with a greater depth. If it
+	// is 0 DefaultMaxStackDepth is used.
+	MaxStackDepth int
+
 	LogicalBreakpoints map[int]*LogicalBreakpoint
 
 	cctx    *ContinueOnceContext
@@ -48,9 +72,11 @@ type TargetGroup struct {
 // NewTargetGroupConfig contains the configuration for a new TargetGroup object,
 type NewTargetGroupConfig struct {
 	DebugInfoDirs       []string   // Directories to search for split debug info
+	DebugInfoFile       string     // File to load the debug info of the executable from, instead of the executable
 	DisableAsyncPreempt bool       // Go 1.14 asynchronous preemption should be disabled
 	StopReason          StopReason // Initial stop reason
 	CanDump             bool       // Can create core dumps (must implement ProcessInternal.MemoryMap)
+	CanSetSignalPolicy  bool       // Backend consults ContinueOnceContext.SignalPolicy when a signal is received
 }
 
 type AddTargetFunc func(ProcessInternal, int, Thread, string, StopReason, string) (*Target, error)
@@ -73,7 +99,8 @@ func NewGroup(procgrp ProcessGroup, cfg NewTargetGroupConfig) (*TargetGroup, Add
 	return grp, grp.addTarget
 }
 
-// Restart copies breakpoints and follow exec status from oldgrp into grp.
+// Restart copies breakpoints, follow exec status and signal policies from
+// oldgrp into grp.
 // Breakpoints that can not be set will be discarded, if discard is not nil
 // it will be called for each discarded breakpoint.
 func Restart(grp, oldgrp *TargetGroup, discard func(*LogicalBreakpoint, error)) {
@@ -100,6 +127,7 @@ func Restart(grp, oldgrp *TargetGroup, discard func(*LogicalBreakpoint, error))
 			delete(grp.LogicalBreakpoints, bp.LogicalID)
 		}
 	}
+	grp.MaxStackDepth = oldgrp.MaxStackDepth
 	if oldgrp.followExecEnabled {
 		rgx := ""
 		if oldgrp.followExecRegex != nil {
@@ -107,6 +135,9 @@ func Restart(grp, oldgrp *TargetGroup, discard func(*LogicalBreakpoint, error))
 		}
 		grp.FollowExec(true, rgx)
 	}
+	for sig, policy := range oldgrp.SignalPolicies() {
+		grp.SetSignalPolicy(sig, policy)
+	}
 }
 
 func (grp *TargetGroup) addTarget(p ProcessInternal, pid int, currentThread Thread, path string, stopReason StopReason, cmdline string) (*Target, error) {
@@ -379,6 +410,8 @@ func enableBreakpointOnTarget(p *Target, lbp *LogicalBreakpoint) error {
 	var err error
 	var addrs []uint64
 	switch {
+	case lbp.Set.FieldWatch != nil:
+		return setFieldWatchAllocBreakpoint(p, lbp)
 	case lbp.Set.File != "":
 		addrs, err = FindFileLocation(p, lbp.Set.File, lbp.Set.Line)
 	case lbp.Set.FunctionName != "":
@@ -427,6 +460,12 @@ func (grp *TargetGroup) disableBreakpoint(lbp *LogicalBreakpoint) error {
 				}
 			}
 		}
+		if lbp.Set.FieldWatch != nil {
+			n++
+			if err := it.clearFieldWatchBreakpoints(lbp); err != nil {
+				errs = append(errs, err)
+			}
+		}
 	}
 	if len(errs) > 0 {
 		buf := new(bytes.Buffer)
@@ -582,6 +621,33 @@ func (grp *TargetGroup) FollowExecEnabled() bool {
 	return grp.followExecEnabled
 }
 
+// SetSignalPolicy sets the policy used when the target process receives
+// signal sig.
+func (grp *TargetGroup) SetSignalPolicy(sig int, policy SignalPolicy) error {
+	if !grp.cfg.CanSetSignalPolicy {
+		return ErrSignalPolicyNotSupported
+	}
+	grp.cctx.StopMu.Lock()
+	defer grp.cctx.StopMu.Unlock()
+	if grp.cctx.signalPolicy == nil {
+		grp.cctx.signalPolicy = make(map[int]SignalPolicy)
+	}
+	if policy == DefaultSignalPolicy {
+		delete(grp.cctx.signalPolicy, sig)
+	} else {
+		grp.cctx.signalPolicy[sig] = policy
+	}
+	return nil
+}
+
+// SignalPolicies returns the signals that do not use DefaultSignalPolicy
+// and their policy.
+func (grp *TargetGroup) SignalPolicies() map[int]SignalPolicy {
+	grp.cctx.StopMu.Lock()
+	defer grp.cctx.StopMu.Unlock()
+	return maps.Clone(grp.cctx.signalPolicy)
+}
+
 // SetEventsFn sets a function that is called to communicate events
 // happening while the target process is running.
 func (grp *TargetGroup) SetEventsFn(eventsFn func(*Event)) {
@@ -641,6 +707,7 @@ type Event struct {
 	*BinaryInfoDownloadEventDetails
 	*BreakpointMaterializedEventDetails
 	*ProcessSpawnedEventDetails
+	*SignalEventDetails
 }
 
 type EventKind uint8
@@ -651,6 +718,7 @@ const (
 	EventBinaryInfoDownload
 	EventBreakpointMaterialized
 	EventProcessSpawned
+	EventSignal
 )
 
 // BinaryInfoDownloadEventDetails describes the details of a BinaryInfoDownloadEvent
@@ -670,3 +738,11 @@ type ProcessSpawnedEventDetails struct {
 	Cmdline    string
 	WillFollow bool
 }
+
+// SignalEventDetails describes the details of a SignalEvent
+type SignalEventDetails struct {
+	Signal   int
+	Name     string // name of the signal, filled in by the caller of SetEventsFn if known
+	ThreadID int
+	Stopped  bool // the target process was stopped because of the signal
+}
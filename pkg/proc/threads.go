@@ -55,6 +55,27 @@ type CommonThread struct {
 	CallReturn   bool // returnValues are the return values of a call injection
 	returnValues []*Variable
 	g            *G // cached g for this thread
+	// Signal is the signal received by this thread that was reported to the
+	// target layer because of its SignalPolicy, 0 if there wasn't one.
+	Signal int
+	// Exception is the last structured exception received by this thread
+	// since the target was resumed, nil if there wasn't one or if the
+	// target didn't stop because of a crash. Only set on windows.
+	Exception *ExceptionInfo
+}
+
+// ExceptionInfo describes a structured exception received by a thread.
+type ExceptionInfo struct {
+	Code        uint32 // exception code, for example 0xc0000005 for access violations
+	Description string // short description of the exception code, if known
+	Address     uint64 // address of the instruction that caused the exception
+	FirstChance bool   // the exception was not yet seen by the program's handlers
+
+	// FaultAddress and Operation are only set for access violations, they
+	// are the inaccessible address and the type of access that was
+	// attempted ("read", "write" or "execute").
+	FaultAddress uint64
+	Operation    string
 }
 
 // ReturnValues reads the return values from the function executing on
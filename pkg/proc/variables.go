@@ -94,6 +94,10 @@ const (
 	variableTrustLen
 
 	variableSaved
+
+	// variableFormatDuration means that ConstDescr should describe the
+	// value of this variable as a time.Duration.
+	variableFormatDuration
 )
 
 // Variable represents a variable. It contains the address, name,
@@ -154,7 +158,13 @@ type LoadConfig struct {
 	// MaxStringLen is the maximum number of bytes read from a string
 	MaxStringLen int
 	// MaxArrayValues is the maximum number of elements read from an array, a slice or a map.
+	// For multidimensional arrays the limit applies to the total number of
+	// elements, in row-major order, unless PerDimension is set.
 	MaxArrayValues int
+	// PerDimension applies MaxArrayValues to each dimension of a
+	// multidimensional array separately, the first MaxArrayValues rows are
+	// loaded, each with its first MaxArrayValues columns.
+	PerDimension bool
 	// MaxStructFields is the maximum number of fields read from a struct, -1 will read all fields.
 	MaxStructFields int
 
@@ -186,11 +196,25 @@ type LoadConfig struct {
 	// sparse map is in scope, but evaluating a single variable will still work
 	// correctly, even if the variable in question is a very sparse map.
 	MaxMapBuckets int
+
+	// RawTime disables the formatting of time.Time and time.Duration values
+	// as human readable strings.
+	RawTime bool
+
+	// LoadChanBuffer requests the elements currently in the buffer of
+	// channels to be loaded, they will be returned, in the order they will be
+	// received, in an additional child of the channel called "buffered".
+	LoadChanBuffer bool
+
+	// LoadSyncMap requests the key/value pairs stored in sync.Map values to
+	// be loaded, they will be returned in an additional child of the
+	// sync.Map called "entries".
+	LoadSyncMap bool
 }
 
-var loadSingleValue = LoadConfig{false, 0, 64, 0, 0, 0}
-var loadFullValue = LoadConfig{true, 1, 64, 64, -1, 0}
-var loadFullValueLongerStrings = LoadConfig{true, 1, 1024 * 1024, 64, -1, 0}
+var loadSingleValue = LoadConfig{MaxStringLen: 64}
+var loadFullValue = LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, MaxStructFields: -1}
+var loadFullValueLongerStrings = LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 1024 * 1024, MaxArrayValues: 64, MaxStructFields: -1}
 
 // G status, from: src/runtime/runtime2.go
 const (
@@ -218,6 +242,9 @@ type G struct {
 	Status  uint64
 	stack   stack // value of stack
 
+	// WaitSince is the value of the target's clock (see Target.Nanotime)
+	// when the goroutine became blocked, as recorded by the runtime. It is
+	// only set by the garbage collector, 0 if unknown.
 	WaitSince  int64
 	WaitReason int64
 
@@ -563,6 +590,16 @@ func (g *G) System(tgt *Target) bool {
 	return strings.HasPrefix(loc.Fn.Name, "runtime.")
 }
 
+// BlockedFor returns how long g has been blocked, now is the current value
+// of the target's clock (see Target.Nanotime). Returns 0 if g isn't
+// blocked or the runtime did not record when it became blocked.
+func (g *G) BlockedFor(now int64) time.Duration {
+	if (g.Status != Gwaiting && g.Status != Gsyscall) || g.WaitSince == 0 || now < g.WaitSince {
+		return 0
+	}
+	return time.Duration(now - g.WaitSince)
+}
+
 func (g *G) Labels() map[string]string {
 	if g.labels != nil {
 		return *g.labels
@@ -1131,6 +1168,39 @@ func (a *Ancestor) Stack(n int) ([]Stackframe, error) {
 	return r, nil
 }
 
+// maxAncestorFrames is the maximum number of frames the runtime saves for
+// each ancestor of a goroutine (runtime.tracebackInnerFrames).
+const maxAncestorFrames = 50
+
+// GoroutineCreationStacktrace returns the stack trace of the goroutine that
+// created g, at the time it executed the 'go' statement that created g.
+// The runtime only saves this stack trace if the target was started with
+// GODEBUG=tracebackancestors=N, otherwise a single frame, for the 'go'
+// statement itself, is returned.
+func GoroutineCreationStacktrace(p *Target, g *G, depth int) ([]Stackframe, error) {
+	if g.GoPC == 0 {
+		return nil, errors.New("goroutine has no creation location")
+	}
+	if ancestors, err := Ancestors(p, g, 1); err == nil && len(ancestors) > 0 {
+		frames, err := ancestors[0].Stack(maxAncestorFrames)
+		if err == nil {
+			// The saved stack trace starts inside runtime.newproc, skip frames up
+			// to the 'go' statement.
+			for i := range frames {
+				if frames[i].Current.PC == g.GoPC {
+					frames = frames[i:]
+					if len(frames) > depth+1 {
+						frames = frames[:depth+1]
+					}
+					return frames, nil
+				}
+			}
+		}
+	}
+	loc := g.Go()
+	return []Stackframe{{Current: loc, Call: loc}}, nil
+}
+
 // structField returns the field named memberName in v.
 // This function is meant for internal use, it does not support interfaces, embeds or member methods. For those cases findStructMemberOrMethod should be used instead.
 func (v *Variable) structField(memberName string) (*Variable, error) {
@@ -1335,6 +1405,230 @@ func (v *Variable) loadValue(cfg LoadConfig) {
 	v.loadValueInternal(0, cfg)
 }
 
+// loadChanBuffer reads the elements currently in the circular buffer of a
+// channel, starting at recvx, and appends them to v.Children as a fake
+// array variable named "buffered".
+func (v *Variable) loadChanBuffer(recurseLevel int, cfg LoadConfig) {
+	field := func(name string) *Variable {
+		for i := range v.Children {
+			if v.Children[i].Name == name {
+				return &v.Children[i]
+			}
+		}
+		return nil
+	}
+	qcountv, dataqsizv, bufv, recvxv := field("qcount"), field("dataqsiz"), field("buf"), field("recvx")
+	if qcountv == nil || dataqsizv == nil || bufv == nil || recvxv == nil || len(bufv.Children) != 1 {
+		return
+	}
+	qcount, err1 := qcountv.asUint()
+	dataqsiz, err2 := dataqsizv.asUint()
+	recvx, err3 := recvxv.asUint()
+	if err1 != nil || err2 != nil || err3 != nil || dataqsiz == 0 || qcount > dataqsiz || recvx >= dataqsiz {
+		return
+	}
+	elemType := v.RealType.(*godwarf.ChanType).ElemType
+	stride := uint64(alignAddr(elemType.Size(), elemType.Align()))
+	base := bufv.Children[0].Addr
+
+	r := v.newVariable("buffered", base, fakeArrayType(qcount, elemType), v.mem)
+	r.Flags |= VariableFakeAddress
+	r.loaded = true
+	count := qcount
+	if count > uint64(cfg.MaxArrayValues) {
+		count = uint64(cfg.MaxArrayValues)
+	}
+	mem := DereferenceMemory(v.mem)
+	for i := range count {
+		idx := (recvx + i) % dataqsiz
+		elemv := v.newVariable("", base+idx*stride, elemType, mem)
+		elemv.loadValueInternal(recurseLevel+1, cfg)
+		r.Children = append(r.Children, *elemv)
+	}
+	v.Children = append(v.Children, *r)
+	v.Len++
+}
+
+// loadSyncMap reads the key/value pairs stored in a sync.Map, by walking
+// the internal/sync.HashTrieMap that backs it, and appends them to
+// v.Children as a fake map variable named "entries".
+// If cfg.MaxMapBuckets is set it limits the number of nodes of the trie
+// that will be visited.
+func (v *Variable) loadSyncMap(recurseLevel int, cfg LoadConfig) {
+	htv, err := v.structField("m")
+	if err != nil {
+		return
+	}
+	rootv, err := htv.structField("root")
+	if err != nil {
+		return
+	}
+	root, err := atomicPointerLoad(rootv)
+	if err != nil {
+		return
+	}
+
+	// sync.Map is always a HashTrieMap[any, any], the keys and values are
+	// read as "interface {}" because the compiler may only emit the shape
+	// type for entry.
+	ifaceType, err := v.bi.findType("interface {}")
+	if err != nil {
+		return
+	}
+	var entries []*Variable // alternating keys and values
+	count := 0
+
+	if root != nil {
+		indirectType := root.DwarfType
+		var entryType godwarf.Type
+		for _, name := range []string{"internal/sync.entry[interface {},interface {}]", "internal/sync.entry[go.shape.interface {},go.shape.interface {}]"} {
+			entryType, err = v.bi.findType(name)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return
+		}
+
+		nodes := 0
+		var walk func(ind *Variable, depth int) error
+		walk = func(ind *Variable, depth int) error {
+			// each level of the trie consumes at least one bit of the hash
+			if depth > 8*v.bi.Arch.PtrSize() {
+				return errors.New("sync.Map trie is too deep")
+			}
+			nodes++
+			if cfg.MaxMapBuckets > 0 && nodes > cfg.MaxMapBuckets {
+				return nil
+			}
+			ind.mem = cacheMemory(ind.mem, ind.Addr, int(ind.RealType.Size()))
+			childrenv, err := ind.structField("children")
+			if err != nil {
+				return err
+			}
+			at, ok := childrenv.RealType.(*godwarf.ArrayType)
+			if !ok {
+				return errors.New("wrong type for sync.Map trie node")
+			}
+			for i := range at.Count {
+				slot := ind.newVariable("", childrenv.Addr+uint64(i*at.Type.Size()), at.Type, ind.mem)
+				n, err := atomicPointerLoad(slot)
+				if err != nil {
+					return err
+				}
+				if n == nil {
+					continue
+				}
+				isEntryv, err := n.structField("isEntry")
+				if err != nil {
+					return err
+				}
+				isEntry, err := readUintRaw(isEntryv.mem, isEntryv.Addr, 1)
+				if err != nil {
+					return err
+				}
+				if isEntry == 0 {
+					if err := walk(n.newVariable("", n.Addr, indirectType, n.mem), depth+1); err != nil {
+						return err
+					}
+					continue
+				}
+				// entries with colliding hashes are chained through the overflow field
+				for e := n.newVariable("", n.Addr, entryType, n.mem); e != nil; {
+					count++
+					if len(entries)/2 < cfg.MaxArrayValues {
+						key, err := e.structField("key")
+						if err != nil {
+							return err
+						}
+						val, err := e.structField("value")
+						if err != nil {
+							return err
+						}
+						entries = append(entries, e.newVariable("", key.Addr, ifaceType, e.mem), e.newVariable("", val.Addr, ifaceType, e.mem))
+					}
+					overflowv, err := e.structField("overflow")
+					if err != nil {
+						return err
+					}
+					e, err = atomicPointerLoad(overflowv)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		if err := walk(root, 0); err != nil {
+			return
+		}
+	}
+
+	r := v.newVariable("entries", v.Addr, fakeMapType(ifaceType, ifaceType, v.bi.Arch.PtrSize()), v.mem)
+	r.Flags |= VariableFakeAddress
+	r.loaded = true
+	r.Base = v.Addr
+	r.Len = int64(count)
+	for _, entry := range entries {
+		entry.loadValueInternal(recurseLevel+1, cfg)
+		r.Children = append(r.Children, *entry)
+	}
+	v.Children = append(v.Children, *r)
+	v.Len++
+}
+
+// atomicPointerType returns the type *T of a sync/atomic.Pointer[T].
+func atomicPointerType(av *Variable) (*godwarf.PtrType, bool) {
+	st, ok := av.RealType.(*godwarf.StructType)
+	if !ok || len(st.Field) == 0 {
+		return nil, false
+	}
+	// The first field of atomic.Pointer[T] is a zero length array of *T
+	at, ok := godwarf.ResolveTypedef(st.Field[0].Type).(*godwarf.ArrayType)
+	if !ok {
+		return nil, false
+	}
+	pt, ok := godwarf.ResolveTypedef(at.Type).(*godwarf.PtrType)
+	return pt, ok
+}
+
+// atomicPointerLoad returns the variable pointed to by av, which must be a
+// sync/atomic.Pointer[T], or nil if the pointer is nil.
+func atomicPointerLoad(av *Variable) (*Variable, error) {
+	pt, ok := atomicPointerType(av)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an atomic.Pointer", av.TypeString())
+	}
+	vv, err := av.structField("v")
+	if err != nil {
+		return nil, err
+	}
+	ptr, err := readUintRaw(vv.mem, vv.Addr, int64(av.bi.Arch.PtrSize()))
+	if err != nil || ptr == 0 {
+		return nil, err
+	}
+	return av.newVariable("", ptr, pt.Type, DereferenceMemory(av.mem)), nil
+}
+
+// loadAtomicPointer replaces the unsafe.Pointer field of a
+// sync/atomic.Pointer[T] with a *T pointer, so that the value it points to
+// is loaded like the target of any other pointer.
+func (v *Variable) loadAtomicPointer(recurseLevel int, cfg LoadConfig) {
+	pt, ok := atomicPointerType(v)
+	if !ok {
+		return
+	}
+	for i := range v.Children {
+		if v.Children[i].Name != "v" || v.Children[i].Kind != reflect.UnsafePointer {
+			continue
+		}
+		f := v.newVariable("v", v.Children[i].Addr, pt, v.mem)
+		f.loadValueInternal(recurseLevel+1, cfg)
+		v.Children[i] = *f
+	}
+}
+
 func (v *Variable) loadValueInternal(recurseLevel int, cfg LoadConfig) {
 	if v.Unreadable != nil || v.loaded || (v.Addr == 0 && v.Base == 0) {
 		return
@@ -1375,6 +1669,9 @@ func (v *Variable) loadValueInternal(recurseLevel int, cfg LoadConfig) {
 		v.Children = sv.Children
 		v.Len = sv.Len
 		v.Base = sv.Addr
+		if cfg.LoadChanBuffer && v.Base != 0 && recurseLevel <= cfg.MaxVariableRecurse {
+			v.loadChanBuffer(recurseLevel, cfg)
+		}
 
 	case reflect.Map:
 		if recurseLevel <= cfg.MaxVariableRecurse {
@@ -1440,9 +1737,15 @@ func (v *Variable) loadValueInternal(recurseLevel int, cfg LoadConfig) {
 				v.Children[i].loadValueInternal(recurseLevel+1, cfg)
 			}
 		}
-		if t.Name == "time.Time" {
+		if t.Name == "time.Time" && !cfg.RawTime {
 			v.formatTime()
 		}
+		if strings.HasPrefix(t.Name, "sync/atomic.Pointer[") && recurseLevel <= cfg.MaxVariableRecurse {
+			v.loadAtomicPointer(recurseLevel, cfg)
+		}
+		if t.Name == "sync.Map" && cfg.LoadSyncMap && recurseLevel <= cfg.MaxVariableRecurse {
+			v.loadSyncMap(recurseLevel, cfg)
+		}
 
 	case reflect.Interface:
 		v.loadInterface(recurseLevel, true, cfg)
@@ -1453,6 +1756,9 @@ func (v *Variable) loadValueInternal(recurseLevel int, cfg LoadConfig) {
 		var val int64
 		val, v.Unreadable = readIntRaw(v.mem, v.Addr, v.RealType.(*godwarf.IntType).ByteSize)
 		v.Value = constant.MakeInt64(val)
+		if !cfg.RawTime && v.DwarfType != nil && v.DwarfType.Common().Name == "time.Duration" {
+			v.Flags |= variableFormatDuration
+		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		if v.Flags&VariableCPURegister != 0 {
 			v.Value = constant.MakeUint64(v.reg.Uint64Val)
@@ -1749,9 +2055,25 @@ func (v *Variable) loadArrayValues(recurseLevel int, cfg LoadConfig) {
 		mem = DereferenceMemory(mem)
 	}
 
+	// Without PerDimension the elements of a multidimensional array share a
+	// single budget of MaxArrayValues elements.
+	_, elemIsArray := godwarf.ResolveTypedef(v.fieldType).(*godwarf.ArrayType)
+	flat := v.Kind == reflect.Array && elemIsArray && !cfg.PerDimension && v.Flags&variableTrustLen == 0
+	budget := cfg.MaxArrayValues
+
 	for i := int64(0); i < count; i++ {
+		elemcfg := cfg
+		if flat {
+			if budget <= 0 {
+				break
+			}
+			elemcfg.MaxArrayValues = budget
+		}
 		fieldvar := v.newVariable("", uint64(int64(v.Base)+(i*v.stride)), v.fieldType, mem)
-		fieldvar.loadValueInternal(recurseLevel+1, cfg)
+		fieldvar.loadValueInternal(recurseLevel+1, elemcfg)
+		if flat {
+			budget -= fieldvar.loadedArrayElements()
+		}
 
 		if fieldvar.Unreadable != nil {
 			errcount++
@@ -1764,6 +2086,19 @@ func (v *Variable) loadArrayValues(recurseLevel int, cfg LoadConfig) {
 	}
 }
 
+// loadedArrayElements returns the number of elements loaded in the
+// innermost dimension of the (possibly multidimensional) array v.
+func (v *Variable) loadedArrayElements() int {
+	if _, elemIsArray := godwarf.ResolveTypedef(v.fieldType).(*godwarf.ArrayType); !elemIsArray {
+		return len(v.Children)
+	}
+	n := 0
+	for i := range v.Children {
+		n += v.Children[i].loadedArrayElements()
+	}
+	return n
+}
+
 func (v *Variable) readComplex(size int64) {
 	var fs int64
 	switch size {
@@ -2158,7 +2493,7 @@ func (v *Variable) ConstDescr() string {
 	}
 	ctyp := v.bi.consts.Get(v.DwarfType)
 	if ctyp == nil {
-		return ""
+		return v.durationDescr()
 	}
 	if typename := v.DwarfType.Common().Name; !strings.Contains(typename, ".") || strings.HasPrefix(typename, "C.") {
 		// only attempt to use constants for user defined type, otherwise every
@@ -2172,9 +2507,21 @@ func (v *Variable) ConstDescr() string {
 		fallthrough
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		n, _ := constant.Int64Val(v.Value)
-		return ctyp.describe(n)
+		if descr := ctyp.describe(n); descr != "" {
+			return descr
+		}
 	}
-	return ""
+	return v.durationDescr()
+}
+
+// durationDescr describes the value of v as a time.Duration (for example
+// "1h2m3s") if v was loaded from a variable of type time.Duration.
+func (v *Variable) durationDescr() string {
+	if v.Flags&variableFormatDuration == 0 || v.Value == nil || v.Value.Kind() != constant.Int {
+		return ""
+	}
+	n, _ := constant.Int64Val(v.Value)
+	return time.Duration(n).String()
 }
 
 // registerVariableTypeConv implements type conversions for CPU register variables (REGNAME.int8, etc)
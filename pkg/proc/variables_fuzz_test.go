@@ -49,7 +49,7 @@ func FuzzEvalExpression(f *testing.F) {
 		f.Skip("not setup")
 	}
 	bi := proc.NewBinaryInfo("linux", "amd64")
-	assertNoError(bi.LoadBinaryInfo(fuzzExecutable, 0, nil), f, "LoadBinaryInfo")
+	assertNoError(bi.LoadBinaryInfo(fuzzExecutable, 0, nil, ""), f, "LoadBinaryInfo")
 	fh, err := os.Open(fuzzInfoPath)
 	assertNoError(err, f, "Open fuzzInfoPath")
 	defer fh.Close()
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-delve/delve/pkg/goversion"
 	"github.com/go-delve/delve/pkg/proc"
@@ -289,6 +290,7 @@ func TestSetVariable(t *testing.T) {
 	}{
 		{"b.ptr", "*main.A", "*main.A {val: 1337}", "nil", "*main.A nil"},
 		{"m2", "map[int]*main.astruct", "map[int]*main.astruct [1: *{A: 10, B: 11}, ]", "nil", "map[int]*main.astruct nil"},
+		{"m3[main.astruct{1, 1}]", "int", "42", "44", "44"},
 		{"fn1", "main.functype", "main.afunc", "nil", "nil"},
 		{"ch1", "chan int", "chan int 4/11", "nil", "chan int nil"},
 		{"s2", "[]main.astruct", "[]main.astruct len: 8, cap: 8, [{A: 1, B: 2},{A: 3, B: 4},{A: 5, B: 6},{A: 7, B: 8},{A: 9, B: 10},{A: 11, B: 12},{A: 13, B: 14},{A: 15, B: 16}]", "nil", "[]main.astruct len: 0, cap: 0, nil"},
@@ -406,6 +408,116 @@ func TestVariableEvaluationShort(t *testing.T) {
 	})
 }
 
+func TestChanBuffer(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("testvariables2", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+
+		buffered := func(expr string, cfg proc.LoadConfig) string {
+			t.Helper()
+			v, err := evalVariableWithCfg(p, expr, cfg)
+			assertNoError(err, t, fmt.Sprintf("EvalVariable(%s)", expr))
+			for i := range v.Children {
+				if v.Children[i].Name == "buffered" {
+					return api.ConvertVar(&v.Children[i]).SinglelineString()
+				}
+			}
+			return ""
+		}
+
+		cfg := pnormalLoadConfig
+		cfg.LoadChanBuffer = true
+		for _, tc := range []struct{ expr, tgt string }{
+			{"ch1", "[4]int [1,4,3,2]"},
+			{"chwrap", "[3]int [2,3,4]"},
+			{"int3chan", "[3]main.ThreeInts [{a: 1, b: 0, c: 0},{a: 2, b: 0, c: 0},{a: 3, b: 0, c: 0}]"},
+			{"chnil", ""},
+		} {
+			if out := buffered(tc.expr, cfg); out != tc.tgt {
+				t.Errorf("%s: expected buffered elements %q got %q", tc.expr, tc.tgt, out)
+			}
+		}
+
+		if out := buffered("chwrap", pnormalLoadConfig); out != "" {
+			t.Errorf("buffered elements loaded without LoadChanBuffer: %q", out)
+		}
+
+		cfg.MaxArrayValues = 2
+		if out := buffered("chwrap", cfg); out != "[3]int [2,3,...+1 more]" {
+			t.Errorf("wrong buffered elements with MaxArrayValues = 2: %q", out)
+		}
+	})
+}
+
+func TestSyncMap(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("syncmap", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+
+		entries := func(expr string, cfg proc.LoadConfig) *proc.Variable {
+			t.Helper()
+			v, err := evalVariableWithCfg(p, expr, cfg)
+			assertNoError(err, t, fmt.Sprintf("EvalVariable(%s)", expr))
+			for i := range v.Children {
+				if v.Children[i].Name == "entries" {
+					return &v.Children[i]
+				}
+			}
+			return nil
+		}
+
+		pairs := func(v *proc.Variable) []string {
+			r := []string{}
+			for i := 0; i+1 < len(v.Children); i += 2 {
+				r = append(r, api.ConvertVar(&v.Children[i]).SinglelineString()+": "+api.ConvertVar(&v.Children[i+1]).SinglelineString())
+			}
+			slices.Sort(r)
+			return r
+		}
+
+		cfg := pnormalLoadConfig
+		cfg.LoadSyncMap = true
+		for _, tc := range []struct {
+			expr string
+			tgt  []string
+		}{
+			{"empty", []string{}},
+			{"deleted", []string{}},
+			{"m", []string{`interface {}(string) "one": interface {}(int) 1`, `interface {}(string) "three": interface {}(int) 3`, `interface {}(string) "two": interface {}(int) 22`}},
+		} {
+			v := entries(tc.expr, cfg)
+			if v == nil {
+				t.Errorf("%s: no entries loaded", tc.expr)
+				continue
+			}
+			if v.Len != int64(len(tc.tgt)) {
+				t.Errorf("%s: expected %d entries, got %d", tc.expr, len(tc.tgt), v.Len)
+			}
+			if out := pairs(v); !slices.Equal(out, tc.tgt) {
+				t.Errorf("%s: expected entries %q got %q", tc.expr, tc.tgt, out)
+			}
+		}
+
+		if v := entries("m", pnormalLoadConfig); v != nil {
+			t.Errorf("entries loaded without LoadSyncMap")
+		}
+
+		v := entries("big", cfg)
+		if v == nil {
+			t.Fatal("big: no entries loaded")
+		}
+		if v.Len != 100 || len(v.Children) != 2*cfg.MaxArrayValues {
+			t.Errorf("big: wrong number of entries, Len=%d children=%d", v.Len, len(v.Children))
+		}
+		for i := 0; i+1 < len(v.Children); i += 2 {
+			k, _ := constant.Int64Val(v.Children[i].Children[0].Value)
+			if out, tgt := api.ConvertVar(&v.Children[i+1]).SinglelineString(), fmt.Sprintf("interface {}(main.point) {x: %d, y: %d}", k, -k); out != tgt {
+				t.Errorf("big: expected %q got %q", tgt, out)
+			}
+		}
+	})
+}
+
 func TestMultilineVariableEvaluation(t *testing.T) {
 	testcases := []varTest{
 		{"a1", true, "\"foofoofoofoofoofoo\"", "", "string", nil},
@@ -642,11 +754,21 @@ func getEvalExpressionTestCases() []varTest {
 		{"str1[0:11]", false, "\"01234567890\"", "\"01234567890\"", "string", nil},
 		{"str1[:3]", false, "\"012\"", "\"012\"", "string", nil},
 		{"str1[3:]", false, "\"34567890\"", "\"34567890\"", "string", nil},
-		{"str1[0:12]", false, "", "", "string", errors.New("index out of bounds")},
-		{"str1[5:3]", false, "", "", "string", errors.New("index out of bounds")},
+		{"str1[0:12]", false, "", "", "string", errors.New("slice bounds out of range [:12] with length 11")},
+		{"str1[5:3]", false, "", "", "string", errors.New("slice bounds out of range [5:3]")},
 		{"str1[11:]", false, "\"\"", "\"\"", "string", nil},
 		{"longbyteslice[:70]", false, "[]uint8 len: 70, cap: 144, [118,101,114,121,32,108,111,110,103,32,115,116,114,105,110,103,32,48,49,50,51,52,53,54,55,56,57,97,48,49,50,51,52,53,54,55,56,57,98,48,49,50,51,52,53,54,55,56,57,99,48,49,50,51,52,53,54,55,56,57,100,48,49,50,51,52,53,54,55,56]", "[]uint8 len: 70, cap: 144, [118,101,114,121,32,108,111,110,103,32,115,116,114,105,110,103,32,48,49,50,51,52,53,54,55,56,57,97,48,49,50,51,52,53,54,55,56,57,98,48,49,50,51,52,53,54,55,56,57,99,48,49,50,51,52,53,54,55,56,57,100,48,49,50,51,52,53,54,55,56]", "[]uint8", nil},
 		{"longbyteslice[:3][:5]", false, "[]uint8 len: 5, cap: 144, [118,101,114,121,32]", "[]uint8 len: 5, cap: 144, [118,101,114,121,32]", "[]uint8", nil},
+		{"s1[1:3:4]", false, "[]string len: 2, cap: 3, [\"two\",\"three\"]", "[]string len: 2, cap: 3, [\"two\",\"three\"]", "[]string", nil},
+		{"a1[1:3:4]", false, "[]string len: 2, cap: 3, [\"two\",\"three\"]", "[]string len: 2, cap: 3, [\"two\",\"three\"]", "[]string", nil},
+		{"s1[1:3:4][:3]", false, "[]string len: 3, cap: 3, [\"two\",\"three\",\"four\"]", "[]string len: 3, cap: 3, [\"two\",\"three\",\"four\"]", "[]string", nil},
+		{"s1[1:3:4][:4]", false, "", "", "", errors.New("slice bounds out of range [:4] with capacity 3")},
+		{"cap(s1[1:3:4])", false, "3", "3", "", nil},
+		{"s1[1:3:6]", false, "", "", "", errors.New("slice bounds out of range [::6] with capacity 5")},
+		{"s1[1:4:3]", false, "", "", "", errors.New("slice bounds out of range [:4:3]")},
+		{"s1[3:2:4]", false, "", "", "", errors.New("slice bounds out of range [3:2:]")},
+		{"s1[-1:]", false, "", "", "", errors.New("invalid slice index -1 (index must be non-negative)")},
+		{"str1[1:2:3]", false, "", "", "", errors.New("invalid operation: 3-index slice of string")},
 
 		// NaN and Inf floats
 		{"pinf", false, "+Inf", "+Inf", "float64", nil},
@@ -697,6 +819,20 @@ func getEvalExpressionTestCases() []varTest {
 		{"err1.(*main.astruct)", false, "*main.astruct {A: 1, B: 2}", "(*main.astruct)(0x…", "*main.astruct", nil},
 		{"err1.(*main.bstruct)", false, "", "", "", errors.New("interface conversion: error is *main.astruct, not *main.bstruct")},
 		{"errnil.(*main.astruct)", false, "", "", "", errors.New("interface conversion: error is nil, not *main.astruct")},
+		{"err1.(type)", false, `"*main.astruct"`, `"*main.astruct"`, "", nil},
+		{"errnil.(type)", false, `"nil"`, `"nil"`, "", nil},
+		{"errtypednil.(type)", false, `"*main.astruct"`, `"*main.astruct"`, "", nil},
+		{`err1.(type) == "*main.astruct"`, false, "true", "true", "", nil},
+		{`err1.(type) != "*main.astruct"`, false, "false", "false", "", nil},
+		{`"*main.bstruct" == err2.(type)`, false, "true", "true", "", nil},
+		{`err1.(type) == "*main.bstruct"`, false, "false", "false", "", nil},
+		{`errnil.(type) == "nil"`, false, "true", "true", "", nil},
+		{`iface3.(type) == "map[string]go/constant.Value"`, false, "true", "true", "", nil},
+		{`iface3.(type) == "map[string]constant.Value"`, false, "true", "true", "", nil},
+		{`iface3.(type) == "map[string]Value"`, false, "false", "false", "", nil},
+		{`(iface2.(type)) == "string" && iface1.(type) == "*main.astruct"`, false, "true", "true", "", nil},
+		{`iface2.(type) == 1`, false, "", "", "", errors.New("can not compare the dynamic type of an interface to int")},
+		{"c1.(type)", false, "", "", "", errors.New("expression \"c1\" not an interface")},
 		{"const1", true, "go/constant.Value(go/constant.int64Val) 3", "go/constant.Value(go/constant.int64Val) 3", "go/constant.Value", nil},
 
 		// combined expressions
@@ -951,6 +1087,9 @@ func getEvalExpressionTestCases() []varTest {
 		{`*(*uint)(uintptr(&i1))`, false, `1`, `1`, "uint", nil},
 		{`*(*uint)(unsafe.Pointer(p1))`, false, `1`, `1`, "uint", nil},
 		{`*(*uint)(unsafe.Pointer(&i1))`, false, `1`, `1`, "uint", nil},
+		{`*(*int)(up1)`, false, `1`, `1`, "int", nil},
+		{`*(*int)(uintptr(up1))`, false, `1`, `1`, "int", nil},
+		{`*(*main.astruct)(up1)`, false, `main.astruct {A: 1, B: 20000}`, `main.astruct {A: 1, B: 20000}`, "main.astruct", nil},
 
 		// issue #4179 local variable shadows package
 		{`issue4179helper.Test`, false, `*github.com/go-delve/delve/_fixtures/internal/issue4179helper.Test {Name: interface {} nil, Age: 0}`, `("*github.com/go-delve/delve/_fixtures/internal/issue4179helper.Test")(…`, "*github.com/go-delve/delve/_fixtures/internal/issue4179helper.Test", nil},
@@ -959,6 +1098,11 @@ func getEvalExpressionTestCases() []varTest {
 		{`badslice`, false, `(unreadable non-zero length array with nil base)`, `(unreadable non-zero length array with nil base)`, "[]int", nil},
 	}
 
+	if runtime.GOOS == "linux" {
+		// Memory mappings are checked when converting integers to pointers
+		testcases = append(testcases, varTest{`*(*int)(unsafe.Pointer(uintptr(0x1000)))`, false, `(unreadable address 0x1000 is not mapped)`, `(unreadable address 0x1000 is not mapped)`, "int", nil})
+	}
+
 	// Conversions to ptr-to-ptr types
 	if goversion.VersionAfterOrEqual(runtime.Version(), 1, 24) {
 		testcases = append(testcases, varTest{`**(**maps.Map)(uintptr(&m1))`, false, `…`, `…`, "internal/runtime/maps.Map", nil})
@@ -986,6 +1130,50 @@ func (err *altError) Error() string {
 	return "[multiple alternatives]"
 }
 
+func TestSetThroughTypeAssertion(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("setiface", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+		for _, tc := range []struct {
+			lhs, rhs string
+			expr     string
+			expected string
+		}{
+			{"ip.(*main.T).A", "10", "ip", "interface {}(*main.T) *{A: 10, B: \"\"}"},
+			{"ip.(*T).A", "11", "ip", "interface {}(*main.T) *{A: 11, B: \"\"}"},
+			{"iv.(T).A", "20", "iv", "interface {}(main.T) {A: 20, B: \"\"}"},
+			{"ii.(*T).A", "30", "ii", "main.I(*main.T) *{A: 30, B: \"\"}"},
+			{"in.(int)", "40", "in", "interface {}(int) 40"},
+		} {
+			assertNoError(setVariable(p, tc.lhs, tc.rhs), t, fmt.Sprintf("SetVariable(%s, %s)", tc.lhs, tc.rhs))
+			v, err := evalVariableWithCfg(p, tc.expr, pnormalLoadConfig)
+			assertNoError(err, t, fmt.Sprintf("EvalVariable(%s)", tc.expr))
+			if out := api.ConvertVar(v).SinglelineString(); out != tc.expected {
+				t.Errorf("%s = %s: got %q expected %q", tc.lhs, tc.rhs, out, tc.expected)
+			}
+		}
+
+		for _, tc := range []struct {
+			lhs, err string
+		}{
+			{"iv.(*T).A", "interface conversion: interface {} is main.T, not *main.T"},
+			{"inil.(*T).A", "interface conversion: interface {} is nil, not *main.T"},
+		} {
+			err := setVariable(p, tc.lhs, "1")
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s: expected error %q got %v", tc.lhs, tc.err, err)
+			}
+		}
+
+		// Outside of type assertions and conversions unqualified names are not
+		// looked up in the package of the current function.
+		_, err := evalVariableWithCfg(p, "T(ip)", pnormalLoadConfig)
+		if err == nil || err.Error() != "could not find symbol value for T" {
+			t.Errorf("T(ip): expected error %q got %v", "could not find symbol value for T", err)
+		}
+	})
+}
+
 func TestEvalExpression(t *testing.T) {
 	testcases := getEvalExpressionTestCases()
 	protest.AllowRecording(t)
@@ -1200,6 +1388,14 @@ func TestPackageRenames(t *testing.T) {
 
 		{`"dir0/pkg".A`, false, "0", "", "int", nil},
 		{`"dir1/pkg".A`, false, "1", "", "int", nil},
+		{`"github.com/go-delve/delve/_fixtures/internal/dir1/pkg".A`, false, "1", "", "int", nil},
+		{`"github.com/go-delve/delve/_fixtures/internal/dir.io".A`, false, `"something"`, "", "string", nil},
+
+		// The local variable A shadows the package variable main.A, the empty
+		// package path refers to the package of the current function.
+		{"A", false, "10", "", "int", nil},
+		{`"".A`, false, `"main.A"`, "", "string", nil},
+		{"main.A", false, `"main.A"`, "", "string", nil},
 
 		{"amap", true, "interface {}(map[go/ast.BadExpr]net/http.Request) [{From: 2, To: 3}: {Method: \"othermethod\", …", "", "interface {}", nil},
 		{"amap2", true, "interface {}(*map[go/ast.BadExpr]net/http.Request) *[{From: 2, To: 3}: {Method: \"othermethod\", …", "", "interface {}", nil},
@@ -1212,6 +1408,7 @@ func TestPackageRenames(t *testing.T) {
 	testcases1_13 := []varTest{
 		// needs DW_AT_go_package_name attribute added to Go1.13
 		{`dirio.A`, false, `"something"`, "", "string", nil},
+		{`pkg.SomeVar.X`, false, "0", "", "float64", nil},
 	}
 
 	if !goversion.VersionAfterOrEqual(runtime.Version(), 1, 7) {
@@ -1227,6 +1424,12 @@ func TestPackageRenames(t *testing.T) {
 
 		if goversion.VersionAfterOrEqual(runtime.Version(), 1, 13) {
 			testPackageRenamesHelper(t, p, testcases1_13)
+
+			// pkg is the name of both dir0/pkg and dir1/pkg and A is defined in both
+			_, err := evalVariableWithCfg(p, "pkg.A", pnormalLoadConfig)
+			if err == nil || !strings.HasPrefix(err.Error(), "ambiguous package name pkg:") {
+				t.Errorf("expected ambiguous package name error for pkg.A, got %v", err)
+			}
 		}
 	})
 }
@@ -1431,6 +1634,22 @@ func TestCallFunction(t *testing.T) {
 		{`mul2ptr(&main.a2struct{1})`, []string{":int:2"}, nil, 1},
 		{`m[main.intpair{3, 1}]`, []string{`:string:"three,one"`}, nil, 0},
 		{`main.Derived{ x: 1, y: 2 }`, []string{`:main.Derived:main.Derived {x: 1, Base: main.Base {y: 2}}`}, nil, 0},
+
+		// make and append
+		{`make([]int, 3)`, []string{`:[]int:[]int len: 3, cap: 3, [0,0,0]`}, nil, 1},
+		{`make([]string, 1, 5)`, []string{`:[]string:[]string len: 1, cap: 5, [""]`}, nil, 1},
+		{`append(intslice, 4, 5)`, []string{`:[]int:[]int len: 5, cap: 6, [1,2,3,4,5]`}, nil, 1},
+		{`append(stringslice, "four")`, []string{`:[]string:[]string len: 4, cap: 6, ["one","two","three","four"]`}, nil, 2},
+
+		// map assignment
+		{`mapassign["one"] = 10;mapassign["one"]`, []string{`mapassign["one"]:int:10`}, nil, 1},
+		{`mapassign["two"] = 2;mapassign["two"]`, []string{`mapassign["two"]:int:2`}, nil, 2},
+		{`m[main.intpair{2, 2}] = "two,two";m[main.intpair{2, 2}]`, []string{`m[main.intpair{2, 2}]:string:"two,two"`}, nil, 2},
+		{`m[main.intpair{1, 1}] = "uno,uno";m[main.intpair{1, 1}]`, []string{`m[main.intpair{1, 1}]:string:"uno,uno"`}, nil, 1},
+		{`mapassignnil["one"] = 1`, nil, errors.New("assignment to entry in nil map mapassignnil"), 1},
+		{`stringsJoin(append(stringslice, "four"), ",")`, []string{`:string:"one,two,three,four"`}, nil, 3},
+		{`make(map[string]int, 1)`, nil, errors.New("make of map[string]int not implemented"), 0},
+		{`append(1, 2)`, nil, errors.New("first argument to append must be a slice; have 1 (type int)"), 0},
 	}
 
 	withTestProcessArgs("fncall", t, ".", nil, protest.AllNonOptimized, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
@@ -1515,7 +1734,7 @@ func testCallFunctionIntl(t *testing.T, grp *proc.TargetGroup, p *proc.Target, t
 		checkEscape = false
 	}
 	t.Logf("call %q", tc.expr)
-	err := proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), callExpr, pnormalLoadConfig, checkEscape)
+	err := proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), callExpr, pnormalLoadConfig, proc.EvalConfig{CheckEscape: checkEscape})
 	if tc.err != nil {
 		t.Logf("\terr = %v\n", err)
 		if err == nil {
@@ -1604,7 +1823,7 @@ func TestIssue4051(t *testing.T) {
 		err := grp.Continue()
 		assertNoError(err, t, "initial continue to breakpoint failed")
 
-		err = proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), `main.Hello("world")`, pnormalLoadConfig, true)
+		err = proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), `main.Hello("world")`, pnormalLoadConfig, proc.EvalConfig{CheckEscape: true})
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -1615,7 +1834,7 @@ func TestIssue4051(t *testing.T) {
 
 		err = grp.Continue()
 		assertNoError(err, t, "initial continue to breakpoint failed")
-		err = proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), `main.Hello("world")`, pnormalLoadConfig, true)
+		err = proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), `main.Hello("world")`, pnormalLoadConfig, proc.EvalConfig{CheckEscape: true})
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -1945,6 +2164,38 @@ func TestCapturedVariable(t *testing.T) {
 	})
 }
 
+func TestClosureCapturedVariablesAtEntry(t *testing.T) {
+	// Checks that variables captured by a closure are listed by name, with
+	// the correct value, when we are stopped at the entry point of the
+	// closure, both for variables captured by reference (a) and by value
+	// (scale).
+	withTestProcess("closurecontents", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		setFunctionBreakpoint(p, t, "main.makeAcc.func1")
+		for _, tgt := range []struct{ a, scale string }{{"0", "3"}, {"3", "3"}} {
+			for {
+				// skip the runtime.Breakpoint calls in main
+				assertNoError(grp.Continue(), t, "Continue()")
+				loc, err := proc.ThreadLocation(p.CurrentThread())
+				assertNoError(err, t, "ThreadLocation()")
+				if loc.Fn != nil && loc.Fn.Name == "main.makeAcc.func1" {
+					break
+				}
+			}
+			scope, err := evalScope(p)
+			assertNoError(err, t, "EvalScope")
+			vars, err := scope.LocalVariables(normalLoadConfig)
+			assertNoError(err, t, "LocalVariables")
+			found := map[string]string{}
+			for _, v := range vars {
+				found[v.Name] = api.ConvertVar(v).SinglelineString()
+			}
+			if found["a"] != tgt.a || found["scale"] != tgt.scale {
+				t.Fatalf("wrong captured variables %v, expected a = %s and scale = %s", found, tgt.a, tgt.scale)
+			}
+		}
+	})
+}
+
 func TestSetupRangeFramesCrash(t *testing.T) {
 	// See issue #3806
 	for _, options := range []protest.BuildFlags{0, protest.EnableInlining | protest.EnableOptimization} {
@@ -2062,7 +2313,44 @@ func TestCallFunctionRegisterArg(t *testing.T) {
 	withTestProcessArgs("issue3310", t, ".", []string{}, protest.AllNonOptimized, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
 		setFileBreakpoint(p, t, fixture.Source, 12)
 		assertNoError(grp.Continue(), t, "Continue()")
-		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "value.Type()", pnormalLoadConfig, true), t, "EvalExpressionWithCalls")
+		assertNoError(proc.EvalExpressionWithCalls(grp, p.SelectedGoroutine(), "value.Type()", pnormalLoadConfig, proc.EvalConfig{CheckEscape: true}), t, "EvalExpressionWithCalls")
+	})
+}
+
+func TestCallFunctionTimeout(t *testing.T) {
+	protest.MustSupportFunctionCalls(t, testBackend)
+	withTestProcess("fncallblock", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+		g := p.SelectedGoroutine()
+		err := proc.EvalExpressionWithCalls(grp, g, "block()", pnormalLoadConfig, proc.EvalConfig{CheckEscape: true, CallTimeout: 500 * time.Millisecond})
+		if _, ok := err.(proc.ErrFunctionCallTimeout); !ok {
+			t.Fatalf("expected timeout error, got %v", err)
+		}
+		if p.StopReason != proc.StopManual {
+			t.Fatalf("wrong stop reason %v", p.StopReason)
+		}
+
+		// The call is still in progress, it completes once it is unblocked
+		// and the target is continued.
+		if err := proc.EvalExpressionWithCalls(grp, g, "block()", pnormalLoadConfig, proc.EvalConfig{CheckEscape: true}); err == nil {
+			t.Fatal("second function call started while the first one is in progress")
+		}
+		scope, err := proc.ThreadScope(p, p.CurrentThread())
+		assertNoError(err, t, "ThreadScope")
+		assertNoError(scope.SetVariable("main.release", "true"), t, "SetVariable")
+		assertNoError(grp.Continue(), t, "Continue()")
+		if p.StopReason != proc.StopCallReturned {
+			t.Fatalf("wrong stop reason %v", p.StopReason)
+		}
+		retvals := p.CurrentThread().Common().ReturnValues(pnormalLoadConfig)
+		if len(retvals) != 1 || retvals[0].Value.String() != "42" {
+			t.Fatalf("wrong return values %v", retvals)
+		}
+
+		err = grp.Continue()
+		if _, exited := err.(proc.ErrProcessExited); !exited {
+			t.Fatalf("expected process exit, got %v", err)
+		}
 	})
 }
 
@@ -2161,3 +2449,155 @@ func TestCGlobal(t *testing.T) {
 		}
 	})
 }
+
+func TestCallInlinedMethod(t *testing.T) {
+	protest.MustSupportFunctionCalls(t, testBackend)
+	withTestProcessArgs("fncallinline", t, ".", nil, protest.EnableInlining, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		testCallFunctionSetBreakpoint(t, p, grp, fixture)
+		assertNoError(grp.Continue(), t, "Continue()")
+		testCallFunction(t, grp, p, testCaseCallFunction{"c.Get()", nil, errors.New("function main.(*counter).Get is inlined"), 0})
+	})
+}
+
+func TestMultidimensionalArrayLoad(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("matrixvars", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+
+		checkRow := func(row *proc.Variable, i, n int) {
+			t.Helper()
+			if row.Len != 100 || len(row.Children) != n {
+				t.Fatalf("wrong number of columns in row %d: Len=%d loaded=%d", i, row.Len, len(row.Children))
+			}
+			for j := range row.Children {
+				if got, _ := constant.Float64Val(row.Children[j].Value); got != float64(i*100+j) {
+					t.Errorf("matrix[%d][%d] = %v", i, j, got)
+				}
+			}
+		}
+
+		// By default MaxArrayValues limits the total number of elements
+		cfg := pnormalLoadConfig
+		cfg.MaxArrayValues = 150
+		v, err := evalVariableWithCfg(p, "matrix", cfg)
+		assertNoError(err, t, "EvalVariable(matrix)")
+		if v.Len != 100 || len(v.Children) != 2 {
+			t.Fatalf("wrong number of rows: Len=%d loaded=%d", v.Len, len(v.Children))
+		}
+		checkRow(&v.Children[0], 0, 100)
+		checkRow(&v.Children[1], 1, 50)
+
+		// With PerDimension the first MaxArrayValues rows are loaded and each
+		// of them has its first MaxArrayValues columns loaded.
+		cfg.MaxArrayValues = 3
+		cfg.PerDimension = true
+		v, err = evalVariableWithCfg(p, "matrix", cfg)
+		assertNoError(err, t, "EvalVariable(matrix)")
+		if v.Len != 100 || len(v.Children) != 3 {
+			t.Fatalf("wrong number of rows: Len=%d loaded=%d", v.Len, len(v.Children))
+		}
+		for i := range v.Children {
+			checkRow(&v.Children[i], i, 3)
+		}
+	})
+}
+
+func TestMapArrayKeys(t *testing.T) {
+	// Maps with array keys and struct keys with padding.
+	protest.AllowRecording(t)
+	withTestProcess("mapkeys", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+		for _, tc := range []struct {
+			name string
+			tgt  map[string]string
+		}{
+			{"m1", map[string]string{"[2]int [1,2]": `"one-two"`, "[2]int [3,4]": `"three-four"`}},
+			{"m2", map[string]string{"main.paddedKey {A: 1, B: [3]int16 [2,3,4], C: 5}": "10", "main.paddedKey {A: 6, B: [3]int16 [7,8,9], C: 10}": "20"}},
+		} {
+			v, err := evalVariableWithCfg(p, tc.name, pnormalLoadConfig)
+			assertNoError(err, t, fmt.Sprintf("EvalVariable(%s)", tc.name))
+			av := api.ConvertVar(v)
+			if len(av.Children) != 2*len(tc.tgt) {
+				t.Fatalf("%s: wrong number of children %d", tc.name, len(av.Children))
+			}
+			for i := 0; i < len(av.Children); i += 2 {
+				key, val := av.Children[i].SinglelineString(), av.Children[i+1].SinglelineString()
+				if tc.tgt[key] != val {
+					t.Errorf("%s: unexpected entry %s: %s", tc.name, key, val)
+				}
+			}
+		}
+	})
+}
+
+func TestTimeFormatting(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("timevars", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+		rawCfg := pnormalLoadConfig
+		rawCfg.RawTime = true
+		testcases := []struct {
+			expr     string
+			cfg      proc.LoadConfig
+			expected string
+		}{
+			{"d1", pnormalLoadConfig, "1h2m3s (3723000000000)"},
+			{"d2", pnormalLoadConfig, "1.5s (1500000000)"},
+			{"d3", pnormalLoadConfig, "Second (1000000000)"},
+			{"d1", rawCfg, "3723000000000"},
+			{"d3", rawCfg, "Second (1000000000)"},
+		}
+		for _, tc := range testcases {
+			v, err := evalVariableWithCfg(p, tc.expr, tc.cfg)
+			assertNoError(err, t, fmt.Sprintf("EvalVariable(%s)", tc.expr))
+			if out := api.ConvertVar(v).SinglelineString(); out != tc.expected {
+				t.Errorf("%s (RawTime=%v): got %q expected %q", tc.expr, tc.cfg.RawTime, out, tc.expected)
+			}
+		}
+
+		v, err := evalVariableWithCfg(p, "tm", pnormalLoadConfig)
+		assertNoError(err, t, "EvalVariable(tm)")
+		if out := api.ConvertVar(v).SinglelineString(); !strings.HasPrefix(out, "time.Time(2024-03-15T10:30:00Z)") {
+			t.Errorf("tm: got %q", out)
+		}
+		v, err = evalVariableWithCfg(p, "tm", rawCfg)
+		assertNoError(err, t, "EvalVariable(tm)")
+		if out := api.ConvertVar(v).SinglelineString(); !strings.HasPrefix(out, "time.Time {wall: ") {
+			t.Errorf("tm (RawTime=true): got %q", out)
+		}
+	})
+}
+
+func TestAtomicTypes(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestProcess("atomictypes", t, func(p *proc.Target, grp *proc.TargetGroup, fixture protest.Fixture) {
+		assertNoError(grp.Continue(), t, "Continue()")
+		for _, tc := range []struct {
+			expr     string
+			expected string
+		}{
+			{"b", "sync/atomic.Bool(true)"},
+			{"i32", "sync/atomic.Int32(-32)"},
+			{"i64", "sync/atomic.Int64(-64)"},
+			{"u32", "sync/atomic.Uint32(32)"},
+			{"u64", "sync/atomic.Uint64(64)"},
+			{"uptr", "sync/atomic.Uintptr(48879)"},
+			{"nptr", "sync/atomic.Pointer[main.T](nil)"},
+			{"val", "sync/atomic.Value(interface {}(int) 42)"},
+			{"nval", "sync/atomic.Value(interface {} nil)"},
+			{"cs", "main.counters {hits: sync/atomic.Int64(3), done: sync/atomic.Bool(false)}"},
+		} {
+			v, err := evalVariableWithCfg(p, tc.expr, pnormalLoadConfig)
+			assertNoError(err, t, fmt.Sprintf("EvalVariable(%s)", tc.expr))
+			if out := api.ConvertVar(v).SinglelineString(); out != tc.expected {
+				t.Errorf("%s: got %q expected %q", tc.expr, out, tc.expected)
+			}
+		}
+
+		v, err := evalVariableWithCfg(p, "ptr", pnormalLoadConfig)
+		assertNoError(err, t, "EvalVariable(ptr)")
+		if out := api.ConvertVar(v).SinglelineString(); !strings.HasPrefix(out, "sync/atomic.Pointer[main.T](*(*main.T)(0x") || !strings.HasSuffix(out, ") {n: 1})") {
+			t.Errorf("ptr: got %q", out)
+		}
+	})
+}
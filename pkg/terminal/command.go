@@ -13,6 +13,7 @@ import (
 	"go/parser"
 	"go/scanner"
 	"io"
+	"maps"
 	"math"
 	"os"
 	"os/exec"
@@ -93,7 +94,7 @@ var (
 	// * Follows pointers
 	// * Loads more array values
 	// * Does not limit struct fields
-	longLoadConfig = api.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, MaxStructFields: -1}
+	longLoadConfig = api.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, PerDimension: true, MaxStructFields: -1}
 	// ShortLoadConfig loads less information, not following pointers
 	// and limiting struct fields loaded to 3.
 	ShortLoadConfig = api.LoadConfig{MaxStringLen: 64, MaxStructFields: 3}
@@ -111,12 +112,13 @@ func DebugCommands(client service.Client) *Commands {
 Type "help" followed by the name of a command for more information about it.`},
 		{aliases: []string{"break", "b"}, group: breakCmds, cmdFn: breakpoint, helpMsg: `Sets a breakpoint.
 
-	break [name] [locspec] [if <condition>]
+	break [-hitcount|-per-g-hitcount <operator> <argument>] [-trace] [-eval <expr list>] [name] [locspec] [-label <key>=<value>] [if <condition>]
 
 Locspec is a location specifier in the form of:
 
   * *<address> Specifies the location of memory address address. address can be specified as a decimal, hexadecimal or octal number
   * <filename>:<line> Specifies the line in filename. filename can be the partial path to a file or even just the base name as long as the expression remains unambiguous.
+  * <filename>:<start>-<end> Specifies every line between start and end (inclusive) in filename, a breakpoint is set on each line that has executable code.
   * <line> Specifies the line in the current file
   * +<offset> Specifies the line offset lines after the current one
   * -<offset> Specifies the line offset lines before the current one
@@ -136,6 +138,19 @@ Finally, you can assign a condition to the newly created breakpoint by using the
 
 	break main.go:55 if i == 5
 
+A hit count condition can be assigned to the newly created breakpoint by using the -hitcount or -per-g-hitcount options, which accept the same operators as 'condition -hitcount':
+
+	break -hitcount >= 50 main.go:55
+	break -hitcount % 10 main.go:55 if i > 5
+
+The -label option restricts the breakpoint to goroutines that have the specified pprof label, goroutines that do not have the label, or have it with a different value, will not stop. The option can be repeated, in which case all labels must match:
+
+	break main.handler -label service=payments
+
+The -eval option specifies a comma separated list of expressions that will be evaluated and printed every time the breakpoint is hit, if the list contains spaces it must be quoted. When combined with -trace the breakpoint will not stop execution, instead the values of the expressions will be printed on a single line, prefixed by the goroutine ID, and execution will continue automatically:
+
+	break -trace -eval 'x, y.field' main.go:55
+
 Alternatively you can set a condition on a breakpoint after created by using the 'on' command.
 
 See also: "help on", "help cond" and "help clear"`},
@@ -148,11 +163,15 @@ A tracepoint is a breakpoint that does not stop the execution of the program, in
 See also: "help on", "help cond" and "help clear"`},
 		{aliases: []string{"watch"}, group: breakCmds, cmdFn: watchpoint, helpMsg: `Set watchpoint.
 
-	watch [-r|-w|-rw] <expr>
+	watch [-r|-w|-rw] [-scope exit|follow] <expr> [if <condition>]
+	watch [-r|-w|-rw] -field [-max <n>] <type>.<field> [if <condition>]
 
 	-r	stops when the memory location is read
 	-w	stops when the memory location is written
 	-rw	stops when the memory location is read or written
+	-scope	what to do when a watched stack variable goes out of scope, see below
+	-field	watches a field of every instance of a struct type, see below
+	-max	maximum number of instances watched at the same time
 
 The memory location is specified with the same expression language used by 'print', for example:
 
@@ -161,8 +180,24 @@ The memory location is specified with the same expression language used by 'prin
 
 will watch the address of variable 'v' and writes to an int at addr '0x1400007c018'.
 
+A condition can be assigned to the watchpoint using the 'if' postfix form, execution will only stop when the condition is true. The condition is evaluated after the memory access has happened, so it will see the new value of the watched expression:
+
+	watch -w counter if counter == 42
+
+Watchpoints on stack variables are cleared when the function containing the variable returns (-scope exit, the default). With -scope follow the watchpoint is instead set again, on the new stack frame, the next time the function is called by the same goroutine, which is useful for recursive functions:
+
+	watch -w -scope follow n
+
+With -field the watchpoint is set on the specified field of every instance of the type allocated on the heap from now on, instances that already exist are not watched:
+
+	watch -w -field -max 2 main.Counter.count
+
+Delve intercepts the allocation of each instance, which slows down programs that allocate a lot of memory. The number of watched instances is limited by -max and by the number of hardware watchpoints, instances allocated after the limit is reached are not watched. Watchpoints on instances freed by the garbage collector are not removed.
+
 Note that writes that do not change the value of the watched memory address might not be reported.
 
+When replaying a recording watchpoints also work backwards: 'rev continue' stops on the instruction that performed the most recent write, before it is executed. The watched expression, and the condition, will therefore see the old value; use 'step-instruction' to execute the write.
+
 See also: "help print".`},
 		{aliases: []string{"restart", "r"}, group: runCmds, cmdFn: restart, helpMsg: `Restart process.
 
@@ -171,12 +206,14 @@ For recorded targets the command takes the following forms:
 	restart					resets to the start of the recording
 	restart [checkpoint]			resets the recording to the given checkpoint
 	restart -r [newargv...]	[redirects...]	re-records the target process
+	restart -rerecord [newargv...] [redirects...]	same as restart -r
 
 For live targets the command takes the following forms:
 
 	restart [newargv...] [redirects...]	restarts the process
 
 If newargv is omitted the process is restarted (or re-recorded) with the same argument vector.
+Re-recording produces a new recording of the target, with the same arguments and environment unless new ones are specified, breakpoints are preserved.
 If -noargs is specified instead, the argument vector is cleared.
 
 A list of file redirections can be specified after the new argument list to override the redirections defined using the '--redirect' command line option. A syntax similar to Unix shells is used:
@@ -190,7 +227,7 @@ A list of file redirections can be specified after the new argument list to over
 
 	continue [<locspec>]
 
-Optional locspec argument allows you to continue until a specific location is reached. The program will halt if a breakpoint is hit before reaching the specified location.
+Optional locspec argument allows you to continue until a specific location is reached. The program will halt if a breakpoint is hit before reaching the specified location. The temporary breakpoint set on the specified location is removed as soon as the program stops, for whatever reason.
 
 For example:
 
@@ -198,18 +235,35 @@ For example:
 	continue encoding/json.Marshal
 `},
 		{aliases: []string{"step", "s"}, group: runCmds, cmdFn: c.step, allowedPrefixes: revPrefix, helpMsg: "Single step through program."},
-		{aliases: []string{"step-instruction", "si", "stepi"}, group: runCmds, allowedPrefixes: revPrefix, cmdFn: c.stepInstruction, helpMsg: "Single step a single cpu instruction."},
-		{aliases: []string{"next-instruction", "ni", "nexti"}, group: runCmds, allowedPrefixes: revPrefix, cmdFn: c.nextInstruction, helpMsg: "Single step a single cpu instruction, skipping function calls."},
+		{aliases: []string{"step-instruction", "si", "stepi"}, group: runCmds, allowedPrefixes: revPrefix, cmdFn: c.stepInstruction, helpMsg: `Single step a single cpu instruction.
+
+	step-instruction [count]
+
+Optional [count] argument allows you to step multiple instructions. Stepping stops early if a breakpoint is hit.
+`},
+		{aliases: []string{"next-instruction", "ni", "nexti"}, group: runCmds, allowedPrefixes: revPrefix, cmdFn: c.nextInstruction, helpMsg: `Single step a single cpu instruction, skipping function calls.
+
+	next-instruction [count]
+
+Optional [count] argument allows you to step multiple instructions. Stepping stops early if a breakpoint is hit.
+`},
 		{aliases: []string{"next", "n"}, group: runCmds, cmdFn: c.next, allowedPrefixes: revPrefix, helpMsg: `Step over to next source line.
 
 	next [count]
 
 Optional [count] argument allows you to skip multiple lines.
 `},
-		{aliases: []string{"stepout", "so"}, group: runCmds, allowedPrefixes: revPrefix, cmdFn: c.stepout, helpMsg: "Step out of the current function."},
+		{aliases: []string{"stepout", "so"}, group: runCmds, allowedPrefixes: revPrefix, cmdFn: c.stepout, helpMsg: `Step out of the current function.
+
+	stepout [count]
+
+Optional [count] argument allows you to step out of multiple functions. Stepping stops early if a breakpoint is hit.
+`},
 		{aliases: []string{"call"}, group: runCmds, cmdFn: c.call, helpMsg: `Resumes process, injecting a function call (EXPERIMENTAL!!!)
 
-	call [-unsafe] <function call expression>
+	call [-unsafe] [-timeout <duration>] <function call expression>
+
+If -timeout is specified and the function call does not return before the timeout expires (for example because it is blocked on a channel) the target is stopped and an error is printed. The duration is specified as a number of seconds or with a unit suffix, for example 500ms or 2s. The call is not aborted, it will complete, and its return values will be printed, once the program is continued.
 
 Current limitations:
 - only pointers to stack-allocated objects can be passed as argument.
@@ -221,9 +275,37 @@ Current limitations:
 - functions can only be called when the goroutine is stopped at a safe
   point.
 - calling a function will resume execution of all goroutines.
+- the builtins make and append are supported only for slices, append
+  always allocates a new backing array.
+- assigning to a map entry that doesn't exist, for example
+  'call m["key"] = 5', inserts the key by calling runtime.mapassign, which
+  can grow the map and move its contents.
 - only supported on linux's native backend.
 `},
-		{aliases: []string{"threads"}, group: goroutineCmds, cmdFn: threads, helpMsg: "Print out info for every traced thread."},
+		{aliases: []string{"handle"}, group: runCmds, cmdFn: handle, helpMsg: `Changes what happens when the program receives a signal.
+
+	handle [<signal> [stop|nostop] [print|noprint] [pass|nopass]]
+
+Without arguments prints the policy of every signal. The signal can be specified by name, with or without the SIG prefix, or by number. Keywords:
+
+	stop	the program is stopped when it receives the signal, implies print
+	nostop	the program keeps running
+	print	a message is printed when the program receives the signal
+	noprint	no message is printed, implies nostop
+	pass	the signal is delivered to the program
+	nopass	the signal is discarded
+
+By default signals are delivered to the program without stopping it or printing a message. The signals used by the debugger (SIGTRAP, SIGSTOP and SIGKILL) can not be configured and synchronous faults, like SIGSEGV, can only be discarded if the program is also stopped. Policies are preserved when the program is restarted.
+
+For example:
+
+	handle SIGUSR1 stop
+	handle USR2 nostop noprint nopass
+
+Only supported on linux's native backend.`},
+		{aliases: []string{"threads"}, group: goroutineCmds, cmdFn: threads, helpMsg: `Print out info for every traced thread.
+
+On linux the scheduling state of each thread and the CPU it last executed on, as reported by the kernel, are also printed between square brackets. For threads stopped by the debugger the state describes what the thread was doing when it was stopped: "running" if it was executing user code or "in syscall <name>" if it was blocked in a system call. This information is not available for core files.`},
 		{aliases: []string{"thread", "tr"}, group: goroutineCmds, cmdFn: thread, helpMsg: `Switch to the specified thread.
 
 	thread <id>`},
@@ -233,14 +315,20 @@ Current limitations:
 		{aliases: []string{"clearall"}, group: breakCmds, cmdFn: clearAll, helpMsg: `Deletes multiple breakpoints.
 
 	clearall [<locspec>]
+	clearall -fn <regexp>
+
+If called with the locspec argument it will delete all the breakpoints matching the locspec. If locspec is omitted all breakpoints are deleted.
+With -fn only the breakpoints set inside a function whose name matches the regular expression are deleted, for example:
 
-If called with the locspec argument it will delete all the breakpoints matching the locspec. If locspec is omitted all breakpoints are deleted.`},
+	clearall -fn ^main\.
+
+The regular expression can optionally be prefixed with 'regexp:'.`},
 		{aliases: []string{"toggle"}, group: breakCmds, cmdFn: toggle, helpMsg: `Toggles on or off a breakpoint.
 
 	toggle <breakpoint name or id>`},
 		{aliases: []string{"goroutines", "grs"}, group: goroutineCmds, cmdFn: c.goroutines, helpMsg: `List program goroutines.
 
-	goroutines [-u|-r|-g|-s] [-t [depth]] [-l] [-with loc expr] [-without loc expr] [-group argument] [-chan expr] [-exec command]
+	goroutines [-u|-r|-g|-s] [-t [depth]] [-l] [-user] [-with loc expr] [-without loc expr] [-group argument] [-chan expr] [-sort blocked] [-exec command]
 
 Print out info for every goroutine. The flag controls what information is shown along with each goroutine:
 
@@ -291,6 +379,19 @@ To only display user (or runtime) goroutines, use:
 	goroutines -with user
 	goroutines -without user
 
+To hide runtime goroutines and report how many were hidden, use:
+
+	goroutines -user
+
+A goroutine is a runtime goroutine if its start function belongs to the runtime package (with the exception of runtime.main).
+
+To only display goroutines whose wait reason contains (or does not contain) the specified string, use:
+
+	goroutines -with reason chan receive
+	goroutines -without reason select
+
+The match is case insensitive, the wait reason extends up to the next option.
+
 CHANNELS
 
 To only show goroutines waiting to send to or receive from a specific channel use:
@@ -301,7 +402,7 @@ Note that 'expr' must not contain spaces.
 
 GROUPING
 
-	goroutines -group (userloc|curloc|goloc|startloc|running|user)
+	goroutines -group (userloc|curloc|goloc|startloc|running|user|reason)
 
 	Where:
 	userloc: groups goroutines by the location of the topmost stackframe in user code
@@ -310,14 +411,21 @@ GROUPING
 	startloc: groups goroutines by the location of the start function
 	running: groups goroutines by whether they are running or not
 	user: groups goroutines by whether they are user or runtime goroutines
+	reason: groups goroutines by their wait reason
 
 
-Groups goroutines by the given location, running status or user classification, up to 5 goroutines per group will be displayed as well as the total number of goroutines in the group.
+Groups goroutines by the given location, running status, user classification or wait reason, up to 5 goroutines per group will be displayed as well as the total number of goroutines in the group.
 
 	goroutines -group label key
 
 Groups goroutines by the value of the label with the specified key.
 
+SORTING
+
+	goroutines -sort blocked
+
+Lists the goroutines that have been blocked the longest first. Blocked goroutines are shown with their wait reason and, when the clock of the target can be read, how long they have been blocked for. Note that the runtime only records when a goroutine became blocked during garbage collection: goroutines that became blocked after the last garbage collection are listed together with goroutines that are not blocked.
+
 EXEC
 
 	goroutines -exec <command>
@@ -329,37 +437,56 @@ Runs the command on every goroutine.
 	goroutine
 	goroutine <id>
 	goroutine <id> <command>
+	goroutine <id> startstack
 
 Called without arguments it will show information about the current goroutine.
 Called with a single argument it will switch to the specified goroutine.
-Called with more arguments it will execute a command on the specified goroutine.`},
+Called with more arguments it will execute a command on the specified goroutine.
+
+The startstack subcommand prints the stack trace of the 'go' statement that created the goroutine. Unless the target was started with GODEBUG=tracebackancestors=N only the frame containing the 'go' statement is available.`},
 		{aliases: []string{"breakpoints", "bp"}, group: breakCmds, cmdFn: breakpoints, helpMsg: `Print out info for active breakpoints.
 
 	breakpoints [-a] [-save <filename>]
 
 Specifying -a prints all physical breakpoint, including internal breakpoints.
-Speciftying -save <filename> saves all breakpoints to the specified file in a format that can be loaded later using the 'source' command.`},
+Specifying -save <filename> saves all breakpoints, along with their conditions and the commands attached to them with 'on', to the specified file in a format that can be loaded later using the 'source' command. Watchpoints are not saved.`},
 		{aliases: []string{"print", "p"}, group: dataCmds, allowedPrefixes: onPrefix | deferredPrefix, cmdFn: c.printVar, helpMsg: `Evaluate an expression.
 
 	[goroutine <n>] [frame <m>] print [%format] <expression>
+	[goroutine <n>] [frame <m>] print [-fmt <format name>] <expression>
 
 See Documentation/cli/expr.md for a description of supported expressions.
 
-The optional format argument is a format specifier, like the ones used by the fmt package. For example "print %x v" will print v as an hexadecimal number.`},
+The optional format argument is a format specifier, like the ones used by the fmt package. For example "print %x v" will print v as an hexadecimal number.
+Alternatively -fmt can be used to specify one of the following formats: hex, oct, bin (which print numbers in hexadecimal, octal and binary with the corresponding prefix), dec, char and str. The format is applied to the value of the expression and to the elements of arrays, slices, maps and structs. For example "print -fmt hex v".
+By default byte slices and arrays are printed as a hexadecimal string of their first 16 bytes, they are printed as a quoted string with str (or "%s" and "%q"), bytes that are not valid UTF-8 are printed as escape sequences.`},
+		{aliases: []string{"errors"}, group: dataCmds, cmdFn: errorsCmd, helpMsg: `Prints the chain of errors wrapped by an error.
+
+	[goroutine <n>] [frame <m>] errors [-call] <expression>
+
+Prints the error, and every error it wraps, with their dynamic types. Errors that wrap multiple errors (for example the ones created by errors.Join or by fmt.Errorf with multiple %w verbs) are printed as a tree. The messages of the errors are not truncated.
+
+Errors defined by the standard library are unwrapped by reading their fields. With -call the Unwrap method of other errors is called using function call injection, see 'help call', otherwise the chain stops at the first error of an unknown type.`},
 		{aliases: []string{"whatis"}, group: dataCmds, cmdFn: whatisCommand, helpMsg: `Prints type of an expression or a type.
 
-	whatis <expression>
-	whatis <type name>`},
+	whatis [-verbose] <expression>
+	whatis [-verbose] <type name>
+
+With -verbose the layout of the type is also printed, as a tree of the types of its fields with their offsets and sizes in bytes. Struct fields, the fields of the structs used to implement slices, strings and interfaces, array elements and the underlying types of named types are expanded, pointers are not followed.`},
 		{aliases: []string{"set"}, group: dataCmds, cmdFn: setVar, helpMsg: `Changes the value of a variable.
 
 	[goroutine <n>] [frame <m>] set <variable> = <value>
 
-See Documentation/cli/expr.md for a description of supported expressions. Only numerical variables and pointers can be changed.`},
+See Documentation/cli/expr.md for a description of supported expressions. Only numerical variables and pointers can be changed.
+
+Map entries can be changed with 'set m[key] = value' only if the key already exists in the map, inserting a new key requires function call injection, use 'call m[key] = value' instead.`},
 		{aliases: []string{"sources"}, cmdFn: sources, helpMsg: `Print list of source files.
 
-	sources [<regex>]
+	sources [-pkg] [<regex>]
 
-If regex is specified only the source files matching it will be returned.`},
+If regex is specified only the source files matching it will be returned.
+
+If -pkg is specified source files are listed grouped by the package they belong to and regex is matched against the import path of the package.`},
 		{aliases: []string{"funcs"}, cmdFn: funcs, helpMsg: `Print list of functions.
 
 	funcs [<regex>]
@@ -395,8 +522,13 @@ If regex is specified only package variables with a name matching it will be ret
 		{aliases: []string{"regs"}, cmdFn: regs, group: dataCmds, helpMsg: `Print contents of CPU registers.
 
 	regs [-a]
+	regs [-follow] <regname>...
+
+Argument -a shows more registers. If one or more register names are specified only those registers are printed, names are case insensitive and architecture specific aliases (like PC, SP and BP) are accepted.
 
-Argument -a shows more registers. Individual registers can also be displayed by 'print' and 'display'. See Documentation/cli/expr.md.`},
+	-follow	also adds the registers to the display list (see 'help display') so that they are printed, in hexadecimal, every time the program stops. Can be abbreviated to -f.
+
+Individual registers can also be displayed by 'print' and 'display'. See Documentation/cli/expr.md.`},
 		{aliases: []string{"exit", "quit", "q"}, cmdFn: exitCommand, helpMsg: `Exit the debugger.
 
 	exit [-c]
@@ -404,23 +536,33 @@ Argument -a shows more registers. Individual registers can also be displayed by
 When connected to a headless instance started with the --accept-multiclient, pass -c to resume the execution of the target process before disconnecting.`},
 		{aliases: []string{"list", "ls", "l"}, cmdFn: listCommand, helpMsg: `Show source code.
 
-	[goroutine <n>] [frame <m>] list [<locspec>]
+	[goroutine <n>] [frame <m>] list [-around <n>] [<locspec>]
+	list [-around <n>] +
+	list [-around <n>] -
 
 Show source around current point or provided locspec.
 
+	-around <n>	show <n> lines of context before and after the line, instead of the number of lines specified by the source-list-line-count configuration option.
+
+The '+' and '-' arguments page forward and backward through the file that was last listed, either by the list command or when the program stopped, showing the same number of lines as the last listing.
+
 For example:
 
 	frame 1 list 69
 	list testvariables.go:10000
 	list main.main:30
-	list 40`},
+	list 40
+	list -around 20 main.go:100
+	list +`},
 		{aliases: []string{"stack", "bt"}, allowedPrefixes: onPrefix, group: stackCmds, cmdFn: stackCommand, helpMsg: `Print stack trace.
 
-	[goroutine <n>] [frame <m>] stack [<depth>] [-full] [-offsets] [-defer] [-a <n>] [-adepth <depth>] [-mode <mode>]
+	[goroutine <n>] [frame <m>] stack [<depth>] [-maxdepth <depth>] [-full] [-offsets] [-defer] [-fast] [-a <n>] [-adepth <depth>] [-mode <mode>]
 
+	-maxdepth <depth>	maximum number of frames to print (default 50), same as specifying <depth>.
 	-full		every stackframe is decorated with the value of its local variables and arguments.
 	-offsets	prints frame offset of each frame.
 	-defer		prints deferred function call stack for each frame.
+	-fast		unwinds the stack following the chain of frame pointers instead of using DWARF, faster but approximate (amd64 and arm64 only).
 	-a <n>		prints stacktrace of n ancestors of the selected goroutine (target process must have tracebackancestors enabled)
 	-adepth <depth>	configures depth of ancestor stacktrace
 	-mode <mode>	specifies the stacktrace mode, possible values are:
@@ -428,6 +570,20 @@ For example:
 			simple	- disables automatic switch between cgo and go
 			fromg	- starts from the registers stored in the runtime.g struct
 `},
+		{aliases: []string{"defers"}, group: stackCmds, cmdFn: defersCommand, helpMsg: `Print the pending deferred calls of the selected goroutine.
+
+	[goroutine <n>] defers
+
+Deferred calls are printed starting with the most recent one, which will be the first to run.
+
+Calls deferred by optimized functions, where the compiler uses open-coded defers, are marked as open-coded; for them the location of the function that deferred them is printed instead of the location of the defer statement. Open-coded defers are only listed for programs built with Go 1.22 or later.`},
+		{aliases: []string{"panic"}, group: stackCmds, cmdFn: panicCommand, helpMsg: `Print the panics in progress on the selected goroutine.
+
+	[goroutine <n>] panic
+
+Panics are printed starting with the most recent one, for each panic the value passed to panic and, if it is running deferred calls, the frame whose deferred calls are being run are printed. If the panic has been recovered this is the frame that will return normally to its caller. Calls to runtime.Goexit are also listed.
+
+The pending deferred calls of the goroutine are printed after the panics, see the defers command.`},
 		{aliases: []string{"frame"},
 			group: stackCmds,
 			cmdFn: func(t *Term, ctx callContext, arg string) error {
@@ -476,12 +632,15 @@ If path ends with the .star extension it will be interpreted as a starlark scrip
 If path is a single '-' character an interactive starlark interpreter will start instead. Type 'exit' to exit.`},
 		{aliases: []string{"disassemble", "disass"}, cmdFn: disassCommand, helpMsg: `Disassembler.
 
-	[goroutine <n>] [frame <m>] disassemble [-a <start> <end>] [-l <locspec>]
+	[goroutine <n>] [frame <m>] disassemble [-a <start> <end>] [-l <locspec>] [-fn <regex>]
 
 If no argument is specified the function being executed in the selected stack frame will be executed.
 
 	-a <start> <end>	disassembles the specified address range
-	-l <locspec>		disassembles the specified function`},
+	-l <locspec>		disassembles the specified function
+	-fn <regex>		disassembles all functions whose name matches the regular expression (at most 50)
+
+The regular expression passed to -fn can optionally be prefixed with 'regexp:'.`},
 		{aliases: []string{"on"}, group: breakCmds, cmdFn: c.onCmd, helpMsg: `Executes a command when a breakpoint is hit.
 
 	on <breakpoint name or id> <command>
@@ -504,6 +663,7 @@ The command 'on x -edit' can be used to edit the list of commands executed when
 	condition <breakpoint name or id> <boolean expression>.
 	condition -hitcount <breakpoint name or id> <operator> <argument>.
 	condition -per-g-hitcount <breakpoint name or id> <operator> <argument>.
+	condition -label <breakpoint name or id> <key>=<value>.
 	condition -clear <breakpoint name or id>.
 
 Specifies that the breakpoint, tracepoint or watchpoint should break only if the boolean expression is true.
@@ -522,7 +682,9 @@ With the -hitcount option a condition on the breakpoint hit count can be set, th
 
 The -per-g-hitcount option works like -hitcount, but use per goroutine hitcount to compare with n.
 
-With the -clear option a condition on the breakpoint can removed.
+With the -label option the breakpoint will only stop goroutines that have the pprof label key with the specified value. The option can be used multiple times to require more than one label.
+
+With the -clear option the condition, the hit count condition and the label conditions on the breakpoint are removed.
 
 The '% n' form means we should stop at the breakpoint when the hitcount is a multiple of n.
 
@@ -559,6 +721,11 @@ If locspec is omitted edit will open the current source file in the editor, othe
 	libraries [-d N]
 
 If used with the -d option it will re-attempt to download the debug symbols for library N, using debuginfod-find.`},
+		{aliases: []string{"plugins"}, cmdFn: plugins, helpMsg: `List loaded Go plugins.
+
+	plugins
+
+For each plugin loaded with plugin.Open prints its plugin path, the path of its shared object and the base address the shared object was loaded at. Plugins built without debug info are marked as such, only function names and line numbers are available for them.`},
 
 		{aliases: []string{"examinemem", "x"}, group: dataCmds, cmdFn: examineMemoryCmd, helpMsg: `Examine raw memory at the given address.
 
@@ -566,25 +733,57 @@ Examine memory:
 
 	examinemem [-fmt <format>] [-count|-len <count>] [-size <size>] <address>
 	examinemem [-fmt <format>] [-count|-len <count>] [-size <size>] -x <expression>
+	examinemem/<count><format><size> <expression>
 
-Format represents the data format and the value is one of this list (default hex): bin(binary), oct(octal), dec(decimal), hex(hexadecimal) and raw.
+Format represents the data format and the value is one of this list (default hex): bin(binary), oct(octal), dec(decimal), hex(hexadecimal), char(character), inst(instructions) and raw.
 Length is the number of bytes (default 1) and must be less than or equal to 1000.
 Address is the memory location of the target to examine. Please note '-len' is deprecated by '-count and -size'.
 Expression can be an integer expression or pointer value of the memory location to examine.
 
+The third form accepts a gdb style format specifier: <count> is the number of units to display, <format> is one of x (hex), d (decimal), o (octal), t (binary), c (char) or i (instructions) and <size> is one of b (1 byte), h (2 bytes), w (4 bytes) or g (8 bytes). Each part is optional.
+
 For example:
 
     x -fmt hex -count 20 -size 1 0xc00008af38
     x -fmt hex -count 20 -size 1 -x 0xc00008af38 + 8
     x -fmt hex -count 20 -size 1 -x &myVar
-    x -fmt hex -count 20 -size 1 -x myPtrVar`},
+    x -fmt hex -count 20 -size 1 -x myPtrVar
+    x/16xb &buf[0]
+    x/4xg &mystruct
+    x/5i RIP`},
+
+		{aliases: []string{"search-mem"}, group: dataCmds, cmdFn: searchMemoryCmd, helpMsg: `Searches the memory of the target for a value.
+
+	search-mem [-align <n>] [-max <n>] <expression>
+
+Evaluates the expression and scans all the readable memory of the target for its value. Pointers are searched for as the address they point to, integers and floating point numbers by their in-memory representation and strings by their contents, a string literal like "\xde\xad\xbe\xef" can be used to search for arbitrary bytes.
+By default only addresses that are a multiple of the natural alignment of the value are reported and at most 100 results are printed, this can be changed with -align and -max (-max 0 means no limit).
+
+Each address is printed along with the package variable, function or goroutine stack containing it, when one can be found. This is mostly useful with core files, for example to find all references to an object:
+
+	search-mem (*main.T)(0xc000012340)
+	search-mem myPtrVar`},
+
+		{aliases: []string{"mappings"}, group: dataCmds, cmdFn: mappingsCmd, helpMsg: `Print the memory mappings of the target.
+
+	mappings
+
+Lists the memory regions of the target process with their address range, permissions, file offset and the file backing them, or a pseudo-path like [heap] or [stack], when known. For live processes on Linux this is read from /proc/PID/maps, for core files from the program headers of the core file.`},
+
+		{aliases: []string{"memstats"}, group: dataCmds, cmdFn: memstatsCmd, helpMsg: `Print memory allocator statistics of the target.
+
+	memstats
+
+Reads the memory statistics maintained by the Go runtime, the same ones returned by runtime.ReadMemStats, directly from the memory of the target. Since the runtime keeps some of these statistics in different places depending on its version values that are only approximated are marked with '~'. Statistics that can not be read are reported as unavailable.`},
 
 		{aliases: []string{"display"}, group: dataCmds, cmdFn: display, helpMsg: `Print value of an expression every time the program stops.
 
-	display -a [%format] <expression>
+	display -a [-change] [%format|-fmt <format name>] <expression>
 	display -d <number>
 
-The '-a' option adds an expression to the list of expression printed every time the program stops. The '-d' option removes the specified expression from the list.
+The '-a' option adds an expression to the list of expression printed every time the program stops. If '-change' is also specified the expression is only printed when its value is different from the last time it was printed. The '-d' option removes the specified expression from the list.
+
+Expressions are evaluated in the current scope, expressions that can not be evaluated at a stop, for example because they refer to local variables of a different function, print an error but remain in the list.
 
 If display is called without arguments it will print the value of all expression in the list.`},
 
@@ -636,6 +835,16 @@ Switches to the specified process.`},
 				cmdFn:   c.rewind,
 				helpMsg: "Run backwards until breakpoint or start of recorded history.",
 			},
+			command{
+				aliases: []string{"rnext"},
+				group:   runCmds,
+				cmdFn:   c.rnext,
+				helpMsg: `Step backwards over to the previous source line.
+
+	rnext [count]
+
+Equivalent to 'rev next': stops at the previous line of the current function without stepping into the functions it called. Optional [count] argument allows you to skip multiple lines.`,
+			},
 			command{
 				aliases: []string{"check", "checkpoint"},
 				cmdFn:   checkpoint,
@@ -714,6 +923,11 @@ func (c *Commands) CallWithContext(cmdstr string, t *Term, ctx callContext) erro
 	if len(vals) > 1 {
 		args = strings.TrimSpace(vals[1])
 	}
+	if name, spec, ok := strings.Cut(cmdname, "/"); ok && c.Find(name, ctx.Prefix).aliases[0] == "examinemem" {
+		// gdb style format specifier, e.g. x/16xb
+		cmdname = name
+		args = strings.TrimSpace("/" + spec + " " + args)
+	}
 	cmd := c.Find(cmdname, ctx.Prefix)
 	if t != nil && len(t.customCommandsInvalidated) > 0 && cmd.group == runCmds {
 		t.customCommandsInvalidated[len(t.customCommandsInvalidated)-1] = true
@@ -827,12 +1041,23 @@ func threads(t *Term, ctx callContext, args string) error {
 		if state.CurrentThread != nil && state.CurrentThread.ID == th.ID {
 			prefix = "* "
 		}
+		var osinfo []string
+		if th.OSState != "" {
+			osinfo = append(osinfo, th.OSState)
+		}
+		if th.LastCPU != nil {
+			osinfo = append(osinfo, fmt.Sprintf("cpu %d", *th.LastCPU))
+		}
+		osstate := ""
+		if len(osinfo) > 0 {
+			osstate = " [" + strings.Join(osinfo, ", ") + "]"
+		}
 		if th.Function != nil {
-			fmt.Fprintf(t.stdout, "%sThread %d at %#v %s:%d %s\n",
+			fmt.Fprintf(t.stdout, "%sThread %d at %#v %s:%d %s%s\n",
 				prefix, th.ID, th.PC, t.formatPath(th.File),
-				th.Line, th.Function.Name())
+				th.Line, th.Function.Name(), osstate)
 		} else {
-			fmt.Fprintf(t.stdout, "%sThread %s\n", prefix, t.formatThread(th))
+			fmt.Fprintf(t.stdout, "%sThread %s%s\n", prefix, t.formatThread(th), osstate)
 		}
 	}
 	return nil
@@ -929,6 +1154,9 @@ func (c *Commands) goroutines(t *Term, ctx callContext, argstr string) error {
 		if len(groups) > 0 {
 			for i := range groups {
 				fmt.Fprintf(t.stdout, "%s\n", groups[i].Name)
+				if flags&api.PrintGoroutinesSortBlocked != 0 {
+					sortGoroutinesByBlocked(gs[groups[i].Offset:][:groups[i].Count])
+				}
 				err = c.printGoroutines(t, ctx, "\t", gs[groups[i].Offset:][:groups[i].Count], fgl, flags, depth, cmd, &done, state)
 				if err != nil {
 					return err
@@ -942,7 +1170,11 @@ func (c *Commands) goroutines(t *Term, ctx callContext, argstr string) error {
 				fmt.Fprintf(t.stdout, "Too many groups\n")
 			}
 		} else {
-			slices.SortFunc(gs, func(a, b *api.Goroutine) int { return cmp.Compare(a.ID, b.ID) })
+			if flags&api.PrintGoroutinesSortBlocked != 0 {
+				sortGoroutinesByBlocked(gs)
+			} else {
+				slices.SortFunc(gs, func(a, b *api.Goroutine) int { return cmp.Compare(a.ID, b.ID) })
+			}
 			err = c.printGoroutines(t, ctx, "", gs, fgl, flags, depth, cmd, &done, state)
 			if err != nil {
 				return err
@@ -950,12 +1182,78 @@ func (c *Commands) goroutines(t *Term, ctx callContext, argstr string) error {
 			gslen += len(gs)
 		}
 	}
-	if gslen > 0 {
+	hidden := 0
+	if flags&api.PrintGoroutinesUserOnly != 0 {
+		hidden, err = countSystemGoroutines(t, c, filters, batchSize)
+		if err != nil {
+			return err
+		}
+	}
+	switch {
+	case gslen > 0 && flags&api.PrintGoroutinesUserOnly != 0:
+		fmt.Fprintf(t.stdout, "[%d goroutines, %d runtime goroutines hidden]\n", gslen, hidden)
+	case gslen > 0:
 		fmt.Fprintf(t.stdout, "[%d goroutines]\n", gslen)
+	case flags&api.PrintGoroutinesUserOnly != 0:
+		fmt.Fprintf(t.stdout, "[%d runtime goroutines hidden]\n", hidden)
 	}
 	return nil
 }
 
+// countSystemGoroutines returns the number of goroutines hidden by the -user
+// flag of the goroutines command, i.e. the number of runtime goroutines
+// matching all other filters.
+func countSystemGoroutines(t *Term, c *Commands, filters []api.ListGoroutinesFilter, batchSize int) (int, error) {
+	sysfilters := make([]api.ListGoroutinesFilter, 0, len(filters))
+	for _, filter := range filters {
+		if filter.Kind == api.GoroutineUser && !filter.Negated {
+			filter.Negated = true
+		}
+		sysfilters = append(sysfilters, filter)
+	}
+	// Grouping returns the size of each group, only one member per group is
+	// requested since we are only interested in the total.
+	group := api.GoroutineGroupingOptions{GroupBy: api.GoroutineUser, MaxGroupMembers: 1, MaxGroups: 1}
+	n := 0
+	for start := 0; start >= 0; {
+		var groups []api.GoroutineGroup
+		var err error
+		_, groups, start, _, err = t.client.ListGoroutinesWithFilter(start, batchSize, sysfilters, &group, &api.EvalScope{GoroutineID: -1, Frame: c.frame})
+		if err != nil {
+			return 0, err
+		}
+		for _, g := range groups {
+			n += g.Total
+		}
+	}
+	return n, nil
+}
+
+// sortGoroutinesByBlocked sorts gs so that the goroutines that have been
+// blocked the longest come first, followed by all other goroutines sorted
+// by ID. Since WaitSince is a timestamp this also works when BlockedFor
+// is not available.
+func sortGoroutinesByBlocked(gs []*api.Goroutine) {
+	waitSince := func(g *api.Goroutine) int64 {
+		if g.Status != api.GoroutineWaiting && g.Status != api.GoroutineSyscall {
+			return 0
+		}
+		return g.WaitSince
+	}
+	slices.SortFunc(gs, func(a, b *api.Goroutine) int {
+		wa, wb := waitSince(a), waitSince(b)
+		switch {
+		case wa != 0 && wb != 0 && wa != wb:
+			return cmp.Compare(wa, wb)
+		case wa != 0 && wb == 0:
+			return -1
+		case wa == 0 && wb != 0:
+			return 1
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
 func selectedGID(state *api.DebuggerState) int64 {
 	if state.SelectedGoroutine == nil {
 		return 0
@@ -1001,9 +1299,23 @@ func (c *Commands) goroutine(t *Term, ctx callContext, argstr string) error {
 	if err != nil {
 		return err
 	}
+	if args[1] == "startstack" {
+		return goroutineStartStack(t, ctx.Scope.GoroutineID)
+	}
 	return c.CallWithContext(args[1], t, ctx)
 }
 
+func goroutineStartStack(t *Term, gid int64) error {
+	stack, err := t.client.GoroutineCreationStacktrace(gid, 50)
+	if err != nil {
+		return err
+	}
+	t.stdout.pw.PageMaybe(nil)
+	fmt.Fprintf(t.stdout, "Goroutine %d created by:\n", gid)
+	printStack(t, t.stdout, stack, "", false)
+	return nil
+}
+
 // Handle "frame", "up", "down" commands.
 func (c *Commands) frameCommand(t *Term, ctx callContext, argstr string, direction frameDirection) error {
 	frame := 1
@@ -1129,7 +1441,10 @@ func (t *Term) formatGoroutine(g *api.Goroutine, fgl api.FormatGoroutineLoc) str
 
 	if (g.Status == api.GoroutineWaiting || g.Status == api.GoroutineSyscall) && g.WaitReason != 0 {
 		fmt.Fprintf(buf, " [%s", api.WaitReasonString(t.goVersion(), g.WaitReason))
-		if g.WaitSince > 0 {
+		switch {
+		case g.BlockedFor > 0:
+			fmt.Fprintf(buf, " %v", g.BlockedFor.Round(time.Millisecond))
+		case g.WaitSince > 0:
 			fmt.Fprintf(buf, " %d", g.WaitSince)
 		}
 		fmt.Fprintf(buf, "]")
@@ -1186,30 +1501,9 @@ func restart(t *Term, ctx callContext, args string) error {
 }
 
 func restartRecorded(t *Term, ctx callContext, args string) error {
-	v := config.Split2PartsBySpace(args)
-
-	rerecord := false
-	resetArgs := false
-	newArgv := []string{}
-	newRedirects := [3]string{}
-	restartPos := ""
-
-	if len(v) > 0 {
-		if v[0] == "-r" {
-			rerecord = true
-			if len(v) == 2 {
-				var err error
-				resetArgs, newArgv, newRedirects, err = parseNewArgv(v[1])
-				if err != nil {
-					return err
-				}
-			}
-		} else {
-			if len(v) > 1 {
-				return errors.New("too many arguments to restart")
-			}
-			restartPos = v[0]
-		}
+	rerecord, restartPos, resetArgs, newArgv, newRedirects, err := parseRestartRecordedArgs(args)
+	if err != nil {
+		return err
 	}
 
 	if err := restartIntl(t, rerecord, restartPos, resetArgs, newArgv, newRedirects); err != nil {
@@ -1226,6 +1520,28 @@ func restartRecorded(t *Term, ctx callContext, args string) error {
 	return nil
 }
 
+// parseRestartRecordedArgs parses the arguments of restart for a recorded
+// target.
+func parseRestartRecordedArgs(args string) (rerecord bool, restartPos string, resetArgs bool, newArgv []string, newRedirects [3]string, err error) {
+	v := config.Split2PartsBySpace(args)
+	newArgv = []string{}
+
+	if len(v) > 0 {
+		if v[0] == "-r" || v[0] == "-rerecord" || v[0] == "--rerecord" {
+			rerecord = true
+			if len(v) == 2 {
+				resetArgs, newArgv, newRedirects, err = parseNewArgv(v[1])
+			}
+		} else {
+			if len(v) > 1 {
+				err = errors.New("too many arguments to restart")
+			}
+			restartPos = v[0]
+		}
+	}
+	return
+}
+
 // parseOptionalCount parses an optional count argument.
 // If there are not arguments, a value of 1 is returned as the default.
 func parseOptionalCount(arg string) (int64, error) {
@@ -1331,7 +1647,7 @@ func printcontextNoState(t *Term) {
 }
 
 func (c *Commands) rebuild(t *Term, ctx callContext, args string) error {
-	if ctx.Prefix == revPrefix {
+	if ctx.Prefix&revPrefix != 0 {
 		return c.rewind(t, ctx, args)
 	}
 	defer t.onStop()
@@ -1353,12 +1669,12 @@ func (c *Commands) cont(t *Term, ctx callContext, args string) error {
 		defer func() {
 			for _, bp := range tmp {
 				if _, err := t.client.ClearBreakpoint(bp.ID); err != nil {
-					fmt.Fprintf(t.stdout, "failed to clear temporary breakpoint: %d", bp.ID)
+					fmt.Fprintf(t.stdout, "failed to clear temporary breakpoint %d: %v\n", bp.ID, err)
 				}
 			}
 		}()
 	}
-	if ctx.Prefix == revPrefix {
+	if ctx.Prefix&revPrefix != 0 {
 		return c.rewind(t, ctx, args)
 	}
 	defer t.onStop()
@@ -1459,7 +1775,7 @@ func (c *Commands) step(t *Term, ctx callContext, args string) error {
 	}
 	c.frame = 0
 	stepfn := t.client.Step
-	if ctx.Prefix == revPrefix {
+	if ctx.Prefix&revPrefix != 0 {
 		stepfn = t.client.ReverseStep
 	}
 	state, err := exitedToError(stepfn())
@@ -1475,15 +1791,15 @@ var errNotOnFrameZero = errors.New("not on topmost frame")
 
 // stepInstruction implements the step-instruction (stepi) command.
 func (c *Commands) stepInstruction(t *Term, ctx callContext, args string) error {
-	return stepInstruction(t, ctx, c.frame, false)
+	return stepInstruction(t, ctx, c.frame, false, args)
 }
 
 // nextInstruction implements the next-instruction (nexti) command.
 func (c *Commands) nextInstruction(t *Term, ctx callContext, args string) error {
-	return stepInstruction(t, ctx, c.frame, true)
+	return stepInstruction(t, ctx, c.frame, true, args)
 }
 
-func stepInstruction(t *Term, ctx callContext, frame int, skipCalls bool) error {
+func stepInstruction(t *Term, ctx callContext, frame int, skipCalls bool, args string) error {
 	if err := scopePrefixSwitch(t, ctx); err != nil {
 		return err
 	}
@@ -1491,15 +1807,22 @@ func stepInstruction(t *Term, ctx callContext, frame int, skipCalls bool) error
 		return errNotOnFrameZero
 	}
 
+	count, err := parseOptionalCount(args)
+	if err != nil {
+		return err
+	} else if count <= 0 {
+		return errors.New("Invalid step-instruction count")
+	}
+
 	defer t.onStop()
-	var fn func(bool) (*api.DebuggerState, error)
-	if ctx.Prefix == revPrefix {
-		fn = t.client.ReverseStepInstruction
+	var fn func(bool, int) (*api.DebuggerState, error)
+	if ctx.Prefix&revPrefix != 0 {
+		fn = t.client.ReverseStepInstructions
 	} else {
-		fn = t.client.StepInstruction
+		fn = t.client.StepInstructions
 	}
 
-	state, err := exitedToError(fn(skipCalls))
+	state, err := exitedToError(fn(skipCalls, int(count)))
 	if err != nil {
 		printcontextNoState(t)
 		return err
@@ -1518,6 +1841,11 @@ func (c *Commands) revCmd(t *Term, ctx callContext, args string) error {
 	return c.CallWithContext(args, t, ctx)
 }
 
+func (c *Commands) rnext(t *Term, ctx callContext, args string) error {
+	ctx.Prefix |= revPrefix
+	return c.next(t, ctx, args)
+}
+
 func (c *Commands) next(t *Term, ctx callContext, args string) error {
 	if err := scopePrefixSwitch(t, ctx); err != nil {
 		return err
@@ -1527,7 +1855,7 @@ func (c *Commands) next(t *Term, ctx callContext, args string) error {
 	}
 
 	nextfn := t.client.Next
-	if ctx.Prefix == revPrefix {
+	if ctx.Prefix&revPrefix != 0 {
 		nextfn = t.client.ReverseNext
 	}
 
@@ -1565,30 +1893,141 @@ func (c *Commands) stepout(t *Term, ctx callContext, args string) error {
 	}
 
 	stepoutfn := t.client.StepOut
-	if ctx.Prefix == revPrefix {
+	if ctx.Prefix&revPrefix != 0 {
 		stepoutfn = t.client.ReverseStepOut
 	}
 
-	state, err := exitedToError(stepoutfn())
+	count, err := parseOptionalCount(args)
 	if err != nil {
-		printcontextNoState(t)
 		return err
+	} else if count <= 0 {
+		return errors.New("Invalid stepout count")
 	}
-	printcontext(t, state)
-	return continueUntilCompleteNext(t, state, "stepout", true)
+	for ; count > 0; count-- {
+		state, err := exitedToError(stepoutfn())
+		if err != nil {
+			printcontextNoState(t)
+			return err
+		}
+		// A breakpoint hit during stepout also stops the loop.
+		finishedStepout := count == 1 || state.NextInProgress
+		if finishedStepout {
+			printcontext(t, state)
+		}
+		if err := continueUntilCompleteNext(t, state, "stepout", finishedStepout); err != nil {
+			return err
+		}
+		if finishedStepout {
+			break
+		}
+	}
+	return nil
+}
+
+func handle(t *Term, ctx callContext, args string) error {
+	argv := strings.Fields(args)
+	policies, err := t.client.ListSignalPolicies()
+	if err != nil {
+		return err
+	}
+	if len(argv) == 0 {
+		printSignalPolicies(t, policies)
+		return nil
+	}
+
+	var policy api.SignalPolicy
+	found := false
+	for _, p := range policies {
+		if strings.EqualFold(p.Name, argv[0]) || strings.EqualFold(p.Name, "SIG"+argv[0]) || strconv.Itoa(p.Signal) == argv[0] {
+			policy, found = p, true
+			break
+		}
+	}
+	if len(argv) == 1 {
+		if !found {
+			return fmt.Errorf("unknown signal %q", argv[0])
+		}
+		printSignalPolicies(t, []api.SignalPolicy{policy})
+		return nil
+	}
+	if !found {
+		// signals without a name still get validated by the server
+		policy = api.SignalPolicy{Pass: true}
+	}
+
+	for _, kw := range argv[1:] {
+		switch kw {
+		case "stop":
+			policy.Stop, policy.Print = true, true
+		case "nostop":
+			policy.Stop = false
+		case "print":
+			policy.Print = true
+		case "noprint":
+			policy.Print, policy.Stop = false, false
+		case "pass":
+			policy.Pass = true
+		case "nopass":
+			policy.Pass = false
+		default:
+			return fmt.Errorf("unknown keyword %q", kw)
+		}
+	}
+	policy, err = t.client.SetSignalPolicy(argv[0], policy.Stop, policy.Print, policy.Pass)
+	if err != nil {
+		return err
+	}
+	printSignalPolicies(t, []api.SignalPolicy{policy})
+	return nil
+}
+
+func printSignalPolicies(t *Term, policies []api.SignalPolicy) {
+	yesno := func(b bool) string {
+		if b {
+			return "Yes"
+		}
+		return "No"
+	}
+	w := new(tabwriter.Writer)
+	w.Init(t.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Signal\tNumber\tStop\tPrint\tPass\n")
+	for _, p := range policies {
+		name := p.Name
+		if name == "" {
+			name = "?"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", name, p.Signal, yesno(p.Stop), yesno(p.Print), yesno(p.Pass))
+	}
+	w.Flush()
 }
 
 func (c *Commands) call(t *Term, ctx callContext, args string) error {
 	if err := scopePrefixSwitch(t, ctx); err != nil {
 		return err
 	}
-	const unsafePrefix = "-unsafe "
 	unsafe := false
-	if strings.HasPrefix(args, unsafePrefix) {
-		unsafe = true
-		args = args[len(unsafePrefix):]
+	var timeout time.Duration
+flagsLoop:
+	for {
+		flag, rest, _ := strings.Cut(args, " ")
+		switch flag {
+		case "-unsafe":
+			unsafe = true
+			args = rest
+		case "-timeout":
+			arg, rest, _ := strings.Cut(strings.TrimSpace(rest), " ")
+			var err error
+			timeout, err = parseCallTimeout(arg)
+			if err != nil {
+				return err
+			}
+			args = rest
+		default:
+			break flagsLoop
+		}
+		args = strings.TrimSpace(args)
 	}
-	state, err := exitedToError(t.client.Call(ctx.Scope.GoroutineID, args, unsafe))
+	state, err := exitedToError(t.client.CallWithTimeout(ctx.Scope.GoroutineID, args, unsafe, timeout))
 	c.frame = 0
 	if err != nil {
 		printcontextNoState(t)
@@ -1598,6 +2037,19 @@ func (c *Commands) call(t *Term, ctx callContext, args string) error {
 	return continueUntilCompleteNext(t, state, "call", true)
 }
 
+// parseCallTimeout parses the argument of 'call -timeout', a plain number
+// is interpreted as a number of seconds.
+func parseCallTimeout(arg string) (time.Duration, error) {
+	if n, err := strconv.Atoi(arg); err == nil {
+		arg = strconv.Itoa(n) + "s"
+	}
+	timeout, err := time.ParseDuration(arg)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be a positive duration", arg)
+	}
+	return timeout, nil
+}
+
 func clear(t *Term, ctx callContext, args string) error {
 	if len(args) == 0 {
 		return errors.New("not enough arguments")
@@ -1612,6 +2064,7 @@ func clear(t *Term, ctx callContext, args string) error {
 	if err != nil {
 		return err
 	}
+	delete(t.bpLocExprs, bp.ID)
 	fmt.Fprintf(t.stdout, "%s cleared at %s\n", formatBreakpointName(bp, true), t.formatBreakpointLocation(bp))
 	return nil
 }
@@ -1623,7 +2076,16 @@ func clearAll(t *Term, ctx callContext, args string) error {
 	}
 
 	var locPCs map[uint64]struct{}
-	if args != "" {
+	var fnRegex *regexp.Regexp
+	if argv := config.Split2PartsBySpace(args); argv[0] == "-fn" {
+		if len(argv) < 2 {
+			return errors.New("not enough arguments to -fn")
+		}
+		fnRegex, err = compileFnRegexp(argv[1])
+		if err != nil {
+			return err
+		}
+	} else if args != "" {
 		locs, _, err := t.client.FindLocation(api.EvalScope{GoroutineID: -1, Frame: 0}, args, true, t.substitutePathRules())
 		if err != nil {
 			return err
@@ -1637,12 +2099,16 @@ func clearAll(t *Term, ctx callContext, args string) error {
 		}
 	}
 
+	cleared := 0
 	for _, bp := range breakPoints {
 		if locPCs != nil {
 			if _, ok := locPCs[bp.Addr]; !ok {
 				continue
 			}
 		}
+		if fnRegex != nil && (bp.FunctionName == "" || !fnRegex.MatchString(bp.FunctionName)) {
+			continue
+		}
 
 		if bp.ID < 0 {
 			continue
@@ -1651,9 +2117,15 @@ func clearAll(t *Term, ctx callContext, args string) error {
 		_, err := t.client.ClearBreakpoint(bp.ID)
 		if err != nil {
 			fmt.Fprintf(t.stdout, "Couldn't delete %s at %s: %s\n", formatBreakpointName(bp, false), t.formatBreakpointLocation(bp), err)
+			continue
 		}
+		delete(t.bpLocExprs, bp.ID)
+		cleared++
 		fmt.Fprintf(t.stdout, "%s cleared at %s\n", formatBreakpointName(bp, true), t.formatBreakpointLocation(bp))
 	}
+	if fnRegex != nil {
+		fmt.Fprintf(t.stdout, "%d breakpoint(s) cleared\n", cleared)
+	}
 	return nil
 }
 
@@ -1721,9 +2193,20 @@ func breakpoints(t *Term, ctx callContext, args string) error {
 			return bp.Name
 		}
 
+		slices.SortFunc(breakPoints, func(a, b *api.Breakpoint) int { return cmp.Compare(a.ID, b.ID) })
+
+		// Tracepoints set on a function also have return tracepoints, those are
+		// recreated by tracing the function by name.
+		traceReturnFns := make(map[string]bool)
+		for _, bp := range breakPoints {
+			if bp.TraceReturn && bp.FunctionName != "" {
+				traceReturnFns[bp.FunctionName] = true
+			}
+		}
+
 		for _, bp := range breakPoints {
 			// We don't need to store these breakpoints
-			if bp.ID < 0 {
+			if bp.ID < 0 || bp.TraceReturn {
 				continue
 			}
 			// Skip watchpoints as they can't be reliably restored
@@ -1731,40 +2214,43 @@ func breakpoints(t *Term, ctx callContext, args string) error {
 				continue
 			}
 
-			var err error
-			if bp.Tracepoint {
-				_, err = fmt.Fprintf(w, "trace %s %s:%d\n", aliaser(bp), bp.File, bp.Line)
-			} else {
-				_, err = fmt.Fprintf(w, "break %s %s:%d\n", aliaser(bp), bp.File, bp.Line)
+			loc := fmt.Sprintf("%s:%d", bp.File, bp.Line)
+			switch {
+			case t.bpLocExprs[bp.ID] != "":
+				loc = t.bpLocExprs[bp.ID]
+			case bp.ExprString != "":
+				loc = bp.ExprString
+			case bp.Tracepoint && traceReturnFns[bp.FunctionName]:
+				loc = bp.FunctionName
+			case bp.File == "":
+				loc = fmt.Sprintf("*%#x", bp.Addr)
 			}
-			if err != nil {
-				return fmt.Errorf("failed to write breakpoint to file %s:%d", bp.File, bp.Line)
+
+			cmdname := "break"
+			if bp.Tracepoint {
+				cmdname = "trace"
 			}
-			if len(bp.Cond) > 0 {
-				_, err = fmt.Fprintf(w, "condition %s %s\n", aliaser(bp), bp.Cond)
-				if err != nil {
-					return fmt.Errorf("failed to write condition to file %d:%s", bp.ID, bp.Cond)
-				}
+			if _, err := fmt.Fprintf(w, "%s %s %s\n", cmdname, aliaser(bp), loc); err != nil {
+				return fmt.Errorf("failed to write breakpoint to file %s", loc)
 			}
-			if bp.HitCond != "" {
-				if bp.HitCondPerG {
-					_, err = fmt.Fprintf(w, "condition -per-g-hitcount %s %s\n", aliaser(bp), bp.HitCond)
-				} else {
-					_, err = fmt.Fprintf(w, "condition -hitcount %s %s\n", aliaser(bp), bp.HitCond)
-				}
-				if err != nil {
-					return fmt.Errorf("failed to write hit condition to file %d:%s", bp.ID, bp.HitCond)
+
+			// Conditions, hit conditions and the commands executed when the
+			// breakpoint is hit are restored using the 'on' command.
+			bpattrs := *bp
+			bpattrs.VerboseDescr = nil
+			for _, attr := range formatBreakpointAttrs("", &bpattrs, false) {
+				if _, err := fmt.Fprintf(w, "on %s %s\n", aliaser(bp), attr); err != nil {
+					return fmt.Errorf("failed to write breakpoint attribute to file %d: %s", bp.ID, attr)
 				}
 			}
 			if bp.Disabled {
-				_, err = fmt.Fprintf(w, "toggle %s\n", aliaser(bp))
-				if err != nil {
+				if _, err := fmt.Fprintf(w, "toggle %s\n", aliaser(bp)); err != nil {
 					return fmt.Errorf("failed to write breakpoint status to file %d", bp.ID)
 				}
 			}
 		}
 
-		fmt.Printf("Breakpoints successfully saved to '%s'\n", saveFile)
+		fmt.Fprintf(t.stdout, "Breakpoints successfully saved to '%s'\n", saveFile)
 		return nil
 	}
 
@@ -1805,6 +2291,9 @@ func formatBreakpointAttrs(prefix string, bp *api.Breakpoint, includeTrace bool)
 			attrs = append(attrs, fmt.Sprintf("%scond -hitcount %s", prefix, bp.HitCond))
 		}
 	}
+	for _, k := range slices.Sorted(maps.Keys(bp.GoroutineLabels)) {
+		attrs = append(attrs, fmt.Sprintf("%scond -label %s=%s", prefix, k, bp.GoroutineLabels[k]))
+	}
 	if bp.Stacktrace > 0 {
 		attrs = append(attrs, fmt.Sprintf("%sstack %d", prefix, bp.Stacktrace))
 	}
@@ -1840,22 +2329,85 @@ func formatBreakpointAttrs(prefix string, bp *api.Breakpoint, includeTrace bool)
 	return attrs
 }
 
-func setBreakpoint(t *Term, ctx callContext, tracepoint bool, argstr string) ([]*api.Breakpoint, error) {
-	var (
-		spec string
+var (
+	breakHitCountRegex = regexp.MustCompile(`^-(hitcount|per-g-hitcount)\s+((?:[=><%!]+\s*)?\d+)(?:\s+|$)`)
+	breakLabelRegex    = regexp.MustCompile(`(?:^|\s+)-label\s+(\S+)`)
+	breakCondRegex     = regexp.MustCompile(`^if | if `)
+)
 
-		requestedBp = &api.Breakpoint{}
-	)
+// parseLabelSelector parses a goroutine label selector in the form key=value.
+func parseLabelSelector(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("wrong label selector %q, expected <key>=<value>", s)
+	}
+	return key, value, nil
+}
 
-	parseSpec := func(args []string) error {
-		switch len(args) {
-		case 1:
-			if len(args[0]) != 0 {
-				spec = argstr
-			} else {
-				// no arg specified
-				spec = "+0"
-			}
+// extractBreakLabels removes all -label options appearing before the
+// condition of a break command and returns them.
+func extractBreakLabels(argstr string) (string, map[string]string, error) {
+	end := len(argstr)
+	if match := breakCondRegex.FindStringIndex(argstr); match != nil {
+		end = match[0]
+	}
+	var labels map[string]string
+	var err error
+	rest := breakLabelRegex.ReplaceAllStringFunc(argstr[:end], func(opt string) string {
+		k, v, err1 := parseLabelSelector(breakLabelRegex.FindStringSubmatch(opt)[1])
+		if err1 != nil {
+			err = err1
+			return opt
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[k] = v
+		return ""
+	})
+	if labels == nil {
+		return argstr, nil, err
+	}
+	return strings.TrimSpace(rest) + argstr[end:], labels, err
+}
+
+// rememberBreakpointLocExpr records the location expression typed by the
+// user to create bp, so that 'breakpoints -save' can write it back instead
+// of the resolved (and path-substituted) location. Expressions relative to
+// the current position are not recorded since they would resolve to a
+// different location when the saved file is sourced.
+func (t *Term) rememberBreakpointLocExpr(bp *api.Breakpoint, spec string) {
+	loc, err := locspec.Parse(spec)
+	if err != nil {
+		return
+	}
+	switch loc := loc.(type) {
+	case *locspec.OffsetLocationSpec, *locspec.LineLocationSpec, *locspec.RegexLocationSpec:
+		return
+	case *locspec.NormalLocationSpec:
+		if loc.LineEnd > 0 {
+			return
+		}
+	}
+	t.bpLocExprs[bp.ID] = spec
+}
+
+func setBreakpoint(t *Term, ctx callContext, tracepoint bool, argstr string) ([]*api.Breakpoint, error) {
+	var (
+		spec string
+
+		requestedBp = &api.Breakpoint{}
+	)
+
+	parseSpec := func(args []string) error {
+		switch len(args) {
+		case 1:
+			if len(args[0]) != 0 {
+				spec = argstr
+			} else {
+				// no arg specified
+				spec = "+0"
+			}
 		case 2:
 			if api.ValidBreakpointName(args[0]) == nil {
 				requestedBp.Name = args[0]
@@ -1869,16 +2421,46 @@ func setBreakpoint(t *Term, ctx callContext, tracepoint bool, argstr string) ([]
 		return nil
 	}
 
+	for {
+		if match := breakHitCountRegex.FindStringSubmatch(argstr); match != nil {
+			requestedBp.HitCond = match[2]
+			requestedBp.HitCondPerG = match[1] == "per-g-hitcount"
+			argstr = argstr[len(match[0]):]
+			continue
+		}
+		if rest, ok := strings.CutPrefix(argstr, "-trace"); ok && (rest == "" || rest[0] == ' ') {
+			tracepoint = true
+			argstr = strings.TrimLeft(rest, " ")
+			continue
+		}
+		if rest, ok := strings.CutPrefix(argstr, "-eval "); ok {
+			exprs, rest, err := parseEvalList(strings.TrimLeft(rest, " "))
+			if err != nil {
+				return nil, err
+			}
+			requestedBp.Variables = append(requestedBp.Variables, exprs...)
+			argstr = strings.TrimLeft(rest, " ")
+			continue
+		}
+		break
+	}
+
+	var err error
+	argstr, requestedBp.GoroutineLabels, err = extractBreakLabels(argstr)
+	if err != nil {
+		return nil, err
+	}
+
 	args := config.Split2PartsBySpace(argstr)
 	if err := parseSpec(args); err != nil {
 		return nil, err
 	}
 
 	requestedBp.Tracepoint = tracepoint
+	requestedBp.TraceEval = tracepoint && len(requestedBp.Variables) > 0
 	locs, substSpec, findLocErr := t.client.FindLocation(ctx.Scope, spec, true, t.substitutePathRules())
 	if findLocErr != nil {
-		r := regexp.MustCompile(`^if | if `)
-		if match := r.FindStringIndex(argstr); match != nil {
+		if match := breakCondRegex.FindStringIndex(argstr); match != nil {
 			requestedBp.Name = ""
 			requestedBp.Cond = argstr[match[1]:]
 			argstr = argstr[:match[0]]
@@ -1902,7 +2484,7 @@ func setBreakpoint(t *Term, ctx callContext, tracepoint bool, argstr string) ([]
 	}
 
 	fns, _ := t.client.ListFunctions(`^plugin\.Open$`, 0)
-	_, err := t.client.GetState()
+	_, err = t.client.GetState()
 	shouldAskToSuspendBreakpointQuestion := ""
 	switch {
 	case len(fns) > 0:
@@ -1927,6 +2509,7 @@ func setBreakpoint(t *Term, ctx callContext, tracepoint bool, argstr string) ([]
 		if err != nil {
 			return nil, err
 		}
+		t.rememberBreakpointLocExpr(bp, spec)
 		fmt.Fprintf(t.stdout, "%s set at %s\n", formatBreakpointName(bp, true), t.formatBreakpointLocation(bp))
 		return nil, nil
 	}
@@ -1934,41 +2517,61 @@ func setBreakpoint(t *Term, ctx callContext, tracepoint bool, argstr string) ([]
 	if findLocErr != nil {
 		return nil, findLocErr
 	}
+	origSpec := spec
 	if substSpec != "" {
 		spec = substSpec
 	}
 
+	loc, err := locspec.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	isLineRange := false
+	if nloc, ok := loc.(*locspec.NormalLocationSpec); ok && nloc.LineEnd > 0 {
+		isLineRange = true
+	}
+
 	created := []*api.Breakpoint{}
 	for _, loc := range locs {
 		requestedBp.Addr = loc.PC
 		requestedBp.Addrs = loc.PCs
 		requestedBp.AddrPid = loc.PCPids
 		if tracepoint {
-			requestedBp.LoadArgs = &ShortLoadConfig
+			if len(requestedBp.Variables) == 0 {
+				requestedBp.LoadArgs = &ShortLoadConfig
+			}
 			t.TraceVerbosity = 0 // Default verbosity for terminal traces
 		}
 
-		bp, err := t.client.CreateBreakpointWithExpr(requestedBp, spec, t.substitutePathRules(), false)
+		expr := spec
+		if isLineRange {
+			// each breakpoint of a line range is only associated with its own line
+			expr = fmt.Sprintf("%s:%d", loc.File, loc.Line)
+		}
+
+		bp, err := t.client.CreateBreakpointWithExpr(requestedBp, expr, t.substitutePathRules(), false)
 		if err != nil {
 			return nil, err
 		}
 		created = append(created, bp)
+		if len(locs) == 1 && !isLineRange {
+			t.rememberBreakpointLocExpr(bp, origSpec)
+		}
 
 		fmt.Fprintf(t.stdout, "%s set at %s\n", formatBreakpointName(bp, true), t.formatBreakpointLocation(bp))
 	}
+	if isLineRange {
+		fmt.Fprintf(t.stdout, "%d breakpoints set\n", len(created))
+	}
 
 	var shouldSetReturnBreakpoints bool
-	loc, err := locspec.Parse(spec)
-	if err != nil {
-		return nil, err
-	}
 	switch t := loc.(type) {
 	case *locspec.NormalLocationSpec:
 		shouldSetReturnBreakpoints = t.LineOffset == -1 && t.FuncBase != nil
 	case *locspec.RegexLocationSpec:
 		shouldSetReturnBreakpoints = true
 	}
-	if tracepoint && shouldSetReturnBreakpoints {
+	if tracepoint && shouldSetReturnBreakpoints && len(requestedBp.Variables) == 0 {
 		for i := range locs {
 			if locs[i].Function == nil {
 				continue
@@ -1993,6 +2596,64 @@ func setBreakpoint(t *Term, ctx callContext, tracepoint bool, argstr string) ([]
 	return created, nil
 }
 
+// parseEvalList parses the argument of the -eval option of break, a comma
+// separated list of expressions, optionally quoted. Returns the list of
+// expressions and the rest of the input.
+func parseEvalList(in string) (exprs []string, rest string, err error) {
+	var list string
+	switch {
+	case in == "":
+		return nil, "", errors.New("expected expression list after -eval")
+	case in[0] == '"':
+		quoted, err := strconv.QuotedPrefix(in)
+		if err != nil {
+			return nil, "", fmt.Errorf("malformed expression list after -eval: %v", err)
+		}
+		list, _ = strconv.Unquote(quoted)
+		rest = in[len(quoted):]
+	case in[0] == '\'':
+		end := strings.IndexByte(in[1:], '\'')
+		if end < 0 {
+			return nil, "", errors.New("malformed expression list after -eval: unterminated quote")
+		}
+		list, rest = in[1:end+1], in[end+2:]
+	default:
+		list, rest, _ = strings.Cut(in, " ")
+	}
+
+	// split at commas that are not nested inside parenthesis, brackets or
+	// string literals.
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(list); i++ {
+		ch := list[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote != '`' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'' || ch == '`':
+			quote = ch
+		case ch == '(' || ch == '[' || ch == '{':
+			depth++
+		case ch == ')' || ch == ']' || ch == '}':
+			depth--
+		case ch == ',' && depth == 0:
+			exprs = append(exprs, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+	exprs = append(exprs, strings.TrimSpace(list[start:]))
+	for _, expr := range exprs {
+		if expr == "" {
+			return nil, "", fmt.Errorf("malformed expression list after -eval: %q", list)
+		}
+	}
+	return exprs, rest, nil
+}
+
 func breakpoint(t *Term, ctx callContext, args string) error {
 	_, err := setBreakpoint(t, ctx, false, args)
 	return err
@@ -2066,9 +2727,10 @@ func edit(t *Term, ctx callContext, args string) error {
 }
 
 func watchpoint(t *Term, ctx callContext, args string) error {
+	const usage = "watch [-r|-w|-rw] [-scope exit|follow] <expr> [if <condition>]"
 	v := strings.SplitN(args, " ", 2)
 	if len(v) != 2 {
-		return errors.New("wrong number of arguments: watch [-r|-w|-rw] <expr>")
+		return errors.New("wrong number of arguments: " + usage)
 	}
 	var wtype api.WatchType
 	switch v[0] {
@@ -2081,14 +2743,82 @@ func watchpoint(t *Term, ctx callContext, args string) error {
 	default:
 		return fmt.Errorf("wrong argument %q to watch", v[0])
 	}
-	bp, err := t.client.CreateWatchpoint(ctx.Scope, v[1], wtype)
+	expr, cond := v[1], ""
+	follow := false
+	if fieldArgs, ok := strings.CutPrefix(expr, "-field "); ok {
+		return fieldWatchpoint(t, strings.TrimSpace(fieldArgs), wtype)
+	}
+	if scopeArgs, ok := strings.CutPrefix(expr, "-scope "); ok {
+		var scope string
+		scope, expr, ok = strings.Cut(strings.TrimSpace(scopeArgs), " ")
+		if !ok {
+			return errors.New("wrong number of arguments: " + usage)
+		}
+		switch scope {
+		case "exit":
+			// default
+		case "follow":
+			follow = true
+		default:
+			return fmt.Errorf("wrong argument %q to -scope", scope)
+		}
+	}
+	if idx := strings.Index(expr, " if "); idx >= 0 {
+		expr, cond = expr[:idx], expr[idx+len(" if "):]
+	}
+	bp, err := t.client.CreateWatchpoint(ctx.Scope, expr, wtype)
 	if err != nil {
 		return err
 	}
+	if cond != "" || follow {
+		bp.Cond = cond
+		bp.WatchFollow = follow
+		if err := t.client.AmendBreakpoint(bp); err != nil {
+			t.client.ClearBreakpoint(bp.ID)
+			return err
+		}
+	}
 	fmt.Fprintf(t.stdout, "%s set at %s\n", formatBreakpointName(bp, true), t.formatBreakpointLocation(bp))
 	return nil
 }
 
+func fieldWatchpoint(t *Term, args string, wtype api.WatchType) error {
+	const usage = "watch [-r|-w|-rw] -field [-max <n>] <type>.<field> [if <condition>]"
+	max := 0
+	if maxArgs, ok := strings.CutPrefix(args, "-max "); ok {
+		var n string
+		n, args, ok = strings.Cut(strings.TrimSpace(maxArgs), " ")
+		if !ok {
+			return errors.New("wrong number of arguments: " + usage)
+		}
+		var err error
+		max, err = strconv.Atoi(n)
+		if err != nil || max < 0 {
+			return fmt.Errorf("wrong argument %q to -max", n)
+		}
+	}
+	typeField, cond := strings.TrimSpace(args), ""
+	if idx := strings.Index(typeField, " if "); idx >= 0 {
+		typeField, cond = typeField[:idx], typeField[idx+len(" if "):]
+	}
+	if typeField == "" {
+		return errors.New("wrong number of arguments: " + usage)
+	}
+	bp, err := t.client.CreateFieldWatchpoint(typeField, wtype, max)
+	if err != nil {
+		return err
+	}
+	if cond != "" {
+		bp.Cond = cond
+		if err := t.client.AmendBreakpoint(bp); err != nil {
+			t.client.ClearBreakpoint(bp.ID)
+			return err
+		}
+	}
+	fmt.Fprintf(t.stdout, "%s set on new instances of %s\n", formatBreakpointName(bp, true), typeField[:strings.LastIndex(typeField, ".")])
+	return nil
+}
+
 func examineMemoryCmd(t *Term, ctx callContext, argstr string) error {
 	args, err := api.ParseExamineMemoryArg(argstr)
 	if err != nil {
@@ -2126,6 +2856,10 @@ func examineMemoryCmd(t *Term, ctx callContext, argstr string) error {
 
 	t.stdout.pw.PageMaybe(nil)
 
+	if args.Format == 'i' {
+		return examineInstructions(t, ctx, address, int(args.Count))
+	}
+
 	start := address
 	remsz := int(args.Count * args.Size)
 
@@ -2146,15 +2880,232 @@ func examineMemoryCmd(t *Term, ctx callContext, argstr string) error {
 	return nil
 }
 
-func parseFormatArg(args string) (fmtstr, argsOut string) {
+// maxInstructionLen is the maximum length of a machine instruction on any of
+// the supported architectures.
+const maxInstructionLen = 15
+
+// examineInstructions prints count instructions starting at address.
+func examineInstructions(t *Term, ctx callContext, address uint64, count int) error {
+	disasm, err := t.client.DisassembleRange(ctx.Scope, address, address+uint64(count*maxInstructionLen), t.conf.GetDisassembleFlavour())
+	if err != nil {
+		return err
+	}
+	if len(disasm) > count {
+		disasm = disasm[:count]
+	}
+	disasmPrint(disasm, t.stdout, false)
+	return nil
+}
+
+type memstatsUnit uint8
+
+const (
+	memstatsCount memstatsUnit = iota
+	memstatsBytes
+	memstatsDuration
+	memstatsTime
+)
+
+// memstatsSource is an expression that evaluates to a field of
+// runtime.MemStats.
+type memstatsSource struct {
+	expr   string
+	approx bool // the value of expr is only an approximation of the field
+}
+
+// memstatsFields lists the fields of runtime.MemStats printed by the
+// memstats command and, for each of them, the expressions that can be used
+// to read them from different versions of the runtime, in order of
+// preference.
+var memstatsFields = []struct {
+	name    string
+	unit    memstatsUnit
+	sources []memstatsSource
+}{
+	{"HeapAlloc", memstatsBytes, []memstatsSource{{"runtime.memstats.heap_alloc", false}, {"runtime.gcController.heapLive", true}}},
+	{"HeapInuse", memstatsBytes, []memstatsSource{{"runtime.memstats.heap_inuse", false}, {"runtime.memstats.heapInUse", false}, {"runtime.gcController.heapInUse", false}}},
+	{"HeapReleased", memstatsBytes, []memstatsSource{{"runtime.memstats.heap_released", false}, {"runtime.memstats.heapReleased", false}, {"runtime.gcController.heapReleased", false}}},
+	{"NumGC", memstatsCount, []memstatsSource{{"runtime.memstats.numgc", false}}},
+	{"NumForcedGC", memstatsCount, []memstatsSource{{"runtime.memstats.numforcedgc", false}}},
+	{"PauseTotalNs", memstatsDuration, []memstatsSource{{"runtime.memstats.pause_total_ns", false}}},
+	{"LastGC", memstatsTime, []memstatsSource{{"runtime.memstats.last_gc_unix", false}}},
+}
+
+const searchMemoryDefaultMax = 100
+
+func searchMemoryCmd(t *Term, ctx callContext, argstr string) error {
+	align, max := 0, searchMemoryDefaultMax
+	args := strings.Fields(argstr)
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if len(args) < 2 {
+			return fmt.Errorf("expected number after %s", args[0])
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 {
+			return fmt.Errorf("expected number after %s", args[0])
+		}
+		switch args[0] {
+		case "-align":
+			align = n
+		case "-max":
+			max = n
+		default:
+			return fmt.Errorf("unknown option %s", args[0])
+		}
+		args = args[2:]
+	}
+	if len(args) == 0 {
+		return errors.New("not enough arguments")
+	}
+	expr := strings.Join(args, " ")
+
+	results, err := t.client.SearchMemory(ctx.Scope, expr, align, max)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(t.stdout, "not found")
+		return nil
+	}
+	for _, res := range results {
+		switch {
+		case res.Symbol != "" && res.Type != "":
+			fmt.Fprintf(t.stdout, "%#x in %s+%#x (%s)\n", res.Addr, res.Symbol, res.Addr-res.SymbolAddr, res.Type)
+		case res.Symbol != "":
+			fmt.Fprintf(t.stdout, "%#x in %s+%#x\n", res.Addr, res.Symbol, res.Addr-res.SymbolAddr)
+		case res.GoroutineID != 0:
+			fmt.Fprintf(t.stdout, "%#x in the stack of goroutine %d\n", res.Addr, res.GoroutineID)
+		default:
+			fmt.Fprintf(t.stdout, "%#x\n", res.Addr)
+		}
+	}
+	if max > 0 && len(results) >= max {
+		fmt.Fprintf(t.stdout, "(showing the first %d results, use -max to see more)\n", max)
+	}
+	return nil
+}
+
+func mappingsCmd(t *Term, ctx callContext, args string) error {
+	if args != "" {
+		return errors.New("too many arguments")
+	}
+	mappings, err := t.client.ListMemoryMappings()
+	if err != nil {
+		return err
+	}
+	w := new(tabwriter.Writer)
+	w.Init(t.stdout, 0, 8, 2, ' ', 0)
+	for _, m := range mappings {
+		fmt.Fprintf(w, "%#x-%#x\t%s\t%#x\t%s\n", m.Start, m.End, m.Perms, m.Offset, m.Name)
+	}
+	return w.Flush()
+}
+
+func memstatsCmd(t *Term, ctx callContext, args string) error {
+	if args != "" {
+		return errors.New("too many arguments")
+	}
+	cfg := api.LoadConfig{MaxVariableRecurse: 1, MaxStructFields: -1}
+	w := new(tabwriter.Writer)
+	w.Init(t.stdout, 0, 8, 2, ' ', 0)
+	found := false
+	var firstErr error
+	for _, field := range memstatsFields {
+		val, approx := "", false
+		for _, src := range field.sources {
+			v, err := t.client.EvalVariable(api.EvalScope{GoroutineID: -1}, src.expr, cfg)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			n, ok := memstatsValue(v)
+			if !ok {
+				continue
+			}
+			val, approx = formatMemstatsValue(n, field.unit), src.approx
+			break
+		}
+		switch {
+		case val == "":
+			val = "unavailable"
+		case approx:
+			found = true
+			val = "~" + val
+		default:
+			found = true
+		}
+		fmt.Fprintf(w, "%s\t%s\n", field.name, val)
+	}
+	if !found {
+		return fmt.Errorf("could not read runtime memory statistics: %v", firstErr)
+	}
+	return w.Flush()
+}
+
+// memstatsValue returns the value of v, which must be an unsigned integer or
+// a struct wrapping one, like the types of package runtime/internal/atomic.
+func memstatsValue(v *api.Variable) (uint64, bool) {
+	for v.Kind == reflect.Struct {
+		var inner *api.Variable
+		for i := range v.Children {
+			if v.Children[i].Name == "v" || v.Children[i].Name == "value" {
+				inner = &v.Children[i]
+				break
+			}
+		}
+		if inner == nil {
+			return 0, false
+		}
+		v = inner
+	}
+	switch v.Kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Int, reflect.Int64:
+		n, err := strconv.ParseUint(v.Value, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func formatMemstatsValue(n uint64, unit memstatsUnit) string {
+	switch unit {
+	case memstatsBytes:
+		const unitSize = 1024
+		if n < unitSize {
+			return fmt.Sprintf("%d", n)
+		}
+		div, exp := uint64(unitSize), 0
+		for m := n / unitSize; m >= unitSize; m /= unitSize {
+			div *= unitSize
+			exp++
+		}
+		return fmt.Sprintf("%d (%.1f %ciB)", n, float64(n)/float64(div), "KMGTPE"[exp])
+	case memstatsDuration:
+		return fmt.Sprintf("%d (%v)", n, time.Duration(n))
+	case memstatsTime:
+		if n == 0 {
+			return "0 (never)"
+		}
+		return fmt.Sprintf("%d (%s)", n, time.Unix(0, int64(n)).UTC().Format(time.RFC3339Nano))
+	}
+	return strconv.FormatUint(n, 10)
+}
+
+func parseFormatArg(args string) (fmtstr, argsOut string, err error) {
+	if rest, ok := strings.CutPrefix(args, "-fmt "); ok {
+		name, rest, _ := strings.Cut(strings.TrimSpace(rest), " ")
+		fmtstr, err := api.ParsePrintFormat(name)
+		return fmtstr, rest, err
+	}
 	if len(args) < 1 || args[0] != '%' {
-		return "", args
+		return "", args, nil
 	}
 	v := strings.SplitN(args, " ", 2)
 	if len(v) == 1 {
-		return v[0], ""
+		return v[0], "", nil
 	}
-	return v[0], v[1]
+	return v[0], v[1], nil
 }
 
 const maxPrintVarChanGoroutines = 100
@@ -2167,7 +3118,10 @@ func (c *Commands) printVar(t *Term, ctx callContext, args string) error {
 		ctx.Breakpoint.Variables = append(ctx.Breakpoint.Variables, args)
 		return nil
 	}
-	fmtstr, args := parseFormatArg(args)
+	fmtstr, args, err := parseFormatArg(args)
+	if err != nil {
+		return err
+	}
 	val, err := t.client.EvalVariable(ctx.Scope, args, t.loadConfig())
 	if err != nil {
 		return err
@@ -2175,7 +3129,7 @@ func (c *Commands) printVar(t *Term, ctx callContext, args string) error {
 
 	t.stdout.pw.PageMaybe(nil)
 
-	fmt.Fprintln(t.stdout, val.StringWithOptions("", fmtstr, api.PrettyNewlines))
+	fmt.Fprintln(t.stdout, val.StringWithOptions("", fmtstr, api.PrettyNewlines|api.PrettyBytesPreview))
 
 	if val.Kind == reflect.Chan {
 		fmt.Fprintln(t.stdout)
@@ -2195,16 +3149,160 @@ func (c *Commands) printVar(t *Term, ctx callContext, args string) error {
 	return nil
 }
 
+// errWrapperFields maps the dynamic type of standard library errors that
+// wrap other errors to the name of the field containing the wrapped
+// error(s), so that the chain can be unwrapped without call injection.
+var errWrapperFields = map[string]string{
+	"*fmt.wrapError":                "err",
+	"*fmt.wrapErrors":               "errs",
+	"*errors.joinError":             "errs",
+	"*io/fs.PathError":              "Err",
+	"*os.LinkError":                 "Err",
+	"*os.SyscallError":              "Err",
+	"*os/exec.Error":                "Err",
+	"*net.OpError":                  "Err",
+	"*net.DNSConfigError":           "Err",
+	"*net/url.Error":                "Err",
+	"*strconv.NumError":             "Err",
+	"context.deadlineExceededError": "",
+	"*errors.errorString":           "",
+	"internal/poll.errNetClosing":   "",
+}
+
+// errChainLoadConfig is the load configuration used to print each error
+// in the chain, strings are not truncated.
+var errChainLoadConfig = api.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 1 << 16, MaxArrayValues: 64, MaxStructFields: -1}
+
+const maxErrChainDepth = 100
+
+// errLayer is an error in an error chain.
+type errLayer struct {
+	typ  string // dynamic type of the error
+	expr string // expression evaluating to the concrete value of the error
+	v    *api.Variable
+}
+
+func errorsCmd(t *Term, ctx callContext, args string) error {
+	useCall := false
+	if rest, ok := strings.CutPrefix(args, "-call"); ok && (rest == "" || rest[0] == ' ') {
+		useCall = true
+		args = strings.TrimSpace(rest)
+	}
+	if args == "" {
+		return errors.New("not enough arguments")
+	}
+	v, err := t.client.EvalVariable(ctx.Scope, args, errChainLoadConfig)
+	if err != nil {
+		return err
+	}
+	if v.Kind != reflect.Interface {
+		return fmt.Errorf("%s is not an interface", args)
+	}
+	layer, ok := errLayerFromInterface(v)
+	if !ok {
+		fmt.Fprintf(t.stdout, "%s nil\n", v.Type)
+		return nil
+	}
+	return printErrChain(t, ctx, layer, useCall, "", 0)
+}
+
+// errLayerFromInterface returns the error contained in the interface
+// variable v, ok is false if v is nil.
+func errLayerFromInterface(v *api.Variable) (layer errLayer, ok bool) {
+	if v.Kind != reflect.Interface || len(v.Children) == 0 || v.Children[0].Kind == reflect.Invalid {
+		return errLayer{}, false
+	}
+	c := &v.Children[0]
+	layer = errLayer{typ: c.Type, v: c}
+	switch {
+	case c.Kind == reflect.Ptr && len(c.Children) > 0 && c.Children[0].Addr != 0:
+		layer.expr = fmt.Sprintf("(*%q)(%#x)", c.Children[0].Type, c.Children[0].Addr)
+	case c.Kind != reflect.Ptr && c.Addr != 0 && c.Flags&api.VariableFakeAddress == 0:
+		layer.expr = fmt.Sprintf("*(*%q)(%#x)", c.Type, c.Addr)
+	}
+	return layer, true
+}
+
+func printErrChain(t *Term, ctx callContext, layer errLayer, useCall bool, indent string, depth int) error {
+	str := layer.v.SinglelineString()
+	if !strings.HasPrefix(str, layer.typ) {
+		str = layer.typ + " " + str
+	}
+	fmt.Fprintf(t.stdout, "%s%s\n", indent, str)
+	if layer.expr == "" {
+		return nil
+	}
+	if depth >= maxErrChainDepth {
+		fmt.Fprintf(t.stdout, "%s  ...\n", indent)
+		return nil
+	}
+
+	var next *api.Variable
+	if field, known := errWrapperFields[layer.typ]; known {
+		if field == "" {
+			return nil
+		}
+		var err error
+		next, err = t.client.EvalVariable(ctx.Scope, layer.expr+"."+field, errChainLoadConfig)
+		if err != nil {
+			return err
+		}
+	} else if useCall {
+		state, err := t.client.Call(ctx.Scope.GoroutineID, layer.expr+".Unwrap()", false)
+		if err != nil {
+			// the error does not wrap other errors
+			return nil
+		}
+		if state.CurrentThread == nil || len(state.CurrentThread.ReturnValues) != 1 {
+			return nil
+		}
+		next = &state.CurrentThread.ReturnValues[0]
+	} else {
+		return nil
+	}
+
+	var children []api.Variable
+	switch next.Kind {
+	case reflect.Interface:
+		children = []api.Variable{*next}
+	case reflect.Slice, reflect.Array:
+		children = next.Children
+	}
+	for i := range children {
+		layer, ok := errLayerFromInterface(&children[i])
+		if !ok {
+			continue
+		}
+		if err := printErrChain(t, ctx, layer, useCall, indent+"  ", depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func whatisCommand(t *Term, ctx callContext, args string) error {
+	verbose := false
+	if rest, ok := strings.CutPrefix(args, "-verbose"); ok && (rest == "" || rest[0] == ' ') {
+		verbose = true
+		args = strings.TrimSpace(rest)
+	}
 	if len(args) == 0 {
 		return errors.New("not enough arguments")
 	}
 	val, err := t.client.EvalVariable(ctx.Scope, args, ShortLoadConfig)
 	if err != nil {
-		info, err2 := t.client.TypeInfo(args)
+		typeInfo := t.client.TypeInfo
+		if verbose {
+			typeInfo = t.client.TypeInfoTree
+		}
+		info, err2 := typeInfo(args)
 		if err2 != nil {
 			return err
 		}
+		if info.Tree != nil {
+			printTypeTree(t.stdout, info.Tree, "")
+			return nil
+		}
 		fmt.Fprintf(t.stdout, "%s type, size: %d bytes\n", info.Kind.String(), info.Size)
 		if info.RealType != "" {
 			fmt.Fprintf(t.stdout, "Real type: %s\n", info.RealType)
@@ -2242,9 +3340,40 @@ func whatisCommand(t *Term, ctx callContext, args string) error {
 	if t.conf.ShowLocationExpr && val.LocationExpr != "" {
 		fmt.Fprintf(t.stdout, "location: %s\n", val.LocationExpr)
 	}
+	if verbose && val.Type != "" {
+		info, err := t.client.TypeInfoTree(val.Type)
+		if err != nil {
+			return err
+		}
+		if info.Tree != nil {
+			printTypeTree(t.stdout, info.Tree, "")
+		}
+	}
 	return nil
 }
 
+func printTypeTree(out io.Writer, node *api.TypeTreeNode, indent string) {
+	fmt.Fprint(out, indent)
+	if node.Name != "" {
+		fmt.Fprintf(out, "%s ", node.Name)
+	}
+	fmt.Fprint(out, node.Type)
+	if node.Kind != reflect.Invalid {
+		fmt.Fprintf(out, " (%s)", node.Kind)
+	}
+	if node.Name != "" {
+		fmt.Fprintf(out, " offset=%d", node.Offset)
+	}
+	fmt.Fprintf(out, " size=%d", node.Size)
+	if node.Embedded {
+		fmt.Fprint(out, " embedded")
+	}
+	fmt.Fprintln(out)
+	for i := range node.Children {
+		printTypeTree(out, &node.Children[i], indent+"  ")
+	}
+}
+
 func setVar(t *Term, ctx callContext, args string) error {
 	// HACK: in go '=' is not an operator, we detect the error and try to recover from it by splitting the input string
 	_, err := parser.ParseExpr(args)
@@ -2306,9 +3435,35 @@ func (t *Term) printSortedStrings(v []string, err error) error {
 }
 
 func sources(t *Term, ctx callContext, args string) error {
+	if v := config.Split2PartsBySpace(args); len(v) >= 1 && v[0] == "-pkg" {
+		filter := ""
+		if len(v) > 1 {
+			filter = v[1]
+		}
+		return sourcesByPackage(t, filter)
+	}
 	return t.printSortedStrings(t.client.ListSources(args))
 }
 
+func sourcesByPackage(t *Term, filter string) error {
+	pkgs, err := t.client.ListPackagesBuildInfo(filter, true)
+	if err != nil {
+		return err
+	}
+	done := false
+	t.stdout.pw.PageMaybe(func() { done = true })
+	for _, pkg := range pkgs {
+		if done {
+			break
+		}
+		fmt.Fprintln(t.stdout, pkg.ImportPath)
+		for _, file := range pkg.Files {
+			fmt.Fprintf(t.stdout, "\t%s\n", file)
+		}
+	}
+	return nil
+}
+
 func packages(t *Term, ctx callContext, args string) error {
 	info, err := t.client.ListPackagesBuildInfo(args, false)
 	if err != nil {
@@ -2365,10 +3520,24 @@ func locals(t *Term, ctx callContext, args string) error {
 		ctx.Breakpoint.LoadLocals = &cfg
 		return nil
 	}
-	locals, err := t.client.ListLocalVariables(ctx.Scope, cfg)
+	if filter != "" {
+		locals, err := t.client.ListLocalVariables(ctx.Scope, cfg)
+		if err != nil {
+			return err
+		}
+		return t.printFilteredVariables("locals", locals, filter, cfg)
+	}
+	locals, targs, err := t.client.ListLocalVariablesWithTypeArguments(ctx.Scope, cfg, t.substitutePathRules())
 	if err != nil {
 		return err
 	}
+	for _, targ := range targs {
+		typ := targ.Type
+		if typ == "" {
+			typ = "(unknown)"
+		}
+		fmt.Fprintf(t.stdout, "type %s = %s\n", targ.Name, typ)
+	}
 	return t.printFilteredVariables("locals", locals, filter, cfg)
 }
 
@@ -2383,7 +3552,23 @@ func vars(t *Term, ctx callContext, args string) error {
 
 func regs(t *Term, ctx callContext, args string) error {
 	includeFp := false
-	if args == "-a" {
+	follow := false
+	var names []string
+	for _, arg := range strings.Fields(args) {
+		switch arg {
+		case "-a":
+			includeFp = true
+		case "-f", "-follow":
+			follow = true
+		default:
+			names = append(names, arg)
+		}
+	}
+	if follow && len(names) == 0 {
+		return errors.New("not enough arguments")
+	}
+	if len(names) > 0 {
+		// floating point registers can only be selected if they are loaded
 		includeFp = true
 	}
 	var regs api.Registers
@@ -2396,7 +3581,152 @@ func regs(t *Term, ctx callContext, args string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Fprintln(t.stdout, regs)
+	if len(names) == 0 {
+		fmt.Fprintln(t.stdout, regs)
+		return nil
+	}
+	var selected api.Registers
+	for _, name := range names {
+		reg, err := findRegister(t, ctx, regs, name)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, reg)
+	}
+	fmt.Fprintln(t.stdout, selected)
+	if follow {
+		for _, name := range names {
+			fmtstr := "%#x"
+			if v, err := t.client.EvalVariable(ctx.Scope, strings.ToUpper(name), ShortLoadConfig); err == nil && v.Kind == reflect.String {
+				// registers larger than 64bits are evaluated as strings of
+				// hexadecimal digits
+				fmtstr = ""
+			}
+			t.addDisplay(strings.ToUpper(name), fmtstr, false)
+		}
+	}
+	return nil
+}
+
+// findRegister returns the register called name. Names are matched case
+// insensitively against the names returned by the target, if that fails
+// the name is evaluated as an expression so that architecture specific
+// aliases (for example PC and SP) can be used.
+func findRegister(t *Term, ctx callContext, regs api.Registers, name string) (api.Register, error) {
+	for _, reg := range regs {
+		if strings.EqualFold(reg.Name, name) {
+			return reg, nil
+		}
+	}
+	v, err := t.client.EvalVariable(ctx.Scope, strings.ToUpper(name), ShortLoadConfig)
+	if err != nil || v.Flags&api.VariableCPURegister == 0 {
+		return api.Register{}, fmt.Errorf("unknown register %q", name)
+	}
+	value := v.Value
+	if n, err := strconv.ParseUint(v.Value, 10, 64); err == nil {
+		value = fmt.Sprintf("%#016x", n)
+	}
+	return api.Register{Name: v.Name, Value: value, DwarfNumber: -1}, nil
+}
+
+func defersCommand(t *Term, ctx callContext, args string) error {
+	if args != "" {
+		return errors.New("too many arguments")
+	}
+	defers, err := t.client.ListGoroutineDefers(ctx.Scope.GoroutineID)
+	if err != nil {
+		return err
+	}
+	if len(defers) == 0 {
+		fmt.Fprintln(t.stdout, "No deferred calls")
+		return nil
+	}
+	printDefers(t, defers)
+	return nil
+}
+
+func printDefers(t *Term, defers []api.Defer) {
+	d := digits(len(defers) - 1)
+	s := strings.Repeat(" ", d+2)
+	for i := range defers {
+		if defers[i].Unreadable != "" {
+			fmt.Fprintf(t.stdout, "%*d  (unreadable defer: %s)\n", d, i, defers[i].Unreadable)
+			continue
+		}
+		fmt.Fprintf(t.stdout, "%*d  %#016x in %s\n", d, i, defers[i].DeferredLoc.PC, defers[i].DeferredLoc.Function.Name())
+		fmt.Fprintf(t.stdout, "%sat %s:%d\n", s, t.formatPath(defers[i].DeferredLoc.File), defers[i].DeferredLoc.Line)
+		openCoded := ""
+		if defers[i].OpenCoded {
+			openCoded = " (open-coded)"
+		}
+		fmt.Fprintf(t.stdout, "%sdeferred by %s%s at %s:%d\n", s, defers[i].DeferLoc.Function.Name(), openCoded, t.formatPath(defers[i].DeferLoc.File), defers[i].DeferLoc.Line)
+	}
+}
+
+func panicCommand(t *Term, ctx callContext, args string) error {
+	if args != "" {
+		return errors.New("too many arguments")
+	}
+	panics, err := t.client.ListGoroutinePanics(ctx.Scope.GoroutineID, t.loadConfig())
+	if err != nil {
+		return err
+	}
+	if len(panics) == 0 {
+		fmt.Fprintln(t.stdout, "No panics in progress")
+		return nil
+	}
+	d := digits(len(panics) - 1)
+	s := strings.Repeat(" ", d+2)
+	for i, p := range panics {
+		if p.Unreadable != "" {
+			fmt.Fprintf(t.stdout, "%*d  (unreadable panic: %s)\n", d, i, p.Unreadable)
+			continue
+		}
+		var status []string
+		if p.Recovered {
+			status = append(status, "recovered")
+		}
+		if p.Repanicked {
+			status = append(status, "repanicked")
+		}
+		statusStr := ""
+		if len(status) > 0 {
+			statusStr = " (" + strings.Join(status, ", ") + ")"
+		}
+		if p.Goexit {
+			fmt.Fprintf(t.stdout, "%*d  runtime.Goexit%s\n", d, i, statusStr)
+		} else {
+			val := "<nil>"
+			if p.Value != nil {
+				val = p.Value.SinglelineString()
+			}
+			fmt.Fprintf(t.stdout, "%*d  panic: %s%s\n", d, i, val, statusStr)
+		}
+		if p.Location == nil {
+			fmt.Fprintf(t.stdout, "%sno deferred calls run yet\n", s)
+		} else {
+			verb := "running deferred calls of"
+			if p.Recovered {
+				verb = "recovered by"
+			}
+			frame := ""
+			if p.Frame >= 0 {
+				frame = fmt.Sprintf("frame %d ", p.Frame)
+			}
+			fmt.Fprintf(t.stdout, "%s%s %s%s at %s:%d\n", s, verb, frame, p.Location.Function.Name(), t.formatPath(p.Location.File), p.Location.Line)
+		}
+	}
+
+	defers, err := t.client.ListGoroutineDefers(ctx.Scope.GoroutineID)
+	if err != nil {
+		return err
+	}
+	if len(defers) == 0 {
+		fmt.Fprintln(t.stdout, "No pending deferred calls")
+		return nil
+	}
+	fmt.Fprintln(t.stdout, "Pending deferred calls:")
+	printDefers(t, defers)
 	return nil
 }
 
@@ -2471,6 +3801,8 @@ func parseStackArgs(argstr string) (stackArgs, error) {
 				r.offsets = true
 			case "-defer":
 				r.opts |= api.StacktraceReadDefers
+			case "-fast":
+				r.opts |= api.StacktraceFramePointers
 			case "-mode":
 				i++
 				if i >= len(args) {
@@ -2501,6 +3833,16 @@ func parseStackArgs(argstr string) (stackArgs, error) {
 					return stackArgs{}, err
 				}
 				r.ancestorDepth = n
+			case "-maxdepth":
+				i++
+				n, err := numarg("-maxdepth")
+				if err != nil {
+					return stackArgs{}, err
+				}
+				if n <= 0 {
+					return stackArgs{}, errors.New("-maxdepth must be a positive number")
+				}
+				r.depth = n
 			default:
 				n, err := strconv.Atoi(args[i])
 				if err != nil {
@@ -2574,11 +3916,60 @@ func getLocation(t *Term, ctx callContext, args string, showContext bool) (file
 }
 
 func listCommand(t *Term, ctx callContext, args string) error {
+	around := -1
+	if argv := config.Split2PartsBySpace(args); argv[0] == "-around" {
+		if len(argv) < 2 {
+			return errors.New("not enough arguments to -around")
+		}
+		argv = config.Split2PartsBySpace(argv[1])
+		n, err := strconv.Atoi(argv[0])
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid argument to -around: %q", argv[0])
+		}
+		around = n
+		args = ""
+		if len(argv) > 1 {
+			args = argv[1]
+		}
+	}
+
+	if args == "+" || args == "-" {
+		pos := t.listPos
+		if pos.file == "" {
+			return errors.New("no previous listing")
+		}
+		size := pos.size
+		if around >= 0 {
+			size = 2*around + 1
+		}
+		var start, end int
+		if args == "+" {
+			if pos.end > pos.lines {
+				return errors.New("already at the end of the file")
+			}
+			start, end = pos.end, pos.end+size
+		} else {
+			if pos.start <= 1 {
+				return errors.New("already at the beginning of the file")
+			}
+			start, end = max(pos.start-size, 1), pos.start
+		}
+		fmt.Fprintf(t.stdout, "Showing %s:%d-%d\n", pos.file, start, min(end, pos.lines+1)-1)
+		return printfileRange(t, pos.file, start, end, pos.arrowLine)
+	}
+
 	file, lineno, showarrow, err := getLocation(t, ctx, args, true)
 	if err != nil {
 		return err
 	}
-	return printfile(t, file, lineno, showarrow)
+	if around < 0 {
+		around = t.conf.GetSourceListLineCount()
+	}
+	arrowLine := 0
+	if showarrow {
+		arrowLine = lineno
+	}
+	return printfileRange(t, file, lineno-around, lineno+around+1, arrowLine)
 }
 
 func (c *Commands) sourceCommand(t *Term, ctx callContext, args string) error {
@@ -2609,7 +4000,11 @@ func (c *Commands) sourceCommand(t *Term, ctx callContext, args string) error {
 	return c.executeFile(t, args)
 }
 
-var errDisasmUsage = errors.New("wrong number of arguments: disassemble [-a <start> <end>] [-l <locspec>]")
+var errDisasmUsage = errors.New("wrong number of arguments: disassemble [-a <start> <end>] [-l <locspec>] [-fn <regex>]")
+
+// maxDisassFunctions is the maximum number of functions disassembled by
+// 'disassemble -fn'.
+const maxDisassFunctions = 50
 
 func disassCommand(t *Term, ctx callContext, args string) error {
 	var cmd, rest string
@@ -2660,6 +4055,8 @@ func disassCommand(t *Term, ctx callContext, args string) error {
 			return errors.New("expression specifies multiple locations")
 		}
 		disasm, disasmErr = t.client.DisassemblePC(ctx.Scope, locs[0].PC, flavor)
+	case "-fn":
+		return disassFunctions(t, ctx, rest, flavor)
 	default:
 		return errDisasmUsage
 	}
@@ -2673,6 +4070,60 @@ func disassCommand(t *Term, ctx callContext, args string) error {
 	return nil
 }
 
+// fnRegexpPrefix is an optional prefix of the argument of -fn flags, it
+// makes explicit that the argument is a regular expression.
+const fnRegexpPrefix = "regexp:"
+
+// compileFnRegexp compiles the argument of a -fn flag.
+func compileFnRegexp(arg string) (*regexp.Regexp, error) {
+	arg = strings.TrimPrefix(arg, fnRegexpPrefix)
+	re, err := regexp.Compile(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %v", arg, err)
+	}
+	return re, nil
+}
+
+func disassFunctions(t *Term, ctx callContext, filter string, flavor api.AssemblyFlavour) error {
+	re, err := compileFnRegexp(filter)
+	if err != nil {
+		return err
+	}
+	filter = re.String()
+	fns, err := t.client.ListFunctions(filter, 0)
+	if err != nil {
+		return err
+	}
+	if len(fns) == 0 {
+		return fmt.Errorf("no function matches %q", filter)
+	}
+	sort.Strings(fns)
+	if len(fns) > maxDisassFunctions {
+		fmt.Fprintf(t.stdout, "Warning: %d functions match %q, only the first %d will be disassembled\n", len(fns), filter, maxDisassFunctions)
+		fns = fns[:maxDisassFunctions]
+	}
+	for i, fn := range fns {
+		if i > 0 {
+			fmt.Fprintln(t.stdout)
+		}
+		locs, _, err := t.client.FindLocation(ctx.Scope, fn, false, t.substitutePathRules())
+		if err == nil && len(locs) == 0 {
+			err = errors.New("could not find function")
+		}
+		if err != nil {
+			fmt.Fprintf(t.stdout, "TEXT %s(SB): %v\n", fn, err)
+			continue
+		}
+		disasm, err := t.client.DisassemblePC(ctx.Scope, locs[0].PC, flavor)
+		if err != nil {
+			fmt.Fprintf(t.stdout, "TEXT %s(SB): %v\n", fn, err)
+			continue
+		}
+		disasmPrint(disasm, t.stdout, true)
+	}
+	return nil
+}
+
 func libraries(t *Term, ctx callContext, args string) error {
 	argv := config.Split2PartsBySpace(args)
 	if len(argv) == 2 {
@@ -2703,6 +4154,28 @@ func libraries(t *Term, ctx callContext, args string) error {
 	return nil
 }
 
+func plugins(t *Term, ctx callContext, args string) error {
+	plugins, err := t.client.ListPlugins()
+	if err != nil {
+		return err
+	}
+	d := digits(len(plugins))
+	for i := range plugins {
+		p := &plugins[i]
+		path := p.Path
+		if path == "" {
+			path = "<unknown shared object>"
+		}
+		fmt.Fprintf(t.stdout, "%"+strconv.Itoa(d)+"d. %#x %s %s\n", i, p.Address, p.PluginPath, path)
+		if p.LoadError != "" {
+			fmt.Fprintf(t.stdout, "    Load error: %s\n", p.LoadError)
+		} else if p.Stripped {
+			fmt.Fprintf(t.stdout, "    No debug info\n")
+		}
+	}
+	return nil
+}
+
 func digits(n int) int {
 	if n <= 0 {
 		return 1
@@ -2750,6 +4223,12 @@ func printcontext(t *Term, state *api.DebuggerState) {
 		return
 	}
 
+	for _, th := range state.Threads {
+		if th.Exception != nil {
+			printException(t, th)
+		}
+	}
+
 	var th *api.Thread
 	if state.SelectedGoroutine == nil {
 		th = state.CurrentThread
@@ -2783,6 +4262,19 @@ func printcontext(t *Term, state *api.DebuggerState) {
 	}
 }
 
+func printException(t *Term, th *api.Thread) {
+	e := th.Exception
+	desc := ""
+	if e.Description != "" {
+		desc = " (" + e.Description + ")"
+	}
+	fmt.Fprintf(t.stdout, "Thread %d received exception %#x%s at %#x", th.ID, e.Code, desc, e.Address)
+	if e.Operation != "" {
+		fmt.Fprintf(t.stdout, ", %s of address %#x", e.Operation, e.FaultAddress)
+	}
+	fmt.Fprintln(t.stdout)
+}
+
 func printcontextLocation(t *Term, loc api.Location) {
 	fmt.Fprintf(t.stdout, "> %s() %s:%d (PC: %#v)\n", loc.Function.Name(), t.formatPath(loc.File), loc.Line, loc.PC)
 	if loc.Function != nil && loc.Function.Optimized {
@@ -2791,7 +4283,7 @@ func printcontextLocation(t *Term, loc api.Location) {
 }
 
 func printReturnValues(t *Term, th *api.Thread) {
-	if th.ReturnValues == nil {
+	if len(th.ReturnValues) == 0 {
 		return
 	}
 	fmt.Fprintln(t.stdout, "Values returned:")
@@ -2945,19 +4437,34 @@ func printBreakpointInfo(t *Term, th *api.Thread, tracepointOnNewline bool) {
 			fmt.Fprintf(t.stdout, "\t%s: %s\n", v.Name, multiLineVar(&v, "\t"))
 		}
 	}
-	if bpi.Stacktrace != nil {
-		// TraceFollowCalls and Stacktrace are mutually exclusive as they pollute each others outputs
-		if th.Breakpoint.TraceFollowCalls <= 0 {
-			tracepointnl()
-			fmt.Fprintf(t.stdout, "\tStack:\n")
-			printStack(t, t.stdout, bpi.Stacktrace, "\t\t", false)
-		}
+	if bpi.Stacktrace != nil && !bp.Tracepoint {
+		// tracepoints print their stack with printTraceStack
+		fmt.Fprintf(t.stdout, "\tStack:\n")
+		printStack(t, t.stdout, bpi.Stacktrace, "\t\t", false)
 	}
 
 }
 
+// printTraceStack prints the callers of a traced function compactly, one
+// per line, immediately below the line reporting its arguments. The first
+// frame of the stack is the traced function itself and is omitted.
+func printTraceStack(t *Term, th *api.Thread) {
+	// TraceFollowCalls and Stacktrace are mutually exclusive as they pollute each others outputs
+	if th.BreakpointInfo == nil || th.Breakpoint.TraceFollowCalls > 0 {
+		return
+	}
+	stack := th.BreakpointInfo.Stacktrace
+	for i := 1; i < len(stack); i++ {
+		frame := &stack[i]
+		fmt.Fprintf(t.stdout, "\t#%d %s at %s:%d\n", i, frame.Function.Name(), t.formatPath(frame.File), frame.Line)
+		if frame.Err != "" {
+			fmt.Fprintf(t.stdout, "\t#%d error: %s\n", i, frame.Err)
+		}
+	}
+}
+
 func printTracepoint(t *Term, th *api.Thread, bpname string, fn *api.Function, args string, hasReturnValue bool) {
-	if t.conf.TraceShowTimestamp {
+	if t.conf.TraceShowTimestamp && t.traceJSON == nil {
 		fmt.Fprintf(t.stdout, "%s ", time.Now().Format(time.RFC3339Nano))
 	}
 
@@ -2989,6 +4496,49 @@ func printTracepoint(t *Term, th *api.Thread, bpname string, fn *api.Function, a
 		tracePrefix = fmt.Sprintf("goroutine(%d):", th.GoroutineID)
 	}
 
+	if t.traceJSON != nil {
+		// Print trace only if there was a match on the function while TraceFollowCalls is on or if it's a regular trace
+		if rootindex == -1 && th.Breakpoint.TraceFollowCalls > 0 {
+			return
+		}
+		var args []api.Variable
+		var stack []api.Stackframe
+		if th.BreakpointInfo != nil {
+			if th.Breakpoint.Tracepoint {
+				for _, arg := range th.BreakpointInfo.Arguments {
+					if (arg.Flags & api.VariableArgument) != 0 {
+						args = append(args, arg)
+					}
+				}
+			}
+			if th.Breakpoint.TraceFollowCalls <= 0 {
+				stack = th.BreakpointInfo.Stacktrace
+			}
+		}
+		if err := t.WriteTraceEvent(th.GoroutineID, fn.Name(), th.Breakpoint.TraceReturn, args, th.ReturnValues, stack); err != nil {
+			fmt.Fprintf(t.stdout, "could not write trace event: %v\n", err)
+		}
+		return
+	}
+
+	if th.Breakpoint.TraceEval && th.BreakpointInfo != nil {
+		// tracepoint created with 'break -trace -eval', print all expressions on
+		// a single line.
+		vals := make([]string, len(th.BreakpointInfo.Variables))
+		for i := range th.BreakpointInfo.Variables {
+			v := &th.BreakpointInfo.Variables[i]
+			vals[i] = fmt.Sprintf("%s: %s", v.Name, v.SinglelineString())
+		}
+		fmt.Fprintf(t.stdout, "%s> %s %s%s %s:%d %s\n", depthPrefix, tracePrefix, bpname, fn.Name(), t.formatPath(th.File), th.Line, strings.Join(vals, ", "))
+		th2 := *th
+		bpi := *th.BreakpointInfo
+		bpi.Variables = nil
+		th2.BreakpointInfo = &bpi
+		printBreakpointInfo(t, &th2, true)
+		printTraceStack(t, th)
+		return
+	}
+
 	verbosity := t.TraceVerbosity
 	if th.Breakpoint.Tracepoint {
 		// Print trace only if there was a match on the function while TraceFollowCalls is on or if it's a regular trace
@@ -3038,6 +4588,7 @@ func printTracepoint(t *Term, th *api.Thread, bpname string, fn *api.Function, a
 		}
 
 		printBreakpointInfo(t, th, !hasReturnValue)
+		printTraceStack(t, th)
 	}
 	if th.Breakpoint.TraceReturn {
 		// Print trace only if there was a match on the function while TraceFollowCalls is on or if it's a regular trace
@@ -3050,16 +4601,6 @@ func printTracepoint(t *Term, th *api.Thread, bpname string, fn *api.Function, a
 			fmt.Fprintf(t.stdout, "%s>> %s %s => (%s)\n", depthPrefix, tracePrefix, fn.Name(), strings.Join(retVals, ","))
 		}
 	}
-	if th.Breakpoint.TraceFollowCalls > 0 {
-		// As of now traceFollowCalls and Stacktrace are mutually exclusive options
-		return
-	}
-	if th.Breakpoint.TraceReturn || !hasReturnValue {
-		if th.BreakpointInfo != nil && th.BreakpointInfo.Stacktrace != nil {
-			fmt.Fprintf(t.stdout, "\tStack:\n")
-			printStack(t, t.stdout, th.BreakpointInfo.Stacktrace, "\t\t", false)
-		}
-	}
 }
 
 type printPosFlags uint8
@@ -3083,15 +4624,20 @@ func printPos(t *Term, th *api.Thread, flags printPosFlags) error {
 }
 
 func printfile(t *Term, filename string, line int, showArrow bool) error {
-	if filename == "" {
-		return nil
-	}
-
 	lineCount := t.conf.GetSourceListLineCount()
 	arrowLine := 0
 	if showArrow {
 		arrowLine = line
 	}
+	return printfileRange(t, filename, line-lineCount, line+lineCount+1, arrowLine)
+}
+
+// printfileRange prints lines [start, end) of filename and remembers them
+// as the current listing position, used by 'list +' and 'list -'.
+func printfileRange(t *Term, filename string, start, end, arrowLine int) error {
+	if filename == "" {
+		return nil
+	}
 
 	var file *os.File
 	path := t.substitutePath(filename)
@@ -3113,7 +4659,17 @@ func printfile(t *Term, filename string, line int, showArrow bool) error {
 		fmt.Fprintln(t.stdout, "Warning: listing may not match stale executable")
 	}
 
-	return t.stdout.ColorizePrint(file.Name(), file, line-lineCount, line+lineCount+1, arrowLine)
+	buf, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	lines := bytes.Count(buf, []byte{'\n'})
+	if len(buf) > 0 && buf[len(buf)-1] != '\n' {
+		lines++
+	}
+	t.listPos = listPosition{file: filename, start: max(start, 1), end: min(end, lines+1), size: end - start, lines: lines, arrowLine: arrowLine}
+
+	return t.stdout.ColorizePrint(file.Name(), bytes.NewReader(buf), start, end, arrowLine)
 }
 
 func printdisass(t *Term, pc uint64) error {
@@ -3243,6 +4799,7 @@ func (c *Commands) parseBreakpointAttrs(t *Term, ctx callContext, r io.Reader) e
 	ctx.Breakpoint.Variables = ctx.Breakpoint.Variables[:0]
 	ctx.Breakpoint.Cond = ""
 	ctx.Breakpoint.HitCond = ""
+	ctx.Breakpoint.GoroutineLabels = nil
 
 	scan := bufio.NewScanner(r)
 	lineno := 0
@@ -3289,12 +4846,44 @@ func conditionCmd(t *Term, ctx callContext, argstr string) error {
 		return t.client.AmendBreakpoint(bp)
 	}
 
+	if args[0] == "-label" {
+		bp := ctx.Breakpoint
+		selector := args[1]
+		if ctx.Prefix != onPrefix {
+			args = config.Split2PartsBySpace(args[1])
+			if len(args) < 2 {
+				return errors.New("not enough arguments")
+			}
+			var err error
+			bp, err = getBreakpointByIDOrName(t, args[0])
+			if err != nil {
+				return err
+			}
+			selector = args[1]
+		}
+		k, v, err := parseLabelSelector(selector)
+		if err != nil {
+			return err
+		}
+		if bp.GoroutineLabels == nil {
+			bp.GoroutineLabels = make(map[string]string)
+		}
+		bp.GoroutineLabels[k] = v
+		if ctx.Prefix == onPrefix {
+			return nil
+		}
+		return t.client.AmendBreakpoint(bp)
+	}
+
 	if args[0] == "-clear" {
 		bp, err := getBreakpointByIDOrName(t, args[1])
 		if err != nil {
 			return err
 		}
 		bp.Cond = ""
+		bp.HitCond = ""
+		bp.HitCondPerG = false
+		bp.GoroutineLabels = nil
 		return t.client.AmendBreakpoint(bp)
 	}
 
@@ -3407,21 +4996,30 @@ func clearCheckpoint(t *Term, ctx callContext, args string) error {
 
 func display(t *Term, ctx callContext, args string) error {
 	const (
-		addOption = "-a "
-		delOption = "-d "
+		addOption    = "-a "
+		delOption    = "-d "
+		changeOption = "-change "
 	)
 	switch {
 	case args == "":
-		t.printDisplays()
+		t.printDisplays(true)
 
 	case strings.HasPrefix(args, addOption):
 		args = strings.TrimSpace(args[len(addOption):])
-		fmtstr, args := parseFormatArg(args)
+		onlyChanged := false
+		if strings.HasPrefix(args, changeOption) {
+			onlyChanged = true
+			args = strings.TrimSpace(args[len(changeOption):])
+		}
+		fmtstr, args, err := parseFormatArg(args)
+		if err != nil {
+			return err
+		}
 		if args == "" {
 			return errors.New("not enough arguments")
 		}
-		t.addDisplay(args, fmtstr)
-		t.printDisplay(len(t.displays) - 1)
+		t.addDisplay(args, fmtstr, onlyChanged)
+		t.printDisplay(len(t.displays)-1, true)
 
 	case strings.HasPrefix(args, delOption):
 		args = strings.TrimSpace(args[len(delOption):])
@@ -3621,6 +5219,8 @@ func (t *Term) formatBreakpointLocation(bp *api.Breakpoint) string {
 				fmt.Fprintf(&out, ",%#x", addr)
 			}
 		}
+	} else if bp.WatchField {
+		fmt.Fprintf(&out, "no instances")
 	} else {
 		// In case we are connecting to an older version of delve that does not return the Addrs field.
 		fmt.Fprintf(&out, "%#x", bp.Addr)
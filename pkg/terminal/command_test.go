@@ -673,6 +673,25 @@ func TestListCmd(t *testing.T) {
 	})
 }
 
+func TestListPaging(t *testing.T) {
+	withTestTerminal("testvariables", t, func(term *FakeTerminal) {
+		term.AssertExecError("list +", "no previous listing")
+		listIsAt(t, term, "list -around 2 testvariables.go:10", -1, 8, 12)
+		listIsAt(t, term, "list +", -1, 13, 17)
+		listIsAt(t, term, "list -around 1 +", -1, 18, 20)
+		listIsAt(t, term, "list -", -1, 15, 17)
+		listIsAt(t, term, "list -around 5 -", -1, 4, 14)
+		listIsAt(t, term, "list -", -1, 1, 3)
+		term.AssertExecError("list -", "already at the beginning of the file")
+		listIsAt(t, term, "list testvariables.go:70", -1, 65, 74)
+		term.AssertExecError("list +", "already at the end of the file")
+		_, err := term.Exec("list -around x 10")
+		if err == nil {
+			t.Fatalf("expected error for invalid -around argument")
+		}
+	})
+}
+
 func TestReverseContinue(t *testing.T) {
 	test.AllowRecording(t)
 	if testBackend != "rr" {
@@ -687,6 +706,21 @@ func TestReverseContinue(t *testing.T) {
 	})
 }
 
+func TestReverseNext(t *testing.T) {
+	test.AllowRecording(t)
+	if testBackend != "rr" {
+		return
+	}
+	withTestTerminal("continuetestprog", t, func(term *FakeTerminal) {
+		term.MustExec("break main.main")
+		listIsAt(t, term, "continue", 16, -1, -1)
+		listIsAt(t, term, "next", 17, -1, -1)
+		listIsAt(t, term, "next", 18, -1, -1)
+		listIsAt(t, term, "rnext", 17, -1, -1)
+		listIsAt(t, term, "goroutine 1 rnext", 16, -1, -1)
+	})
+}
+
 func TestCheckpoints(t *testing.T) {
 	test.AllowRecording(t)
 	if testBackend != "rr" {
@@ -714,6 +748,21 @@ func TestNextWithCount(t *testing.T) {
 	})
 }
 
+func TestStepOutWithCount(t *testing.T) {
+	test.AllowRecording(t)
+	withTestTerminal("nestedbp", t, func(term *FakeTerminal) {
+		term.MustExec("break nestedbp.go:18")
+		listIsAt(t, term, "continue", 18, -1, -1)
+		out := term.MustExec("stepout 2")
+		if !strings.HasPrefix(out, "> main.main()") {
+			t.Fatalf("stepout 2 did not return to main.main: %q", out)
+		}
+		if _, err := term.Exec("stepout 0"); err == nil {
+			t.Fatal("expected error for stepout 0")
+		}
+	})
+}
+
 func TestRestart(t *testing.T) {
 	withTestTerminal("restartargs", t, func(term *FakeTerminal) {
 		term.MustExec("break main.printArgs")
@@ -1010,6 +1059,94 @@ func TestOptimizationCheck(t *testing.T) {
 	}
 }
 
+func TestStackFast(t *testing.T) {
+	if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
+		t.Skip("frame pointer unwinding not supported")
+	}
+	withTestTerminal("stacktraceprog", t, func(term *FakeTerminal) {
+		term.MustExec("break main.stacktraceme")
+		term.MustExec("continue")
+		out := term.MustExec("stack")
+		outFast := term.MustExec("stack -fast")
+		if out != outFast {
+			t.Errorf("output mismatch:\n%s\n%s", out, outFast)
+		}
+		if _, err := term.Exec("stack -fast -full"); err == nil {
+			t.Error("expected error for -fast -full")
+		}
+	})
+}
+
+func TestGoroutineStartStack(t *testing.T) {
+	withTestTerminal("testnextprog", t, func(term *FakeTerminal) {
+		term.MustExec("break main.testgoroutine")
+		term.MustExec("continue")
+		state, err := term.client.GetState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := term.MustExec(fmt.Sprintf("goroutine %d startstack", state.SelectedGoroutine.ID))
+		t.Logf("output %q", out)
+		if !strings.Contains(out, "main.main") || !strings.Contains(out, "testnextprog.go:40") {
+			t.Fatalf("wrong output for startstack: %q", out)
+		}
+	})
+}
+
+func TestGoroutinesSortBlocked(t *testing.T) {
+	gs := []*api.Goroutine{
+		{ID: 1},
+		{ID: 2, Status: api.GoroutineWaiting, WaitSince: 300},
+		{ID: 3, Status: api.GoroutineWaiting},
+		{ID: 4, Status: api.GoroutineSyscall, WaitSince: 100},
+		{ID: 5, WaitSince: 50},
+		{ID: 6, Status: api.GoroutineWaiting, WaitSince: 100},
+	}
+	sortGoroutinesByBlocked(gs)
+	ids := []int64{}
+	for _, g := range gs {
+		ids = append(ids, g.ID)
+	}
+	if want := []int64{4, 6, 2, 1, 3, 5}; !slices.Equal(ids, want) {
+		t.Errorf("wrong order %v, expected %v", ids, want)
+	}
+
+	withTestTerminal("goroutinestackprog", t, func(term *FakeTerminal) {
+		term.MustExec("break main.stacktraceme")
+		term.MustExec("continue")
+		out1 := term.MustExec("goroutines")
+		out2 := term.MustExec("goroutines -sort blocked")
+		if n1, n2 := strings.Count(out1, "Goroutine "), strings.Count(out2, "Goroutine "); n1 != n2 {
+			t.Errorf("wrong number of goroutines with -sort blocked: %d instead of %d\n%s", n2, n1, out2)
+		}
+	})
+}
+
+func TestGoroutinesUser(t *testing.T) {
+	withTestTerminal("goroutinestackprog", t, func(term *FakeTerminal) {
+		term.MustExec("break main.stacktraceme")
+		term.MustExec("continue")
+		all := strings.Count(term.MustExec("goroutines"), "Goroutine ")
+		out := term.MustExec("goroutines -user")
+		var n, hidden int
+		if _, err := fmt.Sscanf(out[strings.LastIndex(out, "["):], "[%d goroutines, %d runtime goroutines hidden]", &n, &hidden); err != nil {
+			t.Fatalf("could not parse output: %v", err)
+		}
+		if shown := strings.Count(out, "Goroutine "); shown != n {
+			t.Errorf("wrong number of goroutines reported: %d instead of %d", n, shown)
+		}
+		if hidden == 0 || n+hidden != all {
+			t.Errorf("wrong number of hidden goroutines %d (shown %d, total %d)", hidden, n, all)
+		}
+		if strings.Contains(out, "[GC sweep wait]") {
+			t.Errorf("runtime goroutine not hidden")
+		}
+		if _, err := term.Exec("goroutines -user -without user"); err == nil {
+			t.Errorf("-user combined with -without user did not fail")
+		}
+	})
+}
+
 func TestTruncateStacktrace(t *testing.T) {
 	if runtime.GOARCH == "ppc64le" && buildMode == "pie" {
 		t.Skip("pie mode broken on ppc64le")
@@ -1028,6 +1165,40 @@ func TestTruncateStacktrace(t *testing.T) {
 		if !strings.Contains(out2, stacktraceTruncatedMessage) {
 			t.Fatalf("stacktrace was not truncated")
 		}
+		out3 := term.MustExec("goroutine 1 stack -maxdepth 1")
+		if !strings.Contains(out3, stacktraceTruncatedMessage) {
+			t.Fatalf("stacktrace was not truncated with -maxdepth")
+		}
+		out4 := term.MustExec("goroutine 1 stack -maxdepth 100")
+		if strings.Contains(out4, stacktraceTruncatedMessage) {
+			t.Fatalf("stacktrace was truncated with -maxdepth 100")
+		}
+		for _, cmd := range []string{"stack -maxdepth 0", "stack -maxdepth -3", "stack -maxdepth", "stack -maxdepth x"} {
+			if _, err := term.Exec(cmd); err == nil {
+				t.Errorf("%q: expected error", cmd)
+			}
+		}
+	})
+}
+
+func TestStepOutReturnValues(t *testing.T) {
+	// stepout should print the values returned by the function, but not
+	// an empty list for functions without results.
+	withTestTerminal("stepoutret", t, func(term *FakeTerminal) {
+		term.MustExec("break main.stepout")
+		term.MustExec("continue")
+		out := term.MustExec("stepout")
+		if !strings.Contains(out, "Values returned:") || !strings.Contains(out, "str: \"return 47\"") || !strings.Contains(out, "num: 48") {
+			t.Fatalf("return values not printed: %q", out)
+		}
+	})
+	withTestTerminal("testnextprog", t, func(term *FakeTerminal) {
+		term.MustExec("break main.helloworld")
+		term.MustExec("continue")
+		out := term.MustExec("stepout")
+		if strings.Contains(out, "Values returned:") {
+			t.Fatalf("unexpected return values header: %q", out)
+		}
 	})
 }
 
@@ -1050,6 +1221,33 @@ func TestIssue1493(t *testing.T) {
 	})
 }
 
+func TestRegsSelect(t *testing.T) {
+	if runtime.GOARCH != "amd64" {
+		t.Skip("test uses amd64 register names")
+	}
+	withTestTerminal("continuetestprog", t, func(term *FakeTerminal) {
+		term.MustExec("break main.main")
+		term.MustExec("continue")
+		out := term.MustExec("regs rip RSP")
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 2 || !strings.Contains(lines[0], "Rip = 0x") || !strings.Contains(lines[1], "Rsp = 0x") {
+			t.Fatalf("wrong output for 'regs rip RSP': %q", out)
+		}
+		out = term.MustExec("regs pc")
+		if !strings.Contains(out, "PC = 0x") {
+			t.Fatalf("wrong output for 'regs pc': %q", out)
+		}
+		if _, err := term.Exec("regs notaregister"); err == nil {
+			t.Fatal("expected error for unknown register")
+		}
+		term.MustExec("regs -follow pc")
+		out = term.MustExec("display")
+		if !strings.Contains(out, "0: PC = 0x") {
+			t.Fatalf("register not added to the display list: %q", out)
+		}
+	})
+}
+
 func findStarFile(name string) string {
 	return filepath.Join(test.FindFixturesDir(), name+".star")
 }
@@ -1071,6 +1269,32 @@ func TestIssue1598(t *testing.T) {
 	})
 }
 
+func TestPrintChanBuffer(t *testing.T) {
+	withTestTerminal("testvariables2", t, func(term *FakeTerminal) {
+		term.MustExec("continue")
+		if out := term.MustExec("print chwrap"); strings.Contains(out, "buffered") {
+			t.Errorf("buffered elements printed without load-chan-buffer: %q", out)
+		}
+		term.MustExec("config load-chan-buffer true")
+		if out := term.MustExec("print chwrap"); !strings.Contains(out, "buffered: [3]int [2,3,4]") {
+			t.Errorf("buffered elements not printed with load-chan-buffer: %q", out)
+		}
+	})
+}
+
+func TestPrintSyncMap(t *testing.T) {
+	withTestTerminal("syncmap", t, func(term *FakeTerminal) {
+		term.MustExec("continue")
+		if out := term.MustExec("print m"); strings.Contains(out, "entries") {
+			t.Errorf("sync.Map entries printed without load-sync-map: %q", out)
+		}
+		term.MustExec("config load-sync-map true")
+		if out := term.MustExec("print m"); !strings.Contains(out, `"two": 22`) {
+			t.Errorf("sync.Map entries not printed with load-sync-map: %q", out)
+		}
+	})
+}
+
 func TestExamineMemoryCmd(t *testing.T) {
 	withTestTerminal("examinememory", t, func(term *FakeTerminal) {
 		term.MustExec("break examinememory.go:19")
@@ -1138,6 +1362,38 @@ func TestExamineMemoryCmd(t *testing.T) {
 		if !strings.Contains(res, firstLine) {
 			t.Fatalf("expected first line: %s", firstLine)
 		}
+
+		// gdb style format specifiers
+		res = term.MustExec("x/4xb &bs[0]")
+		t.Logf("x/4xb result \n%s", res)
+		firstLine = fmt.Sprintf("%#x:   0xff   0x0b   0x0c   0x0d", address)
+		if !strings.Contains(res, firstLine) {
+			t.Fatalf("expected first line: %s", firstLine)
+		}
+		res = term.MustExec("examinemem/2xw " + addressStr + " + 4")
+		t.Logf("examinemem/2xw result \n%s", res)
+		firstLine = fmt.Sprintf("%#x:   0x11100f0e   0x15141312", address+4)
+		if !strings.Contains(res, firstLine) {
+			t.Fatalf("expected first line: %s", firstLine)
+		}
+		res = term.MustExec("x/3c &bs[0]")
+		t.Logf("x/3c result \n%s", res)
+		firstLine = fmt.Sprintf(`%#x:   '\xff'   '\v'   '\f'`, address)
+		if !strings.Contains(res, firstLine) {
+			t.Fatalf("expected first line: %s", firstLine)
+		}
+		state, err := term.client.GetState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res = term.MustExec(fmt.Sprintf("x/2i %#x", state.CurrentThread.PC))
+		t.Logf("x/2i result \n%s", res)
+		if n := len(strings.Split(strings.TrimSpace(res), "\n")); n != 2 {
+			t.Fatalf("expected 2 instructions, got %d", n)
+		}
+		if _, err := term.Exec("x/4z &bs[0]"); err == nil {
+			t.Fatalf("expected error for invalid format specifier")
+		}
 	})
 
 	withTestTerminal("testvariables2", t, func(term *FakeTerminal) {
@@ -1170,6 +1426,25 @@ func TestExamineMemoryCmd(t *testing.T) {
 	})
 }
 
+func TestMemstatsCmd(t *testing.T) {
+	withTestTerminal("testvariables2", t, func(term *FakeTerminal) {
+		term.MustExec("continue")
+		out := term.MustExec("memstats")
+		t.Logf("output %q", out)
+		for _, field := range []string{"HeapAlloc", "HeapInuse", "NumGC", "PauseTotalNs"} {
+			if !strings.Contains(out, field+" ") {
+				t.Errorf("field %s missing from output", field)
+			}
+		}
+		if strings.Contains(out, "unavailable") {
+			t.Errorf("some fields could not be read")
+		}
+		if _, err := term.Exec("memstats foo"); err == nil {
+			t.Error("expected error for extra argument")
+		}
+	})
+}
+
 func TestPrintOnTracepoint(t *testing.T) {
 	withTestTerminal("increment", t, func(term *FakeTerminal) {
 		term.MustExec("trace main.Increment")
@@ -1178,9 +1453,62 @@ func TestPrintOnTracepoint(t *testing.T) {
 		if !strings.Contains(out, "y+1: 4") || !strings.Contains(out, "y+1: 2") || !strings.Contains(out, "y+1: 1") {
 			t.Errorf("output did not contain breakpoint information: %q", out)
 		}
+		// Only tracepoints created with 'break -trace -eval' print the values
+		// on the trace line.
+		if !strings.Contains(out, "> goroutine(1): main.Increment(3)\n") {
+			t.Errorf("breakpoint information printed on the trace line: %q", out)
+		}
+	})
+}
+
+func TestBreakTraceEval(t *testing.T) {
+	withTestTerminal("increment", t, func(term *FakeTerminal) {
+		term.MustExec("break -trace -eval 'y, y*2, nosuchvar' main.Increment")
+		out, _ := term.Exec("continue")
+		for _, tgt := range []string{
+			"> goroutine(1): main.Increment ",
+			"increment.go:6 y: 3, y*2: 6, nosuchvar: (unreadable eval error",
+			"increment.go:6 y: 1, y*2: 2, nosuchvar: (unreadable eval error",
+			"increment.go:6 y: 0, y*2: 0, nosuchvar: (unreadable eval error",
+		} {
+			if !strings.Contains(out, tgt) {
+				t.Errorf("output did not contain %q", tgt)
+			}
+		}
+		if strings.Contains(out, ">> goroutine(1)") {
+			t.Errorf("return tracepoints set")
+		}
 	})
 }
 
+func TestParseEvalList(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		exprs []string
+		rest  string
+	}{
+		{"x main.go:10", []string{"x"}, "main.go:10"},
+		{"x,y.field main.go:10", []string{"x", "y.field"}, "main.go:10"},
+		{"'x, y.field' main.go:10", []string{"x", "y.field"}, " main.go:10"},
+		{`"f(a, b), m[\"a,b\"]" main.go:10`, []string{"f(a, b)", `m["a,b"]`}, " main.go:10"},
+		{"'s[1:2], \"a,b\"'", []string{"s[1:2]", `"a,b"`}, ""},
+	} {
+		exprs, rest, err := parseEvalList(tc.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tc.in, err)
+			continue
+		}
+		if !slices.Equal(exprs, tc.exprs) || rest != tc.rest {
+			t.Errorf("%q: got %q %q, expected %q %q", tc.in, exprs, rest, tc.exprs, tc.rest)
+		}
+	}
+	for _, in := range []string{"", "'x, y", "'x,,y'"} {
+		if _, _, err := parseEvalList(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
+
 func TestPrintCastToInterface(t *testing.T) {
 	withTestTerminal("testvariables2", t, func(term *FakeTerminal) {
 		term.MustExec("continue")
@@ -1236,6 +1564,62 @@ func TestParseNewArgv(t *testing.T) {
 	}
 }
 
+func TestParseRestartRecordedArgs(t *testing.T) {
+	testCases := []struct {
+		in         string
+		rerecord   bool
+		restartPos string
+		resetArgs  bool
+		tgtargs    string
+		tgterr     string
+	}{
+		{"", false, "", false, "", ""},
+		{"c1", false, "c1", false, "", ""},
+		{"c1 c2", false, "", false, "", "too many arguments to restart"},
+		{"-r", true, "", false, "", ""},
+		{"-rerecord", true, "", false, "", ""},
+		{"--rerecord", true, "", false, "", ""},
+		{"-r arg1 arg2", true, "", true, "arg1 | arg2", ""},
+		{"-rerecord arg1 arg2", true, "", true, "arg1 | arg2", ""},
+		{"-rerecord -noargs", true, "", true, "", ""},
+		{"-rerecord <input.txt <input2.txt", true, "", false, "", "redirect error: stdin redirected twice"},
+	}
+
+	for _, tc := range testCases {
+		rerecord, restartPos, resetArgs, newArgv, _, err := parseRestartRecordedArgs(tc.in)
+		if tc.tgterr != "" {
+			if err == nil || err.Error() != tc.tgterr {
+				t.Errorf("%q: expected error %q, got %v", tc.in, tc.tgterr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tc.in, err)
+			continue
+		}
+		argvstr := strings.Join(newArgv, " | ")
+		if rerecord != tc.rerecord || restartPos != tc.restartPos || resetArgs != tc.resetArgs || argvstr != tc.tgtargs {
+			t.Errorf("%q: got %v %q %v %q, expected %v %q %v %q", tc.in, rerecord, restartPos, resetArgs, argvstr, tc.rerecord, tc.restartPos, tc.resetArgs, tc.tgtargs)
+		}
+	}
+}
+
+func TestRestartRerecord(t *testing.T) {
+	test.AllowRecording(t)
+	if testBackend != "rr" {
+		return
+	}
+	withTestTerminal("restartargs", t, func(term *FakeTerminal) {
+		term.MustExec("break main.printArgs")
+		term.MustExec("continue")
+		term.MustExec("restart -rerecord newarg1 newarg2")
+		term.MustExec("continue")
+		if out := term.MustExec("print main.args"); !strings.Contains(out, `"newarg1", "newarg2"`) {
+			t.Fatalf("wrong args after re-recording: %q", out)
+		}
+	})
+}
+
 func TestContinueUntil(t *testing.T) {
 	withTestTerminal("continuetestprog", t, func(term *FakeTerminal) {
 		if runtime.GOARCH != "386" {
@@ -1266,6 +1650,23 @@ func TestPrintFormat(t *testing.T) {
 		if !strings.Contains(out, "0xb\n") {
 			t.Fatalf("output did not contain '0xb': %q", out)
 		}
+
+		for _, tc := range []struct{ in, tgt string }{
+			{"print -fmt hex m2[1].B", "0xb\n"},
+			{"print -fmt bin m2[1].B", "0b1011\n"},
+			{"print -fmt oct m2[1].B", "013\n"},
+			{"print -fmt dec m2[1].B", "11\n"},
+			{"print -fmt char 65", "A\n"},
+			{"print -fmt hex s4[:3]", "[0x1,0x2,0x3]\n"},
+		} {
+			out := term.MustExec(tc.in)
+			if !strings.HasSuffix(out, tc.tgt) {
+				t.Errorf("wrong output for %q: %q (expected %q)", tc.in, out, tc.tgt)
+			}
+		}
+		if _, err := term.Exec("print -fmt inst m2[1].B"); err == nil {
+			t.Errorf("print -fmt inst did not fail")
+		}
 	})
 }
 
@@ -1281,6 +1682,32 @@ func TestHitCondBreakpoint(t *testing.T) {
 		}
 	})
 
+	withTestTerminal("break", t, func(term *FakeTerminal) {
+		term.MustExec("break -hitcount % 4 bp1 main.main:4")
+		listIsAt(t, term, "continue", 7, -1, -1)
+		out := term.MustExec("print i")
+		t.Logf("%q", out)
+		if !strings.Contains(out, "4\n") {
+			t.Fatalf("wrong value of i")
+		}
+		listIsAt(t, term, "continue", 7, -1, -1)
+		out = term.MustExec("print i")
+		t.Logf("%q", out)
+		if !strings.Contains(out, "8\n") {
+			t.Fatalf("wrong value of i")
+		}
+	})
+
+	withTestTerminal("break", t, func(term *FakeTerminal) {
+		term.MustExec("break -hitcount >= 3 main.main:4 if i%2 == 0")
+		listIsAt(t, term, "continue", 7, -1, -1)
+		out := term.MustExec("print i")
+		t.Logf("%q", out)
+		if !strings.Contains(out, "6\n") {
+			t.Fatalf("wrong value of i")
+		}
+	})
+
 	withTestTerminal("condperghitcount", t, func(term *FakeTerminal) {
 		term.MustExec("break bp1 main.main:8")
 		term.MustExec("condition -per-g-hitcount bp1 == 2")
@@ -1301,6 +1728,37 @@ func TestHitCondBreakpoint(t *testing.T) {
 	})
 }
 
+func TestBreakpointLabels(t *testing.T) {
+	withTestTerminal("bplabels", t, func(term *FakeTerminal) {
+		term.MustExec("break bp1 main.handler -label service=payments -label region=eu if n > 0")
+		out := term.MustExec("breakpoints")
+		if !strings.Contains(out, "cond -label region=eu\n") || !strings.Contains(out, "cond -label service=payments\n") || !strings.Contains(out, "cond n > 0\n") {
+			t.Fatalf("wrong breakpoints output: %q", out)
+		}
+		term.MustExec("continue")
+		if out := term.MustExec("print n"); out != "2\n" {
+			t.Fatalf("wrong value of n: %q", out)
+		}
+	})
+
+	withTestTerminal("bplabels", t, func(term *FakeTerminal) {
+		term.MustExec("break bp1 main.handler")
+		term.MustExec("condition -label bp1 service=orders")
+		term.MustExec("continue")
+		if out := term.MustExec("print n"); out != "1\n" {
+			t.Fatalf("wrong value of n: %q", out)
+		}
+		term.MustExec("condition -clear bp1")
+		out := term.MustExec("breakpoints")
+		if strings.Contains(out, "-label") {
+			t.Fatalf("label condition not cleared: %q", out)
+		}
+		if _, err := term.Exec("condition -label bp1 service"); err == nil {
+			t.Fatal("expected error for label without value")
+		}
+	})
+}
+
 func TestCondBreakpointWithFrame(t *testing.T) {
 	withTestTerminal("condframe", t, func(term *FakeTerminal) {
 		term.MustExec("break bp1 callme2")
@@ -1332,6 +1790,17 @@ func TestClearCondBreakpoint(t *testing.T) {
 			t.Fatalf("wrong value of i")
 		}
 	})
+
+	withTestTerminal("break", t, func(term *FakeTerminal) {
+		term.MustExec("break -hitcount > 3 main.main:4")
+		term.MustExec("condition -clear 1")
+		listIsAt(t, term, "continue", 7, -1, -1)
+		out := term.MustExec("print i")
+		t.Logf("%q", out)
+		if !strings.Contains(out, "1\n") {
+			t.Fatalf("wrong value of i")
+		}
+	})
 }
 
 func TestBreakpointEditing(t *testing.T) {
@@ -1480,6 +1949,43 @@ func TestDisassPosCmd(t *testing.T) {
 	})
 }
 
+func TestDisassFunctionsCmd(t *testing.T) {
+	if runtime.GOARCH == "ppc64le" && buildMode == "pie" {
+		t.Skip("pie mode broken on ppc64le")
+	}
+	withTestTerminal("goroutinegroup", t, func(term *FakeTerminal) {
+		out := term.MustExec(`disassemble -fn ^main\.gopoint`)
+		for i := 1; i <= 5; i++ {
+			if !strings.Contains(out, fmt.Sprintf("TEXT main.gopoint%d(SB)", i)) {
+				t.Errorf("main.gopoint%d not disassembled", i)
+			}
+		}
+		if strings.Contains(out, "TEXT main.startpoint") {
+			t.Errorf("non matching function disassembled")
+		}
+
+		out = term.MustExec(`disassemble -fn ^runtime\.`)
+		if !strings.HasPrefix(out, "Warning: ") || strings.Count(out, "TEXT ") != maxDisassFunctions {
+			t.Errorf("output not truncated: %d functions", strings.Count(out, "TEXT "))
+		}
+
+		_, err := term.Exec(`disassemble -fn ^main\.nonexistent`)
+		if err == nil {
+			t.Errorf("expected error for non matching regular expression")
+		}
+
+		out = term.MustExec(`disassemble -fn regexp:^main\.gopoint1$`)
+		if strings.Count(out, "TEXT ") != 1 || !strings.Contains(out, "TEXT main.gopoint1(SB)") {
+			t.Errorf("wrong output for regexp: prefix:\n%s", out)
+		}
+
+		_, err = term.Exec(`disassemble -fn regexp:main.(`)
+		if err == nil {
+			t.Errorf("expected error for invalid regular expression")
+		}
+	})
+}
+
 func TestCreateBreakpointByLocExpr(t *testing.T) {
 	withTestTerminal("math", t, func(term *FakeTerminal) {
 		out := term.MustExec("break main.main")
@@ -1510,6 +2016,68 @@ func TestCreateBreakpointWithCondition(t *testing.T) {
 	})
 }
 
+func TestContinueToLocation(t *testing.T) {
+	withTestTerminal("testnextprog", t, func(term *FakeTerminal) {
+		noTemporaryBreakpoints := func() {
+			t.Helper()
+			out := term.MustExec("breakpoints")
+			if strings.Contains(out, "testnextprog.go:40") {
+				t.Fatalf("temporary breakpoint was not cleared:\n%s", out)
+			}
+		}
+
+		// a different breakpoint is hit before reaching the location
+		term.MustExec("break main.helloworld")
+		term.MustExec("continue testnextprog.go:40")
+		listIsAt(t, term, "list", 13, -1, -1)
+		noTemporaryBreakpoints()
+
+		term.MustExec("continue testnextprog.go:40")
+		listIsAt(t, term, "list", 40, -1, -1)
+		noTemporaryBreakpoints()
+
+		// the location is never reached
+		out, err := term.Exec("continue testnextprog.go:40")
+		if err == nil || !strings.Contains(err.Error(), "has exited with status 0") || strings.Contains(out, "failed to clear") {
+			t.Fatalf("unexpected output: %q %v", out, err)
+		}
+		noTemporaryBreakpoints()
+	})
+}
+
+func TestHandleCmd(t *testing.T) {
+	if runtime.GOOS != "linux" || testBackend != "native" {
+		t.Skip("only supported on linux with the native backend")
+	}
+	withTestTerminal("signalprog", t, func(term *FakeTerminal) {
+		out := term.MustExec("handle USR1")
+		if !strings.Contains(out, "SIGUSR1  10      No    No     Yes") {
+			t.Fatalf("wrong default policy:\n%s", out)
+		}
+
+		if _, err := term.Exec("handle SIGSEGV nopass"); err == nil {
+			t.Fatal("expected error discarding SIGSEGV without stopping")
+		}
+		if _, err := term.Exec("handle SIGTRAP stop"); err == nil {
+			t.Fatal("expected error changing the policy of SIGTRAP")
+		}
+
+		out = term.MustExec("handle 10 stop")
+		if !strings.Contains(out, "SIGUSR1  10      Yes   Yes    Yes") {
+			t.Fatalf("wrong policy:\n%s", out)
+		}
+
+		out = term.MustExec("continue")
+		if !strings.Contains(out, "received signal SIGUSR1") || strings.Contains(out, "Thread 0 ") {
+			t.Fatalf("signal not reported:\n%s", out)
+		}
+		out, err := term.Exec("continue")
+		if err == nil || !strings.Contains(err.Error(), "has exited with status 0") {
+			t.Fatalf("unexpected output: %q %v", out, err)
+		}
+	})
+}
+
 func TestCreateBreakpointWithCondition2(t *testing.T) {
 	withTestTerminal("break", t, func(term *FakeTerminal) {
 		term.MustExec("continue main.main:4")
@@ -1589,6 +2157,35 @@ func TestListPackages(t *testing.T) {
 	})
 }
 
+func TestSourcesByPackage(t *testing.T) {
+	test.AllowRecording(t)
+	withTestTerminal("goroutinestackprog", t, func(term *FakeTerminal) {
+		out := term.MustExec("sources -pkg ^main$")
+		t.Logf("> sources -pkg ^main$\n%s", out)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) < 2 || lines[0] != "main" {
+			t.Fatalf("wrong output %q", out)
+		}
+		found := false
+		for _, line := range lines[1:] {
+			if !strings.HasPrefix(line, "\t") {
+				t.Errorf("unexpected package %q", line)
+			}
+			if strings.HasSuffix(line, "goroutinestackprog.go") {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("output omits goroutinestackprog.go")
+		}
+
+		out = term.MustExec("sources -pkg")
+		if !strings.Contains(out, "\nruntime\n") {
+			t.Error("output omits package 'runtime'")
+		}
+	})
+}
+
 func TestSubstitutePathAndList(t *testing.T) {
 	// checks that substitute path rules do not remain cached after a -clear.
 	// See issue #3565.
@@ -1636,6 +2233,41 @@ func TestDisplay(t *testing.T) {
 	})
 }
 
+func TestDisplayChange(t *testing.T) {
+	withTestTerminal("testnextprog", t, func(term *FakeTerminal) {
+		term.MustExec("break testnextprog.go:24")
+		term.MustExec("continue")
+		out := term.MustExec("display -a -change i")
+		if !strings.Contains(out, "0: i = 0") {
+			t.Errorf("wrong output for 'display -a -change i': %q", out)
+		}
+		term.MustExec("display -a j")
+		out = term.MustExec("next")
+		t.Logf("next: %q", out)
+		if strings.Contains(out, "0: i =") {
+			t.Errorf("unchanged display printed after next: %q", out)
+		}
+		if !strings.Contains(out, "1: j = 1") {
+			t.Errorf("display not printed after next: %q", out)
+		}
+		out = term.MustExec("display")
+		if !strings.Contains(out, "0: i = 0") || !strings.Contains(out, "1: j = 1") {
+			t.Errorf("wrong output for 'display': %q", out)
+		}
+		out = term.MustExec("continue")
+		t.Logf("continue: %q", out)
+		if !strings.Contains(out, "0: i = 1") {
+			t.Errorf("changed display not printed after continue: %q", out)
+		}
+		term.MustExec("clear 1")
+		out = term.MustExec("stepout")
+		t.Logf("stepout: %q", out)
+		if !strings.Contains(out, "0: i = error") {
+			t.Errorf("display of out of scope variable not printed after stepout: %q", out)
+		}
+	})
+}
+
 // compareBreakpoints compares two lists of breakpoints, considering only
 // the fields that are preserved during save/restore. It ignores runtime state like
 // IDs, names, addresses, and hit counts.
@@ -1690,6 +2322,18 @@ func compareBreakpoints(t *testing.T, before, after []*api.Breakpoint) {
 		if b.Disabled != a.Disabled {
 			t.Errorf("Breakpoint %d: Disabled mismatch: before=%v, after=%v", i, b.Disabled, a.Disabled)
 		}
+		if b.Name != "" && b.Name != a.Name {
+			t.Errorf("Breakpoint %d: Name mismatch: before=%q, after=%q", i, b.Name, a.Name)
+		}
+		if b.TraceReturn != a.TraceReturn {
+			t.Errorf("Breakpoint %d: TraceReturn mismatch: before=%v, after=%v", i, b.TraceReturn, a.TraceReturn)
+		}
+		if b.Stacktrace != a.Stacktrace || b.Goroutine != a.Goroutine || !slices.Equal(b.Variables, a.Variables) {
+			t.Errorf("Breakpoint %d: attributes mismatch: before=%v %v %v, after=%v %v %v", i, b.Stacktrace, b.Goroutine, b.Variables, a.Stacktrace, a.Goroutine, a.Variables)
+		}
+		if (b.LoadArgs == nil) != (a.LoadArgs == nil) || (b.LoadArgs != nil && *b.LoadArgs != *a.LoadArgs) {
+			t.Errorf("Breakpoint %d: LoadArgs mismatch: before=%v, after=%v", i, b.LoadArgs, a.LoadArgs)
+		}
 	}
 }
 
@@ -1725,6 +2369,24 @@ func TestBreakpointSave(t *testing.T) {
 				"trace main.main",
 			},
 		},
+		{
+			name: "breakpoint attributes",
+			breakpointCmds: []string{
+				"break foo main.main:4 if i == 3",
+				"condition -hitcount foo > 2",
+				"on foo print i",
+				"on foo stack 4",
+				"on foo goroutine",
+				"on foo args -v",
+			},
+		},
+		{
+			name: "unnamed tracepoint with condition",
+			breakpointCmds: []string{
+				"trace main.main:2",
+				"condition 1 i > 5",
+			},
+		},
 		{
 			name:           "empty breakpoint list",
 			breakpointCmds: []string{},
@@ -1802,6 +2464,48 @@ func TestBreakpointSave(t *testing.T) {
 	})
 }
 
+func TestBreakpointsSaveLocExpr(t *testing.T) {
+	// The location expressions typed by the user are saved, rather than the
+	// resolved location, unless they are relative to the current position.
+	withTestTerminal("break", t, func(term *FakeTerminal) {
+		term.MustExec("break main.main")
+		term.MustExec("break break.go:6")
+		term.MustExec("continue")
+		term.MustExec("break +1")
+
+		f, err := os.CreateTemp("", "test*.txt")
+		if err != nil {
+			t.Fatalf("Failed to create temp file: %v", err)
+		}
+		f.Close()
+		defer os.Remove(f.Name())
+
+		term.MustExec("breakpoints -save " + f.Name())
+		buf, err := os.ReadFile(f.Name())
+		assertNoError(t, err, "ReadFile")
+		saved := string(buf)
+		t.Logf("saved breakpoints:\n%s", saved)
+		for _, tgt := range []string{"break bp1 main.main\n", "break bp2 break.go:6\n"} {
+			if !strings.Contains(saved, tgt) {
+				t.Errorf("expected %q in saved file", tgt)
+			}
+		}
+		if strings.Contains(saved, "+1") {
+			t.Errorf("relative location expression saved")
+		}
+
+		// The location expressions of cleared breakpoints are forgotten
+		term.MustExec("clear 1")
+		if _, ok := term.bpLocExprs[1]; ok {
+			t.Errorf("location expression of breakpoint 1 not removed by clear")
+		}
+		term.MustExec("clearall")
+		if len(term.bpLocExprs) != 0 {
+			t.Errorf("location expressions not removed by clearall: %v", term.bpLocExprs)
+		}
+	})
+}
+
 func TestBreakPointFailWithCond(t *testing.T) {
 	if runtime.GOOS == "freebsd" || runtime.GOOS == "darwin" {
 		t.Skip("follow exec not implemented")
@@ -1994,3 +2698,205 @@ func TestCommandPromptExpansion(t *testing.T) {
 		}
 	})
 }
+
+func TestWatchpointCondition(t *testing.T) {
+	if (runtime.GOOS != "linux" && runtime.GOOS != "darwin") || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64") {
+		t.Skip("watchpoints not supported")
+	}
+	withTestTerminal("databpcountstest", t, func(term *FakeTerminal) {
+		term.MustExec("break main.main")
+		term.MustExec("continue")
+		term.MustExec("watch -w globalvar1 if globalvar1 == 42")
+		term.MustExec("continue")
+		out := term.MustExec("print globalvar1")
+		if out != "42\n" {
+			t.Fatalf("wrong value of globalvar1: %q", out)
+		}
+	})
+}
+
+func TestWatchpointScopeFollow(t *testing.T) {
+	if (runtime.GOOS != "linux" && runtime.GOOS != "darwin") || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64") {
+		t.Skip("watchpoints not supported")
+	}
+	withTestTerminal("databpfollow", t, func(term *FakeTerminal) {
+		term.MustExec("break databpfollow.go:7")
+		term.MustExec("continue")
+		term.MustExec("watch -w -scope follow x")
+		term.MustExec("clear 1")
+		ns := map[string]bool{}
+		for range 20 {
+			out, err := term.Exec("continue")
+			if err != nil || strings.Contains(out, "went out of scope") {
+				break
+			}
+			if n, err := term.Exec("print n"); err == nil {
+				ns[strings.TrimSpace(n)] = true
+			}
+		}
+		if !ns["2"] || !ns["3"] {
+			t.Fatalf("watchpoint was not re-armed on the second call (stopped with n=%v)", ns)
+		}
+	})
+}
+
+func TestBreakpointLineRange(t *testing.T) {
+	withTestTerminal("testnextprog", t, func(term *FakeTerminal) {
+		out := term.MustExec("break testnextprog.go:10-16")
+		t.Logf("%q", out)
+		for _, line := range []int{10, 11, 13, 14, 15} {
+			if !strings.Contains(out, fmt.Sprintf("testnextprog.go:%d\n", line)) {
+				t.Errorf("no breakpoint set on line %d", line)
+			}
+		}
+		if !strings.HasSuffix(out, "\n5 breakpoints set\n") {
+			t.Errorf("wrong number of breakpoints set")
+		}
+		out = term.MustExec("continue")
+		if !strings.Contains(out, "testnextprog.go:10 ") {
+			t.Errorf("wrong stop location: %q", out)
+		}
+	})
+}
+
+func TestDefersCommand(t *testing.T) {
+	withTestTerminal("deferstack", t, func(term *FakeTerminal) {
+		term.MustExec("continue")
+		out := term.MustExec("defers")
+		t.Logf("%q", out)
+		rx := regexp.MustCompile(`(?m)^\d+  0x[0-9a-f]+ in (main\.f\d)$`)
+		var got []string
+		for _, m := range rx.FindAllStringSubmatch(out, -1) {
+			got = append(got, m[1])
+		}
+		if tgt := []string{"main.f2", "main.f3", "main.f1", "main.f2"}; !slices.Equal(got, tgt) {
+			t.Errorf("wrong deferred calls, expected %v got %v", tgt, got)
+		}
+		if !strings.Contains(out, "deferred by main.call2 at ") || !strings.Contains(out, "deferred by main.call1 at ") {
+			t.Errorf("missing defer location")
+		}
+	})
+}
+
+func TestPanicCommand(t *testing.T) {
+	withTestTerminal("panicchain", t, func(term *FakeTerminal) {
+		term.MustExec("continue")
+		out := term.MustExec("panic")
+		t.Logf("%q", out)
+		rx := regexp.MustCompile(`(?m)^(\d+)  panic: (.*)\n +running deferred calls of frame \d+ (main\.\w+) at `)
+		var got []string
+		for _, m := range rx.FindAllStringSubmatch(out, -1) {
+			got = append(got, m[1]+" "+m[2]+" "+m[3])
+		}
+		tgt := []string{
+			`0 interface {}(*errors.errorString) *{s: "second"} main.g`,
+			`1 interface {}(string) "first" main.f`,
+		}
+		if !slices.Equal(got, tgt) {
+			t.Errorf("wrong panics, expected %q got %q", tgt, got)
+		}
+		if !strings.Contains(out, "Pending deferred calls:\n0  ") {
+			t.Errorf("missing pending deferred calls")
+		}
+
+		term.MustExec("continue")
+		out = term.MustExec("panic")
+		t.Logf("%q", out)
+		if !strings.HasPrefix(out, "0  panic: interface {}(string) \"first\" (recovered)\n   recovered by frame 2 main.f at ") {
+			t.Errorf("wrong output after recover")
+		}
+
+		term.Exec("continue")
+		out, err := term.Exec("panic")
+		if err == nil {
+			t.Errorf("expected error after the program exited, got %q", out)
+		}
+	})
+}
+
+func TestWhatisVerbose(t *testing.T) {
+	withTestTerminal("testvariables2", t, func(term *FakeTerminal) {
+		term.MustExec("continue")
+		out := term.MustExec("whatis -verbose namedA1")
+		t.Logf("%s", out)
+		for _, tgt := range []string{
+			"main.astructName1\n",
+			"\nmain.astructName1 (struct) size=16\n",
+			"  A int (int) offset=0 size=8\n",
+			"  B int (int) offset=8 size=8\n",
+		} {
+			if !strings.Contains(out, tgt) {
+				t.Errorf("output does not contain %q", tgt)
+			}
+		}
+		out = term.MustExec("whatis -verbose []main.astruct")
+		t.Logf("%s", out)
+		if !strings.Contains(out, "  array *main.astruct (ptr) offset=0 size=8\n") || !strings.Contains(out, "  cap int (int) offset=16 size=8\n") {
+			t.Errorf("wrong output for slice type")
+		}
+	})
+}
+
+func TestErrorsCommand(t *testing.T) {
+	withTestTerminal("errchain", t, func(term *FakeTerminal) {
+		term.MustExec("continue")
+		out := term.MustExec("errors wrapped2")
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 3 || !strings.HasPrefix(lines[0], "*fmt.wrapError ") || !strings.HasPrefix(lines[1], "  *fmt.wrapError ") || lines[2] != `    *errors.errorString {s: "base error"}` {
+			t.Errorf("wrong output for wrapped2")
+		}
+
+		out = term.MustExec("errors multi")
+		for _, tgt := range []string{
+			"\n  *fmt.wrapError ",
+			"\n    *errors.errorString {s: \"base error\"}\n",
+			"\n  *io/fs.PathError {Op: \"open\", ",
+			"\n    syscall.Errno ENOENT (2)\n",
+		} {
+			if !strings.Contains(out, tgt) {
+				t.Errorf("output does not contain %q", tgt)
+			}
+		}
+
+		term.AssertExec("errors nilerr", "error nil\n")
+		term.AssertExecError("errors base.s", "base.s is not an interface")
+	})
+}
+
+func TestMappingsCommand(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("only implemented on linux")
+	}
+	withTestTerminal("testvariables2", t, func(term *FakeTerminal) {
+		term.MustExec("continue")
+		out := term.MustExec("mappings")
+		t.Logf("%s", out)
+		if !strings.Contains(out, "r-x") || !strings.Contains(out, "[stack]") {
+			t.Errorf("wrong output for mappings")
+		}
+	})
+}
+
+func TestClearAllFunction(t *testing.T) {
+	withTestTerminal("continuetestprog", t, func(term *FakeTerminal) {
+		term.MustExec("break main.main")
+		term.MustExec("break main.sayhi")
+		term.MustExec("break continuetestprog.go:9")
+		term.MustExec("break continuetestprog.go:13")
+		out := term.MustExec("clearall -fn ^main\\.s")
+		t.Logf("%s", out)
+		if !strings.HasSuffix(out, "3 breakpoint(s) cleared\n") {
+			t.Errorf("wrong number of breakpoints cleared")
+		}
+		out = term.MustExec("breakpoints")
+		if !strings.Contains(out, "main.main()") || strings.Contains(out, "main.sayhi()") || strings.Contains(out, "main.sleepytime()") {
+			t.Errorf("wrong breakpoints left: %s", out)
+		}
+		term.AssertExecError("clearall -fn (", "invalid regular expression \"(\": error parsing regexp: missing closing ): `(`")
+		term.AssertExecError("clearall -fn regexp:(", "invalid regular expression \"(\": error parsing regexp: missing closing ): `(`")
+		out = term.MustExec("clearall -fn regexp:^main\\.")
+		if !strings.HasSuffix(out, "1 breakpoint(s) cleared\n") {
+			t.Errorf("wrong number of breakpoints cleared: %s", out)
+		}
+	})
+}
@@ -27,6 +27,8 @@ func configureCmd(t *Term, ctx callContext, args string) error {
 		if t.client != nil { // only happens in tests
 			lcfg := t.loadConfig()
 			t.client.SetReturnValuesLoadConfig(&lcfg)
+			t.client.SetStepSkipNoDebug(t.conf.StepSkipNoDebug)
+			t.client.SetSingleGoroutineStepping(t.conf.SingleGoroutineStepping)
 			t.updateConfig()
 		}
 		return nil
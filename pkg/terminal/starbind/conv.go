@@ -13,7 +13,7 @@ import (
 )
 
 // autoLoadConfig is the load configuration used to automatically load more from a variable
-var autoLoadConfig = api.LoadConfig{MaxVariableRecurse: 1, MaxStringLen: 1024, MaxArrayValues: 64, MaxStructFields: -1}
+var autoLoadConfig = api.LoadConfig{MaxVariableRecurse: 1, MaxStringLen: 1024, MaxArrayValues: 64, PerDimension: true, MaxStructFields: -1}
 
 // interfaceToStarlarkValue converts an interface{} variable (produced by
 // decoding JSON) into a starlark.Value.
@@ -64,6 +64,10 @@ type Term struct {
 	displays []displayEntry
 	oldPid   int
 
+	// ContinueOnStart causes Run to resume the target process after
+	// executing InitFile.
+	ContinueOnStart bool
+
 	stackTraceColors api.StackTraceColors
 
 	historyFile *os.File
@@ -72,6 +76,14 @@ type Term struct {
 
 	substitutePathRulesCache [][2]string
 
+	// bpLocExprs maps the ID of a breakpoint to the location expression, as
+	// typed by the user, that was used to create it. It is used by
+	// 'breakpoints -save'.
+	bpLocExprs map[int]string
+
+	// listPos is the range of source lines printed last
+	listPos listPosition
+
 	// quitContinue is set to true by exitCommand to signal that the process
 	// should be resumed before quitting.
 	quitContinue bool
@@ -84,6 +96,7 @@ type Term struct {
 
 	traceNonInteractive bool
 	TraceVerbosity      int // Verbosity level for trace output (0-4)
+	traceJSON           *traceJSONWriter
 
 	downloadsMu         sync.Mutex
 	downloadsInProgress bool
@@ -96,6 +109,19 @@ type Term struct {
 type displayEntry struct {
 	expr   string
 	fmtstr string
+
+	onlyChanged bool   // only print when the output changes
+	last        string // last output printed
+}
+
+// listPosition is a range of lines of a source file printed by the list
+// command.
+type listPosition struct {
+	file       string
+	start, end int // range of lines printed, end is exclusive
+	size       int // number of lines requested
+	lines      int // number of lines in the file
+	arrowLine  int
 }
 
 // New returns a new Term.
@@ -115,6 +141,8 @@ func New(client service.Client, conf *config.Config) *Term {
 		line:   liner.NewLiner(),
 		cmds:   cmds,
 		stdout: &transcriptWriter{pw: &pagingWriter{w: os.Stdout}},
+
+		bpLocExprs: make(map[int]string),
 	}
 	t.line.SetCtrlZStop(true)
 
@@ -129,6 +157,8 @@ func New(client service.Client, conf *config.Config) *Term {
 	if client != nil {
 		lcfg := t.loadConfig()
 		client.SetReturnValuesLoadConfig(&lcfg)
+		client.SetStepSkipNoDebug(t.conf.StepSkipNoDebug)
+		client.SetSingleGoroutineStepping(t.conf.SingleGoroutineStepping)
 		if state, err := client.GetState(); err == nil {
 			t.oldPid = state.Pid
 		}
@@ -169,6 +199,12 @@ func New(client service.Client, conf *config.Config) *Term {
 				fmt.Fprintf(t.stdout, "Breakpoint %d materialized at %s:%d%s\n", bp.ID, file, bp.Line, extra)
 			case api.EventProcessSpawned:
 				fmt.Fprintf(t.stdout, "Spawned new process '%s' (%d)\n", event.Cmdline, event.ProcessSpawnedEventDetails.PID)
+			case api.EventSignal:
+				name := event.SignalEventDetails.Name
+				if name == "" {
+					name = strconv.Itoa(event.Signal)
+				}
+				fmt.Fprintf(t.stdout, "Thread %d received signal %s\n", event.SignalEventDetails.Thread, name)
 			}
 		})
 	}
@@ -405,6 +441,15 @@ func (t *Term) Run() (int, error) {
 		}
 	}
 
+	if t.ContinueOnStart {
+		if err := t.cmds.Call("continue", t); err != nil {
+			if _, ok := err.(ExitRequestError); ok {
+				return t.handleExit()
+			}
+			fmt.Fprintf(os.Stderr, "Command failed: %s\n", err)
+		}
+	}
+
 	var lastCmd string
 
 	// Ensure that the target process is neither running nor recording by
@@ -611,7 +656,7 @@ func (t *Term) handleExit() (int, error) {
 // loadConfig returns an api.LoadConfig with the parameters specified in
 // the configuration file.
 func (t *Term) loadConfig() api.LoadConfig {
-	r := api.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, MaxStructFields: -1}
+	r := api.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, PerDimension: true, MaxStructFields: -1}
 
 	if t.conf != nil && t.conf.MaxStringLen != nil {
 		r.MaxStringLen = *t.conf.MaxStringLen
@@ -622,6 +667,10 @@ func (t *Term) loadConfig() api.LoadConfig {
 	if t.conf != nil && t.conf.MaxVariableRecurse != nil {
 		r.MaxVariableRecurse = *t.conf.MaxVariableRecurse
 	}
+	if t.conf != nil {
+		r.LoadChanBuffer = t.conf.LoadChanBuffer
+		r.LoadSyncMap = t.conf.LoadSyncMap
+	}
 
 	return r
 }
@@ -630,7 +679,7 @@ func (t *Term) removeDisplay(n int) error {
 	if n < 0 || n >= len(t.displays) {
 		return fmt.Errorf("%d is out of range", n)
 	}
-	t.displays[n] = displayEntry{"", ""}
+	t.displays[n] = displayEntry{}
 	for i := len(t.displays) - 1; i >= 0; i-- {
 		if t.displays[i].expr != "" {
 			t.displays = t.displays[:i+1]
@@ -641,33 +690,45 @@ func (t *Term) removeDisplay(n int) error {
 	return nil
 }
 
-func (t *Term) addDisplay(expr, fmtstr string) {
-	t.displays = append(t.displays, displayEntry{expr: expr, fmtstr: fmtstr})
+func (t *Term) addDisplay(expr, fmtstr string, onlyChanged bool) {
+	t.displays = append(t.displays, displayEntry{expr: expr, fmtstr: fmtstr, onlyChanged: onlyChanged})
 }
 
-func (t *Term) printDisplay(i int) {
-	expr, fmtstr := t.displays[i].expr, t.displays[i].fmtstr
-	val, err := t.client.EvalVariable(api.EvalScope{GoroutineID: -1}, expr, ShortLoadConfig)
+// printDisplay evaluates and prints the i-th display expression. If the
+// display was added with the -change option and force is false nothing is
+// printed unless the output is different from the last time it was
+// printed. Expressions that can not be evaluated in the current scope
+// (for example because they reference local variables of a different
+// function) print an error but are not removed from the list.
+func (t *Term) printDisplay(i int, force bool) {
+	d := &t.displays[i]
+	var out string
+	val, err := t.client.EvalVariable(api.EvalScope{GoroutineID: -1}, d.expr, ShortLoadConfig)
 	if err != nil {
 		if isErrProcessExited(err) {
 			return
 		}
-		fmt.Fprintf(t.stdout, "%d: %s = error %v\n", i, expr, err)
+		out = fmt.Sprintf("%d: %s = error %v\n", i, d.expr, err)
+	} else {
+		out = fmt.Sprintf("%d: %s = %s\n", i, val.Name, val.StringWithOptions("", d.fmtstr, api.PrettyBytesPreview))
+	}
+	if d.onlyChanged && !force && out == d.last {
 		return
 	}
-	fmt.Fprintf(t.stdout, "%d: %s = %s\n", i, val.Name, val.StringWithOptions("", fmtstr, 0))
+	d.last = out
+	fmt.Fprint(t.stdout, out)
 }
 
-func (t *Term) printDisplays() {
+func (t *Term) printDisplays(force bool) {
 	for i := range t.displays {
 		if t.displays[i].expr != "" {
-			t.printDisplay(i)
+			t.printDisplay(i, force)
 		}
 	}
 }
 
 func (t *Term) onStop() {
-	t.printDisplays()
+	t.printDisplays(false)
 	t.cmds.executeBreakpointCustomCommands(t)
 }
 
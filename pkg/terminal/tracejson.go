@@ -0,0 +1,96 @@
+package terminal
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-delve/delve/service/api"
+)
+
+// TraceEvent is the JSON representation of a tracepoint hit, written one
+// per line by 'dlv trace --output-format=json'.
+type TraceEvent struct {
+	// Seq is a sequence number that is incremented for every event written,
+	// it can be used to order events produced by different goroutines.
+	Seq         uint64 `json:"seq"`
+	Time        string `json:"time"`
+	GoroutineID int64  `json:"goroutineID"`
+	Function    string `json:"function"`
+	// Return is true if the event was produced by a function returning,
+	// in which case ReturnValues is set instead of Args.
+	Return       bool              `json:"return,omitempty"`
+	Args         []TraceEventVar   `json:"args,omitempty"`
+	ReturnValues []TraceEventVar   `json:"returnValues,omitempty"`
+	Stack        []TraceEventFrame `json:"stack,omitempty"`
+}
+
+// TraceEventVar is a function argument or return value of a TraceEvent.
+type TraceEventVar struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// TraceEventFrame is a stack frame of a TraceEvent.
+type TraceEventFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+type traceJSONWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	seq uint64
+}
+
+// SetTraceJSONOutput causes tracepoint hits to be written to w as newline
+// delimited JSON objects (see TraceEvent) instead of being printed as
+// text.
+func (t *Term) SetTraceJSONOutput(w io.Writer) {
+	t.traceJSON = &traceJSONWriter{enc: json.NewEncoder(w)}
+}
+
+// WriteTraceEvent writes a tracepoint hit to the JSON output configured
+// with SetTraceJSONOutput. It is safe to call concurrently.
+func (t *Term) WriteTraceEvent(goroutineID int64, fnname string, isRet bool, args, retvals []api.Variable, stack []api.Stackframe) error {
+	w := t.traceJSON
+	if w == nil {
+		return nil
+	}
+	ev := TraceEvent{
+		Time:         time.Now().Format(time.RFC3339Nano),
+		GoroutineID:  goroutineID,
+		Function:     fnname,
+		Return:       isRet,
+		Args:         traceEventVars(args),
+		ReturnValues: traceEventVars(retvals),
+	}
+	for _, frame := range stack {
+		ev.Stack = append(ev.Stack, TraceEventFrame{Function: frame.Function.Name(), File: frame.File, Line: frame.Line})
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.seq++
+	ev.Seq = w.seq
+	return w.enc.Encode(&ev)
+}
+
+func traceEventVars(vars []api.Variable) []TraceEventVar {
+	if len(vars) == 0 {
+		return nil
+	}
+	r := make([]TraceEventVar, 0, len(vars))
+	for i := range vars {
+		v := &vars[i]
+		val := v.SinglelineString()
+		if v.Unreadable != "" {
+			val = "(unreadable " + v.Unreadable + ")"
+		}
+		r = append(r, TraceEventVar{Name: v.Name, Type: v.Type, Value: val})
+	}
+	return r
+}
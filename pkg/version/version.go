@@ -23,12 +23,22 @@ var DelveVersion = Version{
 }
 
 func (v Version) String() string {
-	fixBuild(&v)
-	ver := fmt.Sprintf("Version: %s.%s.%s", v.Major, v.Minor, v.Patch)
+	return fmt.Sprintf("Version: %s\nBuild: %s", v.Number(), v.BuildID())
+}
+
+// Number returns the version number of v, for example "1.2.3-rc".
+func (v Version) Number() string {
+	ver := fmt.Sprintf("%s.%s.%s", v.Major, v.Minor, v.Patch)
 	if v.Metadata != "" {
 		ver += "-" + v.Metadata
 	}
-	return fmt.Sprintf("%s\nBuild: %s", ver, v.Build)
+	return ver
+}
+
+// BuildID returns the VCS revision Delve was built from.
+func (v Version) BuildID() string {
+	fixBuild(&v)
+	return v.Build
 }
 
 var buildInfo = func() string {
@@ -13,6 +13,8 @@ const (
 	PrintGoroutinesStack PrintGoroutinesFlags = 1 << iota
 	PrintGoroutinesLabels
 	PrintGoroutinesExec
+	PrintGoroutinesSortBlocked
+	PrintGoroutinesUserOnly
 )
 
 type FormatGoroutineLoc int
@@ -58,6 +60,9 @@ func ParseGoroutineArgs(argstr string) ([]ListGoroutinesFilter, GoroutineGroupin
 			fgl = FglStart
 		case "-l":
 			flags |= PrintGoroutinesLabels
+		case "-user":
+			flags |= PrintGoroutinesUserOnly
+			filters = append(filters, ListGoroutinesFilter{Kind: GoroutineUser})
 		case "-t":
 			flags |= PrintGoroutinesStack
 			// optional depth argument
@@ -107,6 +112,14 @@ func ParseGoroutineArgs(argstr string) ([]ListGoroutinesFilter, GoroutineGroupin
 			}
 			filters = append(filters, ListGoroutinesFilter{Kind: GoroutineWaitingOnChannel, Arg: args[i]})
 
+		case "-sort":
+			i++
+			if i >= len(args) || args[i] != "blocked" {
+				return nil, GoroutineGroupingOptions{}, 0, 0, 0, 0, "", errors.New("-sort must be followed by 'blocked'")
+			}
+			flags |= PrintGoroutinesSortBlocked
+			batchSize = 0 // sorting only works if run on all goroutines
+
 		case "-exec":
 			flags |= PrintGoroutinesExec
 			cmd = strings.Join(args[i+1:], " ")
@@ -118,6 +131,13 @@ func ParseGoroutineArgs(argstr string) ([]ListGoroutinesFilter, GoroutineGroupin
 			return nil, GoroutineGroupingOptions{}, 0, 0, 0, 0, "", fmt.Errorf("wrong argument: '%s'", arg)
 		}
 	}
+	if flags&PrintGoroutinesUserOnly != 0 {
+		for _, filter := range filters {
+			if filter.Kind == GoroutineUser && filter.Negated {
+				return nil, GoroutineGroupingOptions{}, 0, 0, 0, 0, "", errors.New("-user can not be used with -without user")
+			}
+		}
+	}
 	return filters, group, fgl, flags, depth, batchSize, cmd, nil
 }
 
@@ -141,6 +161,8 @@ func readGoroutinesFilterKind(args []string, i int) (GoroutineField, error) {
 		return GoroutineRunning, nil
 	case "user":
 		return GoroutineUser, nil
+	case "reason":
+		return GoroutineWaitReason, nil
 	default:
 		return GoroutineFieldNone, fmt.Errorf("unrecognized argument to %s %s", args[i-1], args[i])
 	}
@@ -161,12 +183,54 @@ func readGoroutinesFilter(args []string, pi *int) (*ListGoroutinesFilter, error)
 	if *pi+1 >= len(args) {
 		return nil, fmt.Errorf("%s %s needs to be followed by an expression", args[*pi-1], args[*pi])
 	}
+	if r.Kind == GoroutineWaitReason {
+		// wait reasons contain spaces, consume everything up to the next option
+		j := *pi + 1
+		for j < len(args) && !strings.HasPrefix(args[j], "-") {
+			j++
+		}
+		r.Arg = strings.Join(args[*pi+1:j], " ")
+		*pi = j - 1
+		return r, nil
+	}
 	r.Arg = args[*pi+1]
 	*pi++
 
 	return r, nil
 }
 
+// formatNames maps the names accepted by the -fmt option of examinemem and
+// print to format characters.
+var formatNames = map[string]byte{
+	"oct":         'o',
+	"octal":       'o',
+	"hex":         'x',
+	"hexadecimal": 'x',
+	"dec":         'd',
+	"decimal":     'd',
+	"bin":         'b',
+	"binary":      'b',
+	"char":        'c',
+	"inst":        'i',
+}
+
+// ParsePrintFormat converts the name of a format, as accepted by the -fmt
+// option of the print command, into a format specifier for
+// Variable.StringWithOptions.
+func ParsePrintFormat(name string) (string, error) {
+	if name == "str" || name == "string" {
+		return "%s", nil
+	}
+	switch f, ok := formatNames[name]; {
+	case !ok || f == 'i':
+		return "", fmt.Errorf("%q is not a valid format", name)
+	case f == 'x' || f == 'o' || f == 'b':
+		return "%#" + string(f), nil
+	default:
+		return "%" + string(f), nil
+	}
+}
+
 type ExamineMemoryArgs struct {
 	Operand string
 	Count   int64
@@ -191,6 +255,20 @@ func ParseExamineMemoryArg(argstr string) (*ExamineMemoryArgs, error) {
 		args = strings.Split(argstr, " ")
 	)
 
+	// gdb style: /<count><format><size> <expression>
+	if strings.HasPrefix(argstr, "/") {
+		spec, rest, _ := strings.Cut(argstr, " ")
+		if err := parseExamineMemorySpec(spec[1:], &out); err != nil {
+			return nil, err
+		}
+		out.IsExpr = true
+		out.Operand = strings.TrimSpace(rest)
+		if len(out.Operand) == 0 {
+			return nil, errors.New("no address specified")
+		}
+		return &out, nil
+	}
+
 	// nextArg returns the next argument that is not an empty string, if any, and
 	// advances the args slice to the position after that.
 	nextArg := func() string {
@@ -218,17 +296,7 @@ loop:
 			if arg == "raw" {
 				out.RawOut = true
 			} else {
-				fmtMapToPriFmt := map[string]byte{
-					"oct":         'o',
-					"octal":       'o',
-					"hex":         'x',
-					"hexadecimal": 'x',
-					"dec":         'd',
-					"decimal":     'd',
-					"bin":         'b',
-					"binary":      'b',
-				}
-				out.Format, ok = fmtMapToPriFmt[arg]
+				out.Format, ok = formatNames[arg]
 				if !ok {
 					return nil, fmt.Errorf("%q is not a valid format", arg)
 				}
@@ -270,6 +338,47 @@ loop:
 	if len(out.Operand) == 0 {
 		return nil, errors.New("no address specified")
 	}
+	if out.Format == 'c' {
+		out.Size = 1
+	}
 
 	return &out, nil
 }
+
+// parseExamineMemorySpec parses a gdb style format specifier, composed of
+// an optional count followed by format and size letters in any order.
+func parseExamineMemorySpec(spec string, out *ExamineMemoryArgs) error {
+	i := 0
+	for i < len(spec) && spec[i] >= '0' && spec[i] <= '9' {
+		i++
+	}
+	if i > 0 {
+		var err error
+		out.Count, err = strconv.ParseInt(spec[:i], 10, 64)
+		if err != nil || out.Count <= 0 {
+			return errors.New("count must be a positive integer")
+		}
+	}
+	for _, ch := range spec[i:] {
+		switch ch {
+		case 'x', 'd', 'o', 'c', 'i':
+			out.Format = byte(ch)
+		case 't':
+			out.Format = 'b'
+		case 'b':
+			out.Size = 1
+		case 'h':
+			out.Size = 2
+		case 'w':
+			out.Size = 4
+		case 'g':
+			out.Size = 8
+		default:
+			return fmt.Errorf("invalid format specifier %q", ch)
+		}
+	}
+	if out.Format == 'c' {
+		out.Size = 1
+	}
+	return nil
+}
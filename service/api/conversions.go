@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-delve/delve/pkg/dwarf/godwarf"
 	"github.com/go-delve/delve/pkg/dwarf/op"
@@ -23,6 +24,7 @@ func ConvertLogicalBreakpoint(lbp *proc.LogicalBreakpoint) *Breakpoint {
 		Name:             lbp.Name,
 		Tracepoint:       lbp.Tracepoint,
 		TraceReturn:      lbp.TraceReturn,
+		TraceEval:        lbp.TraceEval,
 		Stacktrace:       lbp.Stacktrace,
 		Goroutine:        lbp.Goroutine,
 		Variables:        lbp.Variables,
@@ -34,6 +36,7 @@ func ConvertLogicalBreakpoint(lbp *proc.LogicalBreakpoint) *Breakpoint {
 		UserData:         lbp.UserData,
 		RootFuncName:     lbp.RootFuncName,
 		TraceFollowCalls: lbp.TraceFollowCalls,
+		WatchFollow:      lbp.WatchFollow,
 	}
 
 	b.HitCount = map[string]uint64{}
@@ -43,9 +46,17 @@ func ConvertLogicalBreakpoint(lbp *proc.LogicalBreakpoint) *Breakpoint {
 
 	b.HitCond = lbp.HitCond()
 	b.HitCondPerG = lbp.HitCondPerG
+	b.GoroutineLabels = lbp.GoroutineLabels
 
 	b.Cond = lbp.Cond()
 
+	if fw := lbp.Set.FieldWatch; fw != nil {
+		b.WatchExpr = fw.Type + "." + fw.Field
+		b.WatchType = WatchType(fw.WatchType)
+		b.WatchField = true
+		b.WatchMax = fw.Max
+	}
+
 	return b
 }
 
@@ -58,8 +69,10 @@ func ConvertPhysicalBreakpoints(b *Breakpoint, lbp *proc.LogicalBreakpoint, pids
 		return
 	}
 
-	b.WatchExpr = bps[0].WatchExpr
-	b.WatchType = WatchType(bps[0].WatchType)
+	if !b.WatchField {
+		b.WatchExpr = bps[0].WatchExpr
+		b.WatchType = WatchType(bps[0].WatchType)
+	}
 
 	lg := false
 	for i, bp := range bps {
@@ -110,6 +123,18 @@ func ConvertThread(th proc.Thread, bp *Breakpoint) *Thread {
 		gid = g.ID
 	}
 
+	var exception *ExceptionInfo
+	if e := th.Common().Exception; e != nil {
+		exception = &ExceptionInfo{
+			Code:         e.Code,
+			Description:  e.Description,
+			Address:      e.Address,
+			FirstChance:  e.FirstChance,
+			FaultAddress: e.FaultAddress,
+			Operation:    e.Operation,
+		}
+	}
+
 	return &Thread{
 		ID:          th.ThreadID(),
 		PC:          pc,
@@ -118,6 +143,7 @@ func ConvertThread(th proc.Thread, bp *Breakpoint) *Thread {
 		Function:    function,
 		GoroutineID: gid,
 		Breakpoint:  bp,
+		Exception:   exception,
 	}
 }
 
@@ -298,6 +324,10 @@ func ConvertGoroutine(tgt *proc.Target, g *proc.G) *Goroutine {
 	if g.Unreadable != nil {
 		return &Goroutine{Unreadable: g.Unreadable.Error()}
 	}
+	var blockedFor time.Duration
+	if now, err := tgt.Nanotime(); err == nil {
+		blockedFor = g.BlockedFor(now)
+	}
 	return &Goroutine{
 		ID:             g.ID,
 		CurrentLoc:     ConvertLocation(g.CurrentLoc),
@@ -307,6 +337,7 @@ func ConvertGoroutine(tgt *proc.Target, g *proc.G) *Goroutine {
 		ThreadID:       tid,
 		WaitSince:      g.WaitSince,
 		WaitReason:     g.WaitReason,
+		BlockedFor:     blockedFor,
 		Labels:         g.Labels(),
 		Status:         g.Status,
 	}
@@ -341,6 +372,7 @@ func ConvertAsmInstruction(inst proc.AsmInstruction, text string) AsmInstruction
 	return AsmInstruction{
 		Loc:        ConvertLocation(inst.Loc),
 		DestLoc:    destloc,
+		DestSymbol: inst.DestSymbol,
 		Text:       text,
 		Bytes:      inst.Bytes,
 		Breakpoint: inst.Breakpoint,
@@ -358,8 +390,12 @@ func LoadConfigToProc(cfg *LoadConfig) *proc.LoadConfig {
 		MaxVariableRecurse: cfg.MaxVariableRecurse,
 		MaxStringLen:       cfg.MaxStringLen,
 		MaxArrayValues:     cfg.MaxArrayValues,
+		PerDimension:       cfg.PerDimension,
 		MaxStructFields:    cfg.MaxStructFields,
 		MaxMapBuckets:      0, // MaxMapBuckets is set internally by pkg/proc, read its documentation for an explanation.
+		RawTime:            cfg.RawTime,
+		LoadChanBuffer:     cfg.LoadChanBuffer,
+		LoadSyncMap:        cfg.LoadSyncMap,
 	}
 }
 
@@ -373,7 +409,11 @@ func LoadConfigFromProc(cfg *proc.LoadConfig) *LoadConfig {
 		MaxVariableRecurse: cfg.MaxVariableRecurse,
 		MaxStringLen:       cfg.MaxStringLen,
 		MaxArrayValues:     cfg.MaxArrayValues,
+		PerDimension:       cfg.PerDimension,
 		MaxStructFields:    cfg.MaxStructFields,
+		RawTime:            cfg.RawTime,
+		LoadChanBuffer:     cfg.LoadChanBuffer,
+		LoadSyncMap:        cfg.LoadSyncMap,
 	}
 }
 
@@ -391,6 +431,20 @@ var canonicalRegisterOrder = map[string]int{
 	"sp": 1,
 }
 
+func registerCategory(name string, fp bool, reg *op.DwarfRegister) RegisterCategory {
+	n := strings.ToLower(name)
+	switch {
+	case n == "rflags" || n == "eflags":
+		return RegisterFlags
+	case fp && len(reg.Bytes) >= 16 && !strings.HasPrefix(n, "st("):
+		return RegisterVector
+	case fp:
+		return RegisterFloat
+	default:
+		return RegisterGeneral
+	}
+}
+
 // ConvertRegisters converts proc.Register to api.Register for a slice.
 func ConvertRegisters(in *op.DwarfRegisters, dwarfRegisterToString func(int, *op.DwarfRegister) (string, bool, string), floatingPoint bool) (out []Register) {
 	out = make([]Register, 0, in.CurrentSize())
@@ -403,7 +457,7 @@ func ConvertRegisters(in *op.DwarfRegisters, dwarfRegisterToString func(int, *op
 		if !floatingPoint && fp {
 			continue
 		}
-		out = append(out, Register{name, repr, i})
+		out = append(out, Register{name, repr, i, registerCategory(name, fp, reg)})
 	}
 	// Sort the registers in a canonical order we prefer, this is mostly
 	// because the DWARF register numbering for AMD64 is weird.
@@ -437,6 +491,49 @@ func ConvertImage(image *proc.Image) Image {
 	return Image{Path: image.Path, Address: image.StaticBase, LoadError: lerr}
 }
 
+// ConvertPlugin converts proc.Plugin to api.Plugin.
+func ConvertPlugin(plugin *proc.Plugin) Plugin {
+	r := Plugin{PluginPath: plugin.PluginPath, Text: plugin.Text}
+	if plugin.Image != nil {
+		img := ConvertImage(plugin.Image)
+		r.Path, r.Address, r.LoadError = img.Path, img.Address, img.LoadError
+		r.Stripped = plugin.Image.Stripped()
+	}
+	return r
+}
+
+// ConvertMemorySearchResult converts proc.MemorySearchResult to api.MemorySearchResult.
+func ConvertMemorySearchResult(res *proc.MemorySearchResult) MemorySearchResult {
+	return MemorySearchResult{
+		Addr:        res.Addr,
+		Symbol:      res.Symbol,
+		SymbolAddr:  res.SymbolAddr,
+		Type:        PrettyTypeName(res.Type),
+		GoroutineID: res.GoroutineID,
+	}
+}
+
+// ConvertMemoryMapEntry converts proc.MemoryMapEntry to api.MemoryMapping.
+func ConvertMemoryMapEntry(mme *proc.MemoryMapEntry) MemoryMapping {
+	perms := []byte("---")
+	if mme.Read {
+		perms[0] = 'r'
+	}
+	if mme.Write {
+		perms[1] = 'w'
+	}
+	if mme.Exec {
+		perms[2] = 'x'
+	}
+	return MemoryMapping{
+		Start:  mme.Addr,
+		End:    mme.Addr + mme.Size,
+		Perms:  string(perms),
+		Name:   mme.Filename,
+		Offset: mme.Offset,
+	}
+}
+
 // ConvertDumpState converts proc.DumpState to api.DumpState.
 func ConvertDumpState(dumpState *proc.DumpState) *DumpState {
 	dumpState.Mutex.Lock()
@@ -489,5 +586,14 @@ func ConvertEvent(event *proc.Event) *Event {
 		}
 	}
 
+	if event.SignalEventDetails != nil {
+		r.SignalEventDetails = &SignalEventDetails{
+			Signal:  event.SignalEventDetails.Signal,
+			Name:    event.SignalEventDetails.Name,
+			Thread:  event.SignalEventDetails.ThreadID,
+			Stopped: event.SignalEventDetails.Stopped,
+		}
+	}
+
 	return r
 }
@@ -16,6 +16,8 @@ const (
 	maxShortStringLen = 7
 	// string used for one indentation level (when printing on multiple lines)
 	indentString = "\t"
+	// maximum number of bytes shown by the hexadecimal preview of byte slices and arrays
+	maxBytesPreviewLen = 16
 )
 
 // PrettyFlags specifies how a variable should be formatted.
@@ -24,14 +26,16 @@ type PrettyFlags uint8
 const (
 	prettyTop PrettyFlags = 1 << iota
 	prettyIncludeType
-	PrettyNewlines    // pretty print variable on multiple lines
-	PrettyShortenType // pretty print variable shortening types when they are printed
+	PrettyNewlines     // pretty print variable on multiple lines
+	PrettyShortenType  // pretty print variable shortening types when they are printed
+	PrettyBytesPreview // pretty print byte slices and arrays as a short hexadecimal string when no format is specified
 )
 
-func (flags PrettyFlags) top() bool         { return flags&prettyTop != 0 }
-func (flags PrettyFlags) includeType() bool { return flags&prettyIncludeType != 0 }
-func (flags PrettyFlags) newlines() bool    { return flags&PrettyNewlines != 0 }
-func (flags PrettyFlags) shortenType() bool { return flags&PrettyShortenType != 0 }
+func (flags PrettyFlags) top() bool          { return flags&prettyTop != 0 }
+func (flags PrettyFlags) includeType() bool  { return flags&prettyIncludeType != 0 }
+func (flags PrettyFlags) newlines() bool     { return flags&PrettyNewlines != 0 }
+func (flags PrettyFlags) shortenType() bool  { return flags&PrettyShortenType != 0 }
+func (flags PrettyFlags) bytesPreview() bool { return flags&PrettyBytesPreview != 0 }
 
 func (flags PrettyFlags) set(flag PrettyFlags, v bool) PrettyFlags {
 	if v {
@@ -139,6 +143,10 @@ func (v *Variable) writeTo(buf io.Writer, flags PrettyFlags, indent, fmtstr stri
 			}
 		}
 	case reflect.Struct:
+		if av := v.atomicValue(); av != nil {
+			v.writeAtomicTo(buf, av, flags, indent, fmtstr)
+			return
+		}
 		if v.Value != "" {
 			fmt.Fprintf(buf, "%s(%s)", v.typeStr(flags), v.Value)
 			flags = flags.set(prettyIncludeType, false)
@@ -293,6 +301,9 @@ func (v *Variable) writeSliceTo(buf io.Writer, flags PrettyFlags, indent, fmtstr
 		fmt.Fprintf(buf, "nil")
 		return
 	}
+	if v.writeBytesTo(buf, flags, fmtstr) {
+		return
+	}
 	v.writeSliceOrArrayTo(buf, flags, indent, fmtstr)
 }
 
@@ -300,9 +311,109 @@ func (v *Variable) writeArrayTo(buf io.Writer, flags PrettyFlags, indent, fmtstr
 	if flags.includeType() {
 		fmt.Fprintf(buf, "%s ", v.typeStr(flags))
 	}
+	if v.writeBytesTo(buf, flags, fmtstr) {
+		return
+	}
 	v.writeSliceOrArrayTo(buf, flags, indent, fmtstr)
 }
 
+// writeBytesTo writes the loaded elements of a byte slice or array as a
+// single string if fmtstr uses the verbs s or q, verb s is treated like q so
+// that bytes that aren't valid UTF-8 are printed as escape sequences. If
+// fmtstr is empty and the PrettyBytesPreview flag is set the first
+// maxBytesPreviewLen bytes are written as a hexadecimal string instead.
+// Returns false if v isn't a byte slice or array or neither applies.
+func (v *Variable) writeBytesTo(buf io.Writer, flags PrettyFlags, fmtstr string) bool {
+	if len(v.Children) == 0 {
+		return false
+	}
+	n := len(v.Children)
+	switch {
+	case fmtstr == "" && flags.bytesPreview():
+		fmtstr = "%#x"
+		n = min(n, maxBytesPreviewLen)
+	case fmtstr == "":
+		return false
+	default:
+		switch fmtstr[len(fmtstr)-1] {
+		case 's':
+			fmtstr = fmtstr[:len(fmtstr)-1] + "q"
+		case 'q':
+		default:
+			return false
+		}
+	}
+	b := make([]byte, n)
+	for i := range b {
+		if t := v.Children[i].Type; t != "uint8" && t != "byte" {
+			return false
+		}
+		x, err := strconv.ParseUint(ExtractIntValue(v.Children[i].Value), 10, 8)
+		if err != nil {
+			return false
+		}
+		b[i] = byte(x)
+	}
+	fmt.Fprintf(buf, fmtstr, b)
+	if n != int(v.Len) {
+		fmt.Fprintf(buf, "...+%d more", int(v.Len)-n)
+	}
+	return true
+}
+
+// atomicValue returns the field holding the value of v if v is one of the
+// types of package sync/atomic, nil otherwise.
+func (v *Variable) atomicValue() *Variable {
+	name, ok := strings.CutPrefix(v.Type, "sync/atomic.")
+	if !ok || int(v.Len) != len(v.Children) {
+		return nil
+	}
+	switch {
+	case name == "Bool", name == "Int32", name == "Int64", name == "Uint32", name == "Uint64", name == "Uintptr", name == "Value", strings.HasPrefix(name, "Pointer["):
+	default:
+		return nil
+	}
+	for i := range v.Children {
+		if v.Children[i].Name == "v" {
+			return &v.Children[i]
+		}
+	}
+	return nil
+}
+
+// writeAtomicTo writes the logical value of a sync/atomic type, av is the
+// field returned by atomicValue.
+func (v *Variable) writeAtomicTo(buf io.Writer, av *Variable, flags PrettyFlags, indent, fmtstr string) {
+	if flags.includeType() {
+		fmt.Fprintf(buf, "%s(", v.typeStr(flags))
+	}
+	switch {
+	case v.Type == "sync/atomic.Bool":
+		fmt.Fprint(buf, av.Value != "0")
+	case strings.HasPrefix(v.Type, "sync/atomic.Pointer["):
+		switch {
+		case len(av.Children) == 0 || av.Children[0].Addr == 0:
+			fmt.Fprint(buf, "nil")
+		case av.Kind != reflect.Ptr:
+			fmt.Fprintf(buf, "%#x", av.Children[0].Addr)
+		case av.Children[0].OnlyAddr:
+			av.writePointerTo(buf, flags)
+		default:
+			fmt.Fprint(buf, "*")
+			av.writePointerTo(buf, flags)
+			fmt.Fprint(buf, " ")
+			av.Children[0].writeTo(buf, flags.set(prettyTop, false).set(prettyIncludeType, false), indent, fmtstr)
+		}
+	case v.Type == "sync/atomic.Value":
+		av.writeTo(buf, flags.set(prettyTop, false).set(prettyIncludeType, true), indent, fmtstr)
+	default:
+		av.writeTo(buf, flags.set(prettyTop, false).set(prettyIncludeType, false), indent, fmtstr)
+	}
+	if flags.includeType() {
+		fmt.Fprint(buf, ")")
+	}
+}
+
 func (v *Variable) writeStructTo(buf io.Writer, flags PrettyFlags, indent, fmtstr string) {
 	if int(v.Len) != len(v.Children) && len(v.Children) == 0 {
 		if strings.Contains(v.Type, "/") {
@@ -525,6 +636,9 @@ func PrettyExamineMemory(address uintptr, memArea []byte, isLittleEndian bool, f
 	case 'x':
 		cols = 8
 		colFormat = fmt.Sprintf("0x%%0%dx", colBytes*2) // Always keep one leading '0x' for hex.
+	case 'c':
+		cols = 8
+		colFormat = "%s"
 	default:
 		return fmt.Sprintf("not supported format %q\n", string(format))
 	}
@@ -552,7 +666,11 @@ func PrettyExamineMemory(address uintptr, memArea []byte, isLittleEndian bool, f
 			offset := i*(cols*colBytes) + j*colBytes
 			if offset+colBytes <= len(memArea) {
 				n := byteArrayToUInt64(memArea[offset:offset+colBytes], isLittleEndian)
-				fmt.Fprintf(w, colFormat, n)
+				if format == 'c' {
+					fmt.Fprintf(w, colFormat, quoteByte(n))
+				} else {
+					fmt.Fprintf(w, colFormat, n)
+				}
 			}
 		}
 		fmt.Fprintln(w, "")
@@ -562,6 +680,15 @@ func PrettyExamineMemory(address uintptr, memArea []byte, isLittleEndian bool, f
 	return b.String()
 }
 
+// quoteByte formats n as a character literal, bytes outside of the ASCII
+// range are written as hex escapes.
+func quoteByte(n uint64) string {
+	if n < 0x80 {
+		return strconv.QuoteRuneToASCII(rune(n))
+	}
+	return fmt.Sprintf("'\\x%02x'", n)
+}
+
 func byteArrayToUInt64(buf []byte, isLittleEndian bool) uint64 {
 	var n uint64
 	if isLittleEndian {
@@ -633,9 +760,18 @@ func PrintStack(formatPath func(string) string, out io.Writer, stack []Stackfram
 		fmt.Fprintf(out, fmtstr, ind, i, stack[i].PC, stack[i].Function.Name())
 		fmt.Fprintf(out, "%sat %s\n", s, fileLine(stack[i].File, stack[i].Line))
 
-		if offsets {
+		if stack[i].Inlined && extranl {
+			if stack[i].InlinedCallerIndex >= 0 {
+				fmt.Fprintf(out, "%sinlined in frame %d\n", s, stack[i].InlinedCallerIndex)
+			} else {
+				fmt.Fprintf(out, "%sinlined\n", s)
+			}
+		} else if offsets {
 			fmt.Fprintf(out, "%sframe: %+#x frame pointer %+#x\n", s, stack[i].FrameOffset, stack[i].FramePointerOffset)
 		}
+		if stack[i].FramePointerFallback != "" {
+			fmt.Fprintf(out, "%s(unwound using DWARF: %s)\n", s, stack[i].FramePointerFallback)
+		}
 
 		for j, d := range stack[i].Defers {
 			deferHeader := fmt.Sprintf("%s    defer %d: ", s, j+1)
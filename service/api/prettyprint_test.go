@@ -3,6 +3,8 @@ package api
 import (
 	"fmt"
 	"math"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -57,3 +59,40 @@ func Test_byteArrayToUInt64(t *testing.T) {
 		})
 	}
 }
+
+func TestPrettyByteSlice(t *testing.T) {
+	mkbytes := func(kind reflect.Kind, typ string, len int64, vals ...string) *Variable {
+		v := &Variable{Kind: kind, Type: typ, Base: 0xc000010000, Len: len, Cap: len}
+		for _, val := range vals {
+			v.Children = append(v.Children, Variable{Kind: reflect.Uint, Type: "uint8", Value: val})
+		}
+		return v
+	}
+	long := make([]string, 20)
+	for i := range long {
+		long[i] = strconv.Itoa(i)
+	}
+	tests := []struct {
+		v      *Variable
+		fmtstr string
+		flags  PrettyFlags
+		want   string
+	}{
+		{mkbytes(reflect.Slice, "[]uint8", 5, "116", "195", "168", "115", "116"), "", 0, "[]uint8 len: 5, cap: 5, [116,195,168,115,116]"},
+		{mkbytes(reflect.Slice, "[]uint8", 5, "116", "195", "168", "115", "116"), "", PrettyBytesPreview, "[]uint8 len: 5, cap: 5, 0x74c3a87374"},
+		{mkbytes(reflect.Slice, "[]uint8", 20, long...), "", PrettyBytesPreview, "[]uint8 len: 20, cap: 20, 0x000102030405060708090a0b0c0d0e0f...+4 more"},
+		{mkbytes(reflect.Slice, "[]uint8", 10, "104", "105"), "", PrettyBytesPreview, "[]uint8 len: 10, cap: 10, 0x6869...+8 more"},
+		{mkbytes(reflect.Slice, "[]uint8", 5, "116", "195", "168", "115", "116"), "%x", 0, "[]uint8 len: 5, cap: 5, [74,c3,a8,73,74]"},
+		{mkbytes(reflect.Slice, "[]uint8", 5, "116", "195", "168", "115", "116"), "%#x", PrettyBytesPreview, "[]uint8 len: 5, cap: 5, [0x74,0xc3,0xa8,0x73,0x74]"},
+		{mkbytes(reflect.Slice, "[]uint8", 5, "116", "195", "168", "115", "116"), "%s", 0, `[]uint8 len: 5, cap: 5, "tèst"`},
+		{mkbytes(reflect.Slice, "[]uint8", 3, "104", "255", "105"), "%s", PrettyBytesPreview, `[]uint8 len: 3, cap: 3, "h\xffi"`},
+		{mkbytes(reflect.Array, "[2]uint8", 2, "104", "105"), "%s", 0, `[2]uint8 "hi"`},
+		{mkbytes(reflect.Array, "[2]uint8", 2, "104", "105"), "", PrettyBytesPreview, `[2]uint8 0x6869`},
+		{mkbytes(reflect.Array, "[2]uint8", 2, "104", "105"), "%d", 0, `[2]uint8 [104,105]`},
+	}
+	for _, tc := range tests {
+		if got := tc.v.StringWithOptions("", tc.fmtstr, tc.flags); got != tc.want {
+			t.Errorf("%s with %q: got %q, expected %q", tc.v.Type, tc.fmtstr, got, tc.want)
+		}
+	}
+}
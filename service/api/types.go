@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -107,12 +108,18 @@ type Breakpoint struct {
 	HitCond string
 	// HitCondPerG use per goroutine hitcount as HitCond operand, instead of total hitcount
 	HitCondPerG bool
+	// GoroutineLabels, if not empty, restricts the breakpoint to goroutines
+	// that have all the specified pprof labels with the specified values.
+	GoroutineLabels map[string]string `json:"goroutineLabels,omitempty"`
 
 	// Tracepoint flag, signifying this is a tracepoint.
 	Tracepoint bool `json:"continue"`
 	// TraceReturn flag signifying this is a breakpoint set at a return
 	// statement in a traced function.
 	TraceReturn bool `json:"traceReturn"`
+	// TraceEval signifies that this tracepoint prints the values of
+	// Variables on the same line as the trace, see 'break -trace -eval'.
+	TraceEval bool `json:"traceEval,omitempty"`
 	// retrieve goroutine information
 	Goroutine bool `json:"goroutine"`
 	// number of stack frames to retrieve
@@ -129,6 +136,16 @@ type Breakpoint struct {
 	// WatchExpr is the expression used to create this watchpoint
 	WatchExpr string
 	WatchType WatchType
+	// WatchFollow, for watchpoints on stack variables, causes the
+	// watchpoint to be set again the next time the function containing the
+	// variable is called, instead of being cleared, when it goes out of
+	// scope.
+	WatchFollow bool `json:"watchFollow,omitempty"`
+	// WatchField is true for watchpoints on a field of all instances of a
+	// type, WatchExpr has the form <type>.<field>. WatchMax is the maximum
+	// number of instances watched at the same time, 0 means no limit.
+	WatchField bool `json:"watchField,omitempty"`
+	WatchMax   int  `json:"watchMax,omitempty"`
 
 	VerboseDescr []string `json:"VerboseDescr,omitempty"`
 
@@ -198,6 +215,41 @@ type Thread struct {
 	ReturnValues []Variable
 	// CallReturn is true if ReturnValues are the return values of an injected call.
 	CallReturn bool
+
+	// OSState is the scheduling state of the thread as reported by the
+	// operating system (for example "sleeping" or, for threads stopped by
+	// the debugger, "in syscall read"). It is only set by ListThreads and
+	// GetThread and only on linux.
+	OSState string `json:"osState,omitempty"`
+	// LastCPU is the CPU the thread last executed on, as reported by the
+	// operating system. Like OSState it is only set by ListThreads and
+	// GetThread and only on linux, it is nil when not available.
+	LastCPU *int `json:"lastCPU,omitempty"`
+
+	// Exception is the last structured exception received by the thread
+	// since the target was last resumed, exceptions handled by the program
+	// are not reported. Only set on windows.
+	Exception *ExceptionInfo `json:"exception,omitempty"`
+}
+
+// ExceptionInfo describes a structured exception received by a thread.
+type ExceptionInfo struct {
+	// Code is the exception code, for example 0xc0000005 for access
+	// violations.
+	Code uint32 `json:"code"`
+	// Description is a short description of Code, if known.
+	Description string `json:"description,omitempty"`
+	// Address is the address of the instruction that caused the exception.
+	Address uint64 `json:"address"`
+	// FirstChance is true if the exception had not been seen by the
+	// program's exception handlers yet.
+	FirstChance bool `json:"firstChance"`
+	// FaultAddress is the address that could not be accessed, only set for
+	// access violations.
+	FaultAddress uint64 `json:"faultAddress,omitempty"`
+	// Operation is the type of access that caused an access violation:
+	// "read", "write" or "execute".
+	Operation string `json:"operation,omitempty"`
 }
 
 // Location holds program location information.
@@ -220,6 +272,8 @@ type Stackframe struct {
 	Locals    []Variable
 	Arguments []Variable
 
+	// FrameOffset and FramePointerOffset are the offsets of the CFA and of
+	// the frame pointer from the stack base, they are zero for inlined frames.
 	FrameOffset        int64
 	FramePointerOffset int64
 
@@ -227,6 +281,23 @@ type Stackframe struct {
 
 	Bottom bool `json:"Bottom,omitempty"` // Bottom is true if this is the bottom frame of the stack
 
+	// IsCgo is true if the function of this frame is not Go code, for
+	// example a C function called through cgo.
+	IsCgo bool `json:"IsCgo,omitempty"`
+
+	// Inlined is true if this is a synthetic frame for an inlined call,
+	// inlined frames do not have a physical frame of their own.
+	Inlined bool `json:"Inlined,omitempty"`
+	// InlinedCallerIndex is, for inlined frames, the index in the stacktrace
+	// of the physical frame that contains the inlined call, or -1 if the
+	// physical frame is not part of the stacktrace.
+	InlinedCallerIndex int `json:"InlinedCallerIndex,omitempty"`
+
+	// FramePointerFallback is set, for stacktraces requested with
+	// StacktraceFramePointers, when this frame could not be unwound by
+	// following the frame pointer chain. It describes why.
+	FramePointerFallback string `json:"FramePointerFallback,omitempty"`
+
 	Err string
 }
 
@@ -236,6 +307,27 @@ type Defer struct {
 	DeferLoc    Location // location of the defer statement
 	SP          uint64   // value of SP when the function was deferred
 	Unreadable  string
+	OpenCoded   bool // call deferred with an open-coded defer, DeferLoc is the entry point of the function that deferred it
+}
+
+// Panic describes a panic, or a call to runtime.Goexit, in progress on a
+// goroutine.
+type Panic struct {
+	Value      *Variable // argument of the call to panic, nil for runtime.Goexit
+	Recovered  bool      // a deferred call recovered this panic
+	Repanicked bool      // a deferred call re-raised this panic after recovering it
+	Goexit     bool      // this is a call to runtime.Goexit rather than a panic
+
+	// Frame is the index, in the stacktrace of the goroutine, of the frame
+	// whose deferred calls are being run. If the panic is recovered this is
+	// the frame that will return normally to its caller. It is -1 if no
+	// deferred call has been run yet or the frame could not be found.
+	Frame int
+	// Location is the location of the frame whose deferred calls are being
+	// run, nil if no deferred call has been run yet.
+	Location *Location
+
+	Unreadable string
 }
 
 // Var will return the variable described by 'name' within
@@ -407,9 +499,25 @@ type LoadConfig struct {
 	// MaxStringLen is the maximum number of bytes read from a string
 	MaxStringLen int
 	// MaxArrayValues is the maximum number of elements read from an array, a slice or a map.
+	// For multidimensional arrays the limit applies to the total number of
+	// elements, in row-major order, unless PerDimension is set.
 	MaxArrayValues int
+	// PerDimension applies MaxArrayValues to each dimension of a
+	// multidimensional array separately, the first MaxArrayValues rows are
+	// loaded, each with its first MaxArrayValues columns.
+	PerDimension bool
 	// MaxStructFields is the maximum number of fields read from a struct, -1 will read all fields.
 	MaxStructFields int
+	// RawTime disables the formatting of time.Time and time.Duration values
+	// as human readable strings.
+	RawTime bool `json:",omitempty"`
+	// LoadChanBuffer requests the elements currently in the buffer of
+	// channels to be loaded, in the order they will be received, as an
+	// additional child of the channel called "buffered".
+	LoadChanBuffer bool `json:",omitempty"`
+	// LoadSyncMap requests the key/value pairs stored in sync.Map values to
+	// be loaded as an additional child of the sync.Map called "entries".
+	LoadSyncMap bool `json:",omitempty"`
 }
 
 // Goroutine represents the information relevant to Delve from the runtime's
@@ -430,7 +538,11 @@ type Goroutine struct {
 	Status     uint64 `json:"status"`
 	WaitSince  int64  `json:"waitSince"`
 	WaitReason int64  `json:"waitReason"`
-	Unreadable string `json:"unreadable"`
+	// BlockedFor is how long the goroutine has been blocked, it is 0 if the
+	// goroutine is not blocked, the runtime has not recorded since when or
+	// the clock of the target is not available (for example in core files).
+	BlockedFor time.Duration `json:"blockedFor,omitempty"`
+	Unreadable string        `json:"unreadable"`
 	// Goroutine's pprof labels
 	Labels map[string]string `json:"labels,omitempty"`
 }
@@ -440,6 +552,19 @@ const (
 	GoroutineSyscall = proc.Gsyscall
 )
 
+// ClientConfig is configuration stored by the server for each client
+// connection, see RPCServer.GetConfig and RPCServer.SetConfig.
+type ClientConfig struct {
+	// LoadConfig is used to load variables by requests that do not specify a
+	// load configuration.
+	LoadConfig LoadConfig `json:"loadConfig"`
+	// SubstitutePathRules is used by requests that accept substitute-path
+	// rules when they do not specify any. The first entry of each pair is the
+	// path of a directory as it appears in the executable file, the second
+	// entry is the location of the same directory on the client system.
+	SubstitutePathRules [][2]string `json:"substitutePathRules,omitempty"`
+}
+
 // DebuggerCommand is a command which changes the debugger's execution state.
 type DebuggerCommand struct {
 	// Name is the command to run.
@@ -474,6 +599,34 @@ type DebuggerCommand struct {
 	// violate the rules about stack objects you can disable this safety check
 	// by setting UnsafeCall to true.
 	UnsafeCall bool `json:"unsafeCall,omitempty"`
+
+	// EvalConfig contains additional options for the Call command.
+	EvalConfig *EvalConfig `json:"evalConfig,omitempty"`
+
+	// SkipNoDebug, if set, makes the Step command step over calls to
+	// functions that don't have line information instead of stepping into
+	// them.
+	SkipNoDebug bool `json:"skipNoDebug,omitempty"`
+
+	// Count is the number of instructions executed by StepInstruction,
+	// NextInstruction and their reverse counterparts, zero means one.
+	// Stepping stops early if a breakpoint is hit.
+	Count int `json:"count,omitempty"`
+
+	// SingleGoroutine, if set, makes Next, Step, StepOut and a Continue that
+	// completes one of them ignore breakpoints hit by goroutines other than
+	// the one being stepped.
+	SingleGoroutine bool `json:"singleGoroutine,omitempty"`
+}
+
+// EvalConfig describes how expressions containing function calls are
+// evaluated.
+type EvalConfig struct {
+	// CallTimeout, if not zero, is the maximum amount of time to wait for the
+	// injected function calls to complete. When it expires the target is
+	// stopped and an error is returned, the calls are left in progress and
+	// will complete when the target is continued.
+	CallTimeout time.Duration `json:"callTimeout,omitempty"`
 }
 
 // BreakpointInfo contains information about the current breakpoint
@@ -552,6 +705,9 @@ type AsmInstruction struct {
 	Loc Location
 	// Destination of CALL instructions
 	DestLoc *Location
+	// DestSymbol is the name of the symbol at DestLoc when it isn't a
+	// function with debug symbols, for example "puts@plt" for a PLT stub.
+	DestSymbol string
 	// Text is the formatted representation of the instruction
 	Text string
 	// Bytes is the instruction as read from memory
@@ -583,6 +739,9 @@ type GetVersionOut struct {
 // SetAPIVersionIn is the input for SetAPIVersion.
 type SetAPIVersionIn struct {
 	APIVersion int
+	// ClientToken is the token required by servers started with a client
+	// token, see service.Config.ClientToken.
+	ClientToken string `json:",omitempty"`
 }
 
 // SetAPIVersionOut is the output for SetAPIVersion.
@@ -594,8 +753,26 @@ type Register struct {
 	Name        string
 	Value       string
 	DwarfNumber int
+	Category    RegisterCategory
 }
 
+// RegisterCategory describes the kind of a CPU register.
+type RegisterCategory string
+
+const (
+	// RegisterGeneral is a general purpose register, this includes the
+	// program counter and the stack pointer.
+	RegisterGeneral RegisterCategory = "general"
+	// RegisterFloat is a floating point register or a floating point
+	// control or status register.
+	RegisterFloat RegisterCategory = "float"
+	// RegisterVector is a vector register (XMM/YMM/ZMM on amd64, V registers
+	// on arm64).
+	RegisterVector RegisterCategory = "vector"
+	// RegisterFlags is the flags register.
+	RegisterFlags RegisterCategory = "flags"
+)
+
 // Registers is a list of CPU registers.
 type Registers []Register
 
@@ -637,6 +814,50 @@ type Image struct {
 	Trimpath  bool
 }
 
+// Plugin is a Go plugin loaded by the target process.
+type Plugin struct {
+	// PluginPath is the path of the plugin package, as recorded by the runtime.
+	PluginPath string
+	// Path is the path of the shared object containing the plugin.
+	Path string
+	// Address is the base address of the shared object.
+	Address uint64
+	// Text is the start address of the plugin's text section.
+	Text uint64
+	// Stripped is true if the plugin was built without debug info, only
+	// function names and line numbers are available for it.
+	Stripped  bool
+	LoadError string
+}
+
+// MemorySearchResult is an address where the value searched by
+// SearchMemory was found.
+type MemorySearchResult struct {
+	Addr uint64
+	// Symbol is the name of the package variable or function containing
+	// Addr, if any. SymbolAddr is its start address.
+	Symbol     string
+	SymbolAddr uint64
+	// Type is the type of the package variable containing Addr.
+	Type string
+	// GoroutineID is the ID of the goroutine whose stack contains Addr, or 0.
+	GoroutineID int64
+}
+
+// MemoryMapping is a memory mapping of the target process.
+type MemoryMapping struct {
+	Start uint64
+	End   uint64
+	// Perms are the access permissions of the mapping, in the same format
+	// used by /proc/PID/maps on Linux (for example "r-x").
+	Perms string
+	// Name is the name of the file backing the mapping, or a pseudo-path
+	// like [heap] or [stack], if known.
+	Name string
+	// Offset is the offset of the mapping in the file backing it.
+	Offset uint64
+}
+
 // Ancestor represents a goroutine ancestor
 type Ancestor struct {
 	ID    int64
@@ -662,6 +883,10 @@ const (
 	// StacktraceG requests a stacktrace starting with the register
 	// values saved in the runtime.g structure.
 	StacktraceG
+
+	// StacktraceFramePointers requests a fast, but approximate, stacktrace
+	// obtained by following the chain of saved frame pointers.
+	StacktraceFramePointers
 )
 
 // PackageBuildInfo maps an import path to a directory path.
@@ -671,6 +896,21 @@ type PackageBuildInfo struct {
 	Files         []string
 }
 
+// SourceFile describes a source file of the target and the compile unit it
+// belongs to.
+type SourceFile struct {
+	Path        string
+	Package     string // name of the package, empty for non-Go compile units
+	CompileUnit string // name of the compile unit, for Go compile units this is the import path of the package
+}
+
+// ProducerInfo describes a producer attribute (compiler version and flags)
+// found in the debug info and the files built by it.
+type ProducerInfo struct {
+	Producer string
+	Files    []string
+}
+
 // DumpState describes the state of a core dump in progress
 type DumpState struct {
 	Dumping bool
@@ -703,6 +943,7 @@ const (
 	GoroutineRunning                         // the goroutine is running
 	GoroutineUser                            // the goroutine is a user goroutine
 	GoroutineWaitingOnChannel                // the goroutine is waiting on the channel specified by the argument
+	GoroutineWaitReason                      // the goroutine's wait reason
 )
 
 // GoroutineGroup represents a group of goroutines in the return value of
@@ -714,6 +955,32 @@ type GoroutineGroup struct {
 	Total  int    // total number of goroutines that belong to this group
 }
 
+// GoroutineStackGroup is a set of goroutines that have the same stack, as
+// returned by the ListGoroutineStackGroups API call.
+type GoroutineStackGroup struct {
+	// ID identifies the group, it is a hash of the PC addresses of Stack.
+	ID string
+	// Stack is the stack shared by all goroutines in the group.
+	Stack []Stackframe
+	// Count is the total number of goroutines in the group.
+	Count int
+	// GoroutineIDs contains the IDs of the first MaxGroupMembers
+	// goroutines of the group.
+	GoroutineIDs []int64
+	// Labels contains each distinct set of labels of the goroutines in the group.
+	Labels []map[string]string
+}
+
+// GoroutineEvalResult is the result of evaluating an expression in the
+// topmost frame of a goroutine, as returned by the EvalGoroutines API call.
+type GoroutineEvalResult struct {
+	// Variable is the result of the evaluation, nil if Error is set.
+	Variable *Variable
+	// Error is the error encountered evaluating the expression in this
+	// goroutine.
+	Error string
+}
+
 type GoroutineGroupingOptions struct {
 	GroupBy         GoroutineField
 	GroupByKey      string
@@ -741,6 +1008,8 @@ type Event struct {
 	*BinaryInfoDownloadEventDetails
 	*BreakpointMaterializedEventDetails
 	*ProcessSpawnedEventDetails
+	*SignalEventDetails
+	*StateChangedEventDetails
 }
 
 type EventKind uint8
@@ -751,6 +1020,11 @@ const (
 	EventBinaryInfoDownload
 	EventBreakpointMaterialized
 	EventProcessSpawned
+	EventSignal
+	// EventStateChanged is only sent to clients subscribed with
+	// RPCServer.SubscribeEvents, it does not have a corresponding
+	// proc.EventKind.
+	EventStateChanged EventKind = 0x80
 )
 
 // BinaryInfoDownloadEventDetails describes the details of a BinaryInfoDownloadEvent
@@ -771,12 +1045,83 @@ type ProcessSpawnedEventDetails struct {
 	WillFollow bool
 }
 
+// SignalEventDetails describes the details of a SignalEvent
+type SignalEventDetails struct {
+	Signal int
+	Name   string
+	// Thread is the ID of the thread that received the signal, it can not be
+	// called ThreadID because it would conflict with
+	// ProcessSpawnedEventDetails.ThreadID when encoding to JSON.
+	Thread  int
+	Stopped bool
+}
+
+// StateChangedEventDetails describes the details of a StateChangedEvent
+type StateChangedEventDetails struct {
+	// State is the state of the target after a command that resumed it
+	// completed, nil if the command failed.
+	State *DebuggerState
+	// Err is the error returned by the command, if any.
+	Err string
+}
+
+// SignalPolicy describes what happens when the target process receives a
+// signal.
+type SignalPolicy struct {
+	Signal int    `json:"signal"`
+	Name   string `json:"name"`
+	Stop   bool   `json:"stop"`  // the target process stops
+	Print  bool   `json:"print"` // a SignalEvent is sent
+	Pass   bool   `json:"pass"`  // the signal is delivered to the target process
+}
+
+// AddressLocation describes the source location of an address, see
+// RPCServer.AddressToLine.
+type AddressLocation struct {
+	Location
+	// Exact is false if the address has no line information, in which case
+	// Location describes the entry point of the function containing it.
+	Exact bool
+	// FirstLine is the line number of the first entry of Lines.
+	FirstLine int
+	// Lines contains the source lines surrounding Location.Line, it is
+	// empty if no context was requested or the source file could not be
+	// read.
+	Lines []string `json:",omitempty"`
+}
+
+// TypeArgument is the type argument of a generic function instantiation.
+type TypeArgument struct {
+	// Name is the name of the type parameter.
+	Name string
+	// Type is the concrete type it was instantiated with, or the empty
+	// string if it could not be determined.
+	Type string
+}
+
 type TypeInfo struct {
 	Kind     reflect.Kind
 	Size     int64
 	RealType string
 	Fields   []TypeInfoField
 	Methods  []TypeInfoMethod
+	// Tree is the tree of types that make up the layout of this type, only
+	// returned if it was requested.
+	Tree *TypeTreeNode `json:",omitempty"`
+}
+
+// TypeTreeNode describes a type and, recursively, the types of its fields
+// (for structs and the structs used to implement slices, strings and
+// interfaces), its element type (for arrays) or its underlying type (for
+// other named types). Pointers are not followed.
+type TypeTreeNode struct {
+	Name     string // field name
+	Type     string
+	Kind     reflect.Kind
+	Size     int64
+	Offset   int64 // byte offset within the parent struct
+	Embedded bool
+	Children []TypeTreeNode `json:",omitempty"`
 }
 
 type TypeInfoField struct {
@@ -54,11 +54,20 @@ type Client interface {
 	ReverseStepOut() (*api.DebuggerState, error)
 	// Call resumes process execution while making a function call.
 	Call(goroutineID int64, expr string, unsafe bool) (*api.DebuggerState, error)
+	// CallWithTimeout is like Call but stops the target if the function
+	// call does not complete before timeout expires.
+	CallWithTimeout(goroutineID int64, expr string, unsafe bool, timeout time.Duration) (*api.DebuggerState, error)
 
 	// StepInstruction will step a single cpu instruction.
 	StepInstruction(skipCalls bool) (*api.DebuggerState, error)
 	// ReverseStepInstruction will reverse step a single cpu instruction.
 	ReverseStepInstruction(skipCalls bool) (*api.DebuggerState, error)
+	// StepInstructions will step count cpu instructions, stopping early if
+	// a breakpoint is hit.
+	StepInstructions(skipCalls bool, count int) (*api.DebuggerState, error)
+	// ReverseStepInstructions will reverse step count cpu instructions,
+	// stopping early if a breakpoint is hit.
+	ReverseStepInstructions(skipCalls bool, count int) (*api.DebuggerState, error)
 	// SwitchThread switches the current thread context.
 	SwitchThread(threadID int) (*api.DebuggerState, error)
 	// SwitchGoroutine switches the current goroutine (and the current thread as well)
@@ -76,6 +85,8 @@ type Client interface {
 	CreateBreakpointWithExpr(*api.Breakpoint, string, [][2]string, bool) (*api.Breakpoint, error)
 	// CreateWatchpoint creates a new watchpoint.
 	CreateWatchpoint(api.EvalScope, string, api.WatchType) (*api.Breakpoint, error)
+	// CreateFieldWatchpoint creates a watchpoint on a field of all instances of a type.
+	CreateFieldWatchpoint(typeField string, wtype api.WatchType, max int) (*api.Breakpoint, error)
 	// ListBreakpoints gets all breakpoints.
 	ListBreakpoints(bool) ([]*api.Breakpoint, error)
 	// ClearBreakpoint deletes a breakpoint by ID.
@@ -101,39 +112,71 @@ type Client interface {
 	ListPackageVariables(filter string, cfg api.LoadConfig) ([]api.Variable, error)
 	// EvalVariable returns a variable in the context of the current thread.
 	EvalVariable(scope api.EvalScope, symbol string, cfg api.LoadConfig) (*api.Variable, error)
+	// EvalGoroutines evaluates expr in the topmost frame of every goroutine.
+	EvalGoroutines(expr string, cfg api.LoadConfig) (map[int64]api.GoroutineEvalResult, error)
+	// AddressToLine returns the source location of pc and context lines of
+	// source before and after it.
+	AddressToLine(pc uint64, context int) (*api.AddressLocation, error)
 	// TypeInfo returns informations about a type.
 	TypeInfo(name string) (*api.TypeInfo, error)
+	// TypeInfoTree is like TypeInfo but also returns the tree of types that
+	// make up the layout of the type.
+	TypeInfoTree(name string) (*api.TypeInfo, error)
 
 	// SetVariable sets the value of a variable
 	SetVariable(scope api.EvalScope, symbol, value string) error
 
 	// ListSources lists all source files in the process matching filter.
 	ListSources(filter string) ([]string, error)
+	// ListSourceFiles lists all source files in the process matching filter,
+	// along with their package and compile unit.
+	ListSourceFiles(filter string) ([]api.SourceFile, error)
 	// ListFunctions lists all functions in the process matching filter.
 	ListFunctions(filter string, tracefollow int) ([]string, error)
 	// ListTypes lists all types in the process matching filter.
 	ListTypes(filter string) ([]string, error)
 	// ListPackagesBuildInfo lists all packages in the process matching filter.
 	ListPackagesBuildInfo(filter string, includeFiles bool) ([]api.PackageBuildInfo, error)
+	// ListProducers lists the distinct producer attributes of compile units and the files they cover.
+	ListProducers() ([]api.ProducerInfo, error)
 	// ListLocalVariables lists all local variables in scope.
 	ListLocalVariables(scope api.EvalScope, cfg api.LoadConfig) ([]api.Variable, error)
+	// ListLocalVariablesWithTypeArguments lists all local variables in scope
+	// and the type arguments of the function executing in scope, see
+	// ListTypeArguments.
+	ListLocalVariablesWithTypeArguments(scope api.EvalScope, cfg api.LoadConfig, substitutePathRules [][2]string) ([]api.Variable, []api.TypeArgument, error)
+	// ListTypeArguments lists the type arguments of the generic function
+	// instantiation executing in scope. The names of the type parameters are
+	// read from the source file, substitutePathRules are applied to its path.
+	ListTypeArguments(scope api.EvalScope, substitutePathRules [][2]string) ([]api.TypeArgument, error)
 	// ListFunctionArgs lists all arguments to the current function.
 	ListFunctionArgs(scope api.EvalScope, cfg api.LoadConfig) ([]api.Variable, error)
 	// ListThreadRegisters lists registers and their values, for the given thread.
 	ListThreadRegisters(threadID int, includeFp bool) (api.Registers, error)
 	// ListScopeRegisters lists registers and their values, for the given scope.
 	ListScopeRegisters(scope api.EvalScope, includeFp bool) (api.Registers, error)
+	// SetRegister changes the value of a general purpose register of the
+	// given thread and returns its updated registers.
+	SetRegister(threadID int, name string, value uint64) (api.Registers, error)
 
 	// ListGoroutines lists all goroutines.
 	ListGoroutines(start, count int) ([]*api.Goroutine, int, error)
 	// ListGoroutinesWithFilter lists goroutines matching the filters
 	ListGoroutinesWithFilter(start, count int, filters []api.ListGoroutinesFilter, group *api.GoroutineGroupingOptions, scope *api.EvalScope) ([]*api.Goroutine, []api.GoroutineGroup, int, bool, error)
+	// ListGoroutineStackGroups returns goroutines matching the filters grouped by stack.
+	ListGoroutineStackGroups(filters []api.ListGoroutinesFilter, depth, maxGroupMembers int) ([]api.GoroutineStackGroup, error)
 
 	// Stacktrace returns stacktrace
 	Stacktrace(goroutineID int64, depth, skip int, opts api.StacktraceOptions, cfg *api.LoadConfig) ([]api.Stackframe, error)
 
 	// Ancestors returns ancestor stacktraces
 	Ancestors(goroutineID int64, numAncestors int, depth int) ([]api.Ancestor, error)
+	// GoroutineCreationStacktrace returns the stacktrace of the 'go' statement that created a goroutine.
+	GoroutineCreationStacktrace(goroutineID int64, depth int) ([]api.Stackframe, error)
+	// ListGoroutineDefers returns the pending deferred calls of a goroutine, most recent first.
+	ListGoroutineDefers(goroutineID int64) ([]api.Defer, error)
+	// ListGoroutinePanics returns the panics in progress on a goroutine, most recent first.
+	ListGoroutinePanics(goroutineID int64, cfg api.LoadConfig) ([]api.Panic, error)
 
 	// AttachedToExistingProcess returns whether we attached to a running process or not
 	AttachedToExistingProcess() bool
@@ -171,19 +214,38 @@ type Client interface {
 	// SetReturnValuesLoadConfig sets the load configuration for return values.
 	SetReturnValuesLoadConfig(*api.LoadConfig)
 
+	// SetStepSkipNoDebug sets whether Step should step over calls to functions
+	// that don't have line information.
+	SetStepSkipNoDebug(bool)
+
+	// SetSingleGoroutineStepping sets whether Next, Step and StepOut should
+	// ignore breakpoints hit by other goroutines.
+	SetSingleGoroutineStepping(bool)
+
 	// SetEventsFn sets a function that will be called whenever a debugger event is received.
 	SetEventsFn(func(*api.Event))
 
+	// SubscribeEvents subscribes to the events generated by the target while
+	// it is resumed by any client, see RPCServer.SubscribeEvents.
+	SubscribeEvents() (events <-chan api.Event, cancel func(), err error)
+
 	// IsMulticlient returns true if the headless instance is multiclient.
 	IsMulticlient() bool
 
 	// ListDynamicLibraries returns a list of loaded dynamic libraries.
 	ListDynamicLibraries() ([]api.Image, bool, error)
+	// ListPlugins returns a list of Go plugins loaded by the target process.
+	ListPlugins() ([]api.Plugin, error)
 
 	// ExamineMemory returns the raw memory stored at the given address.
 	// The amount of data to be read is specified by length which must be less than or equal to 1000.
 	// This function will return an error if it reads less than `length` bytes.
 	ExamineMemory(address uint64, length int) ([]byte, bool, error)
+	// SearchMemory searches the memory of the target for the value of expr,
+	// see RPCServer.SearchMemory.
+	SearchMemory(scope api.EvalScope, expr string, align, max int) ([]api.MemorySearchResult, error)
+	// ListMemoryMappings returns the memory mappings of the target process.
+	ListMemoryMappings() ([]api.MemoryMapping, error)
 
 	// StopRecording stops a recording if one is in progress.
 	StopRecording() error
@@ -203,6 +265,12 @@ type Client interface {
 	FollowExec(bool, string) error
 	FollowExecEnabled() bool
 
+	// ListSignalPolicies returns the policy of every signal known to the debugger.
+	ListSignalPolicies() ([]api.SignalPolicy, error)
+	// SetSignalPolicy sets the policy used when the target process receives
+	// a signal, specified by name or number.
+	SetSignalPolicy(sig string, stop, print, pass bool) (api.SignalPolicy, error)
+
 	// Disconnect closes the connection to the server without sending a Detach request first.
 	// If cont is true a continue command will be sent instead.
 	Disconnect(cont bool) error
@@ -216,6 +284,12 @@ type Client interface {
 	// GuessSubstitutePath tries to guess a substitute-path configuration for the client
 	GuessSubstitutePath() ([][2]string, error)
 
+	// GetConfig returns the configuration of this client connection.
+	GetConfig() (*api.ClientConfig, error)
+
+	// SetConfig changes the configuration of this client connection.
+	SetConfig(cfg api.ClientConfig) error
+
 	// CancelDownloads cancels binary info downloads, if any.
 	CancelDownloads() error
 	// DownloadLibraryDebugInfo attempts to download the specified library's debug info.
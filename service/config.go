@@ -1,6 +1,7 @@
 package service
 
 import (
+	"crypto/tls"
 	"net"
 
 	"github.com/go-delve/delve/service/debugger"
@@ -20,6 +21,10 @@ type Config struct {
 	// Listener is used to serve requests.
 	Listener net.Listener
 
+	// TLSConfig, if not nil, is used to wrap the connections accepted by
+	// Listener with TLS. The protocol spoken over the connection is unchanged.
+	TLSConfig *tls.Config
+
 	// ProcessArgs are the arguments to launch a new process.
 	ProcessArgs []string
 
@@ -36,4 +41,10 @@ type Config struct {
 
 	// DisconnectChan will be closed by the server when the client disconnects
 	DisconnectChan chan<- struct{}
+
+	// ClientToken, if not empty, must be presented by JSON-RPC clients in
+	// the RPCServer.SetApiVersion request, which must be the first request
+	// they send. Connections that do not present it, including all DAP
+	// connections, are closed immediately.
+	ClientToken string
 }
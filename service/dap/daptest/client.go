@@ -504,8 +504,11 @@ func (c *Client) RestartFrameRequest() {
 }
 
 // GotoRequest sends a 'goto' request.
-func (c *Client) GotoRequest() {
-	c.send(&dap.GotoRequest{Request: *c.newRequest("goto")})
+func (c *Client) GotoRequest(threadID, targetID int) {
+	request := &dap.GotoRequest{Request: *c.newRequest("goto")}
+	request.Arguments.ThreadId = threadID
+	request.Arguments.TargetId = targetID
+	c.send(request)
 }
 
 // SetExpressionRequest sends a 'setExpression' request.
@@ -538,13 +541,20 @@ func (c *Client) StepInTargetsRequest() {
 }
 
 // GotoTargetsRequest sends a 'gotoTargets' request.
-func (c *Client) GotoTargetsRequest() {
-	c.send(&dap.GotoTargetsRequest{Request: *c.newRequest("gotoTargets")})
+func (c *Client) GotoTargetsRequest(file string, line int) {
+	request := &dap.GotoTargetsRequest{Request: *c.newRequest("gotoTargets")}
+	request.Arguments.Source = dap.Source{Path: file}
+	request.Arguments.Line = line
+	c.send(request)
 }
 
 // CompletionsRequest sends a 'completions' request.
-func (c *Client) CompletionsRequest() {
-	c.send(&dap.CompletionsRequest{Request: *c.newRequest("completions")})
+func (c *Client) CompletionsRequest(text string, column, frameID int) {
+	request := &dap.CompletionsRequest{Request: *c.newRequest("completions")}
+	request.Arguments.Text = text
+	request.Arguments.Column = column
+	request.Arguments.FrameId = frameID
+	c.send(request)
 }
 
 // ExceptionInfoRequest sends a 'exceptionInfo' request.
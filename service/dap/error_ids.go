@@ -31,6 +31,8 @@ const (
 	UnableToReadMemory            = 2016
 	UnableToGetDataBreakpointInfo = 2017
 	UnableToWriteMemory           = 2018
+	UnableToGetGotoTargets        = 2019
+	UnableToGoto                  = 2020
 
 	// Add more codes as we support more requests
 
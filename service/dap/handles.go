@@ -27,6 +27,8 @@ type fullyQualifiedVariable struct {
 	// startIndex is the index of the first child for an array or slice.
 	// This variable represents a chunk of the array, slice or map.
 	startIndex int
+	// frame is the stack frame of a scope of local variables, nil otherwise.
+	frame *stackFrame
 }
 
 func newHandlesMap[T any]() *handlesMap[T] {
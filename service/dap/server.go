@@ -186,6 +186,9 @@ type Session struct {
 	// Reset at every stop.
 	// See also comment for convertVariable.
 	variableHandles *handlesMap[*fullyQualifiedVariable]
+	// gotoTargetHandles maps goto targets to the PC they refer to.
+	// Reset at every stop.
+	gotoTargetHandles *handlesMap[uint64]
 	// referencesCollection track references map for DAP client
 	referencesCollection referencesCollection
 	// args tracks special settings for handling debug session requests.
@@ -354,6 +357,7 @@ var DefaultLoadConfig = proc.LoadConfig{
 	// - vscode viewlet hover truncates at 1023 characters
 	MaxStringLen:    512,
 	MaxArrayValues:  64,
+	PerDimension:    true,
 	MaxStructFields: -1,
 }
 
@@ -422,6 +426,7 @@ func NewSession(conn io.ReadWriteCloser, config *Config, debugger *debugger.Debu
 		conn:              newConnection(conn),
 		stackFrameHandles: newHandlesMap[stackFrame](),
 		variableHandles:   newHandlesMap[*fullyQualifiedVariable](),
+		gotoTargetHandles: newHandlesMap[uint64](),
 		args:              defaultArgs,
 		exceptionErr:      nil,
 		debugger:          debugger,
@@ -864,6 +869,12 @@ func (s *Session) handleRequest(request dap.Message) {
 		s.onDataBreakpointInfoRequest(request)
 	case *dap.SetDataBreakpointsRequest: // Optional (capability 'supportsDataBreakpoints')
 		s.onSetDataBreakpointRequest(request)
+	case *dap.CompletionsRequest: // Optional (capability 'supportsCompletionsRequest')
+		s.onCompletionsRequest(request)
+	case *dap.GotoTargetsRequest: // Optional (capability 'supportsGotoTargetsRequest')
+		s.onGotoTargetsRequest(request)
+	case *dap.GotoRequest: // Optional (capability 'supportsGotoTargetsRequest')
+		s.onGotoRequest(request)
 	//--- Requests that we may want to support ---
 	case *dap.SourceRequest: // Required
 		/*TODO*/ s.sendUnsupportedErrorResponse(request.Request) // https://github.com/go-delve/delve/issues/2851
@@ -878,16 +889,10 @@ func (s *Session) handleRequest(request dap.Message) {
 	//--- Requests that we do not plan to support ---
 	case *dap.RestartFrameRequest: // Optional (capability 'supportsRestartFrame')
 		s.sendUnsupportedErrorResponse(request.Request)
-	case *dap.GotoRequest: // Optional (capability 'supportsGotoTargetsRequest')
-		s.sendUnsupportedErrorResponse(request.Request)
 	case *dap.TerminateThreadsRequest: // Optional (capability 'supportsTerminateThreadsRequest')
 		s.sendUnsupportedErrorResponse(request.Request)
 	case *dap.StepInTargetsRequest: // Optional (capability 'supportsStepInTargetsRequest')
 		s.sendUnsupportedErrorResponse(request.Request)
-	case *dap.GotoTargetsRequest: // Optional (capability 'supportsGotoTargetsRequest')
-		s.sendUnsupportedErrorResponse(request.Request)
-	case *dap.CompletionsRequest: // Optional (capability 'supportsCompletionsRequest')
-		s.sendUnsupportedErrorResponse(request.Request)
 	case *dap.BreakpointLocationsRequest: // Optional (capability 'supportsBreakpointLocationsRequest')
 		s.sendUnsupportedErrorResponse(request.Request)
 	default:
@@ -965,6 +970,8 @@ func (s *Session) onInitializeRequest(request *dap.InitializeRequest) {
 	response.Body.SupportsSteppingGranularity = true
 	response.Body.SupportsLogPoints = true
 	response.Body.SupportsDisassembleRequest = true
+	response.Body.SupportsCompletionsRequest = true
+	response.Body.SupportsGotoTargetsRequest = true
 	// To be enabled by CapabilitiesEvent based on launch configuration
 	response.Body.SupportsStepBack = false
 	response.Body.SupportTerminateDebuggee = false
@@ -1891,6 +1898,8 @@ func (s *Session) onSetInstructionBreakpointsRequest(request *dap.SetInstruction
 // onDataBreakpointInfoRequest handles DataBreakpointInfo requests. If the
 // request can be satisfied the data ID in the response will be in the form:
 // <goid>,<frameidx>,<expression>.
+// Local variables are identified by name so that data breakpoints set on
+// them are cleared when they go out of scope.
 func (s *Session) onDataBreakpointInfoRequest(request *dap.DataBreakpointInfoRequest) {
 	var goid int64 = -1
 	var frame int
@@ -1903,6 +1912,16 @@ func (s *Session) onDataBreakpointInfoRequest(request *dap.DataBreakpointInfoReq
 					s.sendErrorResponse(request.Request, UnableToGetDataBreakpointInfo, "Could not get data breakpoint info", fmt.Sprintf("field %s is unreadable: %v", request.Arguments.Name, child.Unreadable))
 					return
 				}
+				if v.frame != nil && child.Flags&proc.VariableShadowed == 0 {
+					// Watch local variables by name so that the debugger can
+					// detect when they go out of scope.
+					goid = int64(v.frame.goroutineID)
+					frame = v.frame.frameIndex
+					expr = child.Name
+					descr = child.Name
+					found = true
+					break
+				}
 				expr = fmt.Sprintf("*(*[%d]byte)(%#x)", child.RealType.Size(), child.Addr)
 				descr = v.Name + "." + child.Name
 				found = true
@@ -2657,7 +2676,7 @@ func (s *Session) onScopesRequest(request *dap.ScopesRequest) {
 		s.sendErrorResponse(request.Request, UnableToListLocals, "Unable to list locals", err.Error())
 		return
 	}
-	locScope := &fullyQualifiedVariable{&proc.Variable{Name: fmt.Sprintf("Locals%s", suffix), Children: slicePtrVarToSliceVar(append(args, locals...))}, "", true, 0}
+	locScope := &fullyQualifiedVariable{Variable: &proc.Variable{Name: fmt.Sprintf("Locals%s", suffix), Children: slicePtrVarToSliceVar(append(args, locals...))}, isScope: true, frame: &sf}
 	scopeLocals := dap.Scope{Name: locScope.Name, VariablesReference: s.variableHandles.create(locScope)}
 	scopes := []dap.Scope{scopeLocals}
 
@@ -2688,10 +2707,14 @@ func (s *Session) onScopesRequest(request *dap.ScopesRequest) {
 			globals[i].Name = strings.TrimPrefix(g.Name, currPkg+".")
 		}
 
-		globScope := &fullyQualifiedVariable{&proc.Variable{
-			Name:     fmt.Sprintf("Globals (package %s)", currPkg),
-			Children: slicePtrVarToSliceVar(globals),
-		}, currPkg, true, 0}
+		globScope := &fullyQualifiedVariable{
+			Variable: &proc.Variable{
+				Name:     fmt.Sprintf("Globals (package %s)", currPkg),
+				Children: slicePtrVarToSliceVar(globals),
+			},
+			fullyQualifiedNameOrExpr: currPkg,
+			isScope:                  true,
+		}
 		scopeGlobals := dap.Scope{Name: globScope.Name, VariablesReference: s.variableHandles.create(globScope)}
 		scopes = append(scopes, scopeGlobals)
 	}
@@ -2712,7 +2735,7 @@ func (s *Session) onScopesRequest(request *dap.ScopesRequest) {
 				Kind:  reflect.Kind(proc.VariableConstant),
 			}
 		}
-		regsScope := &fullyQualifiedVariable{&proc.Variable{Name: "Registers", Children: regsVar}, "", true, 0}
+		regsScope := &fullyQualifiedVariable{Variable: &proc.Variable{Name: "Registers", Children: regsVar}, isScope: true}
 		scopeRegisters := dap.Scope{Name: regsScope.Name, VariablesReference: s.variableHandles.create(regsScope)}
 		scopes = append(scopes, scopeRegisters)
 	}
@@ -2792,7 +2815,7 @@ func (s *Session) maybeLoadResliced(v *fullyQualifiedVariable, start, count int)
 	if err != nil {
 		return nil, err
 	}
-	return &fullyQualifiedVariable{newV, v.fullyQualifiedNameOrExpr, false, start}, nil
+	return &fullyQualifiedVariable{Variable: newV, fullyQualifiedNameOrExpr: v.fullyQualifiedNameOrExpr, startIndex: start}, nil
 }
 
 // getIndexedVariableCount returns the number of indexed variables
@@ -3086,7 +3109,7 @@ func (s *Session) convertVariableWithOpts(v *proc.Variable, qualifiedNameOrExpr
 		if opts&skipRef != 0 {
 			return 0
 		}
-		return s.variableHandles.create(&fullyQualifiedVariable{v, qualifiedNameOrExpr, false /*not a scope*/, 0})
+		return s.variableHandles.create(&fullyQualifiedVariable{Variable: v, fullyQualifiedNameOrExpr: qualifiedNameOrExpr})
 	}
 	value = formatVar(v)
 	if v.Unreadable != nil {
@@ -3290,7 +3313,7 @@ func (s *Session) onEvaluateRequest(request *dap.EvaluateRequest) {
 			}
 			response.Body = dap.EvaluateResponseBody{
 				Result:             strings.TrimRight(retVarsAsStr.String(), ", "),
-				VariablesReference: s.variableHandles.create(&fullyQualifiedVariable{retVarsAsVar, "", false /*not a scope*/, 0}),
+				VariablesReference: s.variableHandles.create(&fullyQualifiedVariable{Variable: retVarsAsVar}),
 			}
 		}
 	} else { // {expression}
@@ -3328,6 +3351,179 @@ func (s *Session) onEvaluateRequest(request *dap.EvaluateRequest) {
 	s.send(response)
 }
 
+// completionTokenRegex matches the partial identifier, possibly qualified by
+// a package name, preceding the cursor.
+var completionTokenRegex = regexp.MustCompile(`[\pL\pN_.]*$`)
+
+// onCompletionsRequest handles 'completions' requests.
+// This is an optional request enabled by capability 'supportsCompletionsRequest'.
+// The partial identifier before the cursor is matched against the local
+// variables of the selected frame, the package variables and the functions
+// of the target. Symbols of the package of the selected frame are also
+// offered without the package qualifier. Local variables are listed first.
+func (s *Session) onCompletionsRequest(request *dap.CompletionsRequest) {
+	goid, frame := -1, 0
+	if sf, ok := s.stackFrameHandles.get(request.Arguments.FrameId); ok {
+		goid = sf.goroutineID
+		frame = sf.frameIndex
+	}
+
+	text := []rune(request.Arguments.Text)
+	column := min(max(request.Arguments.Column-1, 0), len(text))
+	token := completionTokenRegex.FindString(string(text[:column]))
+	start := column - len([]rune(token)) + 1
+
+	targets := []dap.CompletionItem{}
+	seen := make(map[string]bool)
+	add := func(label, detail string, typ dap.CompletionItemType, order int) {
+		if seen[label] || !strings.HasPrefix(label, token) {
+			return
+		}
+		seen[label] = true
+		targets = append(targets, dap.CompletionItem{
+			Label:    label,
+			Detail:   detail,
+			Type:     typ,
+			SortText: fmt.Sprintf("%d%s", order, label),
+			Start:    start,
+			Length:   len([]rune(token)),
+		})
+	}
+
+	currPkg := ""
+	if fn, err := s.debugger.Function(int64(goid), frame, 0); err == nil && fn != nil {
+		currPkg = fn.PackageName()
+	}
+	unqualified := func(name string) string {
+		if currPkg == "" {
+			return ""
+		}
+		return strings.TrimPrefix(name, currPkg+".")
+	}
+
+	// Only names and types are needed, do not load any values.
+	var cfg proc.LoadConfig
+	args, _ := s.debugger.FunctionArguments(int64(goid), frame, 0, cfg)
+	locals, _ := s.debugger.LocalVariables(int64(goid), frame, 0, cfg)
+	for _, v := range append(args, locals...) {
+		if v.Flags&proc.VariableShadowed != 0 {
+			continue
+		}
+		add(v.Name, v.TypeString(), "variable", 0)
+	}
+
+	// Local variables are always offered, package variables and functions
+	// only once part of their name has been typed.
+	if token != "" {
+		filter := "^" + regexp.QuoteMeta(token)
+		if currPkg != "" {
+			filter = fmt.Sprintf("^(%s|%s\\.%s)", regexp.QuoteMeta(token), regexp.QuoteMeta(currPkg), regexp.QuoteMeta(token))
+		}
+		globals, _ := s.debugger.PackageVariables(filter, cfg)
+		for _, v := range globals {
+			if name := unqualified(v.Name); name != v.Name {
+				add(name, v.TypeString(), "variable", 1)
+			}
+			add(v.Name, v.TypeString(), "variable", 2)
+		}
+		funcs, _ := s.debugger.Functions(filter, 0)
+		for _, name := range funcs {
+			if uname := unqualified(name); uname != name {
+				add(uname, "", "function", 1)
+			}
+			add(name, "", "function", 2)
+		}
+	}
+
+	s.send(&dap.CompletionsResponse{
+		Response: *s.newResponse(request.Request),
+		Body:     dap.CompletionsResponseBody{Targets: targets},
+	})
+}
+
+// onGotoTargetsRequest handles 'gotoTargets' requests.
+// This is an optional request enabled by capability 'supportsGotoTargetsRequest'.
+// Only locations in the function executing in the topmost frame of the
+// selected goroutine are valid targets.
+func (s *Session) onGotoTargetsRequest(request *dap.GotoTargetsRequest) {
+	if request.Arguments.Source.Path == "" {
+		s.sendErrorResponse(request.Request, UnableToGetGotoTargets, "Unable to get goto targets", "empty file path")
+		return
+	}
+	fn, err := s.debugger.Function(-1, 0, 0)
+	if err != nil {
+		s.sendErrorResponse(request.Request, UnableToGetGotoTargets, "Unable to get goto targets", err.Error())
+		return
+	}
+	if fn == nil {
+		s.sendErrorResponse(request.Request, UnableToGetGotoTargets, "Unable to get goto targets", "could not find current function")
+		return
+	}
+
+	locStr := fmt.Sprintf("%s:%d", s.toServerPath(request.Arguments.Source.Path), request.Arguments.Line)
+	locs, _, err := s.debugger.FindLocation(-1, 0, 0, locStr, false, s.args.substitutePathClientToServer)
+	if err != nil {
+		s.sendErrorResponse(request.Request, UnableToGetGotoTargets, "Unable to get goto targets", err.Error())
+		return
+	}
+
+	targets := []dap.GotoTarget{}
+	for _, loc := range locs {
+		for _, pc := range loc.PCs {
+			if pc <= fn.Entry || pc >= fn.End {
+				continue
+			}
+			if s.debugger.CheckJumpToPC(-1, pc) != nil {
+				// JumpToPC would reject pc, for example because it belongs to a
+				// call inlined into the current function
+				continue
+			}
+			targets = append(targets, dap.GotoTarget{
+				Id:                          s.gotoTargetHandles.create(pc),
+				Label:                       fmt.Sprintf("%s:%d", filepath.Base(loc.File), loc.Line),
+				Line:                        loc.Line,
+				InstructionPointerReference: fmt.Sprintf("%#x", pc),
+			})
+			break
+		}
+	}
+	if len(targets) == 0 {
+		s.sendErrorResponse(request.Request, UnableToGetGotoTargets, "Unable to get goto targets", fmt.Sprintf("%s is not in the current function %s", locStr, fn.Name))
+		return
+	}
+
+	s.send(&dap.GotoTargetsResponse{
+		Response: *s.newResponse(request.Request),
+		Body:     dap.GotoTargetsResponseBody{Targets: targets},
+	})
+}
+
+// onGotoRequest handles 'goto' requests.
+// This is an optional request enabled by capability 'supportsGotoTargetsRequest'.
+// The program counter of the goroutine is moved to the target, the code
+// between the current location and the target is not executed.
+func (s *Session) onGotoRequest(request *dap.GotoRequest) {
+	pc, ok := s.gotoTargetHandles.get(request.Arguments.TargetId)
+	if !ok {
+		s.sendErrorResponse(request.Request, UnableToGoto, "Unable to go to target", fmt.Sprintf("unknown goto target %d", request.Arguments.TargetId))
+		return
+	}
+	goid := int64(request.Arguments.ThreadId)
+	if err := s.debugger.JumpToPC(goid, pc); err != nil {
+		s.sendErrorResponse(request.Request, UnableToGoto, "Unable to go to target", err.Error())
+		return
+	}
+	s.send(&dap.GotoResponse{Response: *s.newResponse(request.Request)})
+
+	s.logToConsole("Warning: statements that were skipped were not executed, variables they initialize may contain unexpected values")
+	s.resetHandlesForStoppedEvent()
+	stopped := &dap.StoppedEvent{Event: *s.newEvent("stopped")}
+	stopped.Body.Reason = "goto"
+	stopped.Body.ThreadId = int(goid)
+	stopped.Body.AllThreadsStopped = true
+	s.send(stopped)
+}
+
 func (s *Session) doCall(goid, frame int, expr string) (*api.DebuggerState, []*proc.Variable, error) {
 	// This call might be evaluated in the context of the frame that is not topmost
 	// if the editor is set to view the variables for one of the parent frames.
@@ -4286,6 +4482,7 @@ Use 'Continue' to resume the original step command.`
 func (s *Session) resetHandlesForStoppedEvent() {
 	s.stackFrameHandles.reset()
 	s.variableHandles.reset()
+	s.gotoTargetHandles.reset()
 	s.referencesCollection.reset()
 	s.exceptionErr = nil
 }
@@ -4479,6 +4676,21 @@ func (s *Session) runUntilStopAndNotify(command string, allowNextStateChange *sy
 		}
 	}
 
+	// Watchpoints on stack variables are cleared by the debugger when the
+	// variable goes out of scope, let the client know they no longer exist.
+	if state != nil {
+		for _, wp := range state.WatchOutOfScope {
+			s.logToConsole(fmt.Sprintf("Data breakpoint %d went out of scope and was cleared", wp.ID))
+			s.send(&dap.BreakpointEvent{
+				Event: *s.newEvent("breakpoint"),
+				Body: dap.BreakpointEventBody{
+					Reason:     "removed",
+					Breakpoint: dap.Breakpoint{Id: wp.ID},
+				},
+			})
+		}
+	}
+
 	// NOTE: If we happen to be responding to another request with an is-running
 	// error while this one completes, it is possible that the error response
 	// will arrive after this stopped event.
@@ -4525,8 +4737,9 @@ func (s *Session) resumeOnceAndCheckStop(command string, allowNextStateChange *s
 
 	switch s.debugger.StopReason() {
 	case proc.StopBreakpoint, proc.StopManual:
-		// Make sure a real manual stop was requested or a real breakpoint was hit.
-		if len(gsOnBp) > 0 || s.checkHaltRequested() {
+		// Make sure a real manual stop was requested, a real breakpoint was hit
+		// or a watchpoint went out of scope.
+		if len(gsOnBp) > 0 || s.checkHaltRequested() || len(state.WatchOutOfScope) > 0 {
 			s.setRunningCmd(false)
 		}
 	default:
@@ -4389,6 +4389,161 @@ func TestEvaluateRequest(t *testing.T) {
 	})
 }
 
+func TestCompletionsRequest(t *testing.T) {
+	runTest(t, "testvariables", func(client *daptest.Client, fixture protest.Fixture) {
+		runDebugSessionWithBPs(t, client, "launch",
+			// Launch
+			func() {
+				client.LaunchRequest("exec", fixture.Path, !stopOnEntry)
+			},
+			fixture.Source, []int{}, // Breakpoint set in the program
+			[]onBreakpoint{{ // Stop at first breakpoint
+				execute: func() {
+					checkStop(t, client, 1, "main.foobar", []int{65, 66})
+
+					labels := func(got *dap.CompletionsResponse) []string {
+						sort.Slice(got.Body.Targets, func(i, j int) bool {
+							return got.Body.Targets[i].SortText < got.Body.Targets[j].SortText
+						})
+						r := []string{}
+						for _, item := range got.Body.Targets {
+							r = append(r, item.Label)
+						}
+						return r
+					}
+
+					// Locals of the selected frame
+					client.CompletionsRequest("a", 2, 1000)
+					got := client.ExpectCompletionsResponse(t)
+					if l := labels(got); !slices.Contains(l, "a1") || !slices.Contains(l, "a13") {
+						t.Errorf("\ngot %v\nwant a1...a13", l)
+					}
+					for _, item := range got.Body.Targets {
+						if item.Start != 1 || item.Length != 1 {
+							t.Errorf("\ngot %#v\nwant Start=1 Length=1", item)
+						}
+					}
+
+					// Locals come before functions of the current package
+					client.CompletionsRequest("print(bar", 10, 1000)
+					got = client.ExpectCompletionsResponse(t)
+					if l := labels(got); !slices.Equal(l, []string{"bar", "barfoo"}) {
+						t.Fatalf("\ngot %v\nwant [bar barfoo]", l)
+					}
+					if got.Body.Targets[0].Type != "variable" || got.Body.Targets[0].Detail != "main.FooBar" || got.Body.Targets[1].Type != "function" {
+						t.Errorf("\ngot %#v\nwant variable bar and function barfoo", got.Body.Targets)
+					}
+					if got.Body.Targets[0].Start != 7 || got.Body.Targets[0].Length != 3 {
+						t.Errorf("\ngot %#v\nwant Start=7 Length=3", got.Body.Targets[0])
+					}
+
+					// Package variables, qualified and unqualified
+					client.CompletionsRequest("p1", 3, 1000)
+					got = client.ExpectCompletionsResponse(t)
+					if l := labels(got); !slices.Contains(l, "p1") {
+						t.Errorf("\ngot %v\nwant p1", l)
+					}
+					client.CompletionsRequest("main.p", 7, 1000)
+					got = client.ExpectCompletionsResponse(t)
+					if l := labels(got); !slices.Contains(l, "main.p1") {
+						t.Errorf("\ngot %v\nwant main.p1", l)
+					}
+
+					// Locals of a different frame
+					client.CompletionsRequest("a", 2, 1001)
+					got = client.ExpectCompletionsResponse(t)
+					if l := labels(got); slices.Contains(l, "a1") {
+						t.Errorf("\ngot %v\nwant no a1 in main.main", l)
+					}
+				},
+				disconnect: true,
+			}})
+	})
+}
+
+func TestGotoRequest(t *testing.T) {
+	runTest(t, "testnextprog", func(client *daptest.Client, fixture protest.Fixture) {
+		runDebugSessionWithBPs(t, client, "launch",
+			// Launch
+			func() {
+				client.LaunchRequest("exec", fixture.Path, !stopOnEntry)
+			},
+			fixture.Source, []int{34},
+			[]onBreakpoint{{
+				execute: func() {
+					checkStop(t, client, 1, "main.testnext", 34)
+
+					// Lines outside of the current function are rejected
+					client.GotoTargetsRequest(fixture.Source, 42)
+					er := client.ExpectErrorResponse(t)
+					if er.Body.Error == nil || er.Body.Error.Id != UnableToGetGotoTargets {
+						t.Errorf("\ngot %#v\nwant Id=%d", er, UnableToGetGotoTargets)
+					}
+
+					client.GotoTargetsRequest(fixture.Source, 19)
+					gtr := client.ExpectGotoTargetsResponse(t)
+					if len(gtr.Body.Targets) != 1 || gtr.Body.Targets[0].Line != 19 {
+						t.Fatalf("\ngot %#v\nwant one target at line 19", gtr)
+					}
+
+					client.GotoRequest(1, gtr.Body.Targets[0].Id)
+					client.ExpectGotoResponse(t)
+					client.ExpectOutputEventRegex(t, "^Warning: statements that were skipped were not executed")
+					se := client.ExpectStoppedEvent(t)
+					if se.Body.Reason != "goto" || se.Body.ThreadId != 1 {
+						t.Errorf("\ngot %#v\nwant Reason=\"goto\" ThreadId=1", se)
+					}
+					checkStop(t, client, 1, "main.testnext", 19)
+
+					// Targets are invalidated by the stop
+					client.GotoRequest(1, gtr.Body.Targets[0].Id)
+					er = client.ExpectErrorResponse(t)
+					if er.Body.Error == nil || er.Body.Error.Id != UnableToGoto {
+						t.Errorf("\ngot %#v\nwant Id=%d", er, UnableToGoto)
+					}
+				},
+				disconnect: false,
+			}, {
+				// The loop is executed again
+				execute: func() {
+					checkStop(t, client, 1, "main.testnext", 34)
+				},
+				disconnect: true,
+			}})
+	})
+}
+
+func TestGotoTargetsInlinedCall(t *testing.T) {
+	// Lines of a function inlined into the current function are not valid
+	// goto targets, they belong to the inlined calls.
+	runTestBuildFlags(t, "testinline", func(client *daptest.Client, fixture protest.Fixture) {
+		runDebugSessionWithBPs(t, client, "launch",
+			// Launch
+			func() {
+				client.LaunchRequest("exec", fixture.Path, !stopOnEntry)
+			},
+			fixture.Source, []int{17},
+			[]onBreakpoint{{
+				execute: func() {
+					checkStop(t, client, 1, "main.main", 17)
+
+					client.GotoTargetsRequest(fixture.Source, 6)
+					er := client.ExpectErrorResponse(t)
+					if er.Body.Error == nil || er.Body.Error.Id != UnableToGetGotoTargets {
+						t.Errorf("\ngot %#v\nwant Id=%d", er, UnableToGetGotoTargets)
+					}
+
+					client.GotoTargetsRequest(fixture.Source, 19)
+					gtr := client.ExpectGotoTargetsResponse(t)
+					if len(gtr.Body.Targets) != 1 || gtr.Body.Targets[0].Line != 19 {
+						t.Fatalf("\ngot %#v\nwant one target at line 19", gtr)
+					}
+				},
+				disconnect: true,
+			}})
+	}, protest.EnableInlining, false)
+}
+
 func formatConfig(depth int, showGlobals, showRegisters bool, goroutineFilters string, showPprofLabels []string, hideSystemGoroutines bool, substitutePath [][2]string, followExec bool, followExecRegex string) string {
 	formatStr := `stackTraceDepth	%d
 showGlobalVariables	%v
@@ -7129,7 +7284,7 @@ func TestBadLaunchRequests(t *testing.T) {
 		if _, err := exec.LookPath("rr"); err != nil {
 			client.LaunchRequestWithArgs(map[string]any{"mode": "replay", "backend": "ignored", "traceDirPath": ".."})
 			checkFailedToLaunchWithMessage(client.ExpectVisibleErrorResponse(t),
-				"Failed to launch: backend unavailable")
+				"Failed to launch: rr not found: install it from https://rr-project.org/ or with your distribution's package manager (for example 'apt install rr' or 'dnf install rr') and make sure it is in PATH")
 		}
 
 		// Bad "core" parameters
@@ -8809,6 +8964,22 @@ func TestDataBreakpoints(t *testing.T) {
 			t.Fatalf("got %#v\nwant line 16 or 17\n", frame)
 		}
 
+		// Returning from f makes w go out of scope.
+		client.ContinueRequest(1)
+		client.ExpectContinueResponse(t)
+		client.ExpectOutputEventRegex(t, `Data breakpoint \d+ went out of scope and was cleared`)
+		be := client.ExpectBreakpointEvent(t)
+		if be.Body.Reason != "removed" {
+			t.Fatalf("\ngot %#v\nwant removed breakpoint event\n", be)
+		}
+		client.ExpectStoppedEvent(t)
+
+		client.SetDataBreakpointsRequest([]string{})
+		sdbpr := client.ExpectSetDataBreakpointsResponse(t)
+		if len(sdbpr.Body.Breakpoints) != 0 {
+			t.Fatalf("\ngot %#v\nwant no data breakpoints\n", sdbpr)
+		}
+
 		teardown(client)
 	})
 }
@@ -1,13 +1,21 @@
 package debugger
 
 import (
+	"bufio"
+	"cmp"
 	"debug/dwarf"
 	"debug/elf"
 	"debug/macho"
 	"debug/pe"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"hash/fnv"
 	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path"
@@ -16,6 +24,7 @@ import (
 	"runtime"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -113,6 +122,13 @@ type Config struct {
 	// AttachWaitForDuration is the time (in milliseconds) that the debugger
 	// waits for WaitFor.
 	AttachWaitForDuration float64
+	// CheckAttachUser is true if the debugger should refuse to attach to
+	// processes owned by a different user, unless it is privileged.
+	CheckAttachUser bool
+	// AttachDebugInfo is the path of a file containing the debug info of
+	// the process we are attaching to, used when the executable is
+	// stripped.
+	AttachDebugInfo string
 
 	// CoreFile specifies the path to the core dump to open.
 	CoreFile string
@@ -160,6 +176,10 @@ type Config struct {
 
 	RrOnProcessPid int
 	RrDelOnDetach  bool
+
+	// MaxStackDepth is the maximum number of frames unwound by a stacktrace,
+	// see proc.TargetGroup.MaxStackDepth.
+	MaxStackDepth int
 }
 
 // New creates a new Debugger. ProcessArgs specify the commandline arguments for the
@@ -193,6 +213,11 @@ func New(config *Config, processArgs []string) (*Debugger, error) {
 				Duration: time.Duration(d.config.AttachWaitForDuration * float64(time.Millisecond)),
 			}
 		}
+		if d.config.CheckAttachUser && d.config.AttachPid > 0 {
+			if err := checkAttachUser(d.config.AttachPid); err != nil {
+				return nil, err
+			}
+		}
 		var err error
 		d.target, err = d.Attach(d.config.AttachPid, path, waitFor)
 		if err != nil {
@@ -208,7 +233,13 @@ func New(config *Config, processArgs []string) (*Debugger, error) {
 		case "rr":
 			d.log.Infof("opening trace %s", d.config.CoreFile)
 			d.target, err = gdbserial.Replay(d.config.CoreFile, false, d.config.RrDelOnDetach, d.config.DebugInfoDirectories, d.config.RrOnProcessPid, "")
+			err = betterRRError(err)
 		default:
+			if d.config.Backend != "default" && d.config.Backend != "core" {
+				d.log.Warnf("backend %q can not open core files, using core backend", d.config.Backend)
+			} else {
+				d.log.Infof("using core backend")
+			}
 			d.log.Infof("opening core file %s (executable %s)", d.config.CoreFile, d.processArgs[0])
 			d.target, err = core.OpenCore(d.config.CoreFile, d.processArgs[0], d.config.DebugInfoDirectories)
 		}
@@ -237,11 +268,30 @@ func New(config *Config, processArgs []string) (*Debugger, error) {
 			d.target.Detach(true)
 			return nil, err
 		}
+		if d.config.ExecuteKind == ExecutingGeneratedFile || d.config.ExecuteKind == ExecutingGeneratedTest {
+			d.checkGeneratedBinary()
+		}
+	}
+
+	if d.target != nil {
+		d.target.MaxStackDepth = d.config.MaxStackDepth
 	}
 
 	return d, nil
 }
 
+// checkGeneratedBinary prints a warning if the executable built by Delve
+// does not look like a debuggable Go program, this usually happens when
+// the build flags conflict with the ones used by Delve.
+func (d *Debugger) checkGeneratedBinary() {
+	if d.isRecording() {
+		return
+	}
+	if d.target.Selected.BinInfo().LookupFunc()["main.main"] == nil {
+		fmt.Fprintf(os.Stderr, "Warning: main.main not found in %s, check the build flags (%v)\n", d.processArgs[0], d.config.BuildFlags)
+	}
+}
+
 // canRestart returns true if the target was started with Launch and can be restarted
 func (d *Debugger) canRestart() bool {
 	switch {
@@ -281,6 +331,10 @@ func (d *Debugger) Launch(processArgs []string, wd string) (*proc.TargetGroup, e
 	}
 	processArgs[0] = fullpath
 
+	if err := verifyWorkingDir(wd); err != nil {
+		return nil, err
+	}
+
 	launchFlags := proc.LaunchFlags(0)
 	if d.config.Foreground {
 		launchFlags |= proc.LaunchForeground
@@ -289,7 +343,12 @@ func (d *Debugger) Launch(processArgs []string, wd string) (*proc.TargetGroup, e
 		launchFlags |= proc.LaunchDisableASLR
 	}
 
-	switch d.config.Backend {
+	backend, err := d.selectBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend {
 	case "native":
 		return native.Launch(processArgs, wd, launchFlags, d.config.DebugInfoDirectories, d.config.TTY, d.config.Stdin, d.config.Stdout, d.config.Stderr)
 	case "lldb":
@@ -302,7 +361,7 @@ func (d *Debugger) Launch(processArgs []string, wd string) (*proc.TargetGroup, e
 
 		run, stop, err := gdbserial.RecordAsync(processArgs, wd, false, d.config.Stdin, d.config.Stdout, d.config.Stderr)
 		if err != nil {
-			return nil, err
+			return nil, betterRRError(err)
 		}
 
 		// let the initialization proceed but hold the targetMutex lock so that
@@ -323,6 +382,7 @@ func (d *Debugger) Launch(processArgs []string, wd string) (*proc.TargetGroup, e
 			}
 			d.recordingDone()
 			d.target = grp
+			d.target.MaxStackDepth = d.config.MaxStackDepth
 			if err := d.checkGoVersion(); err != nil {
 				d.log.Error(err)
 				err := d.target.Detach(true)
@@ -333,11 +393,6 @@ func (d *Debugger) Launch(processArgs []string, wd string) (*proc.TargetGroup, e
 		}()
 		return nil, nil
 
-	case "default":
-		if runtime.GOOS == "darwin" {
-			return betterGdbserialLaunchError(gdbserial.LLDBLaunch(processArgs, wd, launchFlags, d.config.DebugInfoDirectories, d.config.TTY, [3]string{d.config.Stdin, d.config.Stdout.Path, d.config.Stderr.Path}))
-		}
-		return native.Launch(processArgs, wd, launchFlags, d.config.DebugInfoDirectories, d.config.TTY, d.config.Stdin, d.config.Stdout, d.config.Stderr)
 	default:
 		return nil, fmt.Errorf("unknown backend %q", d.config.Backend)
 	}
@@ -372,21 +427,77 @@ func (d *Debugger) recordingRun(run func() (string, error)) (*proc.TargetGroup,
 
 // Attach will attach to the process specified by 'pid'.
 func (d *Debugger) Attach(pid int, path string, waitFor *proc.WaitFor) (*proc.TargetGroup, error) {
-	switch d.config.Backend {
+	backend, err := d.selectBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend {
 	case "native":
-		return native.Attach(pid, waitFor, d.config.DebugInfoDirectories)
+		return native.Attach(pid, waitFor, d.config.DebugInfoDirectories, d.config.AttachDebugInfo)
 	case "lldb":
-		return betterGdbserialLaunchError(gdbserial.LLDBAttach(pid, path, waitFor, d.config.DebugInfoDirectories))
-	case "default":
-		if runtime.GOOS == "darwin" {
-			return betterGdbserialLaunchError(gdbserial.LLDBAttach(pid, path, waitFor, d.config.DebugInfoDirectories))
+		if d.config.AttachDebugInfo != "" {
+			return nil, errors.New("loading debug info from a separate file is not supported by the lldb backend")
 		}
-		return native.Attach(pid, waitFor, d.config.DebugInfoDirectories)
+		return betterGdbserialLaunchError(gdbserial.LLDBAttach(pid, path, waitFor, d.config.DebugInfoDirectories))
+	case "rr":
+		return nil, errors.New("can not attach to a process with the rr backend")
 	default:
 		return nil, fmt.Errorf("unknown backend %q", d.config.Backend)
 	}
 }
 
+// selectBackend resolves the configured backend and checks that it is
+// supported by this build of Delve, the choice is logged so that users can
+// find out which backend was used and why.
+func (d *Debugger) selectBackend() (string, error) {
+	backend := ResolveBackend(d.config.Backend)
+	for _, b := range Backends() {
+		if b.Name != backend {
+			continue
+		}
+		if !b.Supported {
+			return "", fmt.Errorf("%s backend is not supported on %s/%s", backend, runtime.GOOS, runtime.GOARCH)
+		}
+		if d.config.Backend == "default" {
+			d.log.Infof("no backend specified, using %s backend (default on %s)", backend, runtime.GOOS)
+		} else {
+			d.log.Infof("using %s backend", backend)
+		}
+		return backend, nil
+	}
+	return "", fmt.Errorf("unknown backend %q", d.config.Backend)
+}
+
+// ResolveBackend returns the name of the backend that will be used when
+// backend is "default" on the current platform.
+func ResolveBackend(backend string) string {
+	if backend != "default" {
+		return backend
+	}
+	if runtime.GOOS == "darwin" {
+		return "lldb"
+	}
+	return "native"
+}
+
+// BackendSupport describes whether a backend is compiled into this build
+// of Delve for the current platform.
+type BackendSupport struct {
+	Name      string `json:"name"`
+	Supported bool   `json:"supported"`
+}
+
+// Backends returns the list of backends known to Delve and whether each
+// one is supported by this build on the current platform.
+func Backends() []BackendSupport {
+	return []BackendSupport{
+		{Name: "native", Supported: native.Enabled},
+		{Name: "lldb", Supported: true},
+		{Name: "rr", Supported: runtime.GOOS == "linux"},
+	}
+}
+
 var errMacOSBackendUnavailable = errors.New("debugserver or lldb-server not found: install Xcode's command line tools or lldb-server")
 
 func betterGdbserialLaunchError(p *proc.TargetGroup, err error) (*proc.TargetGroup, error) {
@@ -400,6 +511,15 @@ func betterGdbserialLaunchError(p *proc.TargetGroup, err error) (*proc.TargetGro
 	return p, errMacOSBackendUnavailable
 }
 
+var errRRBackendUnavailable = errors.New("rr not found: install it from https://rr-project.org/ or with your distribution's package manager (for example 'apt install rr' or 'dnf install rr') and make sure it is in PATH")
+
+func betterRRError(err error) error {
+	if !errors.Is(err, &gdbserial.ErrBackendUnavailable{}) {
+		return err
+	}
+	return errRRBackendUnavailable
+}
+
 // ProcessPid returns the PID of the process
 // the debugger is debugging.
 func (d *Debugger) ProcessPid() int {
@@ -467,6 +587,55 @@ func (d *Debugger) FunctionReturnLocations(fnName string) ([]uint64, error) {
 	return d.functionReturnLocationsInternal(fnName)
 }
 
+// AddressToLine returns the source location of pc and the context lines
+// of source surrounding it. If pc has no line information the location of
+// the entry point of the function containing it is returned instead.
+func (d *Debugger) AddressToLine(pc uint64, context int) (*api.AddressLocation, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+	bi := d.target.Selected.BinInfo()
+	file, line, fn := bi.PCToLine(pc)
+	if fn == nil {
+		return nil, fmt.Errorf("could not find function containing %#x", pc)
+	}
+	r := &api.AddressLocation{Exact: true}
+	if line == 0 {
+		r.Exact = false
+		pc = fn.Entry
+		file, line, _ = bi.PCToLine(pc)
+	}
+	r.Location = api.ConvertLocation(proc.Location{PC: pc, File: file, Line: line, Fn: fn})
+	if context > 0 && file != "" {
+		r.FirstLine, r.Lines = readSourceLines(file, line-context, line+context)
+	}
+	return r, nil
+}
+
+// readSourceLines returns the lines of file between first and last
+// (inclusive) and the number of the first line returned. Errors reading
+// the file are ignored.
+func readSourceLines(file string, first, last int) (int, []string) {
+	if first < 1 {
+		first = 1
+	}
+	fh, err := os.Open(file)
+	if err != nil {
+		return 0, nil
+	}
+	defer fh.Close()
+	var lines []string
+	scan := bufio.NewScanner(fh)
+	for n := 1; n <= last && scan.Scan(); n++ {
+		if n >= first {
+			lines = append(lines, scan.Text())
+		}
+	}
+	if len(lines) == 0 {
+		return 0, nil
+	}
+	return first, lines
+}
+
 // Detach detaches from the target process.
 // If `kill` is true we will kill the process after
 // detaching.
@@ -817,11 +986,30 @@ func (d *Debugger) CreateBreakpoint(requestedBp *api.Breakpoint, locExpr string,
 		}
 	}
 
+	if lbp.TraceReturn {
+		d.followTraceEntry(lbp)
+	}
+
 	createdBp := d.convertBreakpoint(lbp)
 	d.log.Infof("created breakpoint: %#v", createdBp)
 	return createdBp, nil
 }
 
+// followTraceEntry makes the return tracepoint lbp follow the tracepoint set
+// on the entry of the same function, if there is one, so that the condition
+// of the entry tracepoint also decides which returns are traced.
+func (d *Debugger) followTraceEntry(lbp *proc.LogicalBreakpoint) {
+	if lbp.FunctionName == "" {
+		return
+	}
+	for _, entry := range d.target.LogicalBreakpoints {
+		if entry.Tracepoint && entry.FunctionName == lbp.FunctionName {
+			lbp.FollowTraceEntry(entry)
+			return
+		}
+	}
+}
+
 func (d *Debugger) convertBreakpoint(lbp *proc.LogicalBreakpoint) *api.Breakpoint {
 	abp := api.ConvertLogicalBreakpoint(lbp)
 	bps := []*proc.Breakpoint{}
@@ -880,6 +1068,10 @@ func (d *Debugger) amendBreakpoint(amend *api.Breakpoint) error {
 }
 
 func (d *Debugger) isWatchpoint(lbp *proc.LogicalBreakpoint) bool {
+	if lbp.Set.FieldWatch != nil {
+		// field watchpoints can be set again after being disabled
+		return false
+	}
 	t := proc.ValidTargets{Group: d.target}
 	for t.Next() {
 		for _, bp := range t.Breakpoints().M {
@@ -912,6 +1104,7 @@ func (d *Debugger) copyLogicalBreakpointInfo(lbp *proc.LogicalBreakpoint, reques
 	lbp.Name = requested.Name
 	lbp.Tracepoint = requested.Tracepoint
 	lbp.TraceReturn = requested.TraceReturn
+	lbp.TraceEval = requested.TraceEval
 	lbp.Goroutine = requested.Goroutine
 	lbp.Stacktrace = requested.Stacktrace
 	lbp.Variables = requested.Variables
@@ -921,6 +1114,8 @@ func (d *Debugger) copyLogicalBreakpointInfo(lbp *proc.LogicalBreakpoint, reques
 	lbp.UserData = requested.UserData
 	lbp.RootFuncName = requested.RootFuncName
 	lbp.TraceFollowCalls = requested.TraceFollowCalls
+	lbp.WatchFollow = requested.WatchFollow
+	lbp.GoroutineLabels = requested.GoroutineLabels
 
 	return d.target.ChangeBreakpointCondition(lbp, requested.Cond, requested.HitCond, requested.HitCondPerG)
 }
@@ -1035,6 +1230,45 @@ func (d *Debugger) CreateWatchpoint(goid int64, frame, deferredCall int, expr st
 	return d.convertBreakpoint(bp.Logical), nil
 }
 
+// CreateFieldWatchpoint creates a watchpoint on a field of all instances of
+// a struct type allocated from now on, typeField has the form
+// <type>.<field>. At most max instances are watched at the same time, if
+// max is 0 the number of watched instances is only limited by the hardware.
+func (d *Debugger) CreateFieldWatchpoint(typeField string, wtype api.WatchType, max int) (*api.Breakpoint, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	idx := strings.LastIndex(typeField, ".")
+	if idx <= 0 || idx == len(typeField)-1 {
+		return nil, fmt.Errorf("wrong field specification %q, must be <type>.<field>", typeField)
+	}
+	if max < 0 {
+		return nil, fmt.Errorf("invalid maximum number of watchpoints %d", max)
+	}
+
+	d.breakpointIDCounter++
+	id := d.breakpointIDCounter
+	lbp := &proc.LogicalBreakpoint{
+		LogicalID: id,
+		HitCount:  make(map[int64]uint64),
+		Set: proc.SetBreakpoint{FieldWatch: &proc.FieldWatch{
+			Type:      typeField[:idx],
+			Field:     typeField[idx+1:],
+			WatchType: proc.WatchType(wtype),
+			Max:       max,
+		}},
+	}
+	if d.findBreakpointByName(typeField) == nil {
+		lbp.Name = typeField
+	}
+	d.target.LogicalBreakpoints[id] = lbp
+	if err := d.target.SetBreakpointEnabled(lbp, true); err != nil {
+		delete(d.target.LogicalBreakpoints, id)
+		return nil, err
+	}
+	return d.convertBreakpoint(lbp), nil
+}
+
 // Threads returns the threads of the target process.
 func (d *Debugger) Threads() ([]proc.Thread, error) {
 	d.targetMutex.Lock()
@@ -1047,6 +1281,20 @@ func (d *Debugger) Threads() ([]proc.Thread, error) {
 	return d.target.ThreadList(), nil
 }
 
+// ThreadOSInfo returns a description of the scheduling state of thread th
+// (for example "sleeping") and the CPU it last executed on, as reported by
+// the operating system. The state is empty and the CPU is -1 if they are not
+// available, which is always the case for core files (the NT_PRSTATUS notes
+// written by linux do not record them), recordings and on platforms other
+// than linux.
+// The caller must hold the target group lock.
+func (d *Debugger) ThreadOSInfo(th proc.Thread) (state string, lastCPU int) {
+	if recorded, _ := d.target.Recorded(); recorded || d.config.CoreFile != "" {
+		return "", -1
+	}
+	return threadOSInfo(d.target.Selected.Pid(), th.ThreadID())
+}
+
 // FindThread returns the thread for the given 'id'.
 func (d *Debugger) FindThread(id int) (proc.Thread, error) {
 	d.targetMutex.Lock()
@@ -1118,6 +1366,14 @@ func (d *Debugger) Command(command *api.DebuggerCommand, resumeNotify chan struc
 		if eventsFn != nil {
 			eventsFn(&proc.Event{Kind: proc.EventResumed})
 			defer eventsFn(&proc.Event{Kind: proc.EventStopped})
+
+			clientEventsFn := eventsFn
+			eventsFn = func(event *proc.Event) {
+				if event.SignalEventDetails != nil {
+					event.SignalEventDetails.Name = signalNames()[event.SignalEventDetails.Signal]
+				}
+				clientEventsFn(event)
+			}
 		}
 
 		d.target.SetEventsFn(eventsFn)
@@ -1125,6 +1381,8 @@ func (d *Debugger) Command(command *api.DebuggerCommand, resumeNotify chan struc
 		close(resumeNotify)
 	}
 
+	d.target.SingleGoroutineStepping = command.SingleGoroutine
+
 	switch command.Name {
 	case api.Continue:
 		d.log.Debug("continuing")
@@ -1150,7 +1408,11 @@ func (d *Debugger) Command(command *api.DebuggerCommand, resumeNotify chan struc
 				return nil, err
 			}
 		}
-		err = proc.EvalExpressionWithCalls(d.target, g, command.Expr, *api.LoadConfigToProc(command.ReturnInfoLoadConfig), !command.UnsafeCall)
+		evalCfg := proc.EvalConfig{CheckEscape: !command.UnsafeCall}
+		if command.EvalConfig != nil {
+			evalCfg.CallTimeout = command.EvalConfig.CallTimeout
+		}
+		err = proc.EvalExpressionWithCalls(d.target, g, command.Expr, *api.LoadConfigToProc(command.ReturnInfoLoadConfig), evalCfg)
 	case api.Rewind:
 		d.log.Debug("rewinding")
 		if err := d.target.ChangeDirection(proc.Backward); err != nil {
@@ -1174,6 +1436,7 @@ func (d *Debugger) Command(command *api.DebuggerCommand, resumeNotify chan struc
 		if err := d.target.ChangeDirection(proc.Forward); err != nil {
 			return nil, err
 		}
+		d.target.StepSkipNoDebug = command.SkipNoDebug
 		err = d.target.Step()
 	case api.ReverseStep:
 		d.log.Debug("reverse stepping")
@@ -1186,25 +1449,25 @@ func (d *Debugger) Command(command *api.DebuggerCommand, resumeNotify chan struc
 		if err := d.target.ChangeDirection(proc.Forward); err != nil {
 			return nil, err
 		}
-		err = d.target.StepInstruction(false)
+		err = d.target.StepInstructions(false, max(command.Count, 1))
 	case api.ReverseStepInstruction:
 		d.log.Debug("reverse single stepping")
 		if err := d.target.ChangeDirection(proc.Backward); err != nil {
 			return nil, err
 		}
-		err = d.target.StepInstruction(false)
+		err = d.target.StepInstructions(false, max(command.Count, 1))
 	case api.NextInstruction:
 		d.log.Debug("single stepping")
 		if err := d.target.ChangeDirection(proc.Forward); err != nil {
 			return nil, err
 		}
-		err = d.target.StepInstruction(true)
+		err = d.target.StepInstructions(true, max(command.Count, 1))
 	case api.ReverseNextInstruction:
 		d.log.Debug("reverse single stepping")
 		if err := d.target.ChangeDirection(proc.Backward); err != nil {
 			return nil, err
 		}
-		err = d.target.StepInstruction(true)
+		err = d.target.StepInstructions(true, max(command.Count, 1))
 	case api.StepOut:
 		d.log.Debug("step out")
 		if err := d.target.ChangeDirection(proc.Forward); err != nil {
@@ -1373,6 +1636,31 @@ func (d *Debugger) Sources(filter string) ([]string, error) {
 	return files, nil
 }
 
+// SourceFiles returns a list of the source files of the target process
+// matching filter, along with the compile unit each of them belongs to.
+func (d *Debugger) SourceFiles(filter string) ([]proc.SourceFileInfo, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	regex, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter argument: %s", err.Error())
+	}
+
+	files := []proc.SourceFileInfo{}
+	t := proc.ValidTargets{Group: d.target}
+	for t.Next() {
+		for _, f := range t.BinInfo().ListSourceFiles() {
+			if regex.MatchString(f.Path) {
+				files = append(files, f)
+			}
+		}
+	}
+	slices.SortStableFunc(files, func(a, b proc.SourceFileInfo) int { return cmp.Compare(a.Path, b.Path) })
+	files = slices.CompactFunc(files, func(a, b proc.SourceFileInfo) bool { return a.Path == b.Path })
+	return files, nil
+}
+
 // Functions returns a list of functions in the target process.
 func (d *Debugger) Functions(filter string, followCalls int) ([]string, error) {
 	d.targetMutex.Lock()
@@ -1686,6 +1974,80 @@ func (d *Debugger) ThreadRegisters(threadID int) (*op.DwarfRegisters, proc.Dwarf
 	return d.target.Selected.BinInfo().Arch.RegistersToDwarfRegisters(0, regs), d.target.Selected.BinInfo().Arch.DwarfRegisterToString, nil
 }
 
+// SetRegister changes the value of the general purpose register called
+// name of the specified thread.
+func (d *Debugger) SetRegister(threadID int, name string, value uint64) error {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	return d.target.Selected.SetRegister(threadID, name, value)
+}
+
+// JumpToPC moves the program counter of the thread running goroutine goid
+// to pc, which must belong to the function the goroutine is currently
+// executing. If the goroutine is stopped inside an inlined call pc must
+// belong to the same inlined call, targets inside calls inlined into the
+// current function are rejected.
+// Instructions between the old and the new PC are not executed.
+func (d *Debugger) JumpToPC(goid int64, pc uint64) error {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	t := d.target.Selected
+	g, err := checkJumpToPC(t, goid, pc)
+	if err != nil {
+		return err
+	}
+	bi := t.BinInfo()
+	return t.SetRegister(g.Thread.ThreadID(), bi.Arch.RegnumToString(bi.Arch.PCRegNum), pc)
+}
+
+// CheckJumpToPC returns an error if pc is not a valid target for
+// JumpToPC(goid, pc).
+func (d *Debugger) CheckJumpToPC(goid int64, pc uint64) error {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	_, err := checkJumpToPC(d.target.Selected, goid, pc)
+	return err
+}
+
+func checkJumpToPC(t *proc.Target, goid int64, pc uint64) (*proc.G, error) {
+	if _, err := t.Valid(); err != nil {
+		return nil, err
+	}
+	g, err := proc.FindGoroutine(t, goid)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil || g.Thread == nil {
+		return nil, fmt.Errorf("goroutine %d is not running on a thread", goid)
+	}
+	frames, err := proc.GoroutineStacktrace(t, g, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(frames) == 0 || frames[0].Call.Fn == nil {
+		return nil, fmt.Errorf("could not find the current function of goroutine %d", goid)
+	}
+	topframe := &frames[0]
+	curfn := topframe.Call.Fn
+	ok, err := topframe.ExecutesPC(pc)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if t.BinInfo().PCToFunc(pc) == topframe.Current.Fn {
+			return nil, fmt.Errorf("%#x belongs to an inlined call, not to the current function %s", pc, curfn.Name)
+		}
+		return nil, fmt.Errorf("%#x is not in the current function %s", pc, curfn.Name)
+	}
+	if pc == topframe.Current.Fn.Entry {
+		return nil, errors.New("can not jump to the entry point of the current function")
+	}
+	return g, nil
+}
+
 // ScopeRegisters returns registers for the specified scope.
 func (d *Debugger) ScopeRegisters(goid int64, frame, deferredCall int) (*op.DwarfRegisters, proc.DwarfRegisterToStringFunc, error) {
 	d.targetMutex.Lock()
@@ -1710,6 +2072,91 @@ func (d *Debugger) LocalVariables(goid int64, frame, deferredCall int, cfg proc.
 	return s.LocalVariables(cfg)
 }
 
+// TypeArguments returns the type arguments of the instantiation of the
+// generic function executing in the specified scope. The names of the type
+// parameters, which are not recorded in the debug info, are read from the
+// source file, after applying substitutePathRules to its path. If it can
+// not be read they are named after their position.
+func (d *Debugger) TypeArguments(goid int64, frame, deferredCall int, substitutePathRules [][2]string) ([]api.TypeArgument, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	s, err := proc.ConvertEvalScope(d.target.Selected, goid, frame, deferredCall)
+	if err != nil {
+		return nil, err
+	}
+	types, err := s.TypeArguments()
+	if err != nil || len(types) == 0 {
+		return nil, err
+	}
+	file, line, _ := d.target.Selected.BinInfo().PCToLine(s.Fn.Entry)
+	names := typeParamNames(locspec.SubstitutePath(file, substitutePathRules), line, s.Fn)
+	r := make([]api.TypeArgument, len(types))
+	for i := range types {
+		if types[i] != nil {
+			r[i].Type = api.PrettyTypeName(types[i])
+		}
+		if len(names) == len(types) {
+			r[i].Name = names[i]
+		} else {
+			r[i].Name = fmt.Sprintf(".param%d", i)
+		}
+	}
+	return r, nil
+}
+
+// typeParamNames returns the names of the type parameters of fn by parsing
+// its declaration in file, at the specified line.
+func typeParamNames(file string, line int, fn *proc.Function) []string {
+	fset := token.NewFileSet()
+	root, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	for _, decl := range root.Decls {
+		fdecl, ok := decl.(*ast.FuncDecl)
+		if !ok || fdecl.Name.Name != clearTypeParams(fn.BaseName()) || fset.Position(fdecl.Pos()).Line != line {
+			continue
+		}
+		var names []string
+		if fdecl.Recv != nil && len(fdecl.Recv.List) == 1 {
+			rtyp := fdecl.Recv.List[0].Type
+			if star, ok := rtyp.(*ast.StarExpr); ok {
+				rtyp = star.X
+			}
+			var indices []ast.Expr
+			switch rtyp := rtyp.(type) {
+			case *ast.IndexExpr:
+				indices = []ast.Expr{rtyp.Index}
+			case *ast.IndexListExpr:
+				indices = rtyp.Indices
+			}
+			for _, idx := range indices {
+				if id, ok := idx.(*ast.Ident); ok {
+					names = append(names, id.Name)
+				}
+			}
+			return names
+		}
+		if fdecl.Type.TypeParams != nil {
+			for _, field := range fdecl.Type.TypeParams.List {
+				for _, name := range field.Names {
+					names = append(names, name.Name)
+				}
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+func clearTypeParams(name string) string {
+	if i := strings.Index(name, "["); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
 // FunctionArguments returns the arguments to the current function.
 func (d *Debugger) FunctionArguments(goid int64, frame, deferredCall int, cfg proc.LoadConfig) ([]*proc.Variable, error) {
 	d.targetMutex.Lock()
@@ -1747,6 +2194,51 @@ func (d *Debugger) EvalVariableInScope(goid int64, frame, deferredCall int, expr
 	return s.EvalExpression(expr, cfg)
 }
 
+// EvalGoroutines evaluates expr in the topmost frame of every goroutine.
+// Errors that only affect a single goroutine (for example because expr
+// references a variable that does not exist in its topmost frame) are
+// returned in the second map, keyed by goroutine ID, instead of aborting
+// the evaluation.
+func (d *Debugger) EvalGoroutines(expr string, cfg proc.LoadConfig) (map[int64]*proc.Variable, map[int64]error, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	t := d.target.Selected
+	if _, err := t.Valid(); err != nil {
+		return nil, nil, err
+	}
+	gs, _, err := proc.GoroutinesInfo(t, 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	vars := make(map[int64]*proc.Variable)
+	errs := make(map[int64]error)
+	for _, g := range gs {
+		v, err := evalInGoroutine(t, g, expr, cfg)
+		if err != nil {
+			errs[g.ID] = err
+			continue
+		}
+		vars[g.ID] = v
+	}
+	return vars, errs, nil
+}
+
+func evalInGoroutine(t *proc.Target, g *proc.G, expr string, cfg proc.LoadConfig) (*proc.Variable, error) {
+	threadID := t.CurrentThread().ThreadID()
+	if g.Thread != nil {
+		threadID = g.Thread.ThreadID()
+	}
+	locs, err := proc.GoroutineStacktrace(t, g, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(locs) == 0 {
+		return nil, fmt.Errorf("goroutine %d has no stack frames", g.ID)
+	}
+	return proc.FrameToScope(t, t.Memory(), g, threadID, locs...).EvalExpression(expr, cfg)
+}
+
 // LoadResliced will attempt to 'reslice' a map, array or slice so that the values
 // up to cfg.MaxArrayValues children are loaded starting from index start.
 func (d *Debugger) LoadResliced(v *proc.Variable, start int, cfg proc.LoadConfig) (*proc.Variable, error) {
@@ -1826,6 +2318,10 @@ func matchGoroutineFilter(tgt *proc.Target, g *proc.G, filter *api.ListGoroutine
 		val = !g.System(tgt)
 	case api.GoroutineWaitingOnChannel:
 		val = true // handled elsewhere
+	case api.GoroutineWaitReason:
+		if reason := goroutineWaitReason(tgt, g); reason != "" {
+			val = strings.Contains(strings.ToLower(reason), strings.ToLower(filter.Arg))
+		}
 	}
 	if filter.Negated {
 		val = !val
@@ -1833,6 +2329,16 @@ func matchGoroutineFilter(tgt *proc.Target, g *proc.G, filter *api.ListGoroutine
 	return val
 }
 
+// goroutineWaitReason returns a description of the reason g is blocked or
+// the empty string if g is not blocked.
+func goroutineWaitReason(tgt *proc.Target, g *proc.G) string {
+	if (g.Status != proc.Gwaiting && g.Status != proc.Gsyscall) || g.WaitReason == 0 {
+		return ""
+	}
+	ver := goversion.ParseProducer(tgt.BinInfo().Producer())
+	return api.WaitReasonString(&ver, g.WaitReason)
+}
+
 func matchGoroutineLocFilter(loc proc.Location, arg string) bool {
 	return strings.Contains(formatLoc(loc), arg)
 }
@@ -1882,6 +2388,8 @@ func (d *Debugger) GroupGoroutines(gs []*proc.G, group *api.GoroutineGroupingOpt
 			key = fmt.Sprintf("running=%v", g.Thread != nil)
 		case api.GoroutineUser:
 			key = fmt.Sprintf("user=%v", !g.System(d.target.Selected))
+		case api.GoroutineWaitReason:
+			key = fmt.Sprintf("reason=%s", goroutineWaitReason(d.target.Selected, g))
 		}
 		if len(groupMembers[key]) < group.MaxGroupMembers {
 			groupMembers[key] = append(groupMembers[key], g)
@@ -1909,6 +2417,62 @@ func (d *Debugger) GroupGoroutines(gs []*proc.G, group *api.GoroutineGroupingOpt
 	return gsout, groups, tooManyGroups
 }
 
+// GroupGoroutinesByStack groups goroutines that have the same stack, the
+// stack of each goroutine is read up to the specified depth.
+// For each group at most maxGroupMembers goroutine IDs are returned.
+// Groups are sorted by decreasing size.
+func (d *Debugger) GroupGoroutinesByStack(gs []*proc.G, depth, maxGroupMembers int) ([]api.GoroutineStackGroup, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	if _, err := d.target.Valid(); err != nil {
+		return nil, err
+	}
+
+	groups := []api.GoroutineStackGroup{}
+	groupIdx := map[string]int{}
+
+	for _, g := range gs {
+		frames, err := proc.GoroutineStacktrace(d.target.Selected, g, depth, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		h := fnv.New64a()
+		var buf [8]byte
+		for i := range frames {
+			binary.LittleEndian.PutUint64(buf[:], frames[i].Call.PC)
+			h.Write(buf[:])
+		}
+		id := fmt.Sprintf("%016x", h.Sum64())
+
+		i, ok := groupIdx[id]
+		if !ok {
+			stack, err := d.convertStacktrace(frames, nil)
+			if err != nil {
+				return nil, err
+			}
+			i = len(groups)
+			groupIdx[id] = i
+			groups = append(groups, api.GoroutineStackGroup{ID: id, Stack: stack})
+		}
+		group := &groups[i]
+		group.Count++
+		if len(group.GoroutineIDs) < maxGroupMembers {
+			group.GoroutineIDs = append(group.GoroutineIDs, g.ID)
+		}
+		labels := g.Labels()
+		if !slices.ContainsFunc(group.Labels, func(l map[string]string) bool { return maps.Equal(l, labels) }) {
+			group.Labels = append(group.Labels, labels)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups, nil
+}
+
 // Stacktrace returns a list of Stackframes for the given goroutine. The
 // length of the returned list will be min(stack_len, depth).
 // If 'full' is true, then local vars, function args, etc. will be returned as well.
@@ -1935,6 +2499,28 @@ func (d *Debugger) stacktrace(goroutineID int64, depth int, opts api.StacktraceO
 	}
 }
 
+// GoroutineCreationStacktrace returns the stacktrace of the 'go' statement
+// that created the goroutine goroutineID. See
+// proc.GoroutineCreationStacktrace.
+func (d *Debugger) GoroutineCreationStacktrace(goroutineID int64, depth int) ([]proc.Stackframe, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	if _, err := d.target.Valid(); err != nil {
+		return nil, err
+	}
+
+	g, err := proc.FindGoroutine(d.target.Selected, goroutineID)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, errors.New("no selected goroutine")
+	}
+
+	return proc.GoroutineCreationStacktrace(d.target.Selected, g, depth)
+}
+
 // Ancestors returns the stacktraces for the ancestors of a goroutine.
 func (d *Debugger) Ancestors(goroutineID int64, numAncestors, depth int) ([]api.Ancestor, error) {
 	d.targetMutex.Lock()
@@ -1998,6 +2584,21 @@ func (d *Debugger) convertStacktrace(rawlocs []proc.Stackframe, cfg *proc.LoadCo
 			Defers: d.convertDefers(rawlocs[i].Defers),
 
 			Bottom: rawlocs[i].Bottom,
+
+			IsCgo: rawlocs[i].Call.Fn != nil && rawlocs[i].Call.Fn.IsCgo(),
+
+			FramePointerFallback: rawlocs[i].FramePointerFallback,
+		}
+		if rawlocs[i].Inlined {
+			frame.Inlined = true
+			frame.FrameOffset, frame.FramePointerOffset = 0, 0
+			frame.InlinedCallerIndex = -1
+			for j := i + 1; j < len(rawlocs); j++ {
+				if !rawlocs[j].Inlined {
+					frame.InlinedCallerIndex = j
+					break
+				}
+			}
 		}
 		if rawlocs[i].Err != nil {
 			frame.Err = rawlocs[i].Err.Error()
@@ -2022,6 +2623,92 @@ func (d *Debugger) convertStacktrace(rawlocs []proc.Stackframe, cfg *proc.LoadCo
 	return locations, nil
 }
 
+// StacktraceDefers returns the deferred calls of the specified goroutine
+// that haven't been executed yet, starting with the most recent one.
+func (d *Debugger) StacktraceDefers(goroutineID int64) ([]api.Defer, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	if _, err := d.target.Valid(); err != nil {
+		return nil, err
+	}
+
+	g, err := proc.FindGoroutine(d.target.Selected, goroutineID)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, errors.New("no goroutine selected")
+	}
+	return d.convertDefers(g.Defers(d.target.Selected)), nil
+}
+
+// GoroutinePanics returns the panics in progress on the specified goroutine,
+// starting with the most recent one.
+func (d *Debugger) GoroutinePanics(goroutineID int64, cfg proc.LoadConfig) ([]api.Panic, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	if _, err := d.target.Valid(); err != nil {
+		return nil, err
+	}
+
+	g, err := proc.FindGoroutine(d.target.Selected, goroutineID)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, errors.New("no goroutine selected")
+	}
+
+	panics := g.Panics(cfg)
+	var frames []proc.Stackframe
+	for _, p := range panics {
+		if p.FP != 0 {
+			frames, _ = proc.GoroutineStacktrace(d.target.Selected, g, maxPanicFrameDepth, 0)
+			break
+		}
+	}
+
+	r := make([]api.Panic, len(panics))
+	for i, p := range panics {
+		if p.Unreadable != nil {
+			r[i] = api.Panic{Frame: -1, Unreadable: p.Unreadable.Error()}
+			continue
+		}
+		r[i] = api.Panic{
+			Recovered:  p.Recovered,
+			Repanicked: p.Repanicked,
+			Goexit:     p.Goexit,
+			Frame:      -1,
+		}
+		if p.Value != nil {
+			r[i].Value = api.ConvertVar(p.Value)
+		}
+		if p.FP == 0 {
+			continue
+		}
+		for j := range frames {
+			if uint64(frames[j].Regs.CFA) == p.FP {
+				r[i].Frame = j
+				loc := api.ConvertLocation(frames[j].Call)
+				r[i].Location = &loc
+				break
+			}
+		}
+		if r[i].Location == nil {
+			file, line, fn := d.target.Selected.BinInfo().PCToLine(p.PC)
+			loc := api.ConvertLocation(proc.Location{PC: p.PC, File: file, Line: line, Fn: fn})
+			r[i].Location = &loc
+		}
+	}
+	return r, nil
+}
+
+// maxPanicFrameDepth is the maximum depth of the stacktrace searched by
+// GoroutinePanics for the frames being unwound by each panic.
+const maxPanicFrameDepth = 1000
+
 func (d *Debugger) convertDefers(defers []*proc.Defer) []api.Defer {
 	r := make([]api.Defer, len(defers))
 	for i := range defers {
@@ -2048,7 +2735,8 @@ func (d *Debugger) convertDefers(defers []*proc.Defer) []api.Defer {
 					Line: drl,
 					Fn:   drfn,
 				}),
-				SP: defers[i].SP,
+				SP:        defers[i].SP,
+				OpenCoded: defers[i].OpenCoded,
 			}
 		}
 	}
@@ -2236,6 +2924,13 @@ func (d *Debugger) ListDynamicLibraries() []*proc.Image {
 	return d.target.Selected.BinInfo().Images
 }
 
+// Plugins returns the list of Go plugins loaded by the target process.
+func (d *Debugger) Plugins() ([]proc.Plugin, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+	return proc.Plugins(d.target.Selected.BinInfo(), d.target.Selected.Memory())
+}
+
 // ExamineMemory returns the raw memory stored at the given address.
 // The amount of data to be read is specified by length.
 // This function will return an error if it reads less than `length` bytes.
@@ -2255,6 +2950,39 @@ func (d *Debugger) ExamineMemory(address uint64, length int) ([]byte, error) {
 	return data, nil
 }
 
+// SearchMemory evaluates expr in the specified scope and searches the
+// readable memory of the target for its value. If align is 0 the natural
+// alignment of the value is used. See proc.SearchPattern and
+// (*proc.Target).SearchMemory.
+func (d *Debugger) SearchMemory(goid int64, frame, deferredCall int, expr string, align, limit int) ([]proc.MemorySearchResult, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+
+	s, err := proc.ConvertEvalScope(d.target.Selected, goid, frame, deferredCall)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.EvalExpression(expr, proc.LoadConfig{})
+	if err != nil {
+		return nil, err
+	}
+	pattern, defaultAlign, err := proc.SearchPattern(v)
+	if err != nil {
+		return nil, err
+	}
+	if align == 0 {
+		align = defaultAlign
+	}
+	return d.target.Selected.SearchMemory(pattern, align, limit, true)
+}
+
+// MemoryMap returns the memory mappings of the selected target.
+func (d *Debugger) MemoryMap() ([]proc.MemoryMapEntry, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+	return d.target.Selected.MemoryMap()
+}
+
 func (d *Debugger) GetVersion(out *api.GetVersionOut) error {
 	if d.config.CoreFile != "" {
 		if d.config.Backend == "rr" {
@@ -2293,6 +3021,14 @@ func (d *Debugger) ListPackagesBuildInfo(includeFiles bool) []*proc.PackageBuild
 	return d.target.Selected.BinInfo().ListPackagesBuildInfo(includeFiles)
 }
 
+// ListProducers returns the list of distinct DW_AT_producer attributes of
+// the compile units of the selected target, along with the files they cover.
+func (d *Debugger) ListProducers() []*proc.ProducerInfo {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+	return d.target.Selected.BinInfo().ListProducers()
+}
+
 // StopRecording stops a recording (if one is in progress)
 func (d *Debugger) StopRecording() error {
 	d.recordMutex.Lock()
@@ -2454,6 +3190,88 @@ func (d *Debugger) FollowExecEnabled() bool {
 	return d.target.FollowExecEnabled()
 }
 
+// SignalPolicies returns the policy of every signal known to the debugger,
+// ordered by signal number.
+func (d *Debugger) SignalPolicies() []api.SignalPolicy {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+	names := signalNames()
+	policies := d.target.SignalPolicies()
+	sigs := slices.Collect(maps.Keys(names))
+	for sig := range policies {
+		if _, ok := names[sig]; !ok {
+			sigs = append(sigs, sig)
+		}
+	}
+	slices.Sort(sigs)
+	r := make([]api.SignalPolicy, 0, len(sigs))
+	for _, sig := range sigs {
+		policy, ok := policies[sig]
+		if !ok {
+			policy = proc.DefaultSignalPolicy
+		}
+		r = append(r, convertSignalPolicy(sig, names[sig], policy))
+	}
+	return r
+}
+
+// SetSignalPolicy sets the policy used when the target process receives
+// signal sig, specified either by name (with or without the SIG prefix)
+// or by number.
+// Signals used by the debugger can not be configured and synchronous
+// faults (for example SIGSEGV) can only be discarded if the policy also
+// stops the target process, since the faulting instruction would
+// otherwise be executed again immediately.
+func (d *Debugger) SetSignalPolicy(sig string, stop, print, pass bool) (api.SignalPolicy, error) {
+	d.targetMutex.Lock()
+	defer d.targetMutex.Unlock()
+	names := signalNames()
+	signum, err := parseSignal(names, maxSignal, sig)
+	if err != nil {
+		return api.SignalPolicy{}, err
+	}
+	name := names[signum]
+	switch name {
+	case "SIGTRAP", "SIGSTOP", "SIGKILL":
+		return api.SignalPolicy{}, fmt.Errorf("the policy of %s can not be changed", name)
+	case "SIGSEGV", "SIGBUS", "SIGFPE", "SIGILL":
+		if !pass && !stop {
+			return api.SignalPolicy{}, fmt.Errorf("%s can not be discarded without stopping, the faulting instruction would be executed again forever", name)
+		}
+	}
+	policy := proc.SignalPolicy{Stop: stop, Print: print, Pass: pass}
+	if err := d.target.SetSignalPolicy(signum, policy); err != nil {
+		return api.SignalPolicy{}, err
+	}
+	return convertSignalPolicy(signum, name, policy), nil
+}
+
+func convertSignalPolicy(sig int, name string, policy proc.SignalPolicy) api.SignalPolicy {
+	return api.SignalPolicy{Signal: sig, Name: name, Stop: policy.Stop, Print: policy.Print, Pass: policy.Pass}
+}
+
+// parseSignal converts a signal name or number into a signal number.
+// Names must be in names, numbers must either be in names or be between 1
+// and maxsig.
+func parseSignal(names map[int]string, maxsig int, sig string) (int, error) {
+	if n, err := strconv.Atoi(sig); err == nil {
+		if _, known := names[n]; !known && (n <= 0 || n > maxsig) {
+			return 0, fmt.Errorf("invalid signal number %d", n)
+		}
+		return n, nil
+	}
+	name := strings.ToUpper(sig)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	for n := range names {
+		if names[n] == name {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown signal %q", sig)
+}
+
 func (d *Debugger) SetDebugInfoDirectories(v []string) {
 	d.recordMutex.Lock()
 	defer d.recordMutex.Unlock()
@@ -2516,6 +3334,23 @@ func noDebugErrorWarning(err error) error {
 	return err
 }
 
+// verifyWorkingDir checks that wd, if specified, is an existing directory.
+// Without this check a missing working directory would be reported by the
+// backends as a failure to execute the target.
+func verifyWorkingDir(wd string) error {
+	if wd == "" {
+		return nil
+	}
+	fi, err := os.Stat(wd)
+	if err != nil {
+		return fmt.Errorf("invalid working directory: %v", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("invalid working directory: %s is not a directory", wd)
+	}
+	return nil
+}
+
 func verifyBinaryFormat(exePath string) (string, error) {
 	fullpath, err := filepath.Abs(exePath)
 	if err != nil {
@@ -2544,6 +3379,10 @@ func verifyBinaryFormat(exePath string) (string, error) {
 	switch runtime.GOOS {
 	case "darwin":
 		exe, err = macho.NewFile(f)
+		if err != nil {
+			// universal binary
+			exe, err = macho.NewFatFile(f)
+		}
 	case "linux", "freebsd":
 		exe, err = elf.NewFile(f)
 	case "windows":
@@ -2561,10 +3400,36 @@ func verifyBinaryFormat(exePath string) (string, error) {
 
 var attachErrorMessage = attachErrorMessageDefault
 
+var checkAttachUser = checkAttachUserDefault
+
+func checkAttachUserDefault(pid int) error {
+	fmt.Fprintf(os.Stderr, "Warning: can not check the owner of pid %d on %s, --only-same-user ignored\n", pid, runtime.GOOS)
+	return nil
+}
+
 func attachErrorMessageDefault(pid int, err error) error {
 	return fmt.Errorf("could not attach to pid %d: %s", pid, err)
 }
 
+var threadOSInfo = threadOSInfoDefault
+
+func threadOSInfoDefault(pid, tid int) (state string, lastCPU int) {
+	return "", -1
+}
+
+// signalNames returns a map from signal numbers to signal names for the
+// operating system of the target process.
+var signalNames = signalNamesDefault
+
+func signalNamesDefault() map[int]string {
+	return nil
+}
+
+// maxSignal is the highest signal number supported by the operating system
+// of the target process, signals without a name are valid up to this
+// number.
+var maxSignal = 0
+
 func (d *Debugger) maybePrintUnattendedStopWarning(stopReason proc.StopReason, currentThread *api.Thread, clientStatusCh <-chan struct{}) {
 	select {
 	case <-clientStatusCh:
@@ -2790,8 +3655,9 @@ func guessSubstitutePath(args *api.GuessSubstitutePathIn, bins [][]proc.Function
 	return server2Client
 }
 
-// TypeInfo returns informations about a type.
-func (d *Debugger) TypeInfo(name string) (*api.TypeInfo, error) {
+// TypeInfo returns informations about a type. If tree is true the tree of
+// types that make up the layout of the type is also returned.
+func (d *Debugger) TypeInfo(name string, tree bool) (*api.TypeInfo, error) {
 	d.targetMutex.Lock()
 	defer d.targetMutex.Unlock()
 	bi := d.target.Selected.BinInfo()
@@ -2814,6 +3680,11 @@ func (d *Debugger) TypeInfo(name string) (*api.TypeInfo, error) {
 		r.RealType = typ.Type.String()
 	}
 
+	if tree {
+		t := typeTree("", typ, 0, false)
+		r.Tree = &t
+	}
+
 	plainRcvrPfx := name + "."
 	ptrRcvrPfx := ""
 	if dot := strings.LastIndex(name, "."); dot > 0 {
@@ -2831,3 +3702,41 @@ func (d *Debugger) TypeInfo(name string) (*api.TypeInfo, error) {
 
 	return r, nil
 }
+
+func typeTree(name string, typ godwarf.Type, offset int64, embedded bool) api.TypeTreeNode {
+	ctyp := typ.Common()
+	r := api.TypeTreeNode{
+		Name:     name,
+		Type:     api.PrettyTypeName(typ),
+		Kind:     ctyp.ReflectKind,
+		Size:     ctyp.Size(),
+		Offset:   offset,
+		Embedded: embedded,
+	}
+
+	structFields := func(typ *godwarf.StructType) {
+		for _, field := range typ.Field {
+			r.Children = append(r.Children, typeTree(field.Name, field.Type, field.ByteOffset, field.Embedded))
+		}
+	}
+
+	switch typ := typ.(type) {
+	case *godwarf.StructType:
+		structFields(typ)
+	case *godwarf.SliceType:
+		structFields(&typ.StructType)
+	case *godwarf.StringType:
+		structFields(&typ.StructType)
+	case *godwarf.InterfaceType:
+		if styp, ok := godwarf.ResolveTypedef(typ.Type).(*godwarf.StructType); ok {
+			structFields(styp)
+		}
+	case *godwarf.ArrayType:
+		r.Children = append(r.Children, typeTree("", typ.Type, 0, false))
+	case *godwarf.TypedefType:
+		r.Children = append(r.Children, typeTree("", typ.Type, 0, false))
+	case *godwarf.ParametricType:
+		r.Children = append(r.Children, typeTree("", typ.Type, 0, false))
+	}
+	return r
+}
@@ -3,14 +3,21 @@ package debugger
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 	"syscall"
+
+	"golang.org/x/sys/unix"
 )
 
 func init() {
 	attachErrorMessage = attachErrorMessageLinux
+	checkAttachUser = checkAttachUserLinux
+	threadOSInfo = threadOSInfoLinux
+	signalNames = signalNamesLinux
+	maxSignal = sigrtmax
 }
 
 //lint:file-ignore ST1005 errors here can be capitalized
@@ -57,3 +64,222 @@ func attachErrorMessageLinux(pid int, err error) error {
 	}
 	return fallbackerr
 }
+
+// checkAttachUserLinux returns an error if the real UID of process pid, as
+// reported by /proc/<pid>/status, is not the UID of the current user.
+// Root and processes with the CAP_SYS_PTRACE capability can attach to any
+// process.
+func checkAttachUserLinux(pid int) error {
+	if os.Geteuid() == 0 {
+		return nil
+	}
+	if selffh, err := os.Open("/proc/self/status"); err == nil {
+		privileged := statusHasCap(selffh, capSysPtrace)
+		selffh.Close()
+		if privileged {
+			return nil
+		}
+	}
+	statusfh, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return fmt.Errorf("could not attach to pid %d: %v", pid, err)
+	}
+	defer statusfh.Close()
+	return checkStatusUser(pid, statusfh, os.Getuid())
+}
+
+func checkStatusUser(pid int, status io.Reader, uid int) error {
+	const uidPrefix = "Uid:"
+	scan := bufio.NewScanner(status)
+	for scan.Scan() {
+		line := scan.Text()
+		if !strings.HasPrefix(line, uidPrefix) {
+			continue
+		}
+		fields := strings.Fields(line[len(uidPrefix):])
+		if len(fields) == 0 {
+			break
+		}
+		puid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			break
+		}
+		if puid != uid {
+			return fmt.Errorf("could not attach to pid %d: process is owned by uid %d, not by the current user (uid %d), use --only-same-user=false to attach anyway", pid, puid, uid)
+		}
+		return nil
+	}
+	return fmt.Errorf("could not attach to pid %d: could not determine the owner of the process", pid)
+}
+
+// capSysPtrace is the number of the CAP_SYS_PTRACE capability, see
+// capabilities(7).
+const capSysPtrace = 19
+
+// statusHasCap returns true if the effective capability set, as reported by
+// /proc/<pid>/status, contains capability capnum.
+func statusHasCap(status io.Reader, capnum uint) bool {
+	const capEffPrefix = "CapEff:"
+	scan := bufio.NewScanner(status)
+	for scan.Scan() {
+		line := scan.Text()
+		if !strings.HasPrefix(line, capEffPrefix) {
+			continue
+		}
+		caps, err := strconv.ParseUint(strings.TrimSpace(line[len(capEffPrefix):]), 16, 64)
+		if err != nil {
+			return false
+		}
+		return caps&(1<<capnum) != 0
+	}
+	return false
+}
+
+// threadOSInfoLinux returns the state of thread tid of process pid and the
+// CPU it last executed on, both read from /proc/<pid>/task/<tid>/stat.
+// A thread stopped by the debugger is always in the "tracing stop" state,
+// for those the state is derived from /proc/<pid>/task/<tid>/syscall
+// instead, which describes what the thread was doing when it was stopped.
+func threadOSInfoLinux(pid, tid int) (state string, lastCPU int) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/task/%d/stat", pid, tid))
+	if err != nil {
+		return "", -1
+	}
+	state = parseThreadStatState(string(stat))
+	if state == "tracing stop" {
+		if buf, err := os.ReadFile(fmt.Sprintf("/proc/%d/task/%d/syscall", pid, tid)); err == nil {
+			if syscallState := parseThreadSyscall(string(buf)); syscallState != "" {
+				state = syscallState
+			}
+		}
+	}
+	return state, parseThreadStatCPU(string(stat))
+}
+
+// parseThreadStatState returns a description of the state field of a
+// /proc/<pid>/task/<tid>/stat file, see proc(5).
+func parseThreadStatState(stat string) string {
+	// The second field is the executable name in parenthesis and can contain
+	// spaces and parenthesis, the state is the first field after it.
+	idx := strings.LastIndexByte(stat, ')')
+	if idx < 0 {
+		return ""
+	}
+	fields := strings.Fields(stat[idx+1:])
+	if len(fields) == 0 {
+		return ""
+	}
+	switch fields[0] {
+	case "R":
+		return "running"
+	case "S":
+		return "sleeping"
+	case "D":
+		return "disk sleep"
+	case "Z":
+		return "zombie"
+	case "T":
+		return "stopped"
+	case "t":
+		return "tracing stop"
+	case "X", "x":
+		return "dead"
+	case "I":
+		return "idle"
+	case "P":
+		return "parked"
+	case "W":
+		return "waking"
+	case "K":
+		return "wakekill"
+	default:
+		return fields[0]
+	}
+}
+
+// parseThreadSyscall returns a description of the state of a thread given
+// the contents of its /proc/<pid>/task/<tid>/syscall file, see proc(5): the
+// number of the system call the thread is blocked in or -1 if the thread is
+// executing user code.
+func parseThreadSyscall(buf string) string {
+	fields := strings.Fields(buf)
+	if len(fields) == 0 {
+		return ""
+	}
+	if fields[0] == "running" {
+		return "running"
+	}
+	nr, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return ""
+	}
+	if nr < 0 {
+		return "running"
+	}
+	if name := syscallNames[nr]; name != "" {
+		return "in syscall " + name
+	}
+	return "in syscall " + strconv.Itoa(nr)
+}
+
+// syscallNames contains the names of the system calls threads usually
+// block in.
+var syscallNames = map[int]string{
+	unix.SYS_ACCEPT4:         "accept4",
+	unix.SYS_CLOCK_NANOSLEEP: "clock_nanosleep",
+	unix.SYS_CONNECT:         "connect",
+	unix.SYS_EPOLL_PWAIT:     "epoll_pwait",
+	unix.SYS_FUTEX:           "futex",
+	unix.SYS_NANOSLEEP:       "nanosleep",
+	unix.SYS_OPENAT:          "openat",
+	unix.SYS_PPOLL:           "ppoll",
+	unix.SYS_PREAD64:         "pread64",
+	unix.SYS_PSELECT6:        "pselect6",
+	unix.SYS_PWRITE64:        "pwrite64",
+	unix.SYS_READ:            "read",
+	unix.SYS_READV:           "readv",
+	unix.SYS_RECVFROM:        "recvfrom",
+	unix.SYS_RECVMSG:         "recvmsg",
+	unix.SYS_RT_SIGSUSPEND:   "rt_sigsuspend",
+	unix.SYS_SENDMSG:         "sendmsg",
+	unix.SYS_SENDTO:          "sendto",
+	unix.SYS_WAIT4:           "wait4",
+	unix.SYS_WAITID:          "waitid",
+	unix.SYS_WRITE:           "write",
+	unix.SYS_WRITEV:          "writev",
+}
+
+// parseThreadStatCPU returns the processor field of a
+// /proc/<pid>/task/<tid>/stat file, see proc(5), or -1 if it is missing.
+func parseThreadStatCPU(stat string) int {
+	idx := strings.LastIndexByte(stat, ')')
+	if idx < 0 {
+		return -1
+	}
+	// fields[0] is the state field, which is the third field of the file,
+	// processor is the 39th field.
+	const processorIdx = 39 - 3
+	fields := strings.Fields(stat[idx+1:])
+	if len(fields) <= processorIdx {
+		return -1
+	}
+	n, err := strconv.Atoi(fields[processorIdx])
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// sigrtmax is the highest real-time signal number on linux.
+const sigrtmax = 64
+
+// signalNamesLinux returns the names of the standard linux signals.
+func signalNamesLinux() map[int]string {
+	r := make(map[int]string)
+	for sig := syscall.Signal(1); sig < 32; sig++ {
+		if name := unix.SignalName(sig); name != "" {
+			r[int(sig)] = name
+		}
+	}
+	return r
+}
@@ -0,0 +1,136 @@
+package debugger
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestCheckStatusUser(t *testing.T) {
+	const status = "Name:\tcat\nState:\tR (running)\nTgid:\t1234\nPid:\t1234\nPPid:\t1\nUid:\t1000\t1000\t1000\t1000\nGid:\t1000\t1000\t1000\t1000\n"
+	if err := checkStatusUser(1234, strings.NewReader(status), 1000); err != nil {
+		t.Errorf("unexpected error for same user: %v", err)
+	}
+	err := checkStatusUser(1234, strings.NewReader(status), 1001)
+	if err == nil || !strings.Contains(err.Error(), "owned by uid 1000") {
+		t.Errorf("expected owner mismatch error, got %v", err)
+	}
+	if err := checkStatusUser(1234, strings.NewReader("Name:\tcat\n"), 1000); err == nil {
+		t.Errorf("expected error for missing Uid line")
+	}
+	if err := checkAttachUserLinux(os.Getpid()); err != nil {
+		t.Errorf("unexpected error checking own process: %v", err)
+	}
+}
+
+func TestStatusHasCap(t *testing.T) {
+	for _, tc := range []struct {
+		capEff string
+		tgt    bool
+	}{
+		{"0000000000000000", false},
+		{"0000000000080000", true},
+		{"000001ffffffffff", true},
+		{"00000000a80425fb", false},
+		{"garbage", false},
+	} {
+		status := "Name:\tdlv\nCapInh:\t0000000000000000\nCapPrm:\t0000000000000000\nCapEff:\t" + tc.capEff + "\n"
+		if got := statusHasCap(strings.NewReader(status), capSysPtrace); got != tc.tgt {
+			t.Errorf("statusHasCap(CapEff: %s): expected %v got %v", tc.capEff, tc.tgt, got)
+		}
+	}
+	if statusHasCap(strings.NewReader("Name:\tdlv\n"), capSysPtrace) {
+		t.Errorf("expected false for missing CapEff line")
+	}
+}
+
+func TestParseThreadStatState(t *testing.T) {
+	for _, tc := range []struct {
+		stat, state string
+	}{
+		{"1234 (cat) S 1 1234 1234 0 -1 4194304", "sleeping"},
+		{"1234 (a) b) (c) R 1 1234 1234 0 -1 4194304", "running"},
+		{"1234 (cat) t 1 1234", "tracing stop"},
+		{"1234 (cat) Q 1 1234", "Q"},
+		{"1234 (cat)", ""},
+		{"", ""},
+	} {
+		if state := parseThreadStatState(tc.stat); state != tc.state {
+			t.Errorf("parseThreadStatState(%q): expected %q got %q", tc.stat, tc.state, state)
+		}
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if state, _ := threadOSInfoLinux(os.Getpid(), unix.Gettid()); state != "running" {
+		t.Errorf("expected own thread to be running, got %q", state)
+	}
+}
+
+func TestParseThreadSyscall(t *testing.T) {
+	for _, tc := range []struct {
+		syscall, state string
+	}{
+		{"0 0x7 0x3ef3b01afcf 0x1 0x0 0x0 0x0 0x3ef3b01aeb8 0x40d96e", "in syscall read"},
+		{fmt.Sprintf("%d 0x3ef3b01c960 0x80 0x0 0x0 0x0 0x0 0x3ef3b045e38 0x4801e3", unix.SYS_FUTEX), "in syscall futex"},
+		{"1000 0x0 0x0 0x0 0x0 0x0 0x0 0x3ef3b045e38 0x4801e3", "in syscall 1000"},
+		{"-1 0x3ef3b01b7d8 0x49a460", "running"},
+		{"running", "running"},
+		{"", ""},
+	} {
+		if state := parseThreadSyscall(tc.syscall); state != tc.state {
+			t.Errorf("parseThreadSyscall(%q): expected %q got %q", tc.syscall, tc.state, state)
+		}
+	}
+}
+
+func TestParseThreadStatCPU(t *testing.T) {
+	const stat = "1234 (a) b) S 1 1234 1234 0 -1 4194304 100 0 0 0 1 2 0 0 20 0 1 0 100 1000 10 18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 3 0 0 0 0 0"
+	if cpu := parseThreadStatCPU(stat); cpu != 3 {
+		t.Errorf("parseThreadStatCPU(%q): expected 3 got %d", stat, cpu)
+	}
+	for _, stat := range []string{"1234 (cat) S 1 1234", "1234 (cat)", ""} {
+		if cpu := parseThreadStatCPU(stat); cpu != -1 {
+			t.Errorf("parseThreadStatCPU(%q): expected -1 got %d", stat, cpu)
+		}
+	}
+	if _, cpu := threadOSInfoLinux(os.Getpid(), os.Getpid()); cpu < 0 {
+		t.Errorf("could not read the last CPU of own thread")
+	}
+}
+
+func TestDebugger_LaunchRRUnavailable(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	_, err := New(&Config{Backend: "rr"}, []string{os.Args[0]})
+	if err == nil || !strings.Contains(err.Error(), errRRBackendUnavailable.Error()) {
+		t.Fatalf("expected error %q got %v", errRRBackendUnavailable, err)
+	}
+}
+
+func TestParseSignal(t *testing.T) {
+	names := signalNamesLinux()
+	for _, tc := range []struct {
+		sig string
+		tgt int
+	}{
+		{"SIGUSR1", 10},
+		{"usr1", 10},
+		{"sigint", 2},
+		{"10", 10},
+		{"34", 34},
+		{"64", 64},
+	} {
+		n, err := parseSignal(names, sigrtmax, tc.sig)
+		if err != nil || n != tc.tgt {
+			t.Errorf("parseSignal(%q): expected %d got %d %v", tc.sig, tc.tgt, n, err)
+		}
+	}
+	for _, sig := range []string{"SIGNOPE", "nope", "", "0", "-1", "65", "1000"} {
+		if n, err := parseSignal(names, sigrtmax, sig); err == nil {
+			t.Errorf("parseSignal(%q): expected error got %d", sig, n)
+		}
+	}
+}
@@ -115,6 +115,29 @@ func TestDebugger_LaunchCurrentDir(t *testing.T) {
 	}
 }
 
+func TestDebugger_LaunchInvalidWorkingDir(t *testing.T) {
+	fixturesDir := protest.FindFixturesDir()
+	testDir := filepath.Join(fixturesDir, "buildtest")
+	debugname := "debug"
+	exepath := filepath.Join(testDir, debugname)
+	defer os.Remove(exepath)
+	if err := gobuild.GoBuild(debugname, []string{testDir}, fmt.Sprintf("-o %s", exepath)); err != nil {
+		t.Fatalf("go build error %v", err)
+	}
+
+	for _, wd := range []string{filepath.Join(testDir, "nonexistent"), filepath.Join(testDir, "main.go")} {
+		d := new(Debugger)
+		d.config = &Config{}
+		_, err := d.Launch([]string{exepath}, wd)
+		if err == nil {
+			t.Fatalf("%s: expected error but none was generated", wd)
+		}
+		if !strings.Contains(err.Error(), "invalid working directory") {
+			t.Fatalf("%s: unexpected error %v", wd, err)
+		}
+	}
+}
+
 func guessSubstitutePathHelper(t *testing.T, args *api.GuessSubstitutePathIn, fnpaths [][2]string, tgt map[string]string) {
 	const base = 0x40000
 	t.Helper()
@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-delve/delve/service"
@@ -21,8 +22,11 @@ import (
 type RPCClient struct {
 	client *rpc.Client
 
-	retValLoadCfg *api.LoadConfig
-	eventsFn      func(*api.Event)
+	retValLoadCfg   *api.LoadConfig
+	stepSkipNoDebug bool
+	singleGoroutine bool
+
+	eventsFn func(*api.Event)
 }
 
 // Ensure the implementation satisfies the interface.
@@ -48,6 +52,22 @@ func NewClientFromConn(conn net.Conn) *RPCClient {
 	return newFromRPCClient(jsonrpc.NewClient(conn))
 }
 
+// NewClientFromConnWithToken creates a new RPCClient from the given
+// connection, presenting token to the server. The server closes the
+// connection if token is not the one it was started with.
+func NewClientFromConnWithToken(conn net.Conn, token string) (*RPCClient, error) {
+	c := &RPCClient{client: jsonrpc.NewClient(conn)}
+	err := c.call("SetApiVersion", api.SetAPIVersionIn{APIVersion: 2, ClientToken: token}, &api.SetAPIVersionOut{})
+	if err != nil {
+		c.client.Close()
+		if token != "" {
+			return nil, fmt.Errorf("connection rejected by the server, check the client token: %w", err)
+		}
+		return nil, fmt.Errorf("connection rejected by the server: %w", err)
+	}
+	return c, nil
+}
+
 func (c *RPCClient) GetVersion() *api.GetVersionOut {
 	out := new(api.GetVersionOut)
 	c.call("GetVersion", api.GetVersionIn{}, out)
@@ -119,7 +139,7 @@ func (c *RPCClient) continueDir(cmd string) <-chan *api.DebuggerState {
 	go func() {
 		for {
 			out := new(CommandOut)
-			err := c.callWhileDrainingEvents("Command", &api.DebuggerCommand{Name: cmd, ReturnInfoLoadConfig: c.retValLoadCfg, WithEvents: c.eventsFn != nil}, &out)
+			err := c.callWhileDrainingEvents("Command", &api.DebuggerCommand{Name: cmd, ReturnInfoLoadConfig: c.retValLoadCfg, WithEvents: c.eventsFn != nil, SingleGoroutine: c.singleGoroutine}, &out)
 			state := out.State
 			if err != nil {
 				state.Err = err
@@ -180,7 +200,7 @@ func (c *RPCClient) drainEvents() <-chan struct{} {
 
 func (c *RPCClient) Next() (*api.DebuggerState, error) {
 	var out CommandOut
-	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: api.Next, ReturnInfoLoadConfig: c.retValLoadCfg, WithEvents: c.eventsFn != nil}, &out)
+	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: api.Next, ReturnInfoLoadConfig: c.retValLoadCfg, WithEvents: c.eventsFn != nil, SingleGoroutine: c.singleGoroutine}, &out)
 	return &out.State, err
 }
 
@@ -192,7 +212,7 @@ func (c *RPCClient) ReverseNext() (*api.DebuggerState, error) {
 
 func (c *RPCClient) Step() (*api.DebuggerState, error) {
 	var out CommandOut
-	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: api.Step, ReturnInfoLoadConfig: c.retValLoadCfg, WithEvents: c.eventsFn != nil}, &out)
+	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: api.Step, ReturnInfoLoadConfig: c.retValLoadCfg, WithEvents: c.eventsFn != nil, SingleGoroutine: c.singleGoroutine, SkipNoDebug: c.stepSkipNoDebug}, &out)
 	return &out.State, err
 }
 
@@ -204,7 +224,7 @@ func (c *RPCClient) ReverseStep() (*api.DebuggerState, error) {
 
 func (c *RPCClient) StepOut() (*api.DebuggerState, error) {
 	var out CommandOut
-	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: api.StepOut, ReturnInfoLoadConfig: c.retValLoadCfg, WithEvents: c.eventsFn != nil}, &out)
+	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: api.StepOut, ReturnInfoLoadConfig: c.retValLoadCfg, WithEvents: c.eventsFn != nil, SingleGoroutine: c.singleGoroutine}, &out)
 	return &out.State, err
 }
 
@@ -215,28 +235,44 @@ func (c *RPCClient) ReverseStepOut() (*api.DebuggerState, error) {
 }
 
 func (c *RPCClient) Call(goroutineID int64, expr string, unsafe bool) (*api.DebuggerState, error) {
+	return c.CallWithTimeout(goroutineID, expr, unsafe, 0)
+}
+
+func (c *RPCClient) CallWithTimeout(goroutineID int64, expr string, unsafe bool, timeout time.Duration) (*api.DebuggerState, error) {
 	var out CommandOut
-	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: api.Call, ReturnInfoLoadConfig: c.retValLoadCfg, Expr: expr, UnsafeCall: unsafe, GoroutineID: goroutineID, WithEvents: c.eventsFn != nil}, &out)
+	var evalCfg *api.EvalConfig
+	if timeout > 0 {
+		evalCfg = &api.EvalConfig{CallTimeout: timeout}
+	}
+	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: api.Call, ReturnInfoLoadConfig: c.retValLoadCfg, Expr: expr, UnsafeCall: unsafe, EvalConfig: evalCfg, GoroutineID: goroutineID, WithEvents: c.eventsFn != nil}, &out)
 	return &out.State, err
 }
 
 func (c *RPCClient) StepInstruction(skipCalls bool) (*api.DebuggerState, error) {
+	return c.StepInstructions(skipCalls, 0)
+}
+
+func (c *RPCClient) StepInstructions(skipCalls bool, count int) (*api.DebuggerState, error) {
 	var out CommandOut
 	name := api.StepInstruction
 	if skipCalls {
 		name = api.NextInstruction
 	}
-	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: name, WithEvents: c.eventsFn != nil}, &out)
+	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: name, WithEvents: c.eventsFn != nil, Count: count}, &out)
 	return &out.State, err
 }
 
 func (c *RPCClient) ReverseStepInstruction(skipCalls bool) (*api.DebuggerState, error) {
+	return c.ReverseStepInstructions(skipCalls, 0)
+}
+
+func (c *RPCClient) ReverseStepInstructions(skipCalls bool, count int) (*api.DebuggerState, error) {
 	var out CommandOut
 	name := api.ReverseStepInstruction
 	if skipCalls {
 		name = api.ReverseNextInstruction
 	}
-	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: name, WithEvents: c.eventsFn != nil}, &out)
+	err := c.callWhileDrainingEvents("Command", api.DebuggerCommand{Name: name, WithEvents: c.eventsFn != nil, Count: count}, &out)
 	return &out.State, err
 }
 
@@ -310,7 +346,15 @@ func (c *RPCClient) CreateEBPFTracepoint(fnName string) error {
 
 func (c *RPCClient) CreateWatchpoint(scope api.EvalScope, expr string, wtype api.WatchType) (*api.Breakpoint, error) {
 	var out CreateWatchpointOut
-	err := c.call("CreateWatchpoint", CreateWatchpointIn{scope, expr, wtype}, &out)
+	err := c.call("CreateWatchpoint", CreateWatchpointIn{Scope: scope, Expr: expr, Type: wtype}, &out)
+	return out.Breakpoint, err
+}
+
+// CreateFieldWatchpoint creates a watchpoint on a field of all instances
+// of a struct type, typeField has the form <type>.<field>.
+func (c *RPCClient) CreateFieldWatchpoint(typeField string, wtype api.WatchType, max int) (*api.Breakpoint, error) {
+	var out CreateWatchpointOut
+	err := c.call("CreateWatchpoint", CreateWatchpointIn{Expr: typeField, Type: wtype, Field: true, Max: max}, &out)
 	return out.Breakpoint, err
 }
 
@@ -373,6 +417,12 @@ func (c *RPCClient) EvalVariable(scope api.EvalScope, expr string, cfg api.LoadC
 	return out.Variable, err
 }
 
+func (c *RPCClient) EvalGoroutines(expr string, cfg api.LoadConfig) (map[int64]api.GoroutineEvalResult, error) {
+	var out EvalGoroutinesOut
+	err := c.call("EvalGoroutines", EvalGoroutinesIn{expr, &cfg}, &out)
+	return out.Results, err
+}
+
 func (c *RPCClient) SetVariable(scope api.EvalScope, symbol, value string) error {
 	out := new(SetOut)
 	return c.call("Set", SetIn{scope, symbol, value}, out)
@@ -380,10 +430,16 @@ func (c *RPCClient) SetVariable(scope api.EvalScope, symbol, value string) error
 
 func (c *RPCClient) ListSources(filter string) ([]string, error) {
 	sources := new(ListSourcesOut)
-	err := c.call("ListSources", ListSourcesIn{filter}, sources)
+	err := c.call("ListSources", ListSourcesIn{Filter: filter}, sources)
 	return sources.Sources, err
 }
 
+func (c *RPCClient) ListSourceFiles(filter string) ([]api.SourceFile, error) {
+	sources := new(ListSourcesOut)
+	err := c.call("ListSources", ListSourcesIn{Filter: filter, IncludeCompileUnits: true}, sources)
+	return sources.SourceFiles, err
+}
+
 func (c *RPCClient) ListFunctions(filter string, TraceFollow int) ([]string, error) {
 	funcs := new(ListFunctionsOut)
 	err := c.call("ListFunctions", ListFunctionsIn{filter, TraceFollow}, funcs)
@@ -408,12 +464,30 @@ func (c *RPCClient) ListPackagesBuildInfo(filter string, includeFiles bool) ([]a
 	return out.List, err
 }
 
+func (c *RPCClient) ListProducers() ([]api.ProducerInfo, error) {
+	var out ListProducersOut
+	err := c.call("ListProducers", ListProducersIn{}, &out)
+	return out.List, err
+}
+
 func (c *RPCClient) ListLocalVariables(scope api.EvalScope, cfg api.LoadConfig) ([]api.Variable, error) {
 	var out ListLocalVarsOut
-	err := c.call("ListLocalVars", ListLocalVarsIn{scope, cfg}, &out)
+	err := c.call("ListLocalVars", ListLocalVarsIn{Scope: scope, Cfg: cfg}, &out)
 	return out.Variables, err
 }
 
+func (c *RPCClient) ListLocalVariablesWithTypeArguments(scope api.EvalScope, cfg api.LoadConfig, substitutePathRules [][2]string) ([]api.Variable, []api.TypeArgument, error) {
+	var out ListLocalVarsOut
+	err := c.call("ListLocalVars", ListLocalVarsIn{Scope: scope, Cfg: cfg, TypeArguments: true, SubstitutePathRules: substitutePathRules}, &out)
+	return out.Variables, out.TypeArguments, err
+}
+
+func (c *RPCClient) ListTypeArguments(scope api.EvalScope, substitutePathRules [][2]string) ([]api.TypeArgument, error) {
+	var out ListTypeArgumentsOut
+	err := c.call("ListTypeArguments", ListTypeArgumentsIn{scope, substitutePathRules}, &out)
+	return out.TypeArguments, err
+}
+
 func (c *RPCClient) ListThreadRegisters(threadID int, includeFp bool) (api.Registers, error) {
 	out := new(ListRegistersOut)
 	err := c.call("ListRegisters", ListRegistersIn{ThreadID: threadID, IncludeFp: includeFp, Scope: nil}, out)
@@ -426,6 +500,12 @@ func (c *RPCClient) ListScopeRegisters(scope api.EvalScope, includeFp bool) (api
 	return out.Regs, err
 }
 
+func (c *RPCClient) SetRegister(threadID int, name string, value uint64) (api.Registers, error) {
+	out := new(SetRegisterOut)
+	err := c.call("SetRegister", SetRegisterIn{ThreadID: threadID, Name: name, Value: value}, out)
+	return out.Regs, err
+}
+
 func (c *RPCClient) ListFunctionArgs(scope api.EvalScope, cfg api.LoadConfig) ([]api.Variable, error) {
 	var out ListFunctionArgsOut
 	err := c.call("ListFunctionArgs", ListFunctionArgsIn{scope, cfg}, &out)
@@ -447,18 +527,42 @@ func (c *RPCClient) ListGoroutinesWithFilter(start, count int, filters []api.Lis
 	return out.Goroutines, out.Groups, out.Nextg, out.TooManyGroups, err
 }
 
+func (c *RPCClient) ListGoroutineStackGroups(filters []api.ListGoroutinesFilter, depth, maxGroupMembers int) ([]api.GoroutineStackGroup, error) {
+	var out ListGoroutineStackGroupsOut
+	err := c.call("ListGoroutineStackGroups", ListGoroutineStackGroupsIn{filters, depth, maxGroupMembers}, &out)
+	return out.Groups, err
+}
+
 func (c *RPCClient) Stacktrace(goroutineId int64, depth, skip int, opts api.StacktraceOptions, cfg *api.LoadConfig) ([]api.Stackframe, error) {
 	var out StacktraceOut
 	err := c.call("Stacktrace", StacktraceIn{goroutineId, depth, false, false, opts, cfg, skip}, &out)
 	return out.Locations, err
 }
 
+func (c *RPCClient) ListGoroutineDefers(goroutineID int64) ([]api.Defer, error) {
+	var out ListGoroutineDefersOut
+	err := c.call("ListGoroutineDefers", ListGoroutineDefersIn{goroutineID}, &out)
+	return out.Defers, err
+}
+
+func (c *RPCClient) ListGoroutinePanics(goroutineID int64, cfg api.LoadConfig) ([]api.Panic, error) {
+	var out ListGoroutinePanicsOut
+	err := c.call("ListGoroutinePanics", ListGoroutinePanicsIn{goroutineID, cfg}, &out)
+	return out.Panics, err
+}
+
 func (c *RPCClient) Ancestors(goroutineID int64, numAncestors int, depth int) ([]api.Ancestor, error) {
 	var out AncestorsOut
 	err := c.call("Ancestors", AncestorsIn{goroutineID, numAncestors, depth}, &out)
 	return out.Ancestors, err
 }
 
+func (c *RPCClient) GoroutineCreationStacktrace(goroutineID int64, depth int) ([]api.Stackframe, error) {
+	var out GoroutineCreationStacktraceOut
+	err := c.call("GoroutineCreationStacktrace", GoroutineCreationStacktraceIn{goroutineID, depth}, &out)
+	return out.Locations, err
+}
+
 func (c *RPCClient) AttachedToExistingProcess() bool {
 	out := new(AttachedToExistingProcessOut)
 	c.call("AttachedToExistingProcess", AttachedToExistingProcessIn{}, out)
@@ -524,10 +628,54 @@ func (c *RPCClient) SetReturnValuesLoadConfig(cfg *api.LoadConfig) {
 	c.retValLoadCfg = cfg
 }
 
+func (c *RPCClient) SetStepSkipNoDebug(v bool) {
+	c.stepSkipNoDebug = v
+}
+
+func (c *RPCClient) SetSingleGoroutineStepping(v bool) {
+	c.singleGoroutine = v
+}
+
 func (c *RPCClient) SetEventsFn(eventsFn func(*api.Event)) {
 	c.eventsFn = eventsFn
 }
 
+// SubscribeEvents subscribes to the events generated by the target, see
+// RPCServer.SubscribeEvents. Events are delivered on the returned channel,
+// which is closed after cancel is called or if the connection is lost.
+func (c *RPCClient) SubscribeEvents() (events <-chan api.Event, cancel func(), err error) {
+	var out SubscribeEventsOut
+	if err := c.call("SubscribeEvents", SubscribeEventsIn{}, &out); err != nil {
+		return nil, nil, err
+	}
+	ch := make(chan api.Event)
+	done := make(chan struct{})
+	go func() {
+		defer close(ch)
+		for {
+			var wout WaitEventsOut
+			if err := c.call("WaitEvents", WaitEventsIn{ID: out.ID}, &wout); err != nil {
+				return
+			}
+			for _, event := range wout.Events {
+				select {
+				case ch <- event:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			close(done)
+			c.call("UnsubscribeEvents", UnsubscribeEventsIn{ID: out.ID}, &UnsubscribeEventsOut{})
+		})
+	}
+	return ch, cancel, nil
+}
+
 func (c *RPCClient) FunctionReturnLocations(fnName string) ([]uint64, error) {
 	var out FunctionReturnLocationsOut
 	err := c.call("FunctionReturnLocations", FunctionReturnLocationsIn{fnName}, &out)
@@ -554,6 +702,12 @@ func (c *RPCClient) ListDynamicLibraries() ([]api.Image, bool, error) {
 	return out.List, out.ExecutableTrimpath, nil
 }
 
+func (c *RPCClient) ListPlugins() ([]api.Plugin, error) {
+	var out ListPluginsOut
+	err := c.call("ListPlugins", ListPluginsIn{}, &out)
+	return out.List, err
+}
+
 func (c *RPCClient) ExamineMemory(address uint64, count int) ([]byte, bool, error) {
 	out := &ExaminedMemoryOut{}
 
@@ -564,6 +718,18 @@ func (c *RPCClient) ExamineMemory(address uint64, count int) ([]byte, bool, erro
 	return out.Mem, out.IsLittleEndian, nil
 }
 
+func (c *RPCClient) SearchMemory(scope api.EvalScope, expr string, align, max int) ([]api.MemorySearchResult, error) {
+	var out SearchMemoryOut
+	err := c.call("SearchMemory", SearchMemoryIn{Scope: scope, Expr: expr, Align: align, Max: max}, &out)
+	return out.Results, err
+}
+
+func (c *RPCClient) ListMemoryMappings() ([]api.MemoryMapping, error) {
+	var out ListMemoryMappingsOut
+	err := c.call("ListMemoryMappings", ListMemoryMappingsIn{}, &out)
+	return out.Mappings, err
+}
+
 func (c *RPCClient) StopRecording() error {
 	return c.call("StopRecording", StopRecordingIn{}, &StopRecordingOut{})
 }
@@ -602,6 +768,20 @@ func (c *RPCClient) FollowExec(v bool, regex string) error {
 	return err
 }
 
+// ListSignalPolicies returns the policy of every signal known to the debugger.
+func (c *RPCClient) ListSignalPolicies() ([]api.SignalPolicy, error) {
+	out := &ListSignalPoliciesOut{}
+	err := c.call("ListSignalPolicies", ListSignalPoliciesIn{}, out)
+	return out.Policies, err
+}
+
+// SetSignalPolicy sets the policy used when the target process receives a signal.
+func (c *RPCClient) SetSignalPolicy(sig string, stop, print, pass bool) (api.SignalPolicy, error) {
+	out := &SetSignalPolicyOut{}
+	err := c.call("SetSignalPolicy", SetSignalPolicyIn{Signal: sig, Stop: stop, Print: print, Pass: pass}, out)
+	return out.Policy, err
+}
+
 // FollowExecEnabled returns true if follow exec mode is enabled.
 func (c *RPCClient) FollowExecEnabled() bool {
 	out := &FollowExecEnabledOut{}
@@ -709,15 +889,42 @@ func (c *RPCClient) DownloadLibraryDebugInfo(n int) error {
 	return c.call("DownloadLibraryDebugInfo", DownloadLibraryDebugInfoIn{n}, out)
 }
 
+func (c *RPCClient) AddressToLine(pc uint64, context int) (*api.AddressLocation, error) {
+	var out AddressToLineOut
+	err := c.call("AddressToLine", AddressToLineIn{pc, context}, &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out.Location, nil
+}
+
 func (c *RPCClient) TypeInfo(name string) (*api.TypeInfo, error) {
+	return c.typeInfo(name, false)
+}
+
+func (c *RPCClient) TypeInfoTree(name string) (*api.TypeInfo, error) {
+	return c.typeInfo(name, true)
+}
+
+func (c *RPCClient) typeInfo(name string, tree bool) (*api.TypeInfo, error) {
 	var out TypeInfoOut
-	err := c.call("TypeInfo", TypeInfoIn{name}, &out)
+	err := c.call("TypeInfo", TypeInfoIn{name, tree}, &out)
 	if err != nil {
 		return nil, err
 	}
 	return out.TypeInfo, err
 }
 
+func (c *RPCClient) GetConfig() (*api.ClientConfig, error) {
+	var out GetConfigOut
+	err := c.call("GetConfig", GetConfigIn{}, &out)
+	return &out.Config, err
+}
+
+func (c *RPCClient) SetConfig(cfg api.ClientConfig) error {
+	return c.call("SetConfig", SetConfigIn{cfg}, &SetConfigOut{})
+}
+
 func (c *RPCClient) call(method string, args, reply any) error {
 	return c.client.Call("RPCServer."+method, args, reply)
 }
@@ -0,0 +1,138 @@
+package rpc2
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-delve/delve/service/api"
+)
+
+// subscriptionQueueSize is the maximum number of events queued for a
+// subscription, when it is exceeded the oldest events are dropped.
+const subscriptionQueueSize = 1000
+
+// maxSubscriptions is the maximum number of subscriptions that can exist at
+// the same time, across all clients.
+const maxSubscriptions = 100
+
+var errSubscriptionClosed = errors.New("event subscription closed")
+
+// eventBroker delivers the events generated by the target to the clients
+// subscribed with SubscribeEvents.
+// Each subscription has its own queue, clients read it with WaitEvents
+// which returns as soon as at least one event is available.
+type eventBroker struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]*eventSubscription
+}
+
+type eventSubscription struct {
+	owner   *connState // connection that created the subscription
+	queue   []api.Event
+	dropped int
+	waiting bool
+	notify  chan struct{} // receives a value when events are added to queue
+	done    chan struct{} // closed when the subscription is removed
+}
+
+func (b *eventBroker) subscribe(owner *connState) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[int]*eventSubscription)
+	}
+	if len(b.subs) >= maxSubscriptions {
+		return 0, fmt.Errorf("too many event subscriptions (maximum %d)", maxSubscriptions)
+	}
+	b.nextID++
+	b.subs[b.nextID] = &eventSubscription{owner: owner, notify: make(chan struct{}, 1), done: make(chan struct{})}
+	return b.nextID, nil
+}
+
+func (b *eventBroker) unsubscribe(id int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub := b.subs[id]
+	if sub == nil {
+		return errSubscriptionClosed
+	}
+	delete(b.subs, id)
+	close(sub.done)
+	return nil
+}
+
+// unsubscribeAll removes all subscriptions created by owner.
+func (b *eventBroker) unsubscribeAll(owner *connState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, sub := range b.subs {
+		if sub.owner == owner {
+			delete(b.subs, id)
+			close(sub.done)
+		}
+	}
+}
+
+// hasSubscribers returns true if there is at least one subscription.
+func (b *eventBroker) hasSubscribers() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs) > 0
+}
+
+// publish adds event to the queue of all subscriptions.
+func (b *eventBroker) publish(event *api.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subs {
+		if len(sub.queue) >= subscriptionQueueSize {
+			sub.queue = sub.queue[1:]
+			sub.dropped++
+		}
+		sub.queue = append(sub.queue, *event)
+		select {
+		case sub.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// wait waits until at least one event is queued for subscription id and
+// returns all queued events and the number of events dropped since the
+// last call. If cancel is closed while waiting the subscription is removed.
+func (b *eventBroker) wait(id int, cancel <-chan struct{}) ([]api.Event, int, error) {
+	b.mu.Lock()
+	sub := b.subs[id]
+	if sub == nil {
+		b.mu.Unlock()
+		return nil, 0, errSubscriptionClosed
+	}
+	if sub.waiting {
+		b.mu.Unlock()
+		return nil, 0, errors.New("already waiting for events of this subscription")
+	}
+	sub.waiting = true
+	defer func() {
+		b.mu.Lock()
+		sub.waiting = false
+		b.mu.Unlock()
+	}()
+	for len(sub.queue) == 0 {
+		b.mu.Unlock()
+		select {
+		case <-sub.notify:
+		case <-sub.done:
+			return nil, 0, errSubscriptionClosed
+		case <-cancel:
+			b.unsubscribe(id)
+			return nil, 0, errSubscriptionClosed
+		}
+		b.mu.Lock()
+	}
+	events, dropped := sub.queue, sub.dropped
+	sub.queue, sub.dropped = nil, 0
+	b.mu.Unlock()
+	return events, dropped, nil
+}
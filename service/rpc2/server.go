@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/go-delve/delve/pkg/dwarf/op"
@@ -20,12 +21,73 @@ type RPCServer struct {
 	// debugger is a debugger service.
 	debugger   *debugger.Debugger
 	eventsChan chan *proc.Event
+	// broker delivers events to the clients that called SubscribeEvents.
+	broker *eventBroker
+	// conn is the state of the client connection served by this RPCServer,
+	// see NewConnection.
+	conn *connState
+}
+
+// connState is the state of a single client connection.
+type connState struct {
+	mu           sync.Mutex
+	clientConfig api.ClientConfig
 }
 
 const eventBufferSize = 100
 
 func NewServer(config *service.Config, debugger *debugger.Debugger) *RPCServer {
-	return &RPCServer{config, debugger, make(chan *proc.Event, eventBufferSize)}
+	return &RPCServer{
+		config:     config,
+		debugger:   debugger,
+		eventsChan: make(chan *proc.Event, eventBufferSize),
+		broker:     &eventBroker{},
+		conn:       newConnState(),
+	}
+}
+
+func newConnState() *connState {
+	return &connState{
+		clientConfig: api.ClientConfig{
+			LoadConfig: api.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, MaxStructFields: -1},
+		},
+	}
+}
+
+// NewConnection returns a RPCServer that serves the requests received on
+// a new client connection. It shares the debugger and the event
+// subscriptions with s but has its own configuration, see SetConfig.
+func (s *RPCServer) NewConnection() *RPCServer {
+	s2 := *s
+	s2.conn = newConnState()
+	return &s2
+}
+
+// Close releases the resources associated with the client connection
+// served by s, removing all of the event subscriptions it created. It must
+// be called when the connection is closed.
+func (s *RPCServer) Close() {
+	s.broker.unsubscribeAll(s.conn)
+}
+
+// loadConfig returns the load configuration to use for requests that do
+// not specify one.
+func (s *RPCServer) loadConfig() *api.LoadConfig {
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+	cfg := s.conn.clientConfig.LoadConfig
+	return &cfg
+}
+
+// substitutePathRules returns rules if it isn't nil, otherwise the
+// substitute-path rules set with SetConfig.
+func (s *RPCServer) substitutePathRules(rules [][2]string) [][2]string {
+	if rules != nil {
+		return rules
+	}
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+	return s.conn.clientConfig.SubstitutePathRules
 }
 
 type ProcessPidIn struct {
@@ -130,11 +192,29 @@ type CommandOut struct {
 
 // Command interrupts, continues and steps through the program.
 func (s *RPCServer) Command(command api.DebuggerCommand, cb service.RPCCallback) {
-	eventsFn := s.eventsFn
-	if !command.WithEvents {
-		eventsFn = nil
+	var eventsFn func(*proc.Event)
+	subscribed := s.broker.hasSubscribers()
+	switch {
+	case command.WithEvents && subscribed:
+		eventsFn = func(event *proc.Event) {
+			s.eventsFn(event)
+			s.broker.publish(api.ConvertEvent(event))
+		}
+	case command.WithEvents:
+		eventsFn = s.eventsFn
+	case subscribed:
+		eventsFn = func(event *proc.Event) {
+			s.broker.publish(api.ConvertEvent(event))
+		}
 	}
 	st, err := s.debugger.Command(&command, cb.SetupDoneChan(), cb.DisconnectChan(), eventsFn)
+	if subscribed {
+		details := &api.StateChangedEventDetails{State: st}
+		if err != nil {
+			details.Err = err.Error()
+		}
+		s.broker.publish(&api.Event{Kind: api.EventStateChanged, StateChangedEventDetails: details})
+	}
 	if err != nil {
 		cb.Return(nil, err)
 		return
@@ -209,11 +289,14 @@ type StacktraceOut struct {
 func (s *RPCServer) Stacktrace(arg StacktraceIn, out *StacktraceOut) error {
 	cfg := arg.Cfg
 	if cfg == nil && arg.Full {
-		cfg = &api.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, MaxStructFields: -1}
+		cfg = s.loadConfig()
 	}
 	if arg.Defers {
 		arg.Opts |= api.StacktraceReadDefers
 	}
+	if cfg != nil && arg.Opts&api.StacktraceFramePointers != 0 {
+		return errors.New("can not load variables of a stacktrace obtained following frame pointers")
+	}
 	rawlocs, err := s.debugger.Stacktrace(arg.Id, arg.Depth, arg.Opts)
 	if err != nil {
 		return err
@@ -229,6 +312,42 @@ func (s *RPCServer) Stacktrace(arg StacktraceIn, out *StacktraceOut) error {
 	return err
 }
 
+type ListGoroutineDefersIn struct {
+	GoroutineID int64
+}
+
+type ListGoroutineDefersOut struct {
+	Defers []api.Defer
+}
+
+// ListGoroutineDefers returns the deferred calls of goroutine GoroutineID
+// that haven't been executed yet, starting with the most recent one.
+// Calls deferred using open-coded defers are only returned for programs
+// built with Go 1.22 or later.
+func (s *RPCServer) ListGoroutineDefers(arg ListGoroutineDefersIn, out *ListGoroutineDefersOut) error {
+	var err error
+	out.Defers, err = s.debugger.StacktraceDefers(arg.GoroutineID)
+	return err
+}
+
+type ListGoroutinePanicsIn struct {
+	GoroutineID int64
+	Cfg         api.LoadConfig
+}
+
+type ListGoroutinePanicsOut struct {
+	Panics []api.Panic
+}
+
+// ListGoroutinePanics returns the panics, and calls to runtime.Goexit, in
+// progress on goroutine GoroutineID, starting with the most recent one.
+// The argument of each panic is loaded using Cfg.
+func (s *RPCServer) ListGoroutinePanics(arg ListGoroutinePanicsIn, out *ListGoroutinePanicsOut) error {
+	var err error
+	out.Panics, err = s.debugger.GoroutinePanics(arg.GoroutineID, *api.LoadConfigToProc(&arg.Cfg))
+	return err
+}
+
 type AncestorsIn struct {
 	GoroutineID  int64
 	NumAncestors int
@@ -246,6 +365,28 @@ func (s *RPCServer) Ancestors(arg AncestorsIn, out *AncestorsOut) error {
 	return err
 }
 
+type GoroutineCreationStacktraceIn struct {
+	GoroutineID int64
+	Depth       int
+}
+
+type GoroutineCreationStacktraceOut struct {
+	Locations []api.Stackframe
+}
+
+// GoroutineCreationStacktrace returns the stacktrace of the 'go' statement
+// that created goroutine GoroutineID.
+// Unless the target was started with GODEBUG=tracebackancestors=N only the
+// frame containing the 'go' statement is returned.
+func (s *RPCServer) GoroutineCreationStacktrace(arg GoroutineCreationStacktraceIn, out *GoroutineCreationStacktraceOut) error {
+	rawlocs, err := s.debugger.GoroutineCreationStacktrace(arg.GoroutineID, arg.Depth)
+	if err != nil {
+		return err
+	}
+	out.Locations, err = s.debugger.ConvertStacktrace(rawlocs, nil)
+	return err
+}
+
 type ListBreakpointsIn struct {
 	All bool
 }
@@ -280,7 +421,7 @@ func (s *RPCServer) CreateBreakpoint(arg CreateBreakpointIn, out *CreateBreakpoi
 	if err := api.ValidBreakpointName(arg.Breakpoint.Name); err != nil {
 		return err
 	}
-	createdbp, err := s.debugger.CreateBreakpoint(&arg.Breakpoint, arg.LocExpr, arg.SubstitutePathRules, arg.Suspended)
+	createdbp, err := s.debugger.CreateBreakpoint(&arg.Breakpoint, arg.LocExpr, s.substitutePathRules(arg.SubstitutePathRules), arg.Suspended)
 	if err != nil {
 		return err
 	}
@@ -412,6 +553,13 @@ func (s *RPCServer) ListThreads(arg ListThreadsIn, out *ListThreadsOut) (err err
 	_, unlock := s.debugger.LockTargetGroup()
 	defer unlock()
 	out.Threads = api.ConvertThreads(threads, s.debugger.ConvertThreadBreakpoint)
+	for i := range out.Threads {
+		var cpu int
+		out.Threads[i].OSState, cpu = s.debugger.ThreadOSInfo(threads[i])
+		if cpu >= 0 {
+			out.Threads[i].LastCPU = &cpu
+		}
+	}
 	return nil
 }
 
@@ -435,6 +583,11 @@ func (s *RPCServer) GetThread(arg GetThreadIn, out *GetThreadOut) error {
 	_, unlock := s.debugger.LockTargetGroup()
 	defer unlock()
 	out.Thread = api.ConvertThread(t, s.debugger.ConvertThreadBreakpoint(t))
+	var cpu int
+	out.Thread.OSState, cpu = s.debugger.ThreadOSInfo(t)
+	if cpu >= 0 {
+		out.Thread.LastCPU = &cpu
+	}
 	return nil
 }
 
@@ -498,13 +651,59 @@ func (s *RPCServer) ListRegisters(arg ListRegistersIn, out *ListRegistersOut) er
 	return nil
 }
 
+type SetRegisterIn struct {
+	ThreadID int
+	Name     string
+	Value    uint64
+}
+
+type SetRegisterOut struct {
+	Regs api.Registers
+}
+
+// SetRegister changes the value of the general purpose register called
+// SetRegisterIn.Name of thread SetRegisterIn.ThreadID, or of the current
+// thread if ThreadID is 0.
+// On success the updated registers of the thread are returned.
+func (s *RPCServer) SetRegister(arg SetRegisterIn, out *SetRegisterOut) error {
+	if arg.ThreadID == 0 {
+		state, err := s.debugger.State(false)
+		if err != nil {
+			return err
+		}
+		if state.CurrentThread == nil {
+			return errors.New("no current thread")
+		}
+		arg.ThreadID = state.CurrentThread.ID
+	}
+	if err := s.debugger.SetRegister(arg.ThreadID, arg.Name, arg.Value); err != nil {
+		return err
+	}
+	regs, dwarfRegisterToString, err := s.debugger.ThreadRegisters(arg.ThreadID)
+	if err != nil {
+		return err
+	}
+	out.Regs = api.ConvertRegisters(regs, dwarfRegisterToString, false)
+	return nil
+}
+
 type ListLocalVarsIn struct {
 	Scope api.EvalScope
 	Cfg   api.LoadConfig
+
+	// TypeArguments requests the type arguments of the function executing
+	// in scope, see ListTypeArguments.
+	TypeArguments bool
+	// SubstitutePathRules is used to find the source file of the function
+	// when TypeArguments is set, see ListTypeArguments.
+	SubstitutePathRules [][2]string
 }
 
 type ListLocalVarsOut struct {
 	Variables []api.Variable
+	// TypeArguments, if requested, are the type arguments of the function
+	// executing in scope, empty if the function isn't generic.
+	TypeArguments []api.TypeArgument
 }
 
 // ListLocalVars lists all local variables in scope.
@@ -514,6 +713,36 @@ func (s *RPCServer) ListLocalVars(arg ListLocalVarsIn, out *ListLocalVarsOut) er
 		return err
 	}
 	out.Variables = api.ConvertVars(vars)
+	if arg.TypeArguments {
+		out.TypeArguments, err = s.debugger.TypeArguments(arg.Scope.GoroutineID, arg.Scope.Frame, arg.Scope.DeferredCall, s.substitutePathRules(arg.SubstitutePathRules))
+	}
+	return err
+}
+
+type ListTypeArgumentsIn struct {
+	Scope api.EvalScope
+	// SubstitutePathRules is used to find the source file of the function,
+	// which is read to determine the names of the type parameters.
+	SubstitutePathRules [][2]string
+}
+
+type ListTypeArgumentsOut struct {
+	TypeArguments []api.TypeArgument
+}
+
+// ListTypeArguments lists the type arguments of the instantiation of the
+// generic function executing in scope. The list is empty if the function
+// isn't generic.
+// The names of the type parameters are not recorded in the debug info,
+// they are read from the source file of the function after applying
+// SubstitutePathRules (or the substitute-path rules set with SetConfig) to
+// its path.
+func (s *RPCServer) ListTypeArguments(arg ListTypeArgumentsIn, out *ListTypeArgumentsOut) error {
+	targs, err := s.debugger.TypeArguments(arg.Scope.GoroutineID, arg.Scope.Frame, arg.Scope.DeferredCall, s.substitutePathRules(arg.SubstitutePathRules))
+	if err != nil {
+		return err
+	}
+	out.TypeArguments = targs
 	return nil
 }
 
@@ -553,7 +782,7 @@ type EvalOut struct {
 func (s *RPCServer) Eval(arg EvalIn, out *EvalOut) error {
 	cfg := arg.Cfg
 	if cfg == nil {
-		cfg = &api.LoadConfig{FollowPointers: true, MaxVariableRecurse: 1, MaxStringLen: 64, MaxArrayValues: 64, MaxStructFields: -1}
+		cfg = s.loadConfig()
 	}
 	pcfg := *api.LoadConfigToProc(cfg)
 	v, err := s.debugger.EvalVariableInScope(arg.Scope.GoroutineID, arg.Scope.Frame, arg.Scope.DeferredCall, arg.Expr, pcfg)
@@ -564,6 +793,39 @@ func (s *RPCServer) Eval(arg EvalIn, out *EvalOut) error {
 	return nil
 }
 
+type EvalGoroutinesIn struct {
+	Expr string
+	Cfg  *api.LoadConfig
+}
+
+type EvalGoroutinesOut struct {
+	// Results maps each goroutine ID to the result of evaluating Expr in
+	// its topmost frame.
+	Results map[int64]api.GoroutineEvalResult
+}
+
+// EvalGoroutines evaluates arg.Expr in the topmost frame of every
+// goroutine. If the evaluation fails for a goroutine the error is
+// reported in its result instead of failing the whole call.
+func (s *RPCServer) EvalGoroutines(arg EvalGoroutinesIn, out *EvalGoroutinesOut) error {
+	cfg := arg.Cfg
+	if cfg == nil {
+		cfg = s.loadConfig()
+	}
+	vars, errs, err := s.debugger.EvalGoroutines(arg.Expr, *api.LoadConfigToProc(cfg))
+	if err != nil {
+		return err
+	}
+	out.Results = make(map[int64]api.GoroutineEvalResult, len(vars)+len(errs))
+	for goid, v := range vars {
+		out.Results[goid] = api.GoroutineEvalResult{Variable: api.ConvertVar(v)}
+	}
+	for goid, err := range errs {
+		out.Results[goid] = api.GoroutineEvalResult{Error: err.Error()}
+	}
+	return nil
+}
+
 type SetIn struct {
 	Scope  api.EvalScope
 	Symbol string
@@ -581,14 +843,37 @@ func (s *RPCServer) Set(arg SetIn, out *SetOut) error {
 
 type ListSourcesIn struct {
 	Filter string
+
+	// IncludeCompileUnits also returns the package and compile unit of each
+	// source file, in SourceFiles.
+	IncludeCompileUnits bool
 }
 
 type ListSourcesOut struct {
-	Sources []string
+	Sources     []string
+	SourceFiles []api.SourceFile
 }
 
 // ListSources lists all source files in the process matching filter.
+// If arg.IncludeCompileUnits is set out.SourceFiles will also contain the
+// package and compile unit of each file. When a file is part of more than
+// one compile unit, for example because functions defined in it are inlined
+// in other packages, the compile unit of the package where the file resides
+// is reported.
 func (s *RPCServer) ListSources(arg ListSourcesIn, out *ListSourcesOut) error {
+	if arg.IncludeCompileUnits {
+		files, err := s.debugger.SourceFiles(arg.Filter)
+		if err != nil {
+			return err
+		}
+		out.Sources = make([]string, 0, len(files))
+		out.SourceFiles = make([]api.SourceFile, 0, len(files))
+		for _, f := range files {
+			out.Sources = append(out.Sources, f.Path)
+			out.SourceFiles = append(out.SourceFiles, api.SourceFile{Path: f.Path, Package: f.Package, CompileUnit: f.CompileUnit})
+		}
+		return nil
+	}
 	ss, err := s.debugger.Sources(arg.Filter)
 	if err != nil {
 		return err
@@ -731,6 +1016,48 @@ func (s *RPCServer) ListGoroutines(arg ListGoroutinesIn, out *ListGoroutinesOut)
 	return nil
 }
 
+type ListGoroutineStackGroupsIn struct {
+	Filters []api.ListGoroutinesFilter
+
+	// Depth is the maximum number of frames read from the stack of each
+	// goroutine, defaults to 50.
+	Depth int
+	// MaxGroupMembers is the maximum number of goroutine IDs returned for
+	// each group.
+	MaxGroupMembers int
+}
+
+type ListGoroutineStackGroupsOut struct {
+	Groups []api.GoroutineStackGroup
+}
+
+// ListGoroutineStackGroups returns goroutines grouped by their stack, two
+// goroutines belong to the same group if the PC addresses of all the
+// frames of their stacks are the same.
+// For each group the stack, the number of goroutines in the group, the
+// IDs of up to MaxGroupMembers goroutines and the distinct sets of labels
+// of the goroutines are returned. Groups are sorted by decreasing size.
+//
+// If arg.Filters are specified only the goroutines matching the filters
+// are considered, see ListGoroutines for a description of the filters.
+func (s *RPCServer) ListGoroutineStackGroups(arg ListGoroutineStackGroupsIn, out *ListGoroutineStackGroupsOut) error {
+	for _, filter := range arg.Filters {
+		if filter.Kind == api.GoroutineWaitingOnChannel {
+			return errors.New("channel filter not supported by ListGoroutineStackGroups")
+		}
+	}
+	if arg.Depth <= 0 {
+		arg.Depth = 50
+	}
+	gs, _, err := s.debugger.Goroutines(0, 0)
+	if err != nil {
+		return err
+	}
+	gs = s.debugger.FilterGoroutines(gs, arg.Filters)
+	out.Groups, err = s.debugger.GroupGoroutinesByStack(gs, arg.Depth, arg.MaxGroupMembers)
+	return err
+}
+
 type AttachedToExistingProcessIn struct {
 }
 
@@ -777,7 +1104,7 @@ type FindLocationOut struct {
 // NOTE: this function does not actually set breakpoints.
 func (s *RPCServer) FindLocation(arg FindLocationIn, out *FindLocationOut) error {
 	var err error
-	out.Locations, out.SubstituteLocExpr, err = s.debugger.FindLocation(arg.Scope.GoroutineID, arg.Scope.Frame, arg.Scope.DeferredCall, arg.Loc, arg.IncludeNonExecutableLines, arg.SubstitutePathRules)
+	out.Locations, out.SubstituteLocExpr, err = s.debugger.FindLocation(arg.Scope.GoroutineID, arg.Scope.Frame, arg.Scope.DeferredCall, arg.Loc, arg.IncludeNonExecutableLines, s.substitutePathRules(arg.SubstitutePathRules))
 	return err
 }
 
@@ -912,6 +1239,33 @@ func (s *RPCServer) FunctionReturnLocations(in FunctionReturnLocationsIn, out *F
 	return nil
 }
 
+// AddressToLineIn holds the arguments of AddressToLine.
+type AddressToLineIn struct {
+	PC uint64
+	// Context is the number of source lines before and after the line
+	// containing PC that should be returned.
+	Context int
+}
+
+// AddressToLineOut holds the return values of AddressToLine.
+type AddressToLineOut struct {
+	Location api.AddressLocation
+}
+
+// AddressToLine returns the file, line and function of an arbitrary
+// address, for example one read from a log or a profile, along with
+// Context lines of source before and after it. If PC has no line
+// information the location of the entry point of the function containing
+// it is returned and Location.Exact is false.
+func (s *RPCServer) AddressToLine(arg AddressToLineIn, out *AddressToLineOut) error {
+	loc, err := s.debugger.AddressToLine(arg.PC, arg.Context)
+	if err != nil {
+		return err
+	}
+	out.Location = *loc
+	return nil
+}
+
 // ListDynamicLibrariesIn holds the arguments of ListDynamicLibraries
 type ListDynamicLibrariesIn struct {
 }
@@ -937,6 +1291,30 @@ func (s *RPCServer) ListDynamicLibraries(in ListDynamicLibrariesIn, out *ListDyn
 	return nil
 }
 
+// ListPluginsIn holds the arguments of ListPlugins
+type ListPluginsIn struct {
+}
+
+// ListPluginsOut holds the return values of ListPlugins
+type ListPluginsOut struct {
+	List []api.Plugin
+}
+
+// ListPlugins returns the list of Go plugins loaded by the target process.
+func (s *RPCServer) ListPlugins(in ListPluginsIn, out *ListPluginsOut) error {
+	plugins, err := s.debugger.Plugins()
+	if err != nil {
+		return err
+	}
+	_, unlock := s.debugger.LockTargetGroup()
+	defer unlock()
+	out.List = make([]api.Plugin, 0, len(plugins))
+	for i := range plugins {
+		out.List = append(out.List, api.ConvertPlugin(&plugins[i]))
+	}
+	return nil
+}
+
 // ListPackagesBuildInfoIn holds the arguments of ListPackagesBuildInfo.
 type ListPackagesBuildInfoIn struct {
 	IncludeFiles bool
@@ -988,6 +1366,34 @@ func (s *RPCServer) ListPackagesBuildInfo(in ListPackagesBuildInfoIn, out *ListP
 	return nil
 }
 
+// ListProducersIn holds the arguments of ListProducers.
+type ListProducersIn struct {
+}
+
+// ListProducersOut holds the return values of ListProducers.
+type ListProducersOut struct {
+	List []api.ProducerInfo
+}
+
+// ListProducers returns the list of distinct DW_AT_producer attributes
+// (compiler version and flags) of the compile units in the program, along
+// with the files covered by each one.
+// This can be used to find parts of the program that were compiled with
+// optimizations enabled.
+func (s *RPCServer) ListProducers(in ListProducersIn, out *ListProducersOut) error {
+	producers := s.debugger.ListProducers()
+	out.List = make([]api.ProducerInfo, 0, len(producers))
+	for _, pi := range producers {
+		files := make([]string, 0, len(pi.Files))
+		for file := range pi.Files {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		out.List = append(out.List, api.ProducerInfo{Producer: pi.Producer, Files: files})
+	}
+	return nil
+}
+
 // ExamineMemoryIn holds the arguments of ExamineMemory
 type ExamineMemoryIn struct {
 	Address uint64
@@ -1017,6 +1423,68 @@ func (s *RPCServer) ExamineMemory(arg ExamineMemoryIn, out *ExaminedMemoryOut) e
 	return nil
 }
 
+// SearchMemoryIn holds the arguments of SearchMemory
+type SearchMemoryIn struct {
+	Scope api.EvalScope
+	// Expr is evaluated in Scope, its value is what will be searched for.
+	Expr string
+	// Align is the alignment of the addresses reported, if 0 the natural
+	// alignment of the value of Expr is used.
+	Align int
+	// Max is the maximum number of results returned, 0 means no limit.
+	Max int
+}
+
+// SearchMemoryOut holds the return values of SearchMemory
+type SearchMemoryOut struct {
+	Results []api.MemorySearchResult
+}
+
+// SearchMemory searches the readable memory of the target for the value of
+// an expression. Pointers are searched for as the address they point to,
+// integers and floating point numbers by their value and strings by their
+// contents.
+// Each result is annotated with the package variable, function or
+// goroutine stack containing it, when possible.
+// This is mostly useful for core files, for example to find all references
+// to an object.
+func (s *RPCServer) SearchMemory(arg SearchMemoryIn, out *SearchMemoryOut) error {
+	results, err := s.debugger.SearchMemory(arg.Scope.GoroutineID, arg.Scope.Frame, arg.Scope.DeferredCall, arg.Expr, arg.Align, arg.Max)
+	if err != nil {
+		return err
+	}
+	out.Results = make([]api.MemorySearchResult, 0, len(results))
+	for i := range results {
+		out.Results = append(out.Results, api.ConvertMemorySearchResult(&results[i]))
+	}
+	return nil
+}
+
+// ListMemoryMappingsIn holds the arguments of ListMemoryMappings
+type ListMemoryMappingsIn struct {
+}
+
+// ListMemoryMappingsOut holds the return values of ListMemoryMappings
+type ListMemoryMappingsOut struct {
+	Mappings []api.MemoryMapping
+}
+
+// ListMemoryMappings lists the memory mappings of the target process, with
+// their permissions and the file backing them. For live processes on Linux
+// this is read from /proc/PID/maps, for core files from the program headers
+// of the core file.
+func (s *RPCServer) ListMemoryMappings(arg ListMemoryMappingsIn, out *ListMemoryMappingsOut) error {
+	memmap, err := s.debugger.MemoryMap()
+	if err != nil {
+		return err
+	}
+	out.Mappings = make([]api.MemoryMapping, 0, len(memmap))
+	for i := range memmap {
+		out.Mappings = append(out.Mappings, api.ConvertMemoryMapEntry(&memmap[i]))
+	}
+	return nil
+}
+
 type StopRecordingIn struct {
 }
 
@@ -1083,6 +1551,13 @@ type CreateWatchpointIn struct {
 	Scope api.EvalScope
 	Expr  string
 	Type  api.WatchType
+
+	// Field, if set, creates a watchpoint on a field of all instances of a
+	// struct type allocated from now on, Expr must have the form
+	// <type>.<field> and Scope is ignored. Max is the maximum number of
+	// instances watched at the same time, 0 means no limit.
+	Field bool
+	Max   int
 }
 
 type CreateWatchpointOut struct {
@@ -1091,6 +1566,10 @@ type CreateWatchpointOut struct {
 
 func (s *RPCServer) CreateWatchpoint(arg CreateWatchpointIn, out *CreateWatchpointOut) error {
 	var err error
+	if arg.Field {
+		out.Breakpoint, err = s.debugger.CreateFieldWatchpoint(arg.Expr, arg.Type, arg.Max)
+		return err
+	}
 	out.Breakpoint, err = s.debugger.CreateWatchpoint(arg.Scope.GoroutineID, arg.Scope.Frame, arg.Scope.DeferredCall, arg.Expr, arg.Type)
 	return err
 }
@@ -1153,6 +1632,44 @@ func (s *RPCServer) FollowExecEnabled(arg FollowExecEnabledIn, out *FollowExecEn
 	return nil
 }
 
+type ListSignalPoliciesIn struct {
+}
+
+type ListSignalPoliciesOut struct {
+	Policies []api.SignalPolicy
+}
+
+// ListSignalPolicies returns the policy of every signal known to the
+// debugger.
+func (s *RPCServer) ListSignalPolicies(arg ListSignalPoliciesIn, out *ListSignalPoliciesOut) error {
+	out.Policies = s.debugger.SignalPolicies()
+	return nil
+}
+
+type SetSignalPolicyIn struct {
+	// Signal is the name (with or without the SIG prefix) or the number of
+	// the signal.
+	Signal string
+	Stop   bool
+	Print  bool
+	Pass   bool
+}
+
+type SetSignalPolicyOut struct {
+	Policy api.SignalPolicy
+}
+
+// SetSignalPolicy sets the policy used when the target process receives a
+// signal: whether the target process is stopped, the client is notified
+// with a SignalEvent and the signal is delivered to the target process.
+// Signals that were never configured are delivered to the target process
+// without stopping it or notifying the client.
+func (s *RPCServer) SetSignalPolicy(arg SetSignalPolicyIn, out *SetSignalPolicyOut) error {
+	var err error
+	out.Policy, err = s.debugger.SetSignalPolicy(arg.Signal, arg.Stop, arg.Print, arg.Pass)
+	return err
+}
+
 type DebugInfoDirectoriesIn struct {
 	Set  bool
 	List []string
@@ -1209,6 +1726,71 @@ func (s *RPCServer) GetEvents(arg GetEventsIn, cb service.RPCCallback) {
 	cb.Return(out, nil)
 }
 
+type SubscribeEventsIn struct {
+}
+
+type SubscribeEventsOut struct {
+	// ID identifies the subscription in calls to WaitEvents and
+	// UnsubscribeEvents.
+	ID int
+}
+
+// SubscribeEvents creates a subscription to the events generated while
+// the target is running, by any client, without having to issue the
+// command that resumed it. After every command that resumes the target
+// completes an EventStateChanged event is sent, containing the new state
+// of the target, which includes the breakpoints and tracepoints hit.
+//
+// Events are delivered by calling WaitEvents repeatedly, each call returns
+// as soon as at least one event is available. At most 1000 events are kept
+// for each subscription, if they are not read quickly enough the oldest
+// ones are dropped. The subscription is removed by calling
+// UnsubscribeEvents or when the client that created it disconnects. At
+// most 100 subscriptions can exist at the same time.
+//
+// Output of the target process is not delivered as events.
+func (s *RPCServer) SubscribeEvents(arg SubscribeEventsIn, out *SubscribeEventsOut) error {
+	var err error
+	out.ID, err = s.broker.subscribe(s.conn)
+	return err
+}
+
+type WaitEventsIn struct {
+	ID int
+}
+
+type WaitEventsOut struct {
+	Events []api.Event
+	// Dropped is the number of events that were discarded since the last
+	// call to WaitEvents because they were not read quickly enough.
+	Dropped int
+}
+
+// WaitEvents waits for events on a subscription created by
+// SubscribeEvents and returns all events that were queued.
+func (s *RPCServer) WaitEvents(arg WaitEventsIn, cb service.RPCCallback) {
+	close(cb.SetupDoneChan())
+	events, dropped, err := s.broker.wait(arg.ID, cb.DisconnectChan())
+	if err != nil {
+		cb.Return(nil, err)
+		return
+	}
+	cb.Return(&WaitEventsOut{Events: events, Dropped: dropped}, nil)
+}
+
+type UnsubscribeEventsIn struct {
+	ID int
+}
+
+type UnsubscribeEventsOut struct {
+}
+
+// UnsubscribeEvents removes a subscription created by SubscribeEvents, a
+// pending call to WaitEvents for it returns an error.
+func (s *RPCServer) UnsubscribeEvents(arg UnsubscribeEventsIn, out *UnsubscribeEventsOut) error {
+	return s.broker.unsubscribe(arg.ID)
+}
+
 type CancelDownloadsIn struct {
 }
 
@@ -1234,6 +1816,9 @@ func (s *RPCServer) DownloadLibraryDebugInfo(arg DownloadLibraryDebugInfoIn, out
 
 type TypeInfoIn struct {
 	Name string
+	// Tree requests the tree of types that make up the layout of the type,
+	// see api.TypeTreeNode.
+	Tree bool
 }
 
 type TypeInfoOut struct {
@@ -1243,6 +1828,60 @@ type TypeInfoOut struct {
 // TypeInfo returns informations about the specified type.
 func (s *RPCServer) TypeInfo(arg TypeInfoIn, out *TypeInfoOut) error {
 	var err error
-	out.TypeInfo, err = s.debugger.TypeInfo(arg.Name)
+	out.TypeInfo, err = s.debugger.TypeInfo(arg.Name, arg.Tree)
 	return err
 }
+
+type GetConfigIn struct {
+}
+
+type GetConfigOut struct {
+	Config api.ClientConfig
+}
+
+// GetConfig returns the configuration of the client connection, see
+// SetConfig.
+func (s *RPCServer) GetConfig(arg GetConfigIn, out *GetConfigOut) error {
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+	out.Config = s.conn.clientConfig
+	return nil
+}
+
+type SetConfigIn struct {
+	Config api.ClientConfig
+}
+
+type SetConfigOut struct {
+}
+
+// SetConfig changes the configuration of the client connection, other
+// clients connected to the same server are not affected.
+//
+// Config.LoadConfig will be used to load variables by Eval, EvalGoroutines
+// and Stacktrace requests that do not specify a load configuration, and
+// Config.SubstitutePathRules by CreateBreakpoint and FindLocation requests
+// that do not specify any substitute-path rules (a nil slice). Clients can
+// also read the configuration with GetConfig to present the same settings
+// to the user.
+func (s *RPCServer) SetConfig(arg SetConfigIn, out *SetConfigOut) error {
+	cfg := arg.Config.LoadConfig
+	if cfg.MaxVariableRecurse < 0 {
+		return errors.New("invalid load configuration: MaxVariableRecurse can not be negative")
+	}
+	if cfg.MaxStringLen <= 0 || cfg.MaxArrayValues <= 0 {
+		return errors.New("invalid load configuration: MaxStringLen and MaxArrayValues must be greater than zero")
+	}
+	if cfg.MaxStructFields == 0 || cfg.MaxStructFields < -1 {
+		return errors.New("invalid load configuration: MaxStructFields must be greater than zero or -1")
+	}
+	for _, rule := range arg.Config.SubstitutePathRules {
+		if rule[0] == "" && rule[1] == "" {
+			return errors.New("invalid substitute-path rule: both paths are empty")
+		}
+	}
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+	s.conn.clientConfig = arg.Config
+	return nil
+}
@@ -3,6 +3,8 @@ package rpccommon
 import (
 	"bufio"
 	"bytes"
+	"crypto/subtle"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +16,7 @@ import (
 	"reflect"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/go-delve/delve/pkg/logflags"
 	"github.com/go-delve/delve/pkg/version"
@@ -38,11 +41,10 @@ type ServerImpl struct {
 	stopChan chan struct{}
 	// debugger is the debugger service.
 	debugger *debugger.Debugger
-	// s2 is APIv2 server.
-	s2 *rpc2.RPCServer
-	// maps of served methods, one for each supported API.
-	methodMaps []map[string]*methodType
-	log        logflags.Logger
+	// s2 is APIv2 server, each connection is served by a copy of it, see
+	// rpc2.RPCServer.NewConnection.
+	s2  *rpc2.RPCServer
+	log logflags.Logger
 }
 
 type RPCCallback struct {
@@ -79,9 +81,13 @@ func NewServer(config *service.Config) *ServerImpl {
 		logflags.WriteAPIListeningMessage(config.Listener.Addr())
 		logger.Debug("API server pid = ", os.Getpid())
 	}
+	listener := config.Listener
+	if config.TLSConfig != nil {
+		listener = tls.NewListener(listener, config.TLSConfig)
+	}
 	return &ServerImpl{
 		config:   config,
-		listener: config.Listener,
+		listener: listener,
 		stopChan: make(chan struct{}),
 		log:      logger,
 	}
@@ -122,16 +128,6 @@ func (s *ServerImpl) Run() error {
 
 	s.s2 = rpc2.NewServer(s.config, s.debugger)
 
-	rpcServer := &RPCServer{s}
-
-	s.methodMaps = make([]map[string]*methodType, 2)
-
-	s.methodMaps[1] = map[string]*methodType{}
-
-	suitableMethods2(s.s2, s.methodMaps[1])
-	suitableMethodsCommon(rpcServer, s.methodMaps[1])
-	finishMethodsMapInit(s.methodMaps[1])
-
 	go func() {
 		defer s.listener.Close()
 		for {
@@ -153,8 +149,15 @@ func (s *ServerImpl) Run() error {
 				}
 			}
 
-			go s.serveConnectionDemux(c)
+			// Without AcceptMulti only one connection is served: wait until
+			// the client has completed the TLS handshake and presented the
+			// client token before giving up on accepting new connections.
+			accepted := make(chan bool, 1)
+			go s.serveConnectionDemux(c, accepted)
 			if !s.config.AcceptMulti {
+				if !<-accepted {
+					continue
+				}
 				break
 			}
 		}
@@ -162,28 +165,83 @@ func (s *ServerImpl) Run() error {
 	return nil
 }
 
+// clientAuthTimeout is the time a new client has to complete the TLS
+// handshake and to present the client token.
+const clientAuthTimeout = 10 * time.Second
+
 type bufReadWriteCloser struct {
 	*bufio.Reader
 	io.WriteCloser
 }
 
-func (s *ServerImpl) serveConnectionDemux(c io.ReadWriteCloser) {
+// serveConnectionDemux serves connection c with either DAP or JSON-RPC.
+// Whether the client was accepted, i.e. it completed the TLS handshake and
+// presented the client token, is sent to accepted exactly once.
+func (s *ServerImpl) serveConnectionDemux(c net.Conn, accepted chan<- bool) {
+	reject := func(format string, args ...any) {
+		s.log.Warnf(format, args...)
+		c.Close()
+		accepted <- false
+	}
+	if tlsConn, ok := c.(*tls.Conn); ok {
+		tlsConn.SetDeadline(time.Now().Add(clientAuthTimeout))
+		err := tlsConn.Handshake()
+		tlsConn.SetDeadline(time.Time{})
+		if err != nil {
+			reject("closing connection, TLS handshake failed: %v", err)
+			return
+		}
+	}
+
+	// authenticated is called by serveJSONCodec once the client token has
+	// been checked, it is nil if the server does not require one.
+	var authenticated func(ok bool)
+	if s.config.ClientToken == "" {
+		accepted <- true
+	} else {
+		c.SetReadDeadline(time.Now().Add(clientAuthTimeout))
+		authenticated = func(ok bool) {
+			if ok {
+				c.SetReadDeadline(time.Time{})
+			}
+			accepted <- ok
+		}
+	}
+
 	conn := &bufReadWriteCloser{bufio.NewReader(c), c}
 	b, err := conn.Peek(1)
 	if err != nil {
 		s.log.Warnf("error determining new connection protocol: %v", err)
+		c.Close()
+		if authenticated != nil {
+			authenticated(false)
+		}
 		return
 	}
 	if b[0] == 'C' { // C is for DAP's Content-Length
+		if s.config.ClientToken != "" {
+			reject("closing DAP connection, DAP clients can not present a client token")
+			return
+		}
 		s.log.Debugf("serving DAP on new connection")
 		ds := dap.NewSession(conn, &dap.Config{Config: s.config, StopTriggered: s.stopChan}, s.debugger)
 		go ds.ServeDAPCodec()
 	} else {
 		s.log.Debugf("serving JSON-RPC on new connection")
-		go s.serveJSONCodec(conn)
+		go s.serveJSONCodec(conn, authenticated)
 	}
 }
 
+// newMethodMap returns the map of methods served on a connection, s2 is the
+// APIv2 server for the connection.
+func (s *ServerImpl) newMethodMap(s2 *rpc2.RPCServer) map[string]*methodType {
+	methods := map[string]*methodType{}
+	suitableMethods2(s2, methods)
+	suitableMethodsCommon(&RPCServer{s}, methods)
+	finishMethodsMapInit(methods)
+	return methods
+}
+
 func finishMethodsMapInit(methods map[string]*methodType) {
 	for name, method := range methods {
 		mtype := method.method.Type()
@@ -196,15 +254,26 @@ func finishMethodsMapInit(methods map[string]*methodType) {
 	}
 }
 
-func (s *ServerImpl) serveJSONCodec(conn io.ReadWriteCloser) {
+// serveJSONCodec serves JSON-RPC on conn. If authenticated is not nil the
+// first request must present the client token and authenticated is called
+// with the result of the check.
+func (s *ServerImpl) serveJSONCodec(conn io.ReadWriteCloser, authenticated func(ok bool)) {
 	clientDisconnectChan := make(chan struct{})
 	defer func() {
 		close(clientDisconnectChan)
+		if authenticated != nil {
+			// The connection was closed before the client token was checked.
+			authenticated(false)
+			return
+		}
 		if !s.config.AcceptMulti && s.config.DisconnectChan != nil {
 			close(s.config.DisconnectChan)
 		}
 	}()
 
+	s2 := s.s2.NewConnection()
+	defer s2.Close()
+	methods := s.newMethodMap(s2)
 	sending := new(sync.Mutex)
 	codec := jsonrpc.NewServerCodec(conn)
 	var req rpc.Request
@@ -219,7 +288,7 @@ func (s *ServerImpl) serveJSONCodec(conn io.ReadWriteCloser) {
 			break
 		}
 
-		mtype, ok := s.methodMaps[s.config.APIVersion-1][req.ServiceMethod]
+		mtype, ok := methods[req.ServiceMethod]
 		if !ok {
 			s.log.Errorf("rpc: can't find method %s", req.ServiceMethod)
 			s.sendResponse(sending, &req, &rpc.Response{}, nil, codec, fmt.Sprintf("unknown method: %s", req.ServiceMethod))
@@ -246,6 +315,15 @@ func (s *ServerImpl) serveJSONCodec(conn io.ReadWriteCloser) {
 			argv = argv.Elem()
 		}
 
+		if authenticated != nil {
+			if !s.checkClientToken(req.ServiceMethod, argv.Interface()) {
+				s.log.Warnf("closing connection from client that did not present the client token")
+				break
+			}
+			authenticated(true)
+			authenticated = nil
+		}
+
 		if mtype.Synchronous {
 			if logflags.RPC() {
 				argvbytes, _ := json.Marshal(argv.Interface())
@@ -300,6 +378,19 @@ func (s *ServerImpl) serveJSONCodec(conn io.ReadWriteCloser) {
 	codec.Close()
 }
 
+// checkClientToken returns true if the request is a SetApiVersion request
+// carrying the token configured for the server.
+func (s *ServerImpl) checkClientToken(method string, arg any) bool {
+	if method != "RPCServer.SetApiVersion" {
+		return false
+	}
+	in, ok := arg.(api.SetAPIVersionIn)
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(in.ClientToken), []byte(s.config.ClientToken)) == 1
+}
+
 // A value sent as a placeholder for the server's response value when the server
 // receives an invalid request. It is never decoded by the client since the Response
 // contains an error when it is used.
@@ -8,6 +8,7 @@ import (
 )
 
 func suitableMethods2(s *rpc2.RPCServer, methods map[string]*methodType) {
+	methods["RPCServer.AddressToLine"] = &methodType{method: reflect.ValueOf(s.AddressToLine)}
 	methods["RPCServer.AmendBreakpoint"] = &methodType{method: reflect.ValueOf(s.AmendBreakpoint)}
 	methods["RPCServer.Ancestors"] = &methodType{method: reflect.ValueOf(s.Ancestors)}
 	methods["RPCServer.AttachedToExistingProcess"] = &methodType{method: reflect.ValueOf(s.AttachedToExistingProcess)}
@@ -29,6 +30,7 @@ func suitableMethods2(s *rpc2.RPCServer, methods map[string]*methodType) {
 	methods["RPCServer.DumpStart"] = &methodType{method: reflect.ValueOf(s.DumpStart)}
 	methods["RPCServer.DumpWait"] = &methodType{method: reflect.ValueOf(s.DumpWait)}
 	methods["RPCServer.Eval"] = &methodType{method: reflect.ValueOf(s.Eval)}
+	methods["RPCServer.EvalGoroutines"] = &methodType{method: reflect.ValueOf(s.EvalGoroutines)}
 	methods["RPCServer.ExamineMemory"] = &methodType{method: reflect.ValueOf(s.ExamineMemory)}
 	methods["RPCServer.FindLocation"] = &methodType{method: reflect.ValueOf(s.FindLocation)}
 	methods["RPCServer.FollowExec"] = &methodType{method: reflect.ValueOf(s.FollowExec)}
@@ -36,8 +38,10 @@ func suitableMethods2(s *rpc2.RPCServer, methods map[string]*methodType) {
 	methods["RPCServer.FunctionReturnLocations"] = &methodType{method: reflect.ValueOf(s.FunctionReturnLocations)}
 	methods["RPCServer.GetBreakpoint"] = &methodType{method: reflect.ValueOf(s.GetBreakpoint)}
 	methods["RPCServer.GetBufferedTracepoints"] = &methodType{method: reflect.ValueOf(s.GetBufferedTracepoints)}
+	methods["RPCServer.GetConfig"] = &methodType{method: reflect.ValueOf(s.GetConfig)}
 	methods["RPCServer.GetEvents"] = &methodType{method: reflect.ValueOf(s.GetEvents)}
 	methods["RPCServer.GetThread"] = &methodType{method: reflect.ValueOf(s.GetThread)}
+	methods["RPCServer.GoroutineCreationStacktrace"] = &methodType{method: reflect.ValueOf(s.GoroutineCreationStacktrace)}
 	methods["RPCServer.GuessSubstitutePath"] = &methodType{method: reflect.ValueOf(s.GuessSubstitutePath)}
 	methods["RPCServer.IsMulticlient"] = &methodType{method: reflect.ValueOf(s.IsMulticlient)}
 	methods["RPCServer.LastModified"] = &methodType{method: reflect.ValueOf(s.LastModified)}
@@ -46,24 +50,39 @@ func suitableMethods2(s *rpc2.RPCServer, methods map[string]*methodType) {
 	methods["RPCServer.ListDynamicLibraries"] = &methodType{method: reflect.ValueOf(s.ListDynamicLibraries)}
 	methods["RPCServer.ListFunctionArgs"] = &methodType{method: reflect.ValueOf(s.ListFunctionArgs)}
 	methods["RPCServer.ListFunctions"] = &methodType{method: reflect.ValueOf(s.ListFunctions)}
+	methods["RPCServer.ListGoroutineDefers"] = &methodType{method: reflect.ValueOf(s.ListGoroutineDefers)}
+	methods["RPCServer.ListGoroutinePanics"] = &methodType{method: reflect.ValueOf(s.ListGoroutinePanics)}
+	methods["RPCServer.ListGoroutineStackGroups"] = &methodType{method: reflect.ValueOf(s.ListGoroutineStackGroups)}
 	methods["RPCServer.ListGoroutines"] = &methodType{method: reflect.ValueOf(s.ListGoroutines)}
 	methods["RPCServer.ListLocalVars"] = &methodType{method: reflect.ValueOf(s.ListLocalVars)}
+	methods["RPCServer.ListMemoryMappings"] = &methodType{method: reflect.ValueOf(s.ListMemoryMappings)}
 	methods["RPCServer.ListPackageVars"] = &methodType{method: reflect.ValueOf(s.ListPackageVars)}
 	methods["RPCServer.ListPackagesBuildInfo"] = &methodType{method: reflect.ValueOf(s.ListPackagesBuildInfo)}
+	methods["RPCServer.ListPlugins"] = &methodType{method: reflect.ValueOf(s.ListPlugins)}
+	methods["RPCServer.ListProducers"] = &methodType{method: reflect.ValueOf(s.ListProducers)}
 	methods["RPCServer.ListRegisters"] = &methodType{method: reflect.ValueOf(s.ListRegisters)}
+	methods["RPCServer.ListSignalPolicies"] = &methodType{method: reflect.ValueOf(s.ListSignalPolicies)}
 	methods["RPCServer.ListSources"] = &methodType{method: reflect.ValueOf(s.ListSources)}
 	methods["RPCServer.ListTargets"] = &methodType{method: reflect.ValueOf(s.ListTargets)}
 	methods["RPCServer.ListThreads"] = &methodType{method: reflect.ValueOf(s.ListThreads)}
+	methods["RPCServer.ListTypeArguments"] = &methodType{method: reflect.ValueOf(s.ListTypeArguments)}
 	methods["RPCServer.ListTypes"] = &methodType{method: reflect.ValueOf(s.ListTypes)}
 	methods["RPCServer.ProcessPid"] = &methodType{method: reflect.ValueOf(s.ProcessPid)}
 	methods["RPCServer.Recorded"] = &methodType{method: reflect.ValueOf(s.Recorded)}
 	methods["RPCServer.Restart"] = &methodType{method: reflect.ValueOf(s.Restart)}
+	methods["RPCServer.SearchMemory"] = &methodType{method: reflect.ValueOf(s.SearchMemory)}
 	methods["RPCServer.Set"] = &methodType{method: reflect.ValueOf(s.Set)}
+	methods["RPCServer.SetConfig"] = &methodType{method: reflect.ValueOf(s.SetConfig)}
+	methods["RPCServer.SetRegister"] = &methodType{method: reflect.ValueOf(s.SetRegister)}
+	methods["RPCServer.SetSignalPolicy"] = &methodType{method: reflect.ValueOf(s.SetSignalPolicy)}
 	methods["RPCServer.Stacktrace"] = &methodType{method: reflect.ValueOf(s.Stacktrace)}
 	methods["RPCServer.State"] = &methodType{method: reflect.ValueOf(s.State)}
 	methods["RPCServer.StopRecording"] = &methodType{method: reflect.ValueOf(s.StopRecording)}
+	methods["RPCServer.SubscribeEvents"] = &methodType{method: reflect.ValueOf(s.SubscribeEvents)}
 	methods["RPCServer.ToggleBreakpoint"] = &methodType{method: reflect.ValueOf(s.ToggleBreakpoint)}
 	methods["RPCServer.TypeInfo"] = &methodType{method: reflect.ValueOf(s.TypeInfo)}
+	methods["RPCServer.UnsubscribeEvents"] = &methodType{method: reflect.ValueOf(s.UnsubscribeEvents)}
+	methods["RPCServer.WaitEvents"] = &methodType{method: reflect.ValueOf(s.WaitEvents)}
 }
 
 func suitableMethodsCommon(s *RPCServer, methods map[string]*methodType) {
@@ -1,8 +1,15 @@
 package service_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"flag"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"net"
 	"net/rpc"
@@ -912,6 +919,48 @@ func TestClientServer_infoArgs(t *testing.T) {
 	})
 }
 
+func TestClientServer_SetRegister(t *testing.T) {
+	if runtime.GOARCH != "amd64" {
+		t.Skip("test is valid only on AMD64")
+	}
+	withTestClient2("testnextprog", t, func(c service.Client) {
+		fp := testProgPath(t, "testnextprog")
+		_, err := c.CreateBreakpoint(&api.Breakpoint{File: fp, Line: 47})
+		assertNoError(err, t, "CreateBreakpoint()")
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue()")
+
+		regValue := func(regs api.Registers, name string) uint64 {
+			t.Helper()
+			for _, reg := range regs {
+				if reg.Name == name {
+					n, err := strconv.ParseUint(reg.Value, 0, 64)
+					assertNoError(err, t, "ParseUint()")
+					return n
+				}
+			}
+			t.Fatalf("register %s not found in %s", name, regs.String())
+			return 0
+		}
+
+		regs, err := c.SetRegister(0, "rbx", 0xdeadbeef)
+		assertNoError(err, t, "SetRegister(rbx)")
+		if n := regValue(regs, "Rbx"); n != 0xdeadbeef {
+			t.Errorf("wrong value of Rbx returned by SetRegister: %#x", n)
+		}
+		regs, err = c.ListThreadRegisters(state.CurrentThread.ID, false)
+		assertNoError(err, t, "ListThreadRegisters()")
+		if n := regValue(regs, "Rbx"); n != 0xdeadbeef {
+			t.Errorf("wrong value of Rbx after SetRegister: %#x", n)
+		}
+
+		_, err = c.SetRegister(0, "notaregister", 1)
+		assertError(err, t, "SetRegister(notaregister)")
+		_, err = c.SetRegister(0, "xmm0", 1)
+		assertError(err, t, "SetRegister(xmm0)")
+	})
+}
+
 func TestClientServer_traceContinue(t *testing.T) {
 	protest.AllowRecording(t)
 	withTestClient2("integrationprog", t, func(c service.Client) {
@@ -1823,6 +1872,22 @@ func TestClientServer_FpRegisters(t *testing.T) {
 		regs, err := c.ListThreadRegisters(0, true)
 		assertNoError(err, t, "ListThreadRegisters()")
 
+		for _, regcat := range []struct {
+			name string
+			cat  api.RegisterCategory
+		}{
+			{"Rip", api.RegisterGeneral},
+			{"Rflags", api.RegisterFlags},
+			{"ST(0)", api.RegisterFloat},
+			{"XMM0", api.RegisterVector},
+		} {
+			for _, reg := range regs {
+				if reg.Name == regcat.name && reg.Category != regcat.cat {
+					t.Errorf("register %s expected category %q got %q", reg.Name, regcat.cat, reg.Category)
+				}
+			}
+		}
+
 		for _, regtest := range regtests {
 			if regtest.name == "XMM11" && !avx2 {
 				continue
@@ -2140,6 +2205,165 @@ func TestAcceptMulticlient(t *testing.T) {
 	<-serverDone
 }
 
+func TestClientToken(t *testing.T) {
+	if testBackend == "rr" {
+		t.Skip("recording not allowed for TestClientToken")
+	}
+	for _, acceptMulti := range []bool{true, false} {
+		t.Run(fmt.Sprintf("AcceptMulti=%v", acceptMulti), func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("couldn't start listener: %s\n", err)
+			}
+			serverDone := make(chan struct{})
+			go func() {
+				defer close(serverDone)
+				defer listener.Close()
+				disconnectChan := make(chan struct{})
+				server := rpccommon.NewServer(&service.Config{
+					Listener:       listener,
+					ProcessArgs:    []string{protest.BuildFixture(t, "testvariables2", 0).Path},
+					AcceptMulti:    acceptMulti,
+					DisconnectChan: disconnectChan,
+					ClientToken:    "secret",
+					Debugger: debugger.Config{
+						Backend:     testBackend,
+						ExecuteKind: debugger.ExecutingGeneratedTest,
+					},
+				})
+				if err := server.Run(); err != nil {
+					panic(err)
+				}
+				<-disconnectChan
+				server.Stop()
+			}()
+
+			dial := func() net.Conn {
+				conn, err := net.Dial("tcp", listener.Addr().String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return conn
+			}
+
+			if _, err := rpc2.NewClientFromConnWithToken(dial(), "wrong"); err == nil {
+				t.Fatal("connection with the wrong token was accepted")
+			}
+
+			// Requests other than SetApiVersion are not accepted before the token.
+			rpcClient := jsonrpc.NewClient(dial())
+			if err := rpcClient.Call("RPCServer.GetVersion", api.GetVersionIn{}, &api.GetVersionOut{}); err == nil {
+				t.Fatal("request without a token was accepted")
+			}
+			rpcClient.Close()
+
+			// DAP clients can not present a token.
+			dapConn := dial()
+			fmt.Fprintf(dapConn, "Content-Length: 2\r\n\r\n{}")
+			if _, err := dapConn.Read(make([]byte, 1)); err == nil {
+				t.Fatal("DAP connection was accepted")
+			}
+			dapConn.Close()
+
+			client, err := rpc2.NewClientFromConnWithToken(dial(), "secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			state := <-client.Continue()
+			if state.CurrentThread.Function.Name() != "main.main" {
+				t.Fatalf("bad state after continue: %v\n", state)
+			}
+			client.Detach(true)
+			<-serverDone
+		})
+	}
+}
+
+func TestTLSListener(t *testing.T) {
+	if testBackend == "rr" {
+		t.Skip("recording not allowed for TestTLSListener")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(cryptorand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	for _, acceptMulti := range []bool{true, false} {
+		t.Run(fmt.Sprintf("AcceptMulti=%v", acceptMulti), func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("couldn't start listener: %s\n", err)
+			}
+			serverDone := make(chan struct{})
+			go func() {
+				defer close(serverDone)
+				defer listener.Close()
+				disconnectChan := make(chan struct{})
+				server := rpccommon.NewServer(&service.Config{
+					Listener:       listener,
+					ProcessArgs:    []string{protest.BuildFixture(t, "testvariables2", 0).Path},
+					AcceptMulti:    acceptMulti,
+					DisconnectChan: disconnectChan,
+					TLSConfig:      &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}},
+					Debugger: debugger.Config{
+						Backend:     testBackend,
+						ExecuteKind: debugger.ExecutingGeneratedTest,
+					},
+				})
+				if err := server.Run(); err != nil {
+					panic(err)
+				}
+				<-disconnectChan
+				server.Stop()
+			}()
+
+			// Plain connections are rejected.
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			rpcClient := jsonrpc.NewClient(conn)
+			if err := rpcClient.Call("RPCServer.GetVersion", api.GetVersionIn{}, &api.GetVersionOut{}); err == nil {
+				t.Fatal("plain connection was accepted")
+			}
+			rpcClient.Close()
+
+			tlsConn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{RootCAs: pool})
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := rpc2.NewClientFromConn(tlsConn)
+			state := <-client.Continue()
+			if state.CurrentThread.Function.Name() != "main.main" {
+				t.Fatalf("bad state after continue: %v\n", state)
+			}
+			client.Detach(true)
+			<-serverDone
+		})
+	}
+}
+
 func TestForceStopWhileContinue(t *testing.T) {
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -2472,6 +2696,146 @@ func TestClearLogicalBreakpoint(t *testing.T) {
 	})
 }
 
+func TestStacktraceMaxDepth(t *testing.T) {
+	// Stacktraces deeper than the configured maximum depth must be truncated
+	// and end with a frame describing the error, even if the client asks for
+	// more frames.
+	if testBackend == "rr" {
+		protest.MustHaveRecordingAllowed(t)
+	}
+	listener, clientConn := service.ListenerPipe()
+	defer listener.Close()
+	fixture := protest.BuildFixture(t, "deepstack", 0)
+	server := rpccommon.NewServer(&service.Config{
+		Listener:    listener,
+		ProcessArgs: []string{fixture.Path},
+		Debugger: debugger.Config{
+			Backend:       testBackend,
+			ExecuteKind:   debugger.ExecutingGeneratedFile,
+			MaxStackDepth: 50,
+		},
+	})
+	if err := server.Run(); err != nil {
+		t.Fatal(err)
+	}
+	c := rpc2.NewClientFromConn(clientConn)
+	defer c.Detach(true)
+
+	state := <-c.Continue()
+	assertNoError(state.Err, t, "Continue()")
+	frames, err := c.Stacktrace(-1, 5000, 0, 0, nil)
+	assertNoError(err, t, "Stacktrace()")
+	if len(frames) != 51 {
+		t.Fatalf("wrong number of frames %d", len(frames))
+	}
+	for i := range frames[:50] {
+		if frames[i].Err != "" {
+			t.Fatalf("unexpected error for frame %d: %s", i, frames[i].Err)
+		}
+	}
+	if !strings.Contains(frames[50].Err, "possible corruption") {
+		t.Fatalf("wrong error for last frame: %q", frames[50].Err)
+	}
+}
+
+func TestStacktraceInlinedFrames(t *testing.T) {
+	withTestClient2Extended("testinline", t, protest.EnableInlining, [3]string{}, nil, func(c service.Client, fixture protest.Fixture) {
+		_, err := c.CreateBreakpoint(&api.Breakpoint{FunctionName: "main.inlineThis"})
+		assertNoError(err, t, "CreateBreakpoint()")
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue()")
+		frames, err := c.Stacktrace(-1, 10, 0, 0, nil)
+		assertNoError(err, t, "Stacktrace()")
+		if len(frames) < 2 {
+			t.Fatalf("stacktrace too short: %d", len(frames))
+		}
+		if !frames[0].Inlined || frames[0].Function.Name() != "main.inlineThis" {
+			t.Errorf("frame 0 is not an inlined call to main.inlineThis: %s inlined=%v", frames[0].Function.Name(), frames[0].Inlined)
+		}
+		if frames[0].InlinedCallerIndex != 1 || frames[0].FrameOffset != 0 || frames[0].FramePointerOffset != 0 {
+			t.Errorf("wrong inlined frame: caller=%d offset=%#x bpoffset=%#x", frames[0].InlinedCallerIndex, frames[0].FrameOffset, frames[0].FramePointerOffset)
+		}
+		if frames[1].Inlined || frames[1].Function.Name() != "main.main" || frames[1].FrameOffset == 0 {
+			t.Errorf("frame 1 is not the physical frame of main.main: %s inlined=%v offset=%#x", frames[1].Function.Name(), frames[1].Inlined, frames[1].FrameOffset)
+		}
+	})
+}
+
+func TestStacktraceCgoFrames(t *testing.T) {
+	if runtime.GOARCH == "386" || runtime.GOARCH == "ppc64le" || (runtime.GOOS == "windows" && runtime.GOARCH == "arm64") {
+		t.Skip("broken - cgo stacktraces")
+	}
+	protest.MustHaveCgo(t)
+	withTestClient2("cgostacktest/", t, func(c service.Client) {
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue()")
+		state = <-c.Continue()
+		assertNoError(state.Err, t, "Continue()")
+		frames, err := c.Stacktrace(-1, 20, 0, 0, nil)
+		assertNoError(err, t, "Stacktrace()")
+		foundC, foundGo := false, false
+		for _, frame := range frames {
+			if frame.Function == nil {
+				continue
+			}
+			name := frame.Function.Name()
+			switch {
+			case strings.HasPrefix(name, "C."):
+				foundC = true
+				if !frame.IsCgo {
+					t.Errorf("frame %s not marked as cgo", name)
+				}
+			case strings.HasPrefix(name, "main."):
+				foundGo = true
+				if frame.IsCgo {
+					t.Errorf("frame %s marked as cgo", name)
+				}
+			}
+		}
+		if !foundC || !foundGo {
+			t.Fatalf("stacktrace does not cross the Go/C boundary (C frames: %v, Go frames: %v)", foundC, foundGo)
+		}
+	})
+}
+
+func TestClientServer_EvalGoroutines(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestClient2("goroutinestackprog", t, func(c service.Client) {
+		_, err := c.CreateBreakpoint(&api.Breakpoint{FunctionName: "main.stacktraceme"})
+		assertNoError(err, t, "CreateBreakpoint()")
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue()")
+
+		results, err := c.EvalGoroutines("main.dummy", normalLoadConfig)
+		assertNoError(err, t, "EvalGoroutines(main.dummy)")
+		if len(results) < 10 {
+			t.Fatalf("expected at least 10 results, got %d", len(results))
+		}
+		for goid, r := range results {
+			if r.Error != "" || r.Variable == nil || r.Variable.Value != "0" {
+				t.Errorf("goroutine %d: wrong result %#v", goid, r)
+			}
+		}
+
+		// The unqualified name only resolves in goroutines whose topmost frame
+		// belongs to package main.
+		results, err = c.EvalGoroutines("dummy", normalLoadConfig)
+		assertNoError(err, t, "EvalGoroutines(dummy)")
+		if r := results[state.CurrentThread.GoroutineID]; r.Error != "" || r.Variable == nil {
+			t.Errorf("current goroutine: wrong result %#v", r)
+		}
+		nerr := 0
+		for _, r := range results {
+			if r.Error != "" {
+				nerr++
+			}
+		}
+		if nerr == 0 {
+			t.Errorf("expected the evaluation to fail for some goroutines")
+		}
+	})
+}
+
 func TestRedirects(t *testing.T) {
 	const (
 		infile  = "redirect-input.txt"
@@ -3175,6 +3539,67 @@ func TestClientServer_chanGoroutines(t *testing.T) {
 	})
 }
 
+func TestClientServer_goroutinesWaitReasonFilter(t *testing.T) {
+	withTestClient2("changoroutines", t, func(c service.Client) {
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue()")
+
+		filters, _, _, _, _, _, _, err := api.ParseGoroutineArgs("-with reason CHAN SEND -with user")
+		assertNoError(err, t, "ParseGoroutineArgs")
+		gs, _, _, _, err := c.ListGoroutinesWithFilter(0, 100, filters, nil, nil)
+		assertNoError(err, t, "ListGoroutinesWithFilter(chan send)")
+		if len(gs) != 2 {
+			t.Errorf("wrong number of goroutines waiting on chan send: %d", len(gs))
+		}
+
+		gs, _, _, _, err = c.ListGoroutinesWithFilter(0, 100, []api.ListGoroutinesFilter{{Kind: api.GoroutineWaitReason, Arg: "chan receive"}}, nil, nil)
+		assertNoError(err, t, "ListGoroutinesWithFilter(chan receive)")
+		if len(gs) != 1 {
+			t.Errorf("wrong number of goroutines waiting on chan receive: %d", len(gs))
+		}
+	})
+}
+
+func TestClientServer_goroutineStackGroups(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestClient2("goroutinegroup", t, func(c service.Client) {
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue")
+		filters := []api.ListGoroutinesFilter{{Kind: api.GoroutineLabel, Arg: "name=one"}}
+		gs, _, _, _, err := c.ListGoroutinesWithFilter(0, 0, filters, nil, nil)
+		assertNoError(err, t, "ListGoroutinesWithFilter")
+		groups, err := c.ListGoroutineStackGroups(filters, 0, 3)
+		assertNoError(err, t, "ListGoroutineStackGroups")
+
+		total := 0
+		startpoints := map[string]bool{}
+		for i, group := range groups {
+			t.Logf("%s %d %v %v", group.ID, group.Count, group.GoroutineIDs, group.Labels)
+			total += group.Count
+			if len(group.GoroutineIDs) > 3 || len(group.GoroutineIDs) > group.Count {
+				t.Errorf("wrong number of goroutine IDs in group %d: %d", i, len(group.GoroutineIDs))
+			}
+			if i > 0 && group.Count > groups[i-1].Count {
+				t.Errorf("groups not sorted by size")
+			}
+			if len(group.Labels) != 1 || group.Labels[0]["name"] != "one" {
+				t.Errorf("wrong labels for group %d: %v", i, group.Labels)
+			}
+			for _, frame := range group.Stack {
+				if frame.Function != nil && strings.HasPrefix(frame.Function.Name(), "main.startpoint") {
+					startpoints[frame.Function.Name()] = true
+				}
+			}
+		}
+		if total != len(gs) {
+			t.Errorf("wrong number of goroutines in groups: %d (expected %d)", total, len(gs))
+		}
+		if len(startpoints) != 5 {
+			t.Errorf("wrong number of distinct stacks: %v", startpoints)
+		}
+	})
+}
+
 func TestNextInstruction(t *testing.T) {
 	protest.AllowRecording(t)
 	withTestClient2("testprog", t, func(c service.Client) {
@@ -3477,6 +3902,9 @@ func TestTypeInfo(t *testing.T) {
 
 		info, err = c.TypeInfo("main.astruct")
 		assertNoError(err, t, "TypeInfo")
+		if info.Tree != nil {
+			t.Error("unexpected type tree")
+		}
 		t.Logf("%#v", info)
 		t.Logf("kind %s size %d", info.Kind.String(), info.Size)
 		if len(info.Fields) != 2 {
@@ -3491,5 +3919,375 @@ func TestTypeInfo(t *testing.T) {
 		if slices.Index(info.Methods, api.TypeInfoMethod{Name: "main.astruct.NonPointerReceiverMethod"}) < 0 {
 			t.Error("could not find NonPointerReceiverMethod")
 		}
+
+		info, err = c.TypeInfoTree("main.astruct")
+		assertNoError(err, t, "TypeInfo")
+		if info.Tree == nil || len(info.Tree.Children) != 2 {
+			t.Fatalf("wrong type tree %#v", info.Tree)
+		}
+		if b := info.Tree.Children[1]; b.Name != "B" || b.Type != "int" || b.Offset != 8 || b.Size != 8 {
+			t.Errorf("wrong type tree node for field B %#v", b)
+		}
+	})
+}
+
+func TestAddressToLine(t *testing.T) {
+	withTestClient2("testnextprog", t, func(c service.Client) {
+		locs, _, err := c.FindLocation(api.EvalScope{GoroutineID: -1}, "testnextprog.go:19", false, nil)
+		assertNoError(err, t, "FindLocation")
+		if len(locs) != 1 {
+			t.Fatalf("wrong number of locations %d", len(locs))
+		}
+
+		loc, err := c.AddressToLine(locs[0].PC, 2)
+		assertNoError(err, t, "AddressToLine")
+		t.Logf("%#v", loc)
+		if !loc.Exact || filepath.Base(loc.File) != "testnextprog.go" || loc.Line != 19 || loc.Function == nil || loc.Function.Name() != "main.testnext" {
+			t.Errorf("wrong location %#v", loc.Location)
+		}
+		if loc.FirstLine != 17 || len(loc.Lines) != 5 || strings.TrimSpace(loc.Lines[2]) != "j = 1" {
+			t.Errorf("wrong context %d %q", loc.FirstLine, loc.Lines)
+		}
+
+		loc, err = c.AddressToLine(locs[0].PC, 0)
+		assertNoError(err, t, "AddressToLine")
+		if loc.Line != 19 || len(loc.Lines) != 0 {
+			t.Errorf("unexpected context %#v", loc)
+		}
+
+		_, err = c.AddressToLine(0, 0)
+		if err == nil {
+			t.Error("expected error for address 0")
+		}
+	})
+}
+
+func TestListTypeArguments(t *testing.T) {
+	withTestClient2("generictypeargs", t, func(c service.Client) {
+		checkTypeArgs := func(tgt string) {
+			t.Helper()
+			state := <-c.Continue()
+			assertNoError(state.Err, t, "Continue")
+			scope := api.EvalScope{GoroutineID: -1, Frame: 0}
+			targs, err := c.ListTypeArguments(scope, nil)
+			assertNoError(err, t, "ListTypeArguments")
+			if got := fmt.Sprintf("%v", targs); got != tgt {
+				t.Errorf("wrong type arguments, got %s expected %s", got, tgt)
+			}
+		}
+		checkTypeArgs("[{T int}]")
+
+		// The names of the type parameters are read from the source file after
+		// applying the substitute-path rules.
+		fixtures, _ := filepath.Abs(protest.FindFixturesDir())
+		src, err := os.ReadFile(filepath.Join(fixtures, "generictypeargs.go"))
+		assertNoError(err, t, "ReadFile")
+		substdir := t.TempDir()
+		src = []byte(strings.ReplaceAll(string(src), "[T any](x T)", "[Elem any](x Elem)"))
+		assertNoError(os.WriteFile(filepath.Join(substdir, "generictypeargs.go"), src, 0o666), t, "WriteFile")
+		rules := [][2]string{{fixtures, substdir}}
+		targs, err := c.ListTypeArguments(api.EvalScope{GoroutineID: -1, Frame: 0}, rules)
+		assertNoError(err, t, "ListTypeArguments")
+		if got := fmt.Sprintf("%v", targs); got != "[{Elem int}]" {
+			t.Errorf("wrong type arguments with substitute-path rules, got %s", got)
+		}
+
+		checkTypeArgs("[{T string}]")
+		checkTypeArgs("[{E float64}]")
+
+		_, targs, err = c.ListLocalVariablesWithTypeArguments(api.EvalScope{GoroutineID: -1, Frame: 0}, normalLoadConfig, nil)
+		assertNoError(err, t, "ListLocalVariablesWithTypeArguments")
+		if got := fmt.Sprintf("%v", targs); got != "[{E float64}]" {
+			t.Errorf("wrong type arguments from ListLocalVariablesWithTypeArguments, got %s", got)
+		}
+
+		// Not a generic function
+		targs, err = c.ListTypeArguments(api.EvalScope{GoroutineID: -1, Frame: 1}, nil)
+		assertNoError(err, t, "ListTypeArguments")
+		if len(targs) != 0 {
+			t.Errorf("unexpected type arguments for main.main: %v", targs)
+		}
+	})
+}
+
+func TestClientServer_ListProducers(t *testing.T) {
+	withTestClient2("increment", t, func(c service.Client) {
+		producers, err := c.ListProducers()
+		assertNoError(err, t, "ListProducers()")
+		found := false
+		for _, pi := range producers {
+			for _, file := range pi.Files {
+				if strings.HasSuffix(file, "/increment.go") {
+					found = true
+					if !strings.Contains(pi.Producer, "-N") || !strings.Contains(pi.Producer, "-l") {
+						t.Errorf("wrong producer for %s: %q", file, pi.Producer)
+					}
+				}
+			}
+		}
+		if !found {
+			t.Errorf("increment.go not found in %v", producers)
+		}
+	})
+}
+
+func TestClientServer_ListSourceFiles(t *testing.T) {
+	withTestClient2("pkgrenames", t, func(c service.Client) {
+		files, err := c.ListSourceFiles("")
+		assertNoError(err, t, "ListSourceFiles()")
+		sources, err := c.ListSources("")
+		assertNoError(err, t, "ListSources()")
+		if len(files) != len(sources) {
+			t.Errorf("ListSourceFiles returned %d files, ListSources returned %d", len(files), len(sources))
+		}
+		tgt := map[string][2]string{
+			"/_fixtures/pkgrenames.go":             {"main", "main"},
+			"/_fixtures/internal/dir0/pkg/main.go": {"pkg", "github.com/go-delve/delve/_fixtures/internal/dir0/pkg"},
+			"/_fixtures/internal/dir.io/dir.go":    {"dirio", "github.com/go-delve/delve/_fixtures/internal/dir.io"},
+			"/src/runtime/proc.go":                 {"runtime", "runtime"},
+		}
+		found := 0
+		for _, f := range files {
+			for suffix, pkg := range tgt {
+				if !strings.HasSuffix(filepath.ToSlash(f.Path), suffix) {
+					continue
+				}
+				found++
+				if f.Package != pkg[0] || f.CompileUnit != pkg[1] {
+					t.Errorf("%s: wrong package %q and compile unit %q (expected %q and %q)", f.Path, f.Package, f.CompileUnit, pkg[0], pkg[1])
+				}
+			}
+		}
+		if found != len(tgt) {
+			t.Errorf("some files were not found (%d of %d)", found, len(tgt))
+		}
+	})
+}
+
+func TestClientServer_ThreadOSState(t *testing.T) {
+	protest.AllowRecording(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("fixture uses syscall.Pipe")
+	}
+	withTestClient2("threadsyscall", t, func(c service.Client) {
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue()")
+		threads, err := c.ListThreads()
+		assertNoError(err, t, "ListThreads()")
+		inRead := false
+		for _, th := range threads {
+			t.Logf("thread %d %q", th.ID, th.OSState)
+			if runtime.GOOS == "linux" && testBackend == "native" {
+				switch {
+				case th.OSState == "", th.OSState == "tracing stop":
+					t.Errorf("no OS state for thread %d: %q", th.ID, th.OSState)
+				case th.ID == state.CurrentThread.ID && th.OSState != "running":
+					t.Errorf("wrong OS state for current thread %d: %q", th.ID, th.OSState)
+				case th.OSState == "in syscall read":
+					inRead = true
+				}
+			} else if th.OSState != "" {
+				t.Errorf("unexpected OS state for thread %d: %q", th.ID, th.OSState)
+			}
+		}
+		if runtime.GOOS == "linux" && testBackend == "native" && !inRead {
+			t.Error("no thread blocked in the read system call")
+		}
+	})
+}
+
+func TestClientServer_Config(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestClient2("testvariables", t, func(c service.Client) {
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue()")
+
+		cfg, err := c.GetConfig()
+		assertNoError(err, t, "GetConfig()")
+		if cfg.LoadConfig.MaxStringLen != 64 || len(cfg.SubstitutePathRules) != 0 {
+			t.Errorf("unexpected default configuration %#v", cfg)
+		}
+
+		fixtures, _ := filepath.Abs(protest.FindFixturesDir())
+		cfg.LoadConfig.MaxStringLen = 3
+		cfg.SubstitutePathRules = [][2]string{{fixtures, "/nonexistent/dir"}}
+		assertNoError(c.SetConfig(*cfg), t, "SetConfig()")
+
+		cfg2, err := c.GetConfig()
+		assertNoError(err, t, "GetConfig()")
+		if cfg2.LoadConfig.MaxStringLen != 3 || len(cfg2.SubstitutePathRules) != 1 {
+			t.Errorf("configuration not changed %#v", cfg2)
+		}
+
+		// requests without a load configuration use the one set by SetConfig
+		var out rpc2.EvalOut
+		assertNoError(c.CallAPI("Eval", rpc2.EvalIn{Scope: api.EvalScope{GoroutineID: -1}, Expr: "a1"}, &out), t, "Eval")
+		if out.Variable.Value != "foo" || out.Variable.Len != 18 {
+			t.Errorf("wrong value for a1: %q (len %d)", out.Variable.Value, out.Variable.Len)
+		}
+
+		// requests without substitute-path rules use the ones set by SetConfig
+		locs, _, err := c.FindLocation(api.EvalScope{GoroutineID: -1}, "/nonexistent/dir/testvariables.go:25", false, nil)
+		assertNoError(err, t, "FindLocation()")
+		if len(locs) != 1 {
+			t.Errorf("wrong number of locations %d", len(locs))
+		}
+
+		cfg.LoadConfig.MaxArrayValues = -1
+		if err := c.SetConfig(*cfg); err == nil {
+			t.Error("expected error setting negative limit")
+		}
+		if err := c.SetConfig(api.ClientConfig{}); err == nil {
+			t.Error("expected error setting zero load configuration")
+		}
+	})
+}
+
+func TestSubscribeEventsDisconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("couldn't start listener: %s\n", err)
+	}
+	serverDone := make(chan struct{})
+	disconnectChan := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		defer listener.Close()
+		server := rpccommon.NewServer(&service.Config{
+			Listener:       listener,
+			ProcessArgs:    []string{protest.BuildFixture(t, "continuetestprog", 0).Path},
+			AcceptMulti:    true,
+			DisconnectChan: disconnectChan,
+			Debugger: debugger.Config{
+				Backend:     testBackend,
+				ExecuteKind: debugger.ExecutingGeneratedTest,
+			},
+		})
+		if err := server.Run(); err != nil {
+			panic(err)
+		}
+		<-disconnectChan
+		server.Stop()
+	}()
+
+	subscribe := func(c *rpc2.RPCClient) error {
+		return c.CallAPI("SubscribeEvents", rpc2.SubscribeEventsIn{}, &rpc2.SubscribeEventsOut{})
+	}
+
+	c1 := rpc2.NewClient(listener.Addr().String())
+	c2 := rpc2.NewClient(listener.Addr().String())
+
+	// the number of subscriptions is limited
+	var err2 error
+	for range 1000 {
+		err2 = subscribe(c2)
+		if err2 != nil {
+			break
+		}
+	}
+	if err2 == nil {
+		t.Fatal("no limit on the number of subscriptions")
+	}
+	t.Logf("subscribe error: %v", err2)
+
+	// subscriptions are removed when the client that created them disconnects
+	c2.Disconnect(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := subscribe(c1)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("subscriptions not removed after disconnect: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	c1.Detach(true)
+	<-serverDone
+}
+
+func TestClientServer_ConfigPerConnection(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("couldn't start listener: %s\n", err)
+	}
+	serverDone := make(chan struct{})
+	disconnectChan := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		defer listener.Close()
+		server := rpccommon.NewServer(&service.Config{
+			Listener:       listener,
+			ProcessArgs:    []string{protest.BuildFixture(t, "testvariables", 0).Path},
+			AcceptMulti:    true,
+			DisconnectChan: disconnectChan,
+			Debugger: debugger.Config{
+				Backend:     testBackend,
+				ExecuteKind: debugger.ExecutingGeneratedTest,
+			},
+		})
+		if err := server.Run(); err != nil {
+			panic(err)
+		}
+		<-disconnectChan
+		server.Stop()
+	}()
+
+	c1 := rpc2.NewClient(listener.Addr().String())
+	c2 := rpc2.NewClient(listener.Addr().String())
+
+	cfg, err := c1.GetConfig()
+	assertNoError(err, t, "GetConfig()")
+	cfg.LoadConfig.MaxStringLen = 3
+	assertNoError(c1.SetConfig(*cfg), t, "SetConfig()")
+
+	cfg2, err := c2.GetConfig()
+	assertNoError(err, t, "GetConfig()")
+	if cfg2.LoadConfig.MaxStringLen != 64 {
+		t.Errorf("configuration of the second client changed: %#v", cfg2)
+	}
+
+	c2.Disconnect(false)
+	c1.Detach(true)
+	<-serverDone
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	protest.AllowRecording(t)
+	withTestClient2("continuetestprog", t, func(c service.Client) {
+		events, cancel, err := c.SubscribeEvents()
+		assertNoError(err, t, "SubscribeEvents")
+
+		bp, err := c.CreateBreakpoint(&api.Breakpoint{FunctionName: "main.sayhi"})
+		assertNoError(err, t, "CreateBreakpoint")
+
+		state := <-c.Continue()
+		assertNoError(state.Err, t, "Continue")
+
+		var kinds []api.EventKind
+		for ev := range events {
+			t.Logf("event = %#v", ev)
+			kinds = append(kinds, ev.Kind)
+			if ev.Kind != api.EventStateChanged {
+				continue
+			}
+			if ev.StateChangedEventDetails.Err != "" {
+				t.Fatalf("unexpected error: %s", ev.StateChangedEventDetails.Err)
+			}
+			st := ev.StateChangedEventDetails.State
+			if st == nil || st.CurrentThread == nil || st.CurrentThread.Breakpoint == nil || st.CurrentThread.Breakpoint.ID != bp.ID {
+				t.Errorf("wrong state in EventStateChanged: %#v", st)
+			}
+			break
+		}
+		if len(kinds) != 3 || kinds[0] != api.EventResumed || kinds[1] != api.EventStopped || kinds[2] != api.EventStateChanged {
+			t.Errorf("wrong sequence of events: %v", kinds)
+		}
+
+		cancel()
+		for range events {
+		}
 	})
 }